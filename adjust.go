@@ -30,32 +30,32 @@ const (
 )
 
 // adjustHelperFunc defines functions to adjust helper.
-var adjustHelperFunc = [9]func(*File, *xlsxWorksheet, string, adjustDirection, int, int, int) error{
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+var adjustHelperFunc = [9]func(*File, *xlsxWorksheet, string, adjustDirection, int, int, int, map[string]bool) error{
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustConditionalFormats(ws, sheet, dir, num, offset, sheetID)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
-		return f.adjustDataValidations(ws, sheet, dir, num, offset, sheetID)
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
+		return f.adjustDataValidations(ws, sheet, dir, num, offset, sheetID, tableNames)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
-		return f.adjustDefinedNames(ws, sheet, dir, num, offset, sheetID)
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
+		return f.adjustDefinedNames(ws, sheet, dir, num, offset, sheetID, tableNames)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustDrawings(ws, sheet, dir, num, offset, sheetID)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustMergeCells(ws, sheet, dir, num, offset, sheetID)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustAutoFilter(ws, sheet, dir, num, offset, sheetID)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustCalcChain(ws, sheet, dir, num, offset, sheetID)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustTable(ws, sheet, dir, num, offset, sheetID)
 	},
-	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+	func(f *File, ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 		return f.adjustVolatileDeps(ws, sheet, dir, num, offset, sheetID)
 	},
 }
@@ -71,24 +71,25 @@ var adjustHelperFunc = [9]func(*File, *xlsxWorksheet, string, adjustDirection, i
 //
 // TODO: adjustComments, adjustPageBreaks, adjustProtectedCells
 func (f *File) adjustHelper(sheet string, dir adjustDirection, num, offset int) error {
+	f.invalidateAdjustCalcCache(sheet, dir, num)
 	ws, err := f.workSheetReader(sheet)
 	if err != nil {
 		return err
 	}
-	sheetID := f.getSheetID(sheet)
+	sheetID, tableNames := f.getSheetID(sheet), f.getTableNames()
 	if dir == rows {
-		err = f.adjustRowDimensions(sheet, ws, num, offset)
+		err = f.adjustRowDimensions(sheet, ws, num, offset, tableNames)
 	} else {
-		err = f.adjustColDimensions(sheet, ws, num, offset)
+		err = f.adjustColDimensions(sheet, ws, num, offset, tableNames)
 	}
 	if err != nil {
 		return err
 	}
-	f.adjustHyperlinks(ws, sheet, dir, num, offset)
+	f.adjustHyperlinks(ws, sheet, dir, num, offset, tableNames)
 	ws.checkSheet()
 	_ = ws.checkRow()
 	for _, fn := range adjustHelperFunc {
-		if err := fn(f, ws, sheet, dir, num, offset, sheetID); err != nil {
+		if err := fn(f, ws, sheet, dir, num, offset, sheetID, tableNames); err != nil {
 			return err
 		}
 	}
@@ -140,7 +141,7 @@ func (f *File) adjustCols(ws *xlsxWorksheet, col, offset int) error {
 
 // adjustColDimensions provides a function to update column dimensions when
 // inserting or deleting rows or columns.
-func (f *File) adjustColDimensions(sheet string, ws *xlsxWorksheet, col, offset int) error {
+func (f *File) adjustColDimensions(sheet string, ws *xlsxWorksheet, col, offset int, tableNames map[string]bool) error {
 	for rowIdx := range ws.SheetData.Row {
 		for _, v := range ws.SheetData.Row[rowIdx].C {
 			if cellCol, _, _ := CellNameToCoordinates(v.R); col <= cellCol {
@@ -165,7 +166,7 @@ func (f *File) adjustColDimensions(sheet string, ws *xlsxWorksheet, col, offset
 						worksheet.SheetData.Row[rowIdx].C[colIdx].R, _ = CoordinatesToCellName(newCol, cellRow)
 					}
 				}
-				if err := f.adjustFormula(sheet, sheetN, &worksheet.SheetData.Row[rowIdx].C[colIdx], columns, col, offset, false); err != nil {
+				if err := f.adjustFormula(sheet, sheetN, &worksheet.SheetData.Row[rowIdx].C[colIdx], columns, col, offset, false, tableNames); err != nil {
 					return err
 				}
 			}
@@ -176,7 +177,7 @@ func (f *File) adjustColDimensions(sheet string, ws *xlsxWorksheet, col, offset
 
 // adjustRowDimensions provides a function to update row dimensions when
 // inserting or deleting rows or columns.
-func (f *File) adjustRowDimensions(sheet string, ws *xlsxWorksheet, row, offset int) error {
+func (f *File) adjustRowDimensions(sheet string, ws *xlsxWorksheet, row, offset int, tableNames map[string]bool) error {
 	for _, sheetN := range f.GetSheetList() {
 		if sheetN == sheet {
 			continue
@@ -191,7 +192,7 @@ func (f *File) adjustRowDimensions(sheet string, ws *xlsxWorksheet, row, offset
 		numOfRows := len(worksheet.SheetData.Row)
 		for i := 0; i < numOfRows; i++ {
 			r := &worksheet.SheetData.Row[i]
-			if err = f.adjustSingleRowFormulas(sheet, sheetN, r, row, offset, false); err != nil {
+			if err = f.adjustSingleRowFormulas(sheet, sheetN, r, row, offset, false, tableNames); err != nil {
 				return err
 			}
 		}
@@ -210,7 +211,7 @@ func (f *File) adjustRowDimensions(sheet string, ws *xlsxWorksheet, row, offset
 		if newRow := r.R + offset; r.R >= row && newRow > 0 {
 			r.adjustSingleRowDimensions(offset)
 		}
-		if err := f.adjustSingleRowFormulas(sheet, sheet, r, row, offset, false); err != nil {
+		if err := f.adjustSingleRowFormulas(sheet, sheet, r, row, offset, false, tableNames); err != nil {
 			return err
 		}
 	}
@@ -227,9 +228,9 @@ func (r *xlsxRow) adjustSingleRowDimensions(offset int) {
 }
 
 // adjustSingleRowFormulas provides a function to adjust single row formulas.
-func (f *File) adjustSingleRowFormulas(sheet, sheetN string, r *xlsxRow, num, offset int, si bool) error {
+func (f *File) adjustSingleRowFormulas(sheet, sheetN string, r *xlsxRow, num, offset int, si bool, tableNames map[string]bool) error {
 	for i := 0; i < len(r.C); i++ {
-		if err := f.adjustFormula(sheet, sheetN, &r.C[i], rows, num, offset, si); err != nil {
+		if err := f.adjustFormula(sheet, sheetN, &r.C[i], rows, num, offset, si, tableNames); err != nil {
 			return err
 		}
 	}
@@ -279,10 +280,10 @@ func (f *File) adjustCellRef(cellRef string, dir adjustDirection, num, offset in
 
 // adjustFormula provides a function to adjust formula reference and shared
 // formula reference.
-func (f *File) adjustFormula(sheet, sheetN string, cell *xlsxC, dir adjustDirection, num, offset int, si bool) error {
+func (f *File) adjustFormula(sheet, sheetN string, cell *xlsxC, dir adjustDirection, num, offset int, si bool, tableNames map[string]bool) error {
 	var err error
 	if cell.f != "" {
-		if cell.f, err = f.adjustFormulaRef(sheet, sheetN, cell.f, false, dir, num, offset); err != nil {
+		if cell.f, err = f.adjustFormulaRef(sheet, sheetN, cell.f, false, dir, num, offset, tableNames); err != nil {
 			return err
 		}
 	}
@@ -298,7 +299,7 @@ func (f *File) adjustFormula(sheet, sheetN string, cell *xlsxC, dir adjustDirect
 		}
 	}
 	if cell.F.Content != "" {
-		if cell.F.Content, err = f.adjustFormulaRef(sheet, sheetN, cell.F.Content, false, dir, num, offset); err != nil {
+		if cell.F.Content, err = f.adjustFormulaRef(sheet, sheetN, cell.F.Content, false, dir, num, offset, tableNames); err != nil {
 			return err
 		}
 	}
@@ -415,7 +416,7 @@ func (f *File) adjustFormulaOperand(sheet, sheetN string, keepRelative bool, tok
 
 // adjustFormulaRef returns adjusted formula by giving adjusting direction and
 // the base number of column or row, and offset.
-func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool, dir adjustDirection, num, offset int) (string, error) {
+func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool, dir adjustDirection, num, offset int, tableNames map[string]bool) (string, error) {
 	var (
 		val          string
 		definedNames []string
@@ -440,8 +441,16 @@ func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool
 				val += token.TValue
 				continue
 			}
+			if !strings.ContainsAny(token.TValue, "!:0123456789") && tableNames[strings.ToLower(token.TValue)] {
+				val += token.TValue
+				continue
+			}
 			operand, err := f.adjustFormulaOperand(sheet, sheetN, keepRelative, token, dir, num, offset)
 			if err != nil {
+				if tableNames[strings.ToLower(token.TValue)] {
+					val += token.TValue
+					continue
+				}
 				return val, err
 			}
 			val += operand
@@ -460,6 +469,22 @@ func (f *File) adjustFormulaRef(sheet, sheetN, formula string, keepRelative bool
 	return val, nil
 }
 
+// getTableNames provides a function to get the lowercase names of all tables
+// in the workbook, the table name is case insensitive.
+func (f *File) getTableNames() map[string]bool {
+	tableNames := map[string]bool{}
+	for _, sheet := range f.GetSheetList() {
+		tables, err := f.GetTables(sheet)
+		if err != nil {
+			continue
+		}
+		for _, table := range tables {
+			tableNames[strings.ToLower(table.Name)] = true
+		}
+	}
+	return tableNames
+}
+
 // transformParenthesesToken returns formula part with parentheses by given
 // token.
 func transformParenthesesToken(token efp.Token) string {
@@ -606,7 +631,7 @@ func getArrayFormulaTokens(sheet, formula string, definedNames []DefinedName) ([
 
 // adjustHyperlinks provides a function to update hyperlinks when inserting or
 // deleting rows or columns.
-func (f *File) adjustHyperlinks(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int) {
+func (f *File) adjustHyperlinks(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset int, tableNames map[string]bool) {
 	// short path
 	if ws.Hyperlinks == nil || len(ws.Hyperlinks.Hyperlink) == 0 {
 		return
@@ -634,7 +659,7 @@ func (f *File) adjustHyperlinks(ws *xlsxWorksheet, sheet string, dir adjustDirec
 	}
 	for i := range ws.Hyperlinks.Hyperlink {
 		link := &ws.Hyperlinks.Hyperlink[i] // get reference
-		link.Ref, _ = f.adjustFormulaRef(sheet, sheet, link.Ref, false, dir, num, offset)
+		link.Ref, _ = f.adjustFormulaRef(sheet, sheet, link.Ref, false, dir, num, offset, tableNames)
 	}
 }
 
@@ -644,6 +669,7 @@ func (f *File) adjustTable(ws *xlsxWorksheet, sheet string, dir adjustDirection,
 	if ws.TableParts == nil || len(ws.TableParts.TableParts) == 0 {
 		return nil
 	}
+	removed := make(map[string][]string)
 	for idx := 0; idx < len(ws.TableParts.TableParts); idx++ {
 		tbl := ws.TableParts.TableParts[idx]
 		target := f.getSheetRelationshipsTargetByID(sheet, tbl.RID)
@@ -661,18 +687,27 @@ func (f *File) adjustTable(ws *xlsxWorksheet, sheet string, dir adjustDirection,
 		if err != nil {
 			return err
 		}
-		// Remove the table when deleting the header row of the table
-		if dir == rows && num == coordinates[0] && offset == -1 {
+		showHeaderRow := t.HeaderRowCount == nil || *t.HeaderRowCount != 0
+		// Remove the table when deleting the header row of the table, the only
+		// data row of the table without header row, or the only column
+		if offset == -1 && (dir == rows && num == coordinates[1] && (showHeaderRow || coordinates[1] == coordinates[3]) ||
+			dir == columns && num == coordinates[0] && coordinates[0] == coordinates[2]) {
 			ws.TableParts.TableParts = append(ws.TableParts.TableParts[:idx], ws.TableParts.TableParts[idx+1:]...)
 			ws.TableParts.Count = len(ws.TableParts.TableParts)
+			removed[strings.ToLower(t.Name)] = nil
 			idx--
 			continue
 		}
+		colIdx := num - coordinates[0]
+		if dir == columns && offset < 0 && t.TableColumns != nil && colIdx >= 0 && colIdx < len(t.TableColumns.TableColumn) {
+			removed[strings.ToLower(t.Name)] = []string{t.TableColumns.TableColumn[colIdx].Name}
+		}
 		coordinates = f.adjustAutoFilterHelper(dir, coordinates, num, offset)
 		x1, y1, x2, y2 := coordinates[0], coordinates[1], coordinates[2], coordinates[3]
-		if y2-y1 < 1 || x2-x1 < 0 {
+		if (showHeaderRow && y2-y1 < 1) || x2-x1 < 0 {
 			ws.TableParts.TableParts = append(ws.TableParts.TableParts[:idx], ws.TableParts.TableParts[idx+1:]...)
 			ws.TableParts.Count = len(ws.TableParts.TableParts)
+			removed[strings.ToLower(t.Name)] = nil
 			idx--
 			continue
 		}
@@ -680,15 +715,180 @@ func (f *File) adjustTable(ws *xlsxWorksheet, sheet string, dir adjustDirection,
 		if t.AutoFilter != nil {
 			t.AutoFilter.Ref = t.Ref
 		}
-		_ = f.setTableColumns(sheet, true, x1, y1, x2, &t)
+		if showHeaderRow {
+			_ = f.setTableColumns(sheet, true, x1, y1, x2, &t)
+		} else if dir == columns {
+			t.adjustTableColumns(colIdx, offset)
+		}
 		// Currently doesn't support query table
 		t.TableType, t.TotalsRowCount, t.ConnectionID = "", 0, 0
 		table, _ := xml.Marshal(t)
 		f.saveFileList(tableXML, table)
 	}
+	if len(removed) == 0 {
+		return nil
+	}
+	return f.adjustStructuredReferences(removed)
+}
+
+// adjustTableColumns adjust the columns of the table without header row by
+// given index of the inserted or deleted column in the table, the column
+// names of the table without header row couldn't be read from the worksheet.
+func (t *xlsxTable) adjustTableColumns(idx, offset int) {
+	if t.TableColumns == nil || idx < 0 || idx > len(t.TableColumns.TableColumn) {
+		return
+	}
+	columns := t.TableColumns.TableColumn
+	if offset < 0 {
+		if idx < len(columns) {
+			columns = append(columns[:idx], columns[idx+1:]...)
+		}
+	} else if idx > 0 && idx < len(columns) {
+		var inserted []*xlsxTableColumn
+		for i, n := 0, idx+1; i < offset; i, n = i+1, n+1 {
+			for t.getTableColumn("Column"+strconv.Itoa(n)) != nil {
+				n++
+			}
+			inserted = append(inserted, &xlsxTableColumn{Name: "Column" + strconv.Itoa(n)})
+		}
+		columns = append(columns[:idx], append(inserted, columns[idx:]...)...)
+	}
+	for i, column := range columns {
+		column.ID = i + 1
+	}
+	t.TableColumns.Count, t.TableColumns.TableColumn = len(columns), columns
+}
+
+// getTableColumn returns the column of the table by given case-insensitive
+// column name.
+func (t *xlsxTable) getTableColumn(name string) *xlsxTableColumn {
+	if t.TableColumns != nil {
+		for _, column := range t.TableColumns.TableColumn {
+			if column != nil && strings.EqualFold(column.Name, name) {
+				return column
+			}
+		}
+	}
 	return nil
 }
 
+// adjustStructuredReferences provides a function to replace the table names
+// and the structured references in the formulas and defined names with the
+// #REF! error, which refer to the removed tables or the removed columns of
+// the tables. The keys of the removed map are the lowercase table names, and
+// the values are the removed column names, nil values indicate the entire
+// table was removed.
+func (f *File) adjustStructuredReferences(removed map[string][]string) error {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return err
+	}
+	for _, sheetN := range f.GetSheetList() {
+		ws, err := f.workSheetReader(sheetN)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheetN).Error() {
+				continue
+			}
+			return err
+		}
+		for rowIdx := range ws.SheetData.Row {
+			for colIdx := range ws.SheetData.Row[rowIdx].C {
+				c := &ws.SheetData.Row[rowIdx].C[colIdx]
+				c.f = f.adjustStructuredReferenceFormula(sheetN, c.R, c.f, removed)
+				if c.F != nil {
+					c.F.Content = f.adjustStructuredReferenceFormula(sheetN, c.R, c.F.Content, removed)
+				}
+			}
+		}
+	}
+	if wb.DefinedNames != nil {
+		for i := 0; i < len(wb.DefinedNames.DefinedName); i++ {
+			definedName := &wb.DefinedNames.DefinedName[i]
+			definedName.Data = f.adjustStructuredReferenceFormula("", "", definedName.Data, removed)
+		}
+	}
+	return nil
+}
+
+// adjustStructuredReferenceFormula returns the formula which the table names
+// and the structured references to the removed tables or columns have been
+// replaced with the #REF! error. The formula will be returned as is if there
+// are no such structured references in it.
+func (f *File) adjustStructuredReferenceFormula(sheet, cell, formula string, removed map[string][]string) string {
+	var (
+		val      string
+		adjusted bool
+		ps       = efp.ExcelParser()
+	)
+	for _, token := range mergeStructuredReferenceTokens(ps.Parse(formula)) {
+		if token.TType == efp.TokenTypeUnknown {
+			return formula
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange {
+			if columns, ok := removed[strings.ToLower(token.TValue)]; ok && columns == nil ||
+				isStructuredReference(token.TValue) && f.isRemovedStructuredReference(sheet, cell, token.TValue, removed) {
+				val, adjusted = val+formulaErrorREF, true
+				continue
+			}
+			if idx := strings.LastIndex(token.TValue, "!"); idx != -1 {
+				val += escapeSheetName(token.TValue[:idx]) + token.TValue[idx:]
+				continue
+			}
+			val += token.TValue
+			continue
+		}
+		if paren := transformParenthesesToken(token); paren != "" {
+			val += paren
+			continue
+		}
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeText {
+			val += string(efp.QuoteDouble) + strings.ReplaceAll(token.TValue, "\"", "\"\"") + string(efp.QuoteDouble)
+			continue
+		}
+		val += token.TValue
+	}
+	if !adjusted {
+		return formula
+	}
+	return val
+}
+
+// isRemovedStructuredReference returns if the structured reference refers to
+// the removed table or the removed columns of the table. The structured
+// reference without the table name refers to the table which contains the
+// formula cell.
+func (f *File) isRemovedStructuredReference(sheet, cell, reference string, removed map[string][]string) bool {
+	idx := strings.Index(reference, "[")
+	name := reference[:idx]
+	if name == "" {
+		if sheet == "" {
+			return false
+		}
+		tbl, err := f.getTableByCell(sheet, cell)
+		if err != nil || tbl == nil {
+			return false
+		}
+		name = tbl.Name
+	}
+	columns, ok := removed[strings.ToLower(name)]
+	if !ok {
+		return false
+	}
+	if columns == nil {
+		return true
+	}
+	items, err := splitStructuredReferenceItems(reference[idx+1 : len(reference)-1])
+	if err != nil {
+		return false
+	}
+	for _, item := range items {
+		if inStrSlice(columns, item, false) != -1 {
+			return true
+		}
+	}
+	return false
+}
+
 // adjustAutoFilter provides a function to update the auto filter when
 // inserting or deleting rows or columns.
 func (f *File) adjustAutoFilter(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
@@ -959,7 +1159,7 @@ func (f *File) adjustConditionalFormats(ws *xlsxWorksheet, sheet string, dir adj
 
 // adjustDataValidations updates the range of data validations for the worksheet
 // when inserting or deleting rows or columns.
-func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 	for _, sheetN := range f.GetSheetList() {
 		worksheet, err := f.workSheetReader(sheetN)
 		if err != nil {
@@ -991,14 +1191,14 @@ func (f *File) adjustDataValidations(ws *xlsxWorksheet, sheet string, dir adjust
 			}
 			if worksheet.DataValidations.DataValidation[i].Formula1.isFormula() {
 				formula := formulaUnescaper.Replace(worksheet.DataValidations.DataValidation[i].Formula1.Content)
-				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, dir, num, offset); err != nil {
+				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, dir, num, offset, tableNames); err != nil {
 					return err
 				}
 				worksheet.DataValidations.DataValidation[i].Formula1 = &xlsxInnerXML{Content: formulaEscaper.Replace(formula)}
 			}
 			if worksheet.DataValidations.DataValidation[i].Formula2.isFormula() {
 				formula := formulaUnescaper.Replace(worksheet.DataValidations.DataValidation[i].Formula2.Content)
-				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, dir, num, offset); err != nil {
+				if formula, err = f.adjustFormulaRef(sheet, sheetN, formula, false, dir, num, offset, tableNames); err != nil {
 					return err
 				}
 				worksheet.DataValidations.DataValidation[i].Formula2 = &xlsxInnerXML{Content: formulaEscaper.Replace(formula)}
@@ -1133,7 +1333,7 @@ func (f *File) adjustDrawings(ws *xlsxWorksheet, sheet string, dir adjustDirecti
 
 // adjustDefinedNames updates the cell reference of the defined names when
 // inserting or deleting rows or columns.
-func (f *File) adjustDefinedNames(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int) error {
+func (f *File) adjustDefinedNames(ws *xlsxWorksheet, sheet string, dir adjustDirection, num, offset, sheetID int, tableNames map[string]bool) error {
 	wb, err := f.workbookReader()
 	if err != nil {
 		return err
@@ -1141,7 +1341,7 @@ func (f *File) adjustDefinedNames(ws *xlsxWorksheet, sheet string, dir adjustDir
 	if wb.DefinedNames != nil {
 		for i := 0; i < len(wb.DefinedNames.DefinedName); i++ {
 			data := wb.DefinedNames.DefinedName[i].Data
-			if data, err = f.adjustFormulaRef(sheet, "", data, true, dir, num, offset); err == nil {
+			if data, err = f.adjustFormulaRef(sheet, "", data, true, dir, num, offset, tableNames); err == nil {
 				wb.DefinedNames.DefinedName[i].Data = data
 			}
 		}
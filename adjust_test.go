@@ -341,6 +341,81 @@ func TestAdjustTable(t *testing.T) {
 	assert.Equal(t, ErrParameterInvalid, f.RemoveRow(sheetName, 1))
 }
 
+func TestAdjustStructuredReferences(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Item", "Unit Price", "Qty", "Amount"},
+		{"Apple", 2, 3},
+		{"Banana", 4, 5},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:D3", Name: "Sales"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "H2", &[]interface{}{1, 2}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "H2:I3", Name: "Rates", ShowHeaderRow: boolPtr(false)}))
+	for cell, formula := range map[string]string{
+		"D2": "[@[Unit Price]]*[@Qty]",
+		"D3": "[@Qty]&\"pcs\"",
+		"F1": "SUM(Sales[Qty])+SUM(Sales[Unit Price])",
+		"F2": "SUM(Sales)+'Sheet1'!A1",
+		"F3": "SUM(Rates[Column2])",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Quantity", RefersTo: "Sales[[#Data],[Qty]]"}))
+	// Test insert column in the table without header row
+	assert.NoError(t, f.InsertCols("Sheet1", "I", 1))
+	tables, err := f.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, "H3:J3", tables[1].Range)
+	tbl, err := f.getTableXML(tables[1].tableXML)
+	assert.NoError(t, err)
+	assert.Equal(t, []*xlsxTableColumn{{ID: 1, Name: "Column1"}, {ID: 2, Name: "Column3"}, {ID: 3, Name: "Column2"}}, tbl.TableColumns.TableColumn)
+	// Test remove column in the table without header row
+	assert.NoError(t, f.RemoveCol("Sheet1", "J"))
+	formula, err := f.GetCellFormula("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "SUM(#REF!)", formula)
+	// Test remove column in the table
+	assert.NoError(t, f.RemoveCol("Sheet1", "C"))
+	for cell, expected := range map[string]string{
+		"C2": "[@[Unit Price]]*#REF!",
+		"C3": "#REF!&\"pcs\"",
+		"E1": "SUM(#REF!)+SUM(Sales[Unit Price])",
+		"E2": "SUM(Sales)+Sheet1!A1",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	definedNames := f.GetDefinedName()
+	assert.Equal(t, "#REF!", definedNames[0].RefersTo)
+	// Test get the table names in lowercase
+	assert.Equal(t, map[string]bool{"rates": true, "sales": true}, f.getTableNames())
+	// Test remove the header row of the table
+	assert.NoError(t, f.RemoveRow("Sheet1", 1))
+	for cell, expected := range map[string]string{
+		"E1": "SUM(#REF!)+Sheet1!A1",
+		"E2": "SUM(#REF!)",
+	} {
+		formula, err := f.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	// Test adjust structured references with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.adjustStructuredReferences(map[string][]string{"sales": nil}), "XML syntax error on line 1: invalid UTF-8")
+	// Test adjust structured references with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.adjustStructuredReferences(map[string][]string{"sales": nil}), "XML syntax error on line 1: invalid UTF-8")
+	// Test adjust formula with invalid token and unknown table
+	assert.Equal(t, "SUM(Sales[Qty]", f.adjustStructuredReferenceFormula("Sheet1", "A1", "SUM(Sales[Qty]", nil))
+	assert.False(t, f.isRemovedStructuredReference("", "", "[@Qty]", nil))
+}
+
 func TestAdjustHelper(t *testing.T) {
 	f := NewFile()
 	_, err := f.NewSheet("Sheet2")
@@ -479,13 +554,13 @@ func TestAdjustColDimensions(t *testing.T) {
 	ws, err := f.workSheetReader("Sheet1")
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "A1+B1"))
-	assert.Equal(t, ErrColumnNumber, f.adjustColDimensions("Sheet1", ws, 1, MaxColumns))
+	assert.Equal(t, ErrColumnNumber, f.adjustColDimensions("Sheet1", ws, 1, MaxColumns, nil))
 
 	_, err = f.NewSheet("Sheet2")
 	assert.NoError(t, err)
 	f.Sheet.Delete("xl/worksheets/sheet2.xml")
 	f.Pkg.Store("xl/worksheets/sheet2.xml", MacintoshCyrillicCharset)
-	assert.EqualError(t, f.adjustColDimensions("Sheet2", ws, 2, 1), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.adjustColDimensions("Sheet2", ws, 2, 1, nil), "XML syntax error on line 1: invalid UTF-8")
 }
 
 func TestAdjustRowDimensions(t *testing.T) {
@@ -493,13 +568,13 @@ func TestAdjustRowDimensions(t *testing.T) {
 	ws, err := f.workSheetReader("Sheet1")
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "A1+B1"))
-	assert.Equal(t, ErrMaxRows, f.adjustRowDimensions("Sheet1", ws, 1, TotalRows))
+	assert.Equal(t, ErrMaxRows, f.adjustRowDimensions("Sheet1", ws, 1, TotalRows, nil))
 
 	_, err = f.NewSheet("Sheet2")
 	assert.NoError(t, err)
 	f.Sheet.Delete("xl/worksheets/sheet2.xml")
 	f.Pkg.Store("xl/worksheets/sheet2.xml", MacintoshCyrillicCharset)
-	assert.EqualError(t, f.adjustRowDimensions("Sheet1", ws, 2, 1), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.adjustRowDimensions("Sheet1", ws, 2, 1, nil), "XML syntax error on line 1: invalid UTF-8")
 
 	f = NewFile()
 	_, err = f.NewSheet("Sheet2")
@@ -507,7 +582,7 @@ func TestAdjustRowDimensions(t *testing.T) {
 	ws, err = f.workSheetReader("Sheet1")
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetCellFormula("Sheet1", "B2", fmt.Sprintf("Sheet2!A%d", TotalRows)))
-	assert.Equal(t, ErrMaxRows, f.adjustRowDimensions("Sheet2", ws, 1, TotalRows))
+	assert.Equal(t, ErrMaxRows, f.adjustRowDimensions("Sheet2", ws, 1, TotalRows, nil))
 }
 
 func TestAdjustHyperlinks(t *testing.T) {
@@ -515,7 +590,7 @@ func TestAdjustHyperlinks(t *testing.T) {
 	ws, err := f.workSheetReader("Sheet1")
 	assert.NoError(t, err)
 	assert.NoError(t, f.SetCellFormula("Sheet1", "C3", "A1+B1"))
-	f.adjustHyperlinks(ws, "Sheet1", rows, 3, -1)
+	f.adjustHyperlinks(ws, "Sheet1", rows, 3, -1, nil)
 
 	// Test adjust hyperlinks location with positive offset
 	assert.NoError(t, f.SetCellHyperLink("Sheet1", "F5", "Sheet1!A1", "Location"))
@@ -566,13 +641,13 @@ func TestAdjustFormula(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestAdjustFormula.xlsx")))
 	assert.NoError(t, f.Close())
 
-	assert.NoError(t, f.adjustFormula("Sheet1", "Sheet1", &xlsxC{}, rows, 0, 0, false))
-	assert.Equal(t, newCellNameToCoordinatesError("-", newInvalidCellNameError("-")), f.adjustFormula("Sheet1", "Sheet1", &xlsxC{F: &xlsxF{Ref: "-"}}, rows, 0, 0, false))
-	assert.Equal(t, ErrColumnNumber, f.adjustFormula("Sheet1", "Sheet1", &xlsxC{F: &xlsxF{Ref: "XFD1:XFD1"}}, columns, 0, 1, false))
+	assert.NoError(t, f.adjustFormula("Sheet1", "Sheet1", &xlsxC{}, rows, 0, 0, false, nil))
+	assert.Equal(t, newCellNameToCoordinatesError("-", newInvalidCellNameError("-")), f.adjustFormula("Sheet1", "Sheet1", &xlsxC{F: &xlsxF{Ref: "-"}}, rows, 0, 0, false, nil))
+	assert.Equal(t, ErrColumnNumber, f.adjustFormula("Sheet1", "Sheet1", &xlsxC{F: &xlsxF{Ref: "XFD1:XFD1"}}, columns, 0, 1, false, nil))
 
-	_, err := f.adjustFormulaRef("Sheet1", "Sheet1", "XFE1", false, columns, 0, 1)
+	_, err := f.adjustFormulaRef("Sheet1", "Sheet1", "XFE1", false, columns, 0, 1, nil)
 	assert.Equal(t, ErrColumnNumber, err)
-	_, err = f.adjustFormulaRef("Sheet1", "Sheet1", "XFD1", false, columns, 0, 1)
+	_, err = f.adjustFormulaRef("Sheet1", "Sheet1", "XFD1", false, columns, 0, 1, nil)
 	assert.Equal(t, ErrColumnNumber, err)
 
 	f = NewFile()
@@ -1099,7 +1174,7 @@ func TestAdjustDataValidations(t *testing.T) {
 
 	f.Sheet.Delete("xl/worksheets/sheet1.xml")
 	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
-	assert.EqualError(t, f.adjustDataValidations(nil, "Sheet1", columns, 0, 0, 1), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.adjustDataValidations(nil, "Sheet1", columns, 0, 0, 1, nil), "XML syntax error on line 1: invalid UTF-8")
 
 	t.Run("for_escaped_data_validation_rules_formula", func(t *testing.T) {
 		f := NewFile()
@@ -1260,5 +1335,5 @@ func TestAdjustDefinedNames(t *testing.T) {
 	f = NewFile()
 	f.WorkBook = nil
 	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
-	assert.EqualError(t, f.adjustDefinedNames(nil, "Sheet1", columns, 0, 0, 1), "XML syntax error on line 1: invalid UTF-8")
+	assert.EqualError(t, f.adjustDefinedNames(nil, "Sheet1", columns, 0, 0, 1, nil), "XML syntax error on line 1: invalid UTF-8")
 }
@@ -14,6 +14,7 @@ package excelize
 import (
 	"bytes"
 	"container/list"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"math"
@@ -21,8 +22,10 @@ import (
 	"math/cmplx"
 	"math/rand"
 	"net/url"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -220,6 +223,32 @@ type calcContext struct {
 	maxCalcIterations uint
 	iterations        map[string]uint
 	iterationsCache   map[string]formulaArg
+	spillCache        map[string]formulaArg
+	arrayFormulas     map[string][]arrayFormulaRange
+	valueCache        map[string]formulaArg
+	calcCache         map[string]*calcCacheEntry
+	circular          bool
+	scope             *calcScope
+	lambdaDepth       int
+	definedNames      map[string]bool
+	trace             bool
+	traceCell         string
+	steps             []FormulaStep
+}
+
+// calcScope defines the scope of the local names which defined by the LET and
+// LAMBDA formula functions.
+type calcScope struct {
+	names  map[string]formulaArg
+	parent *calcScope
+}
+
+// calcLambda defines the closure which created by the LAMBDA formula
+// function.
+type calcLambda struct {
+	params []string
+	body   []efp.Token
+	scope  *calcScope
 }
 
 // cellRef defines the structure of a cell reference.
@@ -266,6 +295,55 @@ type formulaArg struct {
 	Error                string
 	Type                 ArgType
 	cellRefs, cellRanges *list.List
+	lambda               *calcLambda
+}
+
+// FormulaArg is the argument and the result of the user-defined formula
+// function which registered by the RegisterFormulaFunc function. The Type
+// field specifies the data type of the argument. The boolean value is a number
+// type argument with the Boolean field set to true and the Number field set to
+// 1 or 0. The error value is an error type argument with the Error field set to
+// the error type, such as #N/A. The range reference argument is a matrix type
+// argument.
+type FormulaArg struct {
+	Type    ArgType
+	Number  float64
+	String  string
+	Boolean bool
+	Error   string
+	Matrix  [][]FormulaArg
+}
+
+// FormulaStepType is the type of the formula evaluation step.
+type FormulaStepType byte
+
+// Formula evaluation step types enumeration.
+const (
+	FormulaStepOperand FormulaStepType = iota
+	FormulaStepOperator
+	FormulaStepFunction
+)
+
+// FormulaStep directly maps the intermediate evaluation step of the formula,
+// which returned by the EvaluateFormulaSteps function. The Cell field
+// specifies the formula cell which the step belongs to, such as Sheet1!A1.
+// The Token field specifies the operand reference, the operator or the
+// formula function name of the step. The Operands field specifies the
+// resolved operands of the operator or the arguments of the formula
+// function, and the Result field specifies the evaluated result of the step.
+type FormulaStep struct {
+	Cell     string
+	Type     FormulaStepType
+	Token    string
+	Operands []FormulaArg
+	Result   FormulaArg
+}
+
+// userFormulaFunc defines the user-defined formula function.
+type userFormulaFunc struct {
+	name             string
+	minArgs, maxArgs int
+	fn               func(args []FormulaArg) FormulaArg
 }
 
 // Value returns a string data type of the formula argument.
@@ -355,8 +433,14 @@ type formulaFuncs struct {
 
 // CalcCellValue provides a function to get calculated cell value. This feature
 // is currently in working processing. Iterative calculation, implicit
-// intersection, explicit intersection, array formula, table formula and some
-// other formulas are not supported currently.
+// intersection, explicit intersection, array formula and some other formulas
+// are not supported currently. The dynamic array formula which returns an
+// array, such as FILTER, SORT or SEQUENCE, will be spilled to the cells in the
+// reference range of the array formula, and each cell in that range will get
+// the corresponding element of the result array. The structured references to
+// the tables are supported, such as Table1[Column1], Table1[[#Headers],
+// [Column1]], Table1[#Totals] and [@Column1], the structured reference
+// without the table name refers to the table which contains the formula cell.
 //
 // Supported formula functions:
 //
@@ -405,9 +489,12 @@ type formulaFuncs struct {
 //	BITOR
 //	BITRSHIFT
 //	BITXOR
+//	BYCOL
+//	BYROW
 //	CEILING
 //	CEILING.MATH
 //	CEILING.PRECISE
+//	CELL
 //	CHAR
 //	CHIDIST
 //	CHIINV
@@ -418,6 +505,8 @@ type formulaFuncs struct {
 //	CHISQ.TEST
 //	CHITEST
 //	CHOOSE
+//	CHOOSECOLS
+//	CHOOSEROWS
 //	CLEAN
 //	CODE
 //	COLUMN
@@ -481,6 +570,7 @@ type formulaFuncs struct {
 //	DOLLARDE
 //	DOLLARFR
 //	DPRODUCT
+//	DROP
 //	DSTDEV
 //	DSTDEVP
 //	DSUM
@@ -500,6 +590,7 @@ type formulaFuncs struct {
 //	EVEN
 //	EXACT
 //	EXP
+//	EXPAND
 //	EXPON.DIST
 //	EXPONDIST
 //	F.DIST
@@ -511,6 +602,7 @@ type formulaFuncs struct {
 //	FACTDOUBLE
 //	FALSE
 //	FDIST
+//	FILTER
 //	FIND
 //	FINDB
 //	FINV
@@ -545,6 +637,7 @@ type formulaFuncs struct {
 //	HEX2OCT
 //	HLOOKUP
 //	HOUR
+//	HSTACK
 //	HYPERLINK
 //	HYPGEOM.DIST
 //	HYPGEOMDIST
@@ -579,6 +672,7 @@ type formulaFuncs struct {
 //	IMTAN
 //	INDEX
 //	INDIRECT
+//	INFO
 //	INT
 //	INTERCEPT
 //	INTRATE
@@ -600,12 +694,14 @@ type formulaFuncs struct {
 //	ISREF
 //	ISTEXT
 //	KURT
+//	LAMBDA
 //	LARGE
 //	LCM
 //	LEFT
 //	LEFTB
 //	LEN
 //	LENB
+//	LET
 //	LN
 //	LOG
 //	LOG10
@@ -615,6 +711,8 @@ type formulaFuncs struct {
 //	LOGNORMDIST
 //	LOOKUP
 //	LOWER
+//	MAKEARRAY
+//	MAP
 //	MATCH
 //	MAX
 //	MAXA
@@ -658,6 +756,7 @@ type formulaFuncs struct {
 //	NOW
 //	NPER
 //	NPV
+//	NUMBERVALUE
 //	OCT2BIN
 //	OCT2DEC
 //	OCT2HEX
@@ -666,6 +765,7 @@ type formulaFuncs struct {
 //	ODDFYIELD
 //	ODDLPRICE
 //	ODDLYIELD
+//	OFFSET
 //	OR
 //	PDURATION
 //	PEARSON
@@ -697,11 +797,13 @@ type formulaFuncs struct {
 //	QUOTIENT
 //	RADIANS
 //	RAND
+//	RANDARRAY
 //	RANDBETWEEN
 //	RANK
 //	RANK.EQ
 //	RATE
 //	RECEIVED
+//	REDUCE
 //	REPLACE
 //	REPLACEB
 //	REPT
@@ -715,11 +817,13 @@ type formulaFuncs struct {
 //	ROWS
 //	RRI
 //	RSQ
+//	SCAN
 //	SEARCH
 //	SEARCHB
 //	SEC
 //	SECH
 //	SECOND
+//	SEQUENCE
 //	SERIESSUM
 //	SHEET
 //	SHEETS
@@ -731,6 +835,8 @@ type formulaFuncs struct {
 //	SLN
 //	SLOPE
 //	SMALL
+//	SORT
+//	SORTBY
 //	SQRT
 //	SQRTPI
 //	STANDARDIZE
@@ -760,6 +866,7 @@ type formulaFuncs struct {
 //	T.INV
 //	T.INV.2T
 //	T.TEST
+//	TAKE
 //	TAN
 //	TANH
 //	TBILLEQ
@@ -770,10 +877,13 @@ type formulaFuncs struct {
 //	TEXTAFTER
 //	TEXTBEFORE
 //	TEXTJOIN
+//	TEXTSPLIT
 //	TIME
 //	TIMEVALUE
 //	TINV
+//	TOCOL
 //	TODAY
+//	TOROW
 //	TRANSPOSE
 //	TREND
 //	TRIM
@@ -784,6 +894,7 @@ type formulaFuncs struct {
 //	TYPE
 //	UNICHAR
 //	UNICODE
+//	UNIQUE
 //	UPPER
 //	VALUE
 //	VALUETOTEXT
@@ -795,14 +906,18 @@ type formulaFuncs struct {
 //	VARPA
 //	VDB
 //	VLOOKUP
+//	VSTACK
 //	WEEKDAY
 //	WEEKNUM
 //	WEIBULL
 //	WEIBULL.DIST
 //	WORKDAY
 //	WORKDAY.INTL
+//	WRAPCOLS
+//	WRAPROWS
 //	XIRR
 //	XLOOKUP
+//	XMATCH
 //	XNPV
 //	XOR
 //	YEAR
@@ -819,12 +934,15 @@ func (f *File) CalcCellValue(sheet, cell string, opts ...Options) (result string
 		styleIdx     int
 		token        formulaArg
 	)
-	if token, err = f.calcCellValue(&calcContext{
+	ctx := &calcContext{
 		entry:             fmt.Sprintf("%s!%s", sheet, cell),
 		maxCalcIterations: options.MaxCalcIterations,
 		iterations:        make(map[string]uint),
 		iterationsCache:   make(map[string]formulaArg),
-	}, sheet, cell); err != nil {
+	}
+	token, err = f.calcCellValue(ctx, sheet, cell)
+	f.storeCalcCache(ctx)
+	if err != nil {
 		result = token.String
 		return
 	}
@@ -846,22 +964,379 @@ func (f *File) CalcCellValue(sheet, cell string, opts ...Options) (result string
 	return
 }
 
+// EvaluateFormulaSteps provides a function to evaluate the formula of the cell
+// step by step, and returns the sequence of the intermediate evaluation steps,
+// similar to the "Evaluate Formula" dialog in Excel. Each resolved operand
+// reference, calculated operator and invoked formula function will be
+// recorded as a step in the evaluation order, the steps of the referenced
+// formula cells will be included before the step which references them. The
+// error of the formula evaluation will be returned with the recorded steps.
+// For example, evaluate the formula steps of the cell A3 on Sheet1:
+//
+//	steps, err := f.EvaluateFormulaSteps("Sheet1", "A3")
+//	for _, step := range steps {
+//	    fmt.Println(step.Cell, step.Token, step.Operands, step.Result)
+//	}
+func (f *File) EvaluateFormulaSteps(sheet, cell string, opts ...Options) ([]FormulaStep, error) {
+	options := f.getOptions(opts...)
+	ctx := &calcContext{
+		entry:             fmt.Sprintf("%s!%s", sheet, cell),
+		maxCalcIterations: options.MaxCalcIterations,
+		iterations:        make(map[string]uint),
+		iterationsCache:   make(map[string]formulaArg),
+		trace:             true,
+	}
+	_, err := f.calcCellValue(ctx, sheet, cell)
+	f.storeCalcCache(ctx)
+	return ctx.steps, err
+}
+
+// addFormulaStep append the evaluation step to the calculation context when
+// tracing the formula evaluation.
+func (ctx *calcContext) addFormulaStep(typ FormulaStepType, token string, operands []formulaArg, result formulaArg) {
+	if ctx == nil || !ctx.trace {
+		return
+	}
+	step := FormulaStep{Cell: ctx.traceCell, Type: typ, Token: token, Result: exportFormulaArg(result)}
+	for _, operand := range operands {
+		step.Operands = append(step.Operands, exportFormulaArg(operand))
+	}
+	ctx.mu.Lock()
+	ctx.steps = append(ctx.steps, step)
+	ctx.mu.Unlock()
+}
+
+// calculate evaluate the operator with the operands in the stack, and record
+// the evaluation step when tracing the formula evaluation.
+func (ctx *calcContext) calculate(opdStack *Stack, opt efp.Token) error {
+	if ctx == nil || !ctx.trace {
+		return calculate(opdStack, opt)
+	}
+	var operands []formulaArg
+	count := 2
+	if opt.TType == efp.TokenTypeOperatorPrefix {
+		count = 1
+	}
+	for e := opdStack.list.Back(); e != nil && len(operands) < count; e = e.Prev() {
+		operands = append([]formulaArg{e.Value.(formulaArg)}, operands...)
+	}
+	if err := calculate(opdStack, opt); err != nil {
+		ctx.addFormulaStep(FormulaStepOperator, opt.TValue, operands, newErrorFormulaArg(err.Error(), err.Error()))
+		return err
+	}
+	if result, ok := opdStack.Peek().(formulaArg); ok {
+		ctx.addFormulaStep(FormulaStepOperator, opt.TValue, operands, result)
+	}
+	return nil
+}
+
 // calcCellValue calculate cell value by given context, worksheet name and cell
 // reference.
 func (f *File) calcCellValue(ctx *calcContext, sheet, cell string) (result formulaArg, err error) {
+	if entry, ok := f.loadCalcCache(sheet, cell); ok && !ctx.trace {
+		return entry.result, entry.err
+	}
+	if ctx.trace {
+		traceCell := ctx.traceCell
+		ctx.traceCell = fmt.Sprintf("%s!%s", sheet, cell)
+		defer func() { ctx.traceCell = traceCell }()
+	}
 	var formula string
 	if formula, err = f.getCellFormula(sheet, cell, true); err != nil {
 		return
 	}
+	if formula != "" {
+		defer func() { f.addCalcCache(ctx, sheet, cell, formula, result, err) }()
+	}
+	// the local names are invisible in the formula of other cells
+	scope := ctx.scope
+	ctx.scope = nil
+	defer func() { ctx.scope = scope }()
+	if formula != "" {
+		if spill, ok := f.calcSpillValue(ctx, sheet, cell); ok {
+			return spill, err
+		}
+	}
 	ps := efp.ExcelParser()
 	tokens := ps.Parse(formula)
 	if tokens == nil {
 		return f.cellResolver(ctx, sheet, cell)
 	}
-	result, err = f.evalInfixExp(ctx, sheet, cell, tokens)
+	if result, err = f.evalInfixExp(ctx, sheet, cell, tokens); err == nil && result.lambda != nil {
+		result, err = newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC), errors.New(formulaErrorCALC)
+	}
 	return
 }
 
+// RecalculateAll provides a function to recalculate all formula cells in the
+// workbook, and store the calculated results as the cached values of the
+// cells, so that the spreadsheet applications which don't recalculate
+// formulas on open could display the correct values. The formula cells will
+// be calculated in the order of the calculation chain, and the formula cells
+// which not in the calculation chain will be calculated in the worksheets
+// order. Each formula will be evaluated only once, the calculated result of
+// the referenced formula cells will be reused. For example, recalculate the
+// workbook and save it:
+//
+//	if err := f.RecalculateAll(); err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	if err := f.SaveAs("Book1.xlsx"); err != nil {
+//	    fmt.Println(err)
+//	}
+//
+// The formula cell will keep its original cached value if the formula is
+// invalid.
+func (f *File) RecalculateAll(opts ...Options) error {
+	options := f.getOptions(opts...)
+	cells, err := f.getFormulaCells()
+	if err != nil {
+		return err
+	}
+	ctx := &calcContext{
+		maxCalcIterations: options.MaxCalcIterations,
+		iterations:        make(map[string]uint),
+		iterationsCache:   make(map[string]formulaArg),
+		valueCache:        make(map[string]formulaArg),
+	}
+	for _, cell := range cells {
+		ref := fmt.Sprintf("%s!%s", cell[0], cell[1])
+		ctx.mu.Lock()
+		result, ok := ctx.valueCache[ref]
+		ctx.mu.Unlock()
+		if !ok {
+			ctx.entry = ref
+			if result, err = f.calcCellValue(ctx, cell[0], cell[1]); err != nil {
+				errType := result.String
+				if errType == "" {
+					errType = err.Error()
+				}
+				if inStrSlice([]string{
+					formulaErrorDIV, formulaErrorNAME, formulaErrorNA, formulaErrorNUM,
+					formulaErrorVALUE, formulaErrorREF, formulaErrorNULL, formulaErrorSPILL,
+					formulaErrorCALC, formulaErrorGETTINGDATA,
+				}, errType, true) == -1 {
+					continue
+				}
+				result = newErrorFormulaArg(errType, errType)
+			}
+			ctx.mu.Lock()
+			ctx.valueCache[ref] = result
+			ctx.mu.Unlock()
+		}
+		if err = f.setCellCachedValue(cell[0], cell[1], result); err != nil {
+			return err
+		}
+	}
+	f.storeCalcCache(ctx)
+	return nil
+}
+
+// getFormulaCells returns the worksheet name and cell reference pairs of all
+// formula cells in the workbook, the cells in the calculation chain come
+// first.
+func (f *File) getFormulaCells() ([][2]string, error) {
+	if !f.formulaChecked {
+		if err := f.setArrayFormulaCells(); err != nil {
+			return nil, err
+		}
+		f.formulaChecked = true
+	}
+	var cells, chainCells [][2]string
+	formulaCells := make(map[[2]string]bool)
+	for _, sheet := range f.GetSheetList() {
+		f.mu.Lock()
+		ws, err := f.workSheetReader(sheet)
+		f.mu.Unlock()
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			return nil, err
+		}
+		ws.mu.Lock()
+		for _, row := range ws.SheetData.Row {
+			for _, c := range row.C {
+				if c.F != nil || c.f != "" {
+					cell := [2]string{sheet, c.R}
+					formulaCells[cell] = true
+					cells = append(cells, cell)
+				}
+			}
+		}
+		ws.mu.Unlock()
+	}
+	calcChain, err := f.calcChainReader()
+	if err != nil {
+		return nil, err
+	}
+	sheetMap, sheetID := f.GetSheetMap(), 0
+	for _, c := range calcChain.C {
+		if c.I != 0 {
+			sheetID = c.I
+		}
+		cell := [2]string{sheetMap[sheetID], c.R}
+		if formulaCells[cell] {
+			delete(formulaCells, cell)
+			chainCells = append(chainCells, cell)
+		}
+	}
+	for _, cell := range cells {
+		if formulaCells[cell] {
+			chainCells = append(chainCells, cell)
+		}
+	}
+	return chainCells, nil
+}
+
+// setCellCachedValue provides a function to store the calculated result as
+// the cached value of the formula cell by given worksheet name and cell
+// reference.
+func (f *File) setCellCachedValue(sheet, cell string, result formulaArg) error {
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	c, _, _, err := ws.prepareCell(cell)
+	if err != nil {
+		return err
+	}
+	c.IS, c.XMLSpace = nil, xml.Attr{}
+	switch result.Type {
+	case ArgNumber:
+		if result.Boolean {
+			c.T, c.V = setCellBool(result.Number != 0)
+			return err
+		}
+		_, precision, decimal := isNumeric(result.Value())
+		c.T, c.V = "", strconv.FormatFloat(decimal, 'f', -1, 64)
+		if precision > 15 {
+			c.V = strings.ToUpper(strconv.FormatFloat(decimal, 'G', 15, 64))
+		}
+	case ArgError:
+		c.T, c.V = "e", result.String
+	default:
+		c.setStr(result.Value())
+	}
+	return err
+}
+
+// arrayFormulaRange defines the top-left cell reference, the formula and the
+// reference range coordinates of the array formula in the worksheet.
+type arrayFormulaRange struct {
+	anchor, formula string
+	coordinates     []int
+}
+
+// getArrayFormulaRanges returns the array formula ranges of the worksheet,
+// the ranges will be indexed once and cached in the calculation context.
+func (f *File) getArrayFormulaRanges(ctx *calcContext, sheet string) ([]arrayFormulaRange, error) {
+	ctx.mu.Lock()
+	ranges, ok := ctx.arrayFormulas[sheet]
+	ctx.mu.Unlock()
+	if ok {
+		return ranges, nil
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return nil, err
+	}
+	ws.mu.Lock()
+	for _, r := range ws.SheetData.Row {
+		for _, c := range r.C {
+			if c.F == nil || c.F.T != STCellFormulaTypeArray || !strings.Contains(c.F.Ref, ":") {
+				continue
+			}
+			coordinates, err := rangeRefToCoordinates(c.F.Ref)
+			if err != nil {
+				continue
+			}
+			_ = sortCoordinates(coordinates)
+			ranges = append(ranges, arrayFormulaRange{anchor: c.R, formula: c.F.Content, coordinates: coordinates})
+		}
+	}
+	ws.mu.Unlock()
+	ctx.mu.Lock()
+	if ctx.arrayFormulas == nil {
+		ctx.arrayFormulas = make(map[string][]arrayFormulaRange)
+	}
+	ctx.arrayFormulas[sheet] = ranges
+	ctx.mu.Unlock()
+	return ranges, err
+}
+
+// getArrayFormulaAnchor returns the top-left cell reference and the formula
+// of the array formula whose reference range contains the given cell.
+func (f *File) getArrayFormulaAnchor(ctx *calcContext, sheet, cell string) (string, string, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return "", "", err
+	}
+	ranges, err := f.getArrayFormulaRanges(ctx, sheet)
+	if err != nil {
+		return "", "", err
+	}
+	for _, r := range ranges {
+		if col >= r.coordinates[0] && col <= r.coordinates[2] && row >= r.coordinates[1] && row <= r.coordinates[3] {
+			return r.anchor, r.formula, nil
+		}
+	}
+	return "", "", err
+}
+
+// calcSpillValue calculate the cell value in the spill range of a dynamic
+// array formula. The formula in the top-left cell of the array formula
+// reference range will be evaluated once in the context, and the element
+// of the result array corresponding to the cell will be returned. The second
+// returned value will be false if the cell not in the spill range, or the
+// formula not returns an array.
+func (f *File) calcSpillValue(ctx *calcContext, sheet, cell string) (formulaArg, bool) {
+	if ctx == nil {
+		return newEmptyFormulaArg(), false
+	}
+	anchor, formula, err := f.getArrayFormulaAnchor(ctx, sheet, cell)
+	if err != nil || anchor == "" {
+		return newEmptyFormulaArg(), false
+	}
+	ref := fmt.Sprintf("%s!%s", sheet, anchor)
+	ctx.mu.Lock()
+	if ctx.spillCache == nil {
+		ctx.spillCache = make(map[string]formulaArg)
+	}
+	result, ok := ctx.spillCache[ref]
+	if !ok {
+		ctx.spillCache[ref] = newEmptyFormulaArg()
+		ctx.mu.Unlock()
+		ps := efp.ExcelParser()
+		tokens := ps.Parse(formula)
+		for _, token := range tokens {
+			// only the formula functions could be returns an array at the top
+			// level, skip evaluate others
+			if isFunctionStartToken(token) && token.TValue != "ARRAY" && token.TValue != "ARRAYROW" {
+				result, _ = f.evalInfixExpression(ctx, sheet, anchor, tokens, true)
+				break
+			}
+		}
+		ctx.mu.Lock()
+		ctx.spillCache[ref] = result
+	}
+	ctx.mu.Unlock()
+	if result.Type != ArgMatrix {
+		return result, false
+	}
+	fromCol, fromRow, _ := CellNameToCoordinates(anchor)
+	col, row, _ := CellNameToCoordinates(cell)
+	if row-fromRow >= len(result.Matrix) || col-fromCol >= len(result.Matrix[row-fromRow]) {
+		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA), true
+	}
+	return result.Matrix[row-fromRow][col-fromCol], true
+}
+
 // getPriority calculate arithmetic operator priority.
 func getPriority(token efp.Token) (pri int) {
 	pri = tokenPriority[token.TValue]
@@ -917,7 +1392,23 @@ func newEmptyFormulaArg() formulaArg {
 	return formulaArg{Type: ArgEmpty}
 }
 
+// newErrorFormulaArgByError create an error formula argument by given error,
+// the error type will be #VALUE! if the error is not a formula error.
+func newErrorFormulaArgByError(err error) formulaArg {
+	if strings.HasPrefix(err.Error(), "#") {
+		return newErrorFormulaArg(err.Error(), err.Error())
+	}
+	return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+}
+
 // evalInfixExp evaluate syntax analysis by given infix expression after
+// lexical analysis, and returns the top-left element if the result is an
+// array.
+func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.Token) (formulaArg, error) {
+	return f.evalInfixExpression(ctx, sheet, cell, tokens, false)
+}
+
+// evalInfixExpression evaluate syntax analysis by given infix expression after
 // lexical analysis. Evaluate an infix expression containing formulas by
 // stacks:
 //
@@ -928,8 +1419,11 @@ func newEmptyFormulaArg() formulaArg {
 //	opft - Operator of the operation formula
 //	args - Arguments list of the operation formula
 //
+// The result array of the formula function and the range reference will be
+// kept as is if keepArray is true.
+//
 // TODO: handle subtypes: Nothing, Text, Logical, Error, Concatenation, Intersection, Union
-func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.Token) (formulaArg, error) {
+func (f *File) evalInfixExpression(ctx *calcContext, sheet, cell string, tokens []efp.Token, keepArray bool) (formulaArg, error) {
 	var (
 		err                             error
 		inArray, inArrayRow             bool
@@ -938,18 +1432,52 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 		opdStack, optStack, opfStack    = NewStack(), NewStack(), NewStack()
 		opfdStack, opftStack, argsStack = NewStack(), NewStack(), NewStack()
 	)
+	tokens = mergeStructuredReferenceTokens(tokens)
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
 
 		// out of function stack
 		if opfStack.Len() == 0 {
-			if err = f.parseToken(ctx, sheet, token, opdStack, optStack); err != nil {
+			if keepArray && token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange {
+				result, err := f.parseOperandReference(ctx, sheet, cell, token)
+				if err != nil {
+					if isStructuredReference(token.TValue) {
+						return result, err
+					}
+					return newEmptyFormulaArg(), errors.New(formulaErrorNAME)
+				}
+				opdStack.Push(result)
+				continue
+			}
+			if err = f.parseToken(ctx, sheet, cell, token, opdStack, optStack); err != nil {
 				return newEmptyFormulaArg(), err
 			}
 		}
 
 		// function start
 		if isFunctionStartToken(token) {
+			if isLazyFunctionToken(ctx, token) {
+				end, args := getFunctionArgTokens(tokens, i)
+				arg := f.evalLazyFunc(ctx, sheet, cell, token, args)
+				if arg.lambda != nil && end+1 < len(tokens) && isBeginParenthesesToken(tokens[end+1]) {
+					// invoke the LAMBDA function immediately
+					var params [][]efp.Token
+					end, params = getFunctionArgTokens(tokens, end+1)
+					arg = f.callLambda(ctx, sheet, cell, arg.lambda, f.evalFuncArgTokens(ctx, sheet, cell, params))
+				}
+				var nextToken efp.Token
+				if end+1 < len(tokens) {
+					nextToken = tokens[end+1]
+				}
+				opfStack.Push(token)
+				argsStack.Push(list.New().Init())
+				opftStack.Push(token)
+				if errArg := pushFuncResult(arg, nextToken, keepArray, opfStack, opdStack, opftStack, opfdStack, argsStack); errArg.Type == ArgError {
+					return errArg, errors.New(errArg.Error)
+				}
+				i = end
+				continue
+			}
 			if token.TValue == "ARRAY" {
 				inArray, formulaArray = true, [][]formulaArg{}
 				continue
@@ -974,12 +1502,8 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 			// current token is args or range, skip next token, order required: parse reference first
 			if token.TSubType == efp.TokenSubTypeRange {
 				if opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
-					refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-					if refTo != "" {
-						token.TValue = refTo
-					}
 					// parse reference: must reference at here
-					result, err := f.parseReference(ctx, sheet, token.TValue)
+					result, err := f.parseOperandReference(ctx, sheet, cell, token)
 					if err != nil {
 						return result, err
 					}
@@ -988,11 +1512,7 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				}
 				if nextToken.TType == efp.TokenTypeArgument || nextToken.TType == efp.TokenTypeFunction {
 					// parse reference: reference or range at here
-					refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-					if refTo != "" {
-						token.TValue = refTo
-					}
-					result, err := f.parseReference(ctx, sheet, token.TValue)
+					result, err := f.parseOperandReference(ctx, sheet, cell, token)
 					if err != nil {
 						return result, err
 					}
@@ -1005,6 +1525,18 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 					argsStack.Peek().(*list.List).PushBack(result)
 					continue
 				}
+				if nextToken.TType == efp.TokenTypeOperatorInfix {
+					result, err := f.parseOperandReference(ctx, sheet, cell, token)
+					if err != nil {
+						return result, err
+					}
+					// keep the range as matrix operand for array operations
+					if result.Type == ArgMatrix {
+						opfdStack.Push(result)
+						continue
+					}
+					token = formulaArgToToken(result)
+				}
 			}
 
 			if isEndParenthesesToken(token) && isBeginParenthesesToken(opftStack.Peek().(efp.Token)) {
@@ -1015,16 +1547,19 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 			}
 
 			// check current token is opft
-			if err = f.parseToken(ctx, sheet, token, opfdStack, opftStack); err != nil {
+			if err = f.parseToken(ctx, sheet, cell, token, opfdStack, opftStack); err != nil {
 				return newEmptyFormulaArg(), err
 			}
 
 			// current token is arg
 			if token.TType == efp.TokenTypeArgument {
+				if inArray {
+					continue
+				}
 				for opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
 					// calculate trigger
 					topOpt := opftStack.Peek().(efp.Token)
-					if err := calculate(opfdStack, topOpt); err != nil {
+					if err := ctx.calculate(opfdStack, topOpt); err != nil {
 						argsStack.Peek().(*list.List).PushFront(newErrorFormulaArg(formulaErrorVALUE, err.Error()))
 					}
 					opftStack.Pop()
@@ -1045,18 +1580,23 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 				continue
 			}
 			if inArray && isFunctionStopToken(token) {
-				argsStack.Peek().(*list.List).PushBack(newMatrixFormulaArg(formulaArray))
 				inArray = false
+				// the array is an operand of the operator in the function
+				if opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) || nextToken.TType == efp.TokenTypeOperatorInfix {
+					opfdStack.Push(newMatrixFormulaArg(formulaArray))
+					continue
+				}
+				argsStack.Peek().(*list.List).PushBack(newMatrixFormulaArg(formulaArray))
 				continue
 			}
-			if errArg := f.evalInfixExpFunc(ctx, sheet, cell, token, nextToken, opfStack, opdStack, opftStack, opfdStack, argsStack); errArg.Type == ArgError {
+			if errArg := f.evalInfixExpFunc(ctx, sheet, cell, token, nextToken, keepArray, opfStack, opdStack, opftStack, opfdStack, argsStack); errArg.Type == ArgError {
 				return errArg, errors.New(errArg.Error)
 			}
 		}
 	}
 	for optStack.Len() != 0 {
 		topOpt := optStack.Peek().(efp.Token)
-		if err = calculate(opdStack, topOpt); err != nil {
+		if err = ctx.calculate(opdStack, topOpt); err != nil {
 			return newEmptyFormulaArg(), err
 		}
 		optStack.Pop()
@@ -1068,15 +1608,42 @@ func (f *File) evalInfixExp(ctx *calcContext, sheet, cell string, tokens []efp.T
 }
 
 // evalInfixExpFunc evaluate formula function in the infix expression.
-func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nextToken efp.Token, opfStack, opdStack, opftStack, opfdStack, argsStack *Stack) formulaArg {
+func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nextToken efp.Token, keepArray bool, opfStack, opdStack, opftStack, opfdStack, argsStack *Stack) formulaArg {
 	if !isFunctionStopToken(token) {
 		return newEmptyFormulaArg()
 	}
-	prepareEvalInfixExp(opfStack, opftStack, opfdStack, argsStack)
+	prepareEvalInfixExp(ctx, opfStack, opftStack, opfdStack, argsStack)
 	// call formula function to evaluate
-	arg := callFuncByName(&formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}, strings.NewReplacer(
-		"_xlfn.", "", ".", "dot").Replace(opfStack.Peek().(efp.Token).TValue),
-		[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	fn := &formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}
+	name := opfStack.Peek().(efp.Token).TValue
+	var arg formulaArg
+	if udf, ok := f.getUserFormulaFunc(name); ok {
+		arg = udf.call(argsStack.Peek().(*list.List))
+	} else if lambda := f.getLambdaByName(ctx, sheet, name); lambda != nil {
+		var args []formulaArg
+		for e := argsStack.Peek().(*list.List).Front(); e != nil; e = e.Next() {
+			args = append(args, e.Value.(formulaArg))
+		}
+		arg = f.callLambda(ctx, sheet, cell, lambda, args)
+	} else {
+		arg = callFuncByName(fn, strings.NewReplacer(
+			"_xlfn.", "", "_xlws.", "", ".", "dot").Replace(name),
+			[]reflect.Value{reflect.ValueOf(argsStack.Peek().(*list.List))})
+	}
+	if ctx != nil && ctx.trace {
+		var args []formulaArg
+		for e := argsStack.Peek().(*list.List).Front(); e != nil; e = e.Next() {
+			args = append(args, e.Value.(formulaArg))
+		}
+		ctx.addFormulaStep(FormulaStepFunction, name, args, arg)
+	}
+	return pushFuncResult(arg, nextToken, keepArray, opfStack, opdStack, opftStack, opfdStack, argsStack)
+}
+
+// pushFuncResult push the result of the formula function to the operand
+// stack or the arguments list of the outer formula function, and pop the
+// function from the stacks.
+func pushFuncResult(arg formulaArg, nextToken efp.Token, keepArray bool, opfStack, opdStack, opftStack, opfdStack, argsStack *Stack) formulaArg {
 	if arg.Type == ArgError && opfStack.Len() == 1 {
 		return arg
 	}
@@ -1092,7 +1659,7 @@ func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nex
 		argsStack.Peek().(*list.List).PushBack(arg)
 		return newEmptyFormulaArg()
 	}
-	if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 {
+	if arg.Type == ArgMatrix && len(arg.Matrix) > 0 && len(arg.Matrix[0]) > 0 && !keepArray {
 		opdStack.Push(arg.Matrix[0][0])
 		return newEmptyFormulaArg()
 	}
@@ -1100,79 +1667,404 @@ func (f *File) evalInfixExpFunc(ctx *calcContext, sheet, cell string, token, nex
 	return newEmptyFormulaArg()
 }
 
-// prepareEvalInfixExp check the token and stack state for formula function
-// evaluate.
-func prepareEvalInfixExp(opfStack, opftStack, opfdStack, argsStack *Stack) {
-	// current token is function stop
-	for opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
-		// calculate trigger
-		topOpt := opftStack.Peek().(efp.Token)
-		if err := calculate(opfdStack, topOpt); err != nil {
-			argsStack.Peek().(*list.List).PushBack(newErrorFormulaArg(err.Error(), err.Error()))
-			opftStack.Pop()
-			continue
-		}
-		opftStack.Pop()
-	}
-	argument := true
-	if opftStack.Len() > 2 && opfdStack.Len() == 1 {
-		topOpt := opftStack.Pop()
-		if opftStack.Peek().(efp.Token).TType == efp.TokenTypeOperatorInfix {
-			argument = false
+// maxLambdaDepth defines the maximum nesting depth of the LAMBDA function
+// calls.
+const maxLambdaDepth = 1024
+
+// localName returns the normalized local name which defined by the LET and
+// LAMBDA formula functions.
+func localName(name string) string {
+	return strings.ToUpper(strings.TrimPrefix(name, "_xlpm."))
+}
+
+// lookup find the value of the local name in the scope and the parent scopes.
+func (s *calcScope) lookup(name string) (formulaArg, bool) {
+	for scope := s; scope != nil; scope = scope.parent {
+		if arg, ok := scope.names[localName(name)]; ok {
+			return arg, true
 		}
-		opftStack.Push(topOpt)
 	}
-	// push opfd to args
-	if argument && opfdStack.Len() > 0 {
-		argsStack.Peek().(*list.List).PushBack(opfdStack.Pop().(formulaArg))
+	return newEmptyFormulaArg(), false
+}
+
+// parseOperandReference parse the value of the range operand token, and
+// record the evaluation step when tracing the formula evaluation.
+func (f *File) parseOperandReference(ctx *calcContext, sheet, cell string, token efp.Token) (formulaArg, error) {
+	result, err := f.resolveOperandReference(ctx, sheet, cell, token)
+	if err == nil {
+		ctx.addFormulaStep(FormulaStepOperand, token.TValue, nil, result)
 	}
+	return result, err
 }
 
-// calcPow evaluate exponentiation arithmetic operations.
-func calcPow(rOpd, lOpd formulaArg, opdStack *Stack) error {
-	lOpdVal := lOpd.ToNumber()
-	if lOpdVal.Type != ArgNumber {
-		return errors.New(lOpdVal.Value())
+// resolveOperandReference resolve the value of the range operand token, the
+// local name which defined by the LET and LAMBDA formula functions takes
+// precedence over the defined name and the cell reference. The structured
+// reference and the table name will be resolved by the table which contains
+// the formula cell or the table with the given name.
+func (f *File) resolveOperandReference(ctx *calcContext, sheet, cell string, token efp.Token) (formulaArg, error) {
+	if ctx != nil {
+		if arg, ok := ctx.scope.lookup(token.TValue); ok {
+			return arg, nil
+		}
 	}
-	rOpdVal := rOpd.ToNumber()
-	if rOpdVal.Type != ArgNumber {
-		return errors.New(rOpdVal.Value())
+	if isStructuredReference(token.TValue) {
+		return f.parseStructuredReferenceOperand(ctx, sheet, cell, token.TValue)
 	}
-	opdStack.Push(newNumberFormulaArg(math.Pow(lOpdVal.Number, rOpdVal.Number)))
-	return nil
+	reference := token.TValue
+	refTo := f.getDefinedNameRefTo(token.TValue, sheet)
+	if refTo != "" {
+		reference = refTo
+	}
+	result, err := f.parseReference(ctx, sheet, reference)
+	if err != nil && refTo == "" {
+		if _, tbl, _ := f.getTableByName(token.TValue); tbl != nil {
+			return f.parseStructuredReferenceOperand(ctx, sheet, cell, token.TValue+"[]")
+		}
+	}
+	if err == nil || refTo == "" || ctx == nil {
+		return result, err
+	}
+	// the defined name refers to a formula or a constant
+	arg, err := f.evalDefinedName(ctx, sheet, refTo)
+	if err != nil {
+		return newErrorFormulaArgByError(err), err
+	}
+	return arg, nil
 }
 
-// calcEq evaluate equal arithmetic operations.
-func calcEq(rOpd, lOpd formulaArg, opdStack *Stack) error {
-	opdStack.Push(newBoolFormulaArg(rOpd.Value() == lOpd.Value()))
-	return nil
+// isStructuredReference returns if the given operand is a structured
+// reference to the table, such as Table1[Column1] and [@Column1].
+func isStructuredReference(operand string) bool {
+	return strings.HasSuffix(operand, "]") && strings.Contains(operand, "[") &&
+		!strings.Contains(operand, "!")
 }
 
-// calcNEq evaluate not equal arithmetic operations.
-func calcNEq(rOpd, lOpd formulaArg, opdStack *Stack) error {
-	opdStack.Push(newBoolFormulaArg(rOpd.Value() != lOpd.Value()))
-	return nil
+// parseStructuredReferenceOperand resolve the structured reference operand
+// to the value of the cell or the range which it refers to. The reference to
+// a single cell, such as [@Column1], will be resolved as a cell reference.
+func (f *File) parseStructuredReferenceOperand(ctx *calcContext, sheet, cell, reference string) (formulaArg, error) {
+	ref, err := f.parseStructuredReference(sheet, cell, reference)
+	if err != nil {
+		if msg := err.Error(); msg == formulaErrorREF || msg == formulaErrorVALUE {
+			return newErrorFormulaArg(msg, msg), err
+		}
+		return newErrorFormulaArg(formulaErrorNAME, err.Error()), err
+	}
+	idx := strings.LastIndex(ref, "!")
+	if from, to, ok := strings.Cut(ref[idx+1:], ":"); ok && from == to {
+		ref = ref[:idx+1] + from
+	}
+	return f.parseReference(ctx, sheet, ref)
 }
 
-// calcL evaluate less than arithmetic operations.
-func calcL(rOpd, lOpd formulaArg, opdStack *Stack) error {
-	if rOpd.Type == ArgNumber && lOpd.Type == ArgNumber {
-		opdStack.Push(newBoolFormulaArg(lOpd.Number < rOpd.Number))
+// evalDefinedName evaluate the formula which the defined name refers to.
+func (f *File) evalDefinedName(ctx *calcContext, sheet, refTo string) (formulaArg, error) {
+	ps := efp.ExcelParser()
+	tokens := ps.Parse(strings.TrimPrefix(refTo, "="))
+	if len(tokens) == 0 || ctx.definedNames[refTo] {
+		return newEmptyFormulaArg(), errors.New(formulaErrorNAME)
 	}
-	if rOpd.Type == ArgString && lOpd.Type == ArgString {
-		opdStack.Push(newBoolFormulaArg(strings.Compare(lOpd.Value(), rOpd.Value()) == -1))
+	if ctx.definedNames == nil {
+		ctx.definedNames = make(map[string]bool)
 	}
-	if rOpd.Type == ArgNumber && lOpd.Type == ArgString {
-		opdStack.Push(newBoolFormulaArg(false))
+	scope := ctx.scope
+	ctx.scope, ctx.definedNames[refTo] = nil, true
+	defer func() {
+		ctx.scope = scope
+		delete(ctx.definedNames, refTo)
+	}()
+	return f.evalInfixExpression(ctx, sheet, "", tokens, true)
+}
+
+// getLambdaByName returns the LAMBDA function by given formula function name,
+// the LAMBDA function could be assigned to a local name by the LET function
+// or a defined name. The defined name will not override the built-in
+// formula functions.
+func (f *File) getLambdaByName(ctx *calcContext, sheet, name string) *calcLambda {
+	if ctx == nil {
+		return nil
 	}
-	if rOpd.Type == ArgString && lOpd.Type == ArgNumber {
-		opdStack.Push(newBoolFormulaArg(true))
+	if arg, ok := ctx.scope.lookup(name); ok {
+		return arg.lambda
+	}
+	if reflect.ValueOf(&formulaFuncs{}).MethodByName(strings.NewReplacer(
+		"_xlfn.", "", "_xlws.", "", ".", "dot").Replace(name)).IsValid() {
+		return nil
+	}
+	if refTo := f.getDefinedNameRefTo(name, sheet); refTo != "" {
+		if arg, err := f.evalDefinedName(ctx, sheet, refTo); err == nil {
+			return arg.lambda
+		}
 	}
 	return nil
 }
 
-// calcLe evaluate less than or equal arithmetic operations.
-func calcLe(rOpd, lOpd formulaArg, opdStack *Stack) error {
+// isLazyFunctionToken determine if the token is the start of the formula
+// function which arguments should be evaluated on demand.
+func isLazyFunctionToken(ctx *calcContext, token efp.Token) bool {
+	if ctx == nil {
+		return false
+	}
+	switch strings.ToUpper(strings.TrimPrefix(token.TValue, "_xlfn.")) {
+	case "LET", "LAMBDA":
+		return true
+	case "IF":
+		// evaluate the branches on demand in the LAMBDA function to allow
+		// recursive calls
+		return ctx.scope != nil
+	}
+	return false
+}
+
+// getFunctionArgTokens returns the index of the stop token which matched with
+// the function start or begin parentheses token at the given index, and the
+// tokens of each argument.
+func getFunctionArgTokens(tokens []efp.Token, start int) (int, [][]efp.Token) {
+	var (
+		args  [][]efp.Token
+		arg   []efp.Token
+		depth int
+	)
+	for i := start; i < len(tokens); i++ {
+		token := tokens[i]
+		if isFunctionStartToken(token) || isBeginParenthesesToken(token) {
+			if depth++; depth == 1 {
+				continue
+			}
+		}
+		if isFunctionStopToken(token) || isEndParenthesesToken(token) {
+			if depth--; depth == 0 {
+				if len(arg) > 0 || len(args) > 0 {
+					args = append(args, arg)
+				}
+				return i, args
+			}
+		}
+		// the arguments in the parentheses are separated by the union operator
+		if depth == 1 && (token.TType == efp.TokenTypeArgument || token.TSubType == efp.TokenSubTypeUnion) {
+			args, arg = append(args, arg), nil
+			continue
+		}
+		arg = append(arg, token)
+	}
+	return len(tokens) - 1, append(args, arg)
+}
+
+// evalFuncArgTokens evaluate the tokens of each function argument.
+func (f *File) evalFuncArgTokens(ctx *calcContext, sheet, cell string, args [][]efp.Token) []formulaArg {
+	var results []formulaArg
+	for _, tokens := range args {
+		if len(tokens) == 0 {
+			results = append(results, newEmptyFormulaArg())
+			continue
+		}
+		arg, err := f.evalInfixExpression(ctx, sheet, cell, tokens, true)
+		if err != nil {
+			arg = newErrorFormulaArgByError(err)
+		}
+		results = append(results, arg)
+	}
+	return results
+}
+
+// evalLazyFunc evaluate the formula function which arguments should be
+// evaluated on demand.
+func (f *File) evalLazyFunc(ctx *calcContext, sheet, cell string, token efp.Token, args [][]efp.Token) formulaArg {
+	switch strings.ToUpper(strings.TrimPrefix(token.TValue, "_xlfn.")) {
+	case "LET":
+		return f.evalLET(ctx, sheet, cell, args)
+	case "LAMBDA":
+		return evalLAMBDA(ctx, args)
+	default:
+		return f.evalLazyIF(ctx, sheet, cell, args)
+	}
+}
+
+// getLocalNameToken returns the local name by given tokens of the LET and
+// LAMBDA function argument.
+func getLocalNameToken(tokens []efp.Token) (string, bool) {
+	if len(tokens) != 1 || tokens[0].TType != efp.TokenTypeOperand || tokens[0].TSubType != efp.TokenSubTypeRange {
+		return "", false
+	}
+	name := strings.TrimPrefix(tokens[0].TValue, "_xlpm.")
+	if _, _, err := CellNameToCoordinates(name); err == nil || strings.ContainsAny(name, "!:") {
+		return "", false
+	}
+	return localName(name), true
+}
+
+// evalLET evaluate the LET function, which assigns names to calculation
+// results. The syntax of the function is:
+//
+//	LET(name1,name_value1,[name2,name_value2,...],calculation)
+func (f *File) evalLET(ctx *calcContext, sheet, cell string, args [][]efp.Token) formulaArg {
+	if len(args) < 3 || len(args)%2 == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, "LET requires an odd number of arguments and at least 3 arguments")
+	}
+	scope := ctx.scope
+	ctx.scope = &calcScope{names: map[string]formulaArg{}, parent: scope}
+	defer func() { ctx.scope = scope }()
+	for i := 0; i < len(args)-1; i += 2 {
+		name, ok := getLocalNameToken(args[i])
+		if !ok {
+			return newErrorFormulaArg(formulaErrorNAME, "LET requires a valid name")
+		}
+		ctx.scope.names[name] = f.evalFuncArgTokens(ctx, sheet, cell, args[i+1:i+2])[0]
+	}
+	return f.evalFuncArgTokens(ctx, sheet, cell, args[len(args)-1:])[0]
+}
+
+// evalLAMBDA evaluate the LAMBDA function, which creates a custom and
+// reusable function. The syntax of the function is:
+//
+//	LAMBDA([parameter1,parameter2,...],calculation)
+func evalLAMBDA(ctx *calcContext, args [][]efp.Token) formulaArg {
+	if len(args) == 0 || len(args[len(args)-1]) == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, "LAMBDA requires at least 1 argument")
+	}
+	lambda := &calcLambda{body: args[len(args)-1], scope: ctx.scope}
+	names := map[string]bool{}
+	for _, tokens := range args[:len(args)-1] {
+		name, ok := getLocalNameToken(tokens)
+		if !ok || names[name] {
+			return newErrorFormulaArg(formulaErrorVALUE, "LAMBDA requires a valid parameter name")
+		}
+		names[name] = true
+		lambda.params = append(lambda.params, name)
+	}
+	return formulaArg{Type: ArgUnknown, lambda: lambda}
+}
+
+// evalLazyIF evaluate the IF function in the LAMBDA function, the branch
+// which not be chosen by the condition will not be evaluated.
+func (f *File) evalLazyIF(ctx *calcContext, sheet, cell string, args [][]efp.Token) formulaArg {
+	fn := &formulaFuncs{f: f, sheet: sheet, cell: cell, ctx: ctx}
+	argsList := list.New()
+	if len(args) == 0 || len(args) > 3 {
+		for _, arg := range f.evalFuncArgTokens(ctx, sheet, cell, args) {
+			argsList.PushBack(arg)
+		}
+		return fn.IF(argsList)
+	}
+	cond := f.evalFuncArgTokens(ctx, sheet, cell, args[:1])[0]
+	if cond.Type == ArgError {
+		return cond
+	}
+	argsList.PushBack(cond)
+	logical := fn.IF(argsList)
+	if logical.Type == ArgError || len(args) == 1 {
+		return logical
+	}
+	for i := 1; i < len(args); i++ {
+		if (i == 1) == (logical.Number == 1) {
+			argsList.PushBack(f.evalFuncArgTokens(ctx, sheet, cell, args[i:i+1])[0])
+			continue
+		}
+		argsList.PushBack(newEmptyFormulaArg())
+	}
+	return fn.IF(argsList)
+}
+
+// callLambda call the LAMBDA function with given arguments.
+func (f *File) callLambda(ctx *calcContext, sheet, cell string, lambda *calcLambda, args []formulaArg) formulaArg {
+	if len(args) != len(lambda.params) {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("LAMBDA requires %d arguments", len(lambda.params)))
+	}
+	if ctx.lambdaDepth >= maxLambdaDepth {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	scope := &calcScope{names: map[string]formulaArg{}, parent: lambda.scope}
+	for i, name := range lambda.params {
+		scope.names[name] = args[i]
+	}
+	parent := ctx.scope
+	ctx.scope = scope
+	ctx.lambdaDepth++
+	defer func() {
+		ctx.scope = parent
+		ctx.lambdaDepth--
+	}()
+	result, err := f.evalInfixExpression(ctx, sheet, cell, lambda.body, true)
+	if err != nil {
+		return newErrorFormulaArgByError(err)
+	}
+	return result
+}
+
+// prepareEvalInfixExp check the token and stack state for formula function
+// evaluate.
+func prepareEvalInfixExp(ctx *calcContext, opfStack, opftStack, opfdStack, argsStack *Stack) {
+	// current token is function stop
+	for opftStack.Peek().(efp.Token) != opfStack.Peek().(efp.Token) {
+		// calculate trigger
+		topOpt := opftStack.Peek().(efp.Token)
+		if err := ctx.calculate(opfdStack, topOpt); err != nil {
+			argsStack.Peek().(*list.List).PushBack(newErrorFormulaArg(err.Error(), err.Error()))
+			opftStack.Pop()
+			continue
+		}
+		opftStack.Pop()
+	}
+	argument := true
+	if opftStack.Len() > 2 && opfdStack.Len() == 1 {
+		topOpt := opftStack.Pop()
+		if opftStack.Peek().(efp.Token).TType == efp.TokenTypeOperatorInfix {
+			argument = false
+		}
+		opftStack.Push(topOpt)
+	}
+	// push opfd to args
+	if argument && opfdStack.Len() > 0 {
+		argsStack.Peek().(*list.List).PushBack(opfdStack.Pop().(formulaArg))
+	}
+}
+
+// calcPow evaluate exponentiation arithmetic operations.
+func calcPow(rOpd, lOpd formulaArg, opdStack *Stack) error {
+	lOpdVal := lOpd.ToNumber()
+	if lOpdVal.Type != ArgNumber {
+		return errors.New(lOpdVal.Value())
+	}
+	rOpdVal := rOpd.ToNumber()
+	if rOpdVal.Type != ArgNumber {
+		return errors.New(rOpdVal.Value())
+	}
+	opdStack.Push(newNumberFormulaArg(math.Pow(lOpdVal.Number, rOpdVal.Number)))
+	return nil
+}
+
+// calcEq evaluate equal arithmetic operations.
+func calcEq(rOpd, lOpd formulaArg, opdStack *Stack) error {
+	opdStack.Push(newBoolFormulaArg(rOpd.Value() == lOpd.Value()))
+	return nil
+}
+
+// calcNEq evaluate not equal arithmetic operations.
+func calcNEq(rOpd, lOpd formulaArg, opdStack *Stack) error {
+	opdStack.Push(newBoolFormulaArg(rOpd.Value() != lOpd.Value()))
+	return nil
+}
+
+// calcL evaluate less than arithmetic operations.
+func calcL(rOpd, lOpd formulaArg, opdStack *Stack) error {
+	if rOpd.Type == ArgNumber && lOpd.Type == ArgNumber {
+		opdStack.Push(newBoolFormulaArg(lOpd.Number < rOpd.Number))
+	}
+	if rOpd.Type == ArgString && lOpd.Type == ArgString {
+		opdStack.Push(newBoolFormulaArg(strings.Compare(lOpd.Value(), rOpd.Value()) == -1))
+	}
+	if rOpd.Type == ArgNumber && lOpd.Type == ArgString {
+		opdStack.Push(newBoolFormulaArg(false))
+	}
+	if rOpd.Type == ArgString && lOpd.Type == ArgNumber {
+		opdStack.Push(newBoolFormulaArg(true))
+	}
+	return nil
+}
+
+// calcLe evaluate less than or equal arithmetic operations.
+func calcLe(rOpd, lOpd formulaArg, opdStack *Stack) error {
 	if rOpd.Type == ArgNumber && lOpd.Type == ArgNumber {
 		opdStack.Push(newBoolFormulaArg(lOpd.Number <= rOpd.Number))
 	}
@@ -1300,6 +2192,10 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 			return ErrInvalidFormula
 		}
 		opd := opdStack.Pop().(formulaArg)
+		if opd.Type == ArgMatrix {
+			opdStack.Push(calcMatrixPrefix(opd))
+			return nil
+		}
 		opdStack.Push(newNumberFormulaArg(0 - opd.ToNumber().Number))
 	}
 	if opt.TValue == "-" && opt.TType == efp.TokenTypeOperatorInfix {
@@ -1308,6 +2204,10 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(formulaArg)
 		lOpd := opdStack.Pop().(formulaArg)
+		if rOpd.Type == ArgMatrix || lOpd.Type == ArgMatrix {
+			opdStack.Push(calcMatrix(rOpd, lOpd, opt))
+			return nil
+		}
 		if err := calcSubtract(rOpd, lOpd, opdStack); err != nil {
 			return err
 		}
@@ -1331,6 +2231,10 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 		}
 		rOpd := opdStack.Pop().(formulaArg)
 		lOpd := opdStack.Pop().(formulaArg)
+		if rOpd.Type == ArgMatrix || lOpd.Type == ArgMatrix {
+			opdStack.Push(calcMatrix(rOpd, lOpd, opt))
+			return nil
+		}
 		if opt.TValue != "&" {
 			if rOpd.Value() == "" {
 				rOpd = newNumberFormulaArg(0)
@@ -1350,8 +2254,143 @@ func calculate(opdStack *Stack, opt efp.Token) error {
 	return nil
 }
 
+// formulaArgToMatrix returns the two-dimensional array of the formula
+// argument, the list will be converted to a single row and the scalar value
+// will be converted to a 1-by-1 array.
+func formulaArgToMatrix(arg formulaArg) [][]formulaArg {
+	switch arg.Type {
+	case ArgMatrix:
+		return arg.Matrix
+	case ArgList:
+		return [][]formulaArg{arg.List}
+	default:
+		return [][]formulaArg{{arg}}
+	}
+}
+
+// getMatrixSize returns the number of rows and the maximum number of columns
+// of the matrix.
+func getMatrixSize(mtx [][]formulaArg) (rows, cols int) {
+	for _, row := range mtx {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	return len(mtx), cols
+}
+
+// maxFormulaArrayCells defined the maximum number of elements in the array
+// which generated by the formula functions by given size. The array which
+// fits on the worksheet may have billions of elements, this budget limits the
+// memory used by the calculation engine for a single array, which is about
+// 600MB for 4,194,304 elements.
+const maxFormulaArrayCells = 1 << 22
+
+// isArraySizeExceeded returns if the array size which specified by the
+// formula function arguments exceeds the worksheet limits or the elements
+// budget of the array, the arguments should be checked before allocating the
+// array.
+func isArraySizeExceeded(rows, cols float64) bool {
+	return rows > TotalRows || cols > MaxColumns || rows*cols > maxFormulaArrayCells
+}
+
+// padMatrix returns a new matrix with the given size, the elements out of
+// the range of the source matrix will be filled with the given value.
+func padMatrix(mtx [][]formulaArg, rows, cols int, pad formulaArg) [][]formulaArg {
+	result := make([][]formulaArg, rows)
+	for r := range result {
+		result[r] = make([]formulaArg, cols)
+		for c := range result[r] {
+			if r < len(mtx) && c < len(mtx[r]) {
+				result[r][c] = mtx[r][c]
+				continue
+			}
+			result[r][c] = pad
+		}
+	}
+	return result
+}
+
+// getMatrixCell returns the element of the matrix by given row and column
+// index, the single row or column will be expanded to fit the index.
+func getMatrixCell(mtx [][]formulaArg, row, col int) (formulaArg, bool) {
+	if len(mtx) == 1 {
+		row = 0
+	}
+	if row >= len(mtx) {
+		return newEmptyFormulaArg(), false
+	}
+	if len(mtx[row]) == 1 {
+		col = 0
+	}
+	if col >= len(mtx[row]) {
+		return newEmptyFormulaArg(), false
+	}
+	return mtx[row][col], true
+}
+
+// calcMatrixPrefix evaluate negation operation for each element of the
+// matrix.
+func calcMatrixPrefix(opd formulaArg) formulaArg {
+	mtx := make([][]formulaArg, len(opd.Matrix))
+	for r, row := range opd.Matrix {
+		mtx[r] = make([]formulaArg, len(row))
+		for c, cell := range row {
+			if cell.Type == ArgError {
+				mtx[r][c] = cell
+				continue
+			}
+			num := cell.ToNumber()
+			if num.Type != ArgNumber {
+				mtx[r][c] = newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+				continue
+			}
+			mtx[r][c] = newNumberFormulaArg(0 - num.Number)
+		}
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
+// calcMatrix evaluate arithmetic operations on each pair of elements of the
+// operands when the left-hand side or the right-hand side operand is a
+// matrix. The single row or column operand will be expanded, and the element
+// out of the range of the operand will be #N/A.
+func calcMatrix(rOpd, lOpd formulaArg, opt efp.Token) formulaArg {
+	lMtx, rMtx := formulaArgToMatrix(lOpd), formulaArgToMatrix(rOpd)
+	rows, cols := len(lMtx), 0
+	if len(rMtx) > rows {
+		rows = len(rMtx)
+	}
+	for _, mtx := range [][][]formulaArg{lMtx, rMtx} {
+		if len(mtx) > 0 && len(mtx[0]) > cols {
+			cols = len(mtx[0])
+		}
+	}
+	result := make([][]formulaArg, rows)
+	for r := 0; r < rows; r++ {
+		result[r] = make([]formulaArg, cols)
+		for c := 0; c < cols; c++ {
+			lhs, lok := getMatrixCell(lMtx, r, c)
+			rhs, rok := getMatrixCell(rMtx, r, c)
+			if !lok || !rok {
+				result[r][c] = newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+				continue
+			}
+			opdStack := NewStack()
+			opdStack.Push(lhs)
+			opdStack.Push(rhs)
+			if err := calculate(opdStack, opt); err != nil {
+				result[r][c] = newErrorFormulaArgByError(err)
+				continue
+			}
+			result[r][c] = opdStack.Pop().(formulaArg)
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // parseOperatorPrefixToken parse operator prefix token.
-func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Token) (err error) {
+func (f *File) parseOperatorPrefixToken(ctx *calcContext, optStack, opdStack *Stack, token efp.Token) (err error) {
 	if optStack.Len() == 0 {
 		optStack.Push(token)
 		return
@@ -1369,7 +2408,7 @@ func (f *File) parseOperatorPrefixToken(optStack, opdStack *Stack, token efp.Tok
 	}
 	for tokenPriority <= topOptPriority {
 		optStack.Pop()
-		if err = calculate(opdStack, topOpt); err != nil {
+		if err = ctx.calculate(opdStack, topOpt); err != nil {
 			return
 		}
 		if optStack.Len() > 0 {
@@ -1443,21 +2482,20 @@ func formulaArgToToken(arg formulaArg) efp.Token {
 
 // parseToken parse basic arithmetic operator priority and evaluate based on
 // operators and operands.
-func (f *File) parseToken(ctx *calcContext, sheet string, token efp.Token, opdStack, optStack *Stack) error {
+func (f *File) parseToken(ctx *calcContext, sheet, cell string, token efp.Token, opdStack, optStack *Stack) error {
 	// parse reference: must reference at here
 	if token.TSubType == efp.TokenSubTypeRange {
-		refTo := f.getDefinedNameRefTo(token.TValue, sheet)
-		if refTo != "" {
-			token.TValue = refTo
-		}
-		result, err := f.parseReference(ctx, sheet, token.TValue)
+		result, err := f.parseOperandReference(ctx, sheet, cell, token)
 		if err != nil {
+			if isStructuredReference(token.TValue) {
+				return err
+			}
 			return errors.New(formulaErrorNAME)
 		}
 		token = formulaArgToToken(result)
 	}
 	if isOperatorPrefixToken(token) {
-		if err := f.parseOperatorPrefixToken(optStack, opdStack, token); err != nil {
+		if err := f.parseOperatorPrefixToken(ctx, optStack, opdStack, token); err != nil {
 			return err
 		}
 	}
@@ -1467,7 +2505,7 @@ func (f *File) parseToken(ctx *calcContext, sheet string, token efp.Token, opdSt
 	if isEndParenthesesToken(token) { // )
 		for !isBeginParenthesesToken(optStack.Peek().(efp.Token)) { // != (
 			topOpt := optStack.Peek().(efp.Token)
-			if err := calculate(opdStack, topOpt); err != nil {
+			if err := ctx.calculate(opdStack, topOpt); err != nil {
 				return err
 			}
 			optStack.Pop()
@@ -1477,6 +2515,7 @@ func (f *File) parseToken(ctx *calcContext, sheet string, token efp.Token, opdSt
 	if token.TType == efp.TokenTypeOperatorPostfix && !opdStack.Empty() {
 		topOpd := opdStack.Pop().(formulaArg)
 		opdStack.Push(newNumberFormulaArg(topOpd.Number / 100))
+		ctx.addFormulaStep(FormulaStepOperator, token.TValue, []formulaArg{topOpd}, opdStack.Peek().(formulaArg))
 	}
 	// opd
 	if isOperand(token) {
@@ -1540,14 +2579,30 @@ func (cr *cellRange) prepareCellRange(col, row bool, cellRef cellRef) error {
 // parseReference parse reference and extract values by given reference
 // characters and default sheet name.
 func (f *File) parseReference(ctx *calcContext, sheet, reference string) (formulaArg, error) {
+	cr, isRange, err := parseReferenceRange(sheet, reference)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorNAME, err.Error()), err
+	}
+	cellRanges, cellRefs := list.New(), list.New()
+	if isRange {
+		cellRanges.PushBack(cr)
+	} else {
+		cellRefs.PushBack(cr.From)
+	}
+	return f.rangeResolver(ctx, cellRefs, cellRanges)
+}
+
+// parseReferenceRange parse the cell reference or the range reference by
+// given reference characters and default sheet name, and returns the cell
+// range and whether the reference is a range reference.
+func parseReferenceRange(sheet, reference string) (cellRange, bool, error) {
+	var cr cellRange
 	reference = strings.ReplaceAll(reference, "$", "")
-	ranges, cellRanges, cellRefs := strings.Split(reference, ":"), list.New(), list.New()
-	if len(ranges) > 1 {
-		var cr cellRange
+	if ranges := strings.Split(reference, ":"); len(ranges) > 1 {
 		for i, ref := range ranges {
 			cellRef, col, row, err := parseRef(ref)
 			if err != nil {
-				return newErrorFormulaArg(formulaErrorNAME, "invalid reference"), errors.New("invalid reference")
+				return cr, true, errors.New("invalid reference")
 			}
 			if i == 0 {
 				if col {
@@ -1563,21 +2618,40 @@ func (f *File) parseReference(ctx *calcContext, sheet, reference string) (formul
 				continue
 			}
 			if err := cr.prepareCellRange(col, row, cellRef); err != nil {
-				return newErrorFormulaArg(formulaErrorNAME, err.Error()), err
+				return cr, true, err
 			}
 		}
-		cellRanges.PushBack(cr)
-		return f.rangeResolver(ctx, cellRefs, cellRanges)
+		return cr, true, nil
 	}
 	cellRef, _, _, err := parseRef(reference)
 	if err != nil {
-		return newErrorFormulaArg(formulaErrorNAME, "invalid reference"), errors.New("invalid reference")
+		return cr, false, errors.New("invalid reference")
 	}
 	if cellRef.Sheet == "" {
 		cellRef.Sheet = sheet
 	}
-	cellRefs.PushBack(cellRef)
-	return f.rangeResolver(ctx, cellRefs, cellRanges)
+	cr.From, cr.To = cellRef, cellRef
+	return cr, false, nil
+}
+
+// mergeStructuredReferenceTokens merge the tokens of the structured
+// reference which split by the formula parser. For example, the parser splits
+// the Table1[[#Headers],[Column1]] into "Table1[[#Headers]", "," and
+// "[Column1]]".
+func mergeStructuredReferenceTokens(tokens []efp.Token) []efp.Token {
+	var merged []efp.Token
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token.TType == efp.TokenTypeOperand && token.TSubType == efp.TokenSubTypeRange {
+			depth := strings.Count(token.TValue, "[") - strings.Count(token.TValue, "]")
+			for ; depth > 0 && i+1 < len(tokens); i++ {
+				token.TValue += tokens[i+1].TValue
+				depth += strings.Count(tokens[i+1].TValue, "[") - strings.Count(tokens[i+1].TValue, "]")
+			}
+		}
+		merged = append(merged, token)
+	}
+	return merged
 }
 
 // prepareValueRange prepare value range.
@@ -1622,14 +2696,27 @@ func (f *File) cellResolver(ctx *calcContext, sheet, cell string) (formulaArg, e
 	ref := fmt.Sprintf("%s!%s", sheet, cell)
 	if formula, _ := f.getCellFormula(sheet, cell, true); len(formula) != 0 {
 		ctx.mu.Lock()
+		if cached, ok := ctx.valueCache[ref]; ok {
+			ctx.mu.Unlock()
+			return cached, nil
+		}
+		if ctx.entry == ref {
+			ctx.circular = true
+		}
 		if ctx.entry != ref {
 			if ctx.iterations[ref] <= f.options.MaxCalcIterations {
 				ctx.iterations[ref]++
 				ctx.mu.Unlock()
 				arg, _ = f.calcCellValue(ctx, sheet, cell)
+				ctx.mu.Lock()
 				ctx.iterationsCache[ref] = arg
+				if ctx.valueCache != nil {
+					ctx.valueCache[ref] = arg
+				}
+				ctx.mu.Unlock()
 				return arg, nil
 			}
+			ctx.circular = true
 			ctx.mu.Unlock()
 			return ctx.iterationsCache[ref], nil
 		}
@@ -1743,6 +2830,165 @@ func callFuncByName(receiver interface{}, name string, params []reflect.Value) (
 	return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("not support %s function", name))
 }
 
+// RegisterFormulaFunc provides a function to register the user-defined formula
+// function by given function name, the minimum and maximum number of
+// arguments and the callback function. Set the maximum number of arguments to
+// -1 for unlimited arguments. The registered function takes precedence over
+// the built-in formula function with the same name, so it can be used to
+// override the built-in formula functions. The function name with the _xludf.
+// prefix in the formula refers to the registered function. The results of the
+// formulas which using the registered functions will not be cached by the
+// calculation cache. For example, register the FXRATE function which requires
+// 2 arguments:
+//
+//	err := f.RegisterFormulaFunc("FXRATE", 2, 2, func(args []excelize.FormulaArg) excelize.FormulaArg {
+//	    if args[0].String == "USD" && args[1].String == "EUR" {
+//	        return excelize.FormulaArg{Type: excelize.ArgNumber, Number: 0.92}
+//	    }
+//	    return excelize.FormulaArg{Type: excelize.ArgError, Error: "#N/A"}
+//	})
+func (f *File) RegisterFormulaFunc(name string, minArgs, maxArgs int, fn func(args []FormulaArg) FormulaArg) error {
+	if fn == nil || minArgs < 0 || (maxArgs != -1 && maxArgs < minArgs) {
+		return ErrParameterInvalid
+	}
+	normalized := normalizeFormulaFuncName(name)
+	if normalized == "" {
+		return newInvalidNameError(name)
+	}
+	for i, r := range normalized {
+		if (r < 'A' || r > 'Z') && r != '_' && (i == 0 || (r < '0' || r > '9') && r != '.') {
+			return newInvalidNameError(name)
+		}
+	}
+	f.userFormulaFuncs.Store(normalized, &userFormulaFunc{name: normalized, minArgs: minArgs, maxArgs: maxArgs, fn: fn})
+	f.clearCalcCache()
+	return nil
+}
+
+// UnregisterFormulaFunc provides a function to remove the user-defined formula
+// function which registered by the RegisterFormulaFunc function by given
+// function name.
+func (f *File) UnregisterFormulaFunc(name string) {
+	f.userFormulaFuncs.Delete(normalizeFormulaFuncName(name))
+	f.clearCalcCache()
+}
+
+// normalizeFormulaFuncName returns the upper case formula function name
+// without the prefix, such as _xludf. and _xlfn.
+func normalizeFormulaFuncName(name string) string {
+	name = strings.ToUpper(name)
+	for _, prefix := range []string{"_XLUDF.", "_XLFN.", "_XLWS."} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// getUserFormulaFunc returns the user-defined formula function by given
+// function name.
+func (f *File) getUserFormulaFunc(name string) (*userFormulaFunc, bool) {
+	udf, ok := f.userFormulaFuncs.Load(normalizeFormulaFuncName(name))
+	if !ok {
+		return nil, ok
+	}
+	return udf.(*userFormulaFunc), ok
+}
+
+// call the user-defined formula function by given arguments list.
+func (udf *userFormulaFunc) call(argsList *list.List) formulaArg {
+	plural := func(n int) string {
+		if n == 1 {
+			return "argument"
+		}
+		return "arguments"
+	}
+	if argsList.Len() < udf.minArgs {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires at least %d %s", udf.name, udf.minArgs, plural(udf.minArgs)))
+	}
+	if udf.maxArgs != -1 && argsList.Len() > udf.maxArgs {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s allows at most %d %s", udf.name, udf.maxArgs, plural(udf.maxArgs)))
+	}
+	args := make([]FormulaArg, 0, argsList.Len())
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		args = append(args, exportFormulaArg(arg.Value.(formulaArg)))
+	}
+	return importFormulaArg(udf.fn(args))
+}
+
+// exportFormulaArg converts the formula argument to the argument of the
+// user-defined formula function.
+func exportFormulaArg(arg formulaArg) FormulaArg {
+	switch arg.Type {
+	case ArgNumber:
+		return FormulaArg{Type: ArgNumber, Number: arg.Number, Boolean: arg.Boolean}
+	case ArgString:
+		return FormulaArg{Type: ArgString, String: arg.String}
+	case ArgError:
+		return FormulaArg{Type: ArgError, Error: arg.String}
+	case ArgList:
+		return exportFormulaArg(newMatrixFormulaArg([][]formulaArg{arg.List}))
+	case ArgMatrix:
+		matrix := make([][]FormulaArg, len(arg.Matrix))
+		for r, row := range arg.Matrix {
+			matrix[r] = make([]FormulaArg, len(row))
+			for c, cell := range row {
+				matrix[r][c] = exportFormulaArg(cell)
+			}
+		}
+		return FormulaArg{Type: ArgMatrix, Matrix: matrix}
+	}
+	return FormulaArg{Type: arg.Type}
+}
+
+// importFormulaArg converts the result of the user-defined formula function
+// to the formula argument.
+func importFormulaArg(arg FormulaArg) formulaArg {
+	switch arg.Type {
+	case ArgNumber:
+		if arg.Boolean {
+			return newBoolFormulaArg(arg.Number != 0)
+		}
+		return newNumberFormulaArg(arg.Number)
+	case ArgString:
+		return newStringFormulaArg(arg.String)
+	case ArgError:
+		if arg.Error == "" {
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		return newErrorFormulaArg(arg.Error, arg.Error)
+	case ArgMatrix:
+		matrix := make([][]formulaArg, len(arg.Matrix))
+		for r, row := range arg.Matrix {
+			matrix[r] = make([]formulaArg, len(row))
+			for c, cell := range row {
+				matrix[r][c] = importFormulaArg(cell)
+			}
+		}
+		return newMatrixFormulaArg(matrix)
+	case ArgEmpty:
+		return newEmptyFormulaArg()
+	}
+	return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+}
+
+// toNumberArgs converts the formula function arguments to numbers, the
+// omitted and empty arguments will be replaced by the given default values.
+func toNumberArgs(argsList *list.List, defaults ...float64) ([]float64, formulaArg) {
+	args := make([]float64, len(defaults))
+	copy(args, defaults)
+	i := 0
+	for arg := argsList.Front(); arg != nil && i < len(args); arg = arg.Next() {
+		if token := arg.Value.(formulaArg); token.Type != ArgEmpty && token.Value() != "" {
+			num := token.ToNumber()
+			if num.Type != ArgNumber {
+				return args, newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			args[i] = num.Number
+		}
+		i++
+	}
+	return args, newEmptyFormulaArg()
+}
+
 // formulaCriteriaParser parse formula criteria.
 func formulaCriteriaParser(exp formulaArg) *formulaCriteria {
 	prepareValue := func(cond string) (expected float64, err error) {
@@ -5144,6 +6390,48 @@ func (fn *formulaFuncs) RAND(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(rand.New(rand.NewSource(time.Now().UnixNano())).Float64())
 }
 
+// RANDARRAY function returns an array of random numbers. The user can specify
+// the number of rows and columns to fill, minimum and maximum values, and
+// whether to return whole numbers or decimal values. The syntax of the
+// function is:
+//
+//	RANDARRAY([rows],[columns],[min],[max],[whole_number])
+func (fn *formulaFuncs) RANDARRAY(argsList *list.List) formulaArg {
+	if argsList.Len() > 5 {
+		return newErrorFormulaArg(formulaErrorVALUE, "RANDARRAY allows at most 5 arguments")
+	}
+	args, errArg := toNumberArgs(argsList, 1, 1, 0, 1, 0)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	if isArraySizeExceeded(args[0], args[1]) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	rows, cols, minVal, maxVal, whole := int(args[0]), int(args[1]), args[2], args[3], args[4] != 0
+	if rows < 0 || cols < 0 || minVal > maxVal {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if rows == 0 || cols == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if whole && (minVal != math.Trunc(minVal) || maxVal != math.Trunc(maxVal)) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	mtx := make([][]formulaArg, rows)
+	for i := range mtx {
+		mtx[i] = make([]formulaArg, cols)
+		for j := range mtx[i] {
+			if whole {
+				mtx[i][j] = newNumberFormulaArg(minVal + float64(r.Int63n(int64(maxVal-minVal+1))))
+				continue
+			}
+			mtx[i][j] = newNumberFormulaArg(minVal + r.Float64()*(maxVal-minVal))
+		}
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
 // RANDBETWEEN function generates a random integer between two supplied
 // integers. The syntax of the function is:
 //
@@ -5455,6 +6743,41 @@ func (fn *formulaFuncs) SECH(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(1 / math.Cosh(number.Number))
 }
 
+// SEQUENCE function generates a list of sequential numbers in an array, such
+// as 1, 2, 3, 4. The syntax of the function is:
+//
+//	SEQUENCE(rows,[columns],[start],[step])
+func (fn *formulaFuncs) SEQUENCE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SEQUENCE requires at least 1 argument")
+	}
+	if argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SEQUENCE allows at most 4 arguments")
+	}
+	args, errArg := toNumberArgs(argsList, 1, 1, 1, 1)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	if isArraySizeExceeded(args[0], args[1]) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	rows, cols, start, step := int(args[0]), int(args[1]), args[2], args[3]
+	if rows < 0 || cols < 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if rows == 0 || cols == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	mtx := make([][]formulaArg, rows)
+	for i := range mtx {
+		mtx[i] = make([]formulaArg, cols)
+		for j := range mtx[i] {
+			mtx[i][j] = newNumberFormulaArg(start + float64(i*cols+j)*step)
+		}
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
 // SERIESSUM function returns the sum of a power series. The syntax of the
 // function is:
 //
@@ -11409,6 +12732,128 @@ func (fn *formulaFuncs) ZTEST(argsList *list.List) formulaArg {
 
 // Information Functions
 
+// getCellFormatCode returns the format code of the formula function CELL by
+// given style.
+func getCellFormatCode(style *Style) string {
+	formats := map[int]string{
+		0: "G", 1: "F0", 2: "F2", 3: ",0", 4: ",2", 5: "C0", 6: "C0-", 7: "C2",
+		8: "C2-", 9: "P0", 10: "P2", 11: "S2", 12: "G", 13: "G", 14: "D4",
+		15: "D1", 16: "D2", 17: "D3", 18: "D7", 19: "D6", 20: "D9", 21: "D8",
+		22: "D4", 37: ",0", 38: ",0-", 39: ",2", 40: ",2-", 45: "D9", 46: "D9",
+		47: "D8", 48: "S2", 49: "G",
+	}
+	if style == nil || style.CustomNumFmt != nil {
+		return "G"
+	}
+	if format, ok := formats[style.NumFmt]; ok {
+		return format
+	}
+	return "G"
+}
+
+// CELL function returns information about the formatting, location, or
+// contents of a cell. The syntax of the function is:
+//
+//	CELL(info_type,[reference])
+func (fn *formulaFuncs) CELL(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "CELL requires at least 1 argument")
+	}
+	if argsList.Len() > 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "CELL allows at most 2 arguments")
+	}
+	sheet, cell := fn.sheet, fn.cell
+	if argsList.Len() == 2 {
+		arg := argsList.Back().Value.(formulaArg)
+		var ref cellRef
+		if arg.cellRanges != nil && arg.cellRanges.Len() > 0 {
+			ref = arg.cellRanges.Front().Value.(cellRange).From
+		} else if arg.cellRefs != nil && arg.cellRefs.Len() > 0 {
+			ref = arg.cellRefs.Front().Value.(cellRef)
+		} else {
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		if ref.Sheet != "" {
+			sheet = ref.Sheet
+		}
+		cell, _ = CoordinatesToCellName(ref.Col, ref.Row)
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+	}
+	styleIdx, _ := fn.f.GetCellStyle(sheet, cell)
+	style, _ := fn.f.GetStyle(styleIdx)
+	switch strings.ToLower(argsList.Front().Value.(formulaArg).Value()) {
+	case "address":
+		address, _ := CoordinatesToCellName(col, row, true)
+		if sheet != fn.sheet {
+			address = fmt.Sprintf("%s!%s", sheet, address)
+		}
+		return newStringFormulaArg(address)
+	case "col":
+		return newNumberFormulaArg(float64(col))
+	case "color":
+		if style != nil && (style.NegRed || (style.CustomNumFmt != nil && strings.Contains(strings.ToLower(*style.CustomNumFmt), "[red]"))) {
+			return newNumberFormulaArg(1)
+		}
+		return newNumberFormulaArg(0)
+	case "contents":
+		value, err := fn.f.cellResolver(fn.ctx, sheet, cell)
+		if err != nil {
+			return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+		}
+		return value
+	case "filename":
+		if fn.f.Path == "" {
+			return newStringFormulaArg("")
+		}
+		dir, name := filepath.Split(fn.f.Path)
+		return newStringFormulaArg(fmt.Sprintf("%s[%s]%s", dir, name, sheet))
+	case "format":
+		return newStringFormulaArg(getCellFormatCode(style))
+	case "parentheses":
+		if style != nil && style.CustomNumFmt != nil && strings.Contains(*style.CustomNumFmt, "(") {
+			return newNumberFormulaArg(1)
+		}
+		return newNumberFormulaArg(0)
+	case "prefix":
+		if cellType, _ := fn.f.GetCellType(sheet, cell); cellType != CellTypeSharedString && cellType != CellTypeInlineString {
+			return newStringFormulaArg("")
+		}
+		if style != nil && style.Alignment != nil {
+			if prefix, ok := map[string]string{"left": "'", "right": "\"", "center": "^", "fill": "\\"}[style.Alignment.Horizontal]; ok {
+				return newStringFormulaArg(prefix)
+			}
+		}
+		return newStringFormulaArg("'")
+	case "protect":
+		if style != nil && style.Protection != nil && !style.Protection.Locked {
+			return newNumberFormulaArg(0)
+		}
+		return newNumberFormulaArg(1)
+	case "row":
+		return newNumberFormulaArg(float64(row))
+	case "type":
+		value, err := fn.f.cellResolver(fn.ctx, sheet, cell)
+		if err != nil {
+			return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+		}
+		if value.Type == ArgEmpty {
+			return newStringFormulaArg("b")
+		}
+		if value.Type == ArgString {
+			return newStringFormulaArg("l")
+		}
+		return newStringFormulaArg("v")
+	case "width":
+		colName, _ := ColumnNumberToName(col)
+		width, _ := fn.f.GetColWidth(sheet, colName)
+		return newNumberFormulaArg(math.Trunc((width*7 - 5) / 7))
+	}
+	return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+}
+
 // ERRORdotTYPE function receives an error value and returns an integer, that
 // tells you the type of the supplied error. The syntax of the function is:
 //
@@ -11431,6 +12876,54 @@ func (fn *formulaFuncs) ERRORdotTYPE(argsList *list.List) formulaArg {
 	return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
 }
 
+// INFO function returns information about the current operating environment.
+// The syntax of the function is:
+//
+//	INFO(type_text)
+func (fn *formulaFuncs) INFO(argsList *list.List) formulaArg {
+	if argsList.Len() != 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "INFO requires 1 argument")
+	}
+	switch strings.ToLower(argsList.Front().Value.(formulaArg).Value()) {
+	case "directory":
+		if fn.f.Path == "" {
+			return newStringFormulaArg("")
+		}
+		dir, _ := filepath.Split(fn.f.Path)
+		return newStringFormulaArg(dir)
+	case "numfile":
+		return newNumberFormulaArg(float64(len(fn.f.GetSheetList())))
+	case "origin":
+		topLeftCell := "A1"
+		if opts, err := fn.f.GetSheetView(fn.sheet, -1); err == nil && opts.TopLeftCell != nil && *opts.TopLeftCell != "" {
+			topLeftCell = *opts.TopLeftCell
+		}
+		col, row, err := CellNameToCoordinates(topLeftCell)
+		if err != nil {
+			return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+		}
+		cell, _ := CoordinatesToCellName(col, row, true)
+		return newStringFormulaArg("$A:" + cell)
+	case "osversion":
+		return newStringFormulaArg(fmt.Sprintf("%s (%s)", runtime.GOOS, runtime.GOARCH))
+	case "recalc":
+		return newStringFormulaArg("Automatic")
+	case "release":
+		if props, err := fn.f.GetAppProps(); err == nil && props.AppVersion != "" {
+			return newStringFormulaArg(props.AppVersion)
+		}
+		return newStringFormulaArg("16.0")
+	case "system":
+		if runtime.GOOS == "darwin" {
+			return newStringFormulaArg("mac")
+		}
+		return newStringFormulaArg("pcdos")
+	case "memavail", "memused", "totmem":
+		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	}
+	return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+}
+
 // ISBLANK function tests if a specified cell is blank (empty) and if so,
 // returns TRUE; Otherwise the function returns FALSE. The syntax of the
 // function is:
@@ -11845,6 +13338,74 @@ func (fn *formulaFuncs) AND(argsList *list.List) formulaArg {
 	return newBoolFormulaArg(and)
 }
 
+// getLambdaArg returns the LAMBDA function by given formula function argument.
+func getLambdaArg(arg formulaArg, name string, params int) (*calcLambda, formulaArg) {
+	if arg.lambda == nil {
+		return nil, newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires a LAMBDA function as the last argument", name))
+	}
+	if len(arg.lambda.params) != params {
+		return nil, newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires a LAMBDA function with %d parameters", name, params))
+	}
+	return arg.lambda, newEmptyFormulaArg()
+}
+
+// callLambdaScalar call the LAMBDA function with given arguments, and returns
+// a #CALC! error if the result is not a single value.
+func (fn *formulaFuncs) callLambdaScalar(lambda *calcLambda, args ...formulaArg) formulaArg {
+	result := fn.f.callLambda(fn.ctx, fn.sheet, fn.cell, lambda, args)
+	if result.Type != ArgMatrix && result.Type != ArgList {
+		return result
+	}
+	if mtx := formulaArgToMatrix(result); len(mtx) == 1 && len(mtx[0]) == 1 {
+		return mtx[0][0]
+	}
+	return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+}
+
+// BYCOL function applies a LAMBDA function to each column and returns an
+// array of the results. The syntax of the function is:
+//
+//	BYCOL(array,lambda)
+func (fn *formulaFuncs) BYCOL(argsList *list.List) formulaArg {
+	if argsList.Len() != 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "BYCOL requires 2 arguments")
+	}
+	lambda, errArg := getLambdaArg(argsList.Back().Value.(formulaArg), "BYCOL", 1)
+	if lambda == nil {
+		return errArg
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	row := make([]formulaArg, len(mtx[0]))
+	for c := range row {
+		col := make([][]formulaArg, len(mtx))
+		for r := range mtx {
+			col[r] = []formulaArg{mtx[r][c]}
+		}
+		row[c] = fn.callLambdaScalar(lambda, newMatrixFormulaArg(col))
+	}
+	return newMatrixFormulaArg([][]formulaArg{row})
+}
+
+// BYROW function applies a LAMBDA function to each row and returns an array
+// of the results. The syntax of the function is:
+//
+//	BYROW(array,lambda)
+func (fn *formulaFuncs) BYROW(argsList *list.List) formulaArg {
+	if argsList.Len() != 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "BYROW requires 2 arguments")
+	}
+	lambda, errArg := getLambdaArg(argsList.Back().Value.(formulaArg), "BYROW", 1)
+	if lambda == nil {
+		return errArg
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	result := make([][]formulaArg, len(mtx))
+	for r, row := range mtx {
+		result[r] = []formulaArg{fn.callLambdaScalar(lambda, newMatrixFormulaArg([][]formulaArg{row}))}
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // FALSE function returns the logical value FALSE. The syntax of the
 // function is:
 //
@@ -11910,6 +13471,70 @@ func (fn *formulaFuncs) IFS(argsList *list.List) formulaArg {
 	return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
 }
 
+// MAKEARRAY function returns a calculated array of a specified row and
+// column size, by applying a LAMBDA function. The syntax of the function is:
+//
+//	MAKEARRAY(rows,cols,lambda)
+func (fn *formulaFuncs) MAKEARRAY(argsList *list.List) formulaArg {
+	if argsList.Len() != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "MAKEARRAY requires 3 arguments")
+	}
+	lambda, errArg := getLambdaArg(argsList.Back().Value.(formulaArg), "MAKEARRAY", 2)
+	if lambda == nil {
+		return errArg
+	}
+	args, errArg := toNumberArgs(argsList, 0, 0)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	rows, cols := int(args[0]), int(args[1])
+	if rows < 1 || cols < 1 || isArraySizeExceeded(args[0], args[1]) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	result := make([][]formulaArg, rows)
+	for r := range result {
+		result[r] = make([]formulaArg, cols)
+		for c := range result[r] {
+			result[r][c] = fn.callLambdaScalar(lambda, newNumberFormulaArg(float64(r+1)), newNumberFormulaArg(float64(c+1)))
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// MAP function returns an array formed by mapping each value in the arrays
+// to a new value by applying a LAMBDA function. The syntax of the function
+// is:
+//
+//	MAP(array1,[array2,...],lambda)
+func (fn *formulaFuncs) MAP(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "MAP requires at least 2 arguments")
+	}
+	lambda, errArg := getLambdaArg(argsList.Back().Value.(formulaArg), "MAP", argsList.Len()-1)
+	if lambda == nil {
+		return errArg
+	}
+	var arrays [][][]formulaArg
+	for arg := argsList.Front(); arg != argsList.Back(); arg = arg.Next() {
+		arrays = append(arrays, formulaArgToMatrix(arg.Value.(formulaArg)))
+	}
+	result := make([][]formulaArg, len(arrays[0]))
+	for r := range result {
+		result[r] = make([]formulaArg, len(arrays[0][r]))
+		for c := range result[r] {
+			args := make([]formulaArg, len(arrays))
+			for i, mtx := range arrays {
+				var ok bool
+				if args[i], ok = getMatrixCell(mtx, r, c); !ok {
+					args[i] = newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+				}
+			}
+			result[r][c] = fn.callLambdaScalar(lambda, args...)
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // NOT function returns the opposite to a supplied logical value. The syntax
 // of the function is:
 //
@@ -11976,6 +13601,54 @@ func (fn *formulaFuncs) OR(argsList *list.List) formulaArg {
 	return newBoolFormulaArg(or)
 }
 
+// REDUCE function reduces an array to an accumulated value by applying a
+// LAMBDA function to each value and returning the total value in the
+// accumulator. The syntax of the function is:
+//
+//	REDUCE([initial_value],array,lambda)
+func (fn *formulaFuncs) REDUCE(argsList *list.List) formulaArg {
+	if argsList.Len() != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "REDUCE requires 3 arguments")
+	}
+	lambda, errArg := getLambdaArg(argsList.Back().Value.(formulaArg), "REDUCE", 2)
+	if lambda == nil {
+		return errArg
+	}
+	acc := argsList.Front().Value.(formulaArg)
+	for _, row := range formulaArgToMatrix(argsList.Front().Next().Value.(formulaArg)) {
+		for _, cell := range row {
+			acc = fn.f.callLambda(fn.ctx, fn.sheet, fn.cell, lambda, []formulaArg{acc, cell})
+		}
+	}
+	return acc
+}
+
+// SCAN function scans an array by applying a LAMBDA function to each value
+// and returns an array that has each intermediate value. The syntax of the
+// function is:
+//
+//	SCAN([initial_value],array,lambda)
+func (fn *formulaFuncs) SCAN(argsList *list.List) formulaArg {
+	if argsList.Len() != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SCAN requires 3 arguments")
+	}
+	lambda, errArg := getLambdaArg(argsList.Back().Value.(formulaArg), "SCAN", 2)
+	if lambda == nil {
+		return errArg
+	}
+	acc := argsList.Front().Value.(formulaArg)
+	mtx := formulaArgToMatrix(argsList.Front().Next().Value.(formulaArg))
+	result := make([][]formulaArg, len(mtx))
+	for r, row := range mtx {
+		result[r] = make([]formulaArg, len(row))
+		for c, cell := range row {
+			acc = fn.callLambdaScalar(lambda, acc, cell)
+			result[r][c] = acc
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // SWITCH function compares a number of supplied values to a supplied test
 // expression and returns a result corresponding to the first value that
 // matches the test expression. A default value can be supplied, to be
@@ -13965,19 +15638,71 @@ func (fn *formulaFuncs) mid(name string, argsList *list.List) formulaArg {
 				}
 			}
 		}
-		return newStringFormulaArg(result)
+		return newStringFormulaArg(result)
+	}
+	// MID
+	textLen := utf8.RuneCountInString(text)
+	if startNum > textLen {
+		return newStringFormulaArg("")
+	}
+	startNum--
+	endNum := startNum + int(numCharsArg.Number)
+	if endNum > textLen+1 {
+		return newStringFormulaArg(string([]rune(text)[startNum:]))
+	}
+	return newStringFormulaArg(string([]rune(text)[startNum:endNum]))
+}
+
+// NUMBERVALUE function converts text to a number, in a locale-independent
+// way. The syntax of the function is:
+//
+//	NUMBERVALUE(text,[decimal_separator],[group_separator])
+func (fn *formulaFuncs) NUMBERVALUE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "NUMBERVALUE requires at least 1 argument")
+	}
+	if argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "NUMBERVALUE allows at most 3 arguments")
+	}
+	separators := []string{".", ","}
+	for arg, i := argsList.Front().Next(), 0; arg != nil; arg, i = arg.Next(), i+1 {
+		token := arg.Value.(formulaArg)
+		if token.Type == ArgError {
+			return token
+		}
+		if sep := []rune(token.Value()); len(sep) > 0 {
+			separators[i] = string(sep[0])
+		}
 	}
-	// MID
-	textLen := utf8.RuneCountInString(text)
-	if startNum > textLen {
-		return newStringFormulaArg("")
+	decimal, group := separators[0], separators[1]
+	if decimal == group {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
 	}
-	startNum--
-	endNum := startNum + int(numCharsArg.Number)
-	if endNum > textLen+1 {
-		return newStringFormulaArg(string([]rune(text)[startNum:]))
+	token := argsList.Front().Value.(formulaArg)
+	if token.Type == ArgError {
+		return token
 	}
-	return newStringFormulaArg(string([]rune(text)[startNum:endNum]))
+	text := strings.Join(strings.Fields(token.Value()), "")
+	var percent int
+	for ; strings.HasSuffix(text, "%"); percent++ {
+		text = strings.TrimSuffix(text, "%")
+	}
+	if text == "" {
+		return newNumberFormulaArg(0)
+	}
+	integer, fraction, found := strings.Cut(text, decimal)
+	if found && (strings.Contains(fraction, decimal) || strings.Contains(fraction, group)) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	text = strings.ReplaceAll(integer, group, "")
+	if found {
+		text += "." + fraction
+	}
+	num, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	return newNumberFormulaArg(num / math.Pow(100, float64(percent)))
 }
 
 // PROPER converts all characters in a supplied text string to proper case
@@ -14392,6 +16117,121 @@ func textJoin(arg *list.Element, arr []string, ignoreEmpty bool) ([]string, form
 	return arr, newBoolFormulaArg(true)
 }
 
+// splitTextByDelimiters splits the text by any of the given delimiters, the
+// longest delimiter takes precedence when several delimiters match at the
+// same position.
+func splitTextByDelimiters(text string, delimiters []string, ignoreCase bool) []string {
+	if len(delimiters) == 0 {
+		return []string{text}
+	}
+	var parts []string
+	start := 0
+	for i := 0; i < len(text); {
+		var matched int
+		for _, delimiter := range delimiters {
+			if i+len(delimiter) > len(text) || len(delimiter) <= matched {
+				continue
+			}
+			if text[i:i+len(delimiter)] == delimiter || (ignoreCase && strings.EqualFold(text[i:i+len(delimiter)], delimiter)) {
+				matched = len(delimiter)
+			}
+		}
+		if matched > 0 {
+			parts = append(parts, text[start:i])
+			i += matched
+			start = i
+			continue
+		}
+		i++
+	}
+	return append(parts, text[start:])
+}
+
+// getTextSplitDelimiters returns the non-empty delimiters by given delimiter
+// argument of the formula function TEXTSPLIT.
+func getTextSplitDelimiters(arg formulaArg) ([]string, formulaArg) {
+	var delimiters []string
+	for _, row := range formulaArgToMatrix(arg) {
+		for _, cell := range row {
+			if cell.Type == ArgError {
+				return nil, cell
+			}
+			if cell.Value() != "" {
+				delimiters = append(delimiters, cell.Value())
+			}
+		}
+	}
+	return delimiters, newEmptyFormulaArg()
+}
+
+// TEXTSPLIT function splits text strings by using column and row
+// delimiters. The syntax of the function is:
+//
+//	TEXTSPLIT(text,col_delimiter,[row_delimiter],[ignore_empty],[match_mode],[pad_with])
+func (fn *formulaFuncs) TEXTSPLIT(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "TEXTSPLIT requires at least 2 arguments")
+	}
+	if argsList.Len() > 6 {
+		return newErrorFormulaArg(formulaErrorVALUE, "TEXTSPLIT allows at most 6 arguments")
+	}
+	args := make([]formulaArg, 6)
+	for arg, i := argsList.Front(), 0; arg != nil; arg, i = arg.Next(), i+1 {
+		args[i] = arg.Value.(formulaArg)
+	}
+	if args[0].Type == ArgError {
+		return args[0]
+	}
+	colDelimiters, errArg := getTextSplitDelimiters(args[1])
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	rowDelimiters, errArg := getTextSplitDelimiters(args[2])
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	if len(colDelimiters) == 0 && len(rowDelimiters) == 0 {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	opts := list.New()
+	opts.PushBack(args[3])
+	opts.PushBack(args[4])
+	nums, errArg := toNumberArgs(opts, 0, 0)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	ignoreEmpty, ignoreCase := nums[0] != 0, nums[1] == 1
+	if nums[1] != 0 && nums[1] != 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	pad := newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	if argsList.Len() == 6 {
+		pad = args[5]
+	}
+	var result [][]formulaArg
+	var cols int
+	for _, line := range splitTextByDelimiters(args[0].Value(), rowDelimiters, ignoreCase) {
+		if ignoreEmpty && line == "" {
+			continue
+		}
+		var row []formulaArg
+		for _, part := range splitTextByDelimiters(line, colDelimiters, ignoreCase) {
+			if ignoreEmpty && part == "" {
+				continue
+			}
+			row = append(row, newStringFormulaArg(part))
+		}
+		if len(row) > cols {
+			cols = len(row)
+		}
+		result = append(result, row)
+	}
+	if len(result) == 0 || cols == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	return newMatrixFormulaArg(padMatrix(result, len(result), cols, pad))
+}
+
 // TRIM removes extra spaces (i.e. all spaces except for single spaces between
 // words or characters) from a supplied text string. The syntax of the
 // function is:
@@ -14665,6 +16505,78 @@ func (fn *formulaFuncs) CHOOSE(argsList *list.List) formulaArg {
 	return arg.Value.(formulaArg)
 }
 
+// getChosenIndexes returns the zero-based indexes by given index arguments
+// of the formula functions CHOOSECOLS and CHOOSEROWS, the negative index
+// counts from the end.
+func getChosenIndexes(argsList *list.List, size int) ([]int, formulaArg) {
+	var indexes []int
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		for _, row := range formulaArgToMatrix(arg.Value.(formulaArg)) {
+			for _, cell := range row {
+				num := cell.ToNumber()
+				if num.Type != ArgNumber {
+					return nil, newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+				}
+				idx := int(num.Number)
+				if idx < 0 {
+					idx += size + 1
+				}
+				if idx < 1 || idx > size || int(math.Abs(num.Number)) > size {
+					return nil, newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+				}
+				indexes = append(indexes, idx-1)
+			}
+		}
+	}
+	return indexes, newEmptyFormulaArg()
+}
+
+// CHOOSECOLS function returns the specified columns from an array. The
+// syntax of the function is:
+//
+//	CHOOSECOLS(array,col_num1,[col_num2],...)
+func (fn *formulaFuncs) CHOOSECOLS(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "CHOOSECOLS requires at least 2 arguments")
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	rows, cols := getMatrixSize(mtx)
+	indexes, errArg := getChosenIndexes(argsList, cols)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	mtx = padMatrix(mtx, rows, cols, newEmptyFormulaArg())
+	result := make([][]formulaArg, rows)
+	for r := range result {
+		for _, idx := range indexes {
+			result[r] = append(result[r], mtx[r][idx])
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// CHOOSEROWS function returns the specified rows from an array. The syntax
+// of the function is:
+//
+//	CHOOSEROWS(array,row_num1,[row_num2],...)
+func (fn *formulaFuncs) CHOOSEROWS(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "CHOOSEROWS requires at least 2 arguments")
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	rows, cols := getMatrixSize(mtx)
+	indexes, errArg := getChosenIndexes(argsList, rows)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	mtx = padMatrix(mtx, rows, cols, newEmptyFormulaArg())
+	var result [][]formulaArg
+	for _, idx := range indexes {
+		result = append(result, mtx[idx])
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // matchPatternToRegExp convert find text pattern to regular expression.
 func matchPatternToRegExp(findText string, dbcs bool) (string, bool) {
 	var (
@@ -14878,6 +16790,150 @@ func (fn *formulaFuncs) COLUMNS(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(float64(result))
 }
 
+// getTakeDropArgs returns the array and the number of rows and columns by
+// given arguments of the formula functions TAKE and DROP, the omitted number
+// of rows or columns will be the size of the array if fill is true.
+func getTakeDropArgs(name string, argsList *list.List, fill bool) ([][]formulaArg, []int, formulaArg) {
+	if argsList.Len() < 2 {
+		return nil, nil, newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires at least 2 arguments", name))
+	}
+	if argsList.Len() > 3 {
+		return nil, nil, newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s allows at most 3 arguments", name))
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	rows, cols := getMatrixSize(mtx)
+	opts := list.New()
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		opts.PushBack(arg.Value.(formulaArg))
+	}
+	defaults := []float64{0, 0}
+	if fill {
+		defaults = []float64{float64(rows), float64(cols)}
+	}
+	args, errArg := toNumberArgs(opts, defaults...)
+	if errArg.Type == ArgError {
+		return nil, nil, errArg
+	}
+	return padMatrix(mtx, rows, cols, newEmptyFormulaArg()), []int{int(args[0]), int(args[1])}, newEmptyFormulaArg()
+}
+
+// DROP function excludes a specified number of rows or columns from the
+// start or end of an array. The syntax of the function is:
+//
+//	DROP(array,rows,[columns])
+func (fn *formulaFuncs) DROP(argsList *list.List) formulaArg {
+	mtx, args, errArg := getTakeDropArgs("DROP", argsList, false)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	rows, cols := getMatrixSize(mtx)
+	fromRow, toRow, fromCol, toCol := 0, rows, 0, cols
+	if args[0] >= 0 {
+		fromRow = args[0]
+	} else {
+		toRow = rows + args[0]
+	}
+	if args[1] >= 0 {
+		fromCol = args[1]
+	} else {
+		toCol = cols + args[1]
+	}
+	if fromRow >= toRow || fromCol >= toCol {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	var result [][]formulaArg
+	for _, row := range mtx[fromRow:toRow] {
+		result = append(result, row[fromCol:toCol])
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// EXPAND function expands or pads an array to specified row and column
+// dimensions. The syntax of the function is:
+//
+//	EXPAND(array,rows,[columns],[pad_with])
+func (fn *formulaFuncs) EXPAND(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "EXPAND requires at least 2 arguments")
+	}
+	if argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "EXPAND allows at most 4 arguments")
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	rows, cols := getMatrixSize(mtx)
+	opts := list.New()
+	for arg := argsList.Front().Next(); arg != nil && opts.Len() < 2; arg = arg.Next() {
+		opts.PushBack(arg.Value.(formulaArg))
+	}
+	args, errArg := toNumberArgs(opts, float64(rows), float64(cols))
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	if int(args[0]) < rows || int(args[1]) < cols || isArraySizeExceeded(args[0], args[1]) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	pad := newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	if argsList.Len() == 4 {
+		pad = argsList.Back().Value.(formulaArg)
+	}
+	return newMatrixFormulaArg(padMatrix(padMatrix(mtx, rows, cols, newEmptyFormulaArg()), int(args[0]), int(args[1]), pad))
+}
+
+// FILTER function filters a range or an array of data based on the supplied
+// criteria, and returns the rows or columns that meet the criteria. The
+// syntax of the function is:
+//
+//	FILTER(array,include,[if_empty])
+func (fn *formulaFuncs) FILTER(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FILTER requires at least 2 arguments")
+	}
+	if argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "FILTER allows at most 3 arguments")
+	}
+	array := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	include := formulaArgToMatrix(argsList.Front().Next().Value.(formulaArg))
+	rows, cols := len(array), len(array[0])
+	var byCol bool
+	switch {
+	case len(include) == rows && len(include[0]) == 1:
+	case len(include) == 1 && len(include[0]) == cols:
+		byCol = true
+	default:
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if byCol {
+		array, include = transposeMatrix(array), transposeMatrix(include)
+	}
+	var mtx [][]formulaArg
+	for i, row := range include {
+		cond := row[0]
+		switch cond.Type {
+		case ArgError:
+			return cond
+		case ArgString:
+			if cond.String != "" {
+				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			continue
+		case ArgNumber:
+			if cond.Number != 0 {
+				mtx = append(mtx, array[i])
+			}
+		}
+	}
+	if len(mtx) == 0 {
+		if argsList.Len() == 3 {
+			return argsList.Back().Value.(formulaArg)
+		}
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if byCol {
+		mtx = transposeMatrix(mtx)
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
 // FORMULATEXT function returns a formula as a text string. The syntax of the
 // function is:
 //
@@ -14973,6 +17029,35 @@ func (fn *formulaFuncs) HLOOKUP(argsList *list.List) formulaArg {
 	return newErrorFormulaArg(formulaErrorNA, "HLOOKUP no result found")
 }
 
+// HSTACK function appends arrays horizontally and in sequence to return a
+// larger array, the missing elements will be filled with #N/A error. The
+// syntax of the function is:
+//
+//	HSTACK(array1,[array2],...)
+func (fn *formulaFuncs) HSTACK(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "HSTACK requires at least 1 argument")
+	}
+	var arrays [][][]formulaArg
+	var rows int
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		mtx := formulaArgToMatrix(arg.Value.(formulaArg))
+		if len(mtx) > rows {
+			rows = len(mtx)
+		}
+		arrays = append(arrays, mtx)
+	}
+	result := make([][]formulaArg, rows)
+	for _, mtx := range arrays {
+		_, cols := getMatrixSize(mtx)
+		mtx = padMatrix(padMatrix(mtx, len(mtx), cols, newEmptyFormulaArg()), rows, cols, newErrorFormulaArg(formulaErrorNA, formulaErrorNA))
+		for r, row := range mtx {
+			result[r] = append(result[r], row...)
+		}
+	}
+	return newMatrixFormulaArg(result)
+}
+
 // HYPERLINK function creates a hyperlink to a specified location. The syntax
 // of the function is:
 //
@@ -15026,45 +17111,148 @@ func calcMatch(matchType int, criteria *formulaCriteria, lookupArray []formulaAr
 	if idx == -1 {
 		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
 	}
-	return newNumberFormulaArg(float64(idx + 1))
+	return newNumberFormulaArg(float64(idx + 1))
+}
+
+// MATCH function looks up a value in an array, and returns the position of
+// the value within the array. The user can specify that the function should
+// only return a result if an exact match is found, or that the function
+// should return the position of the closest match (above or below), if an
+// exact match is not found. The syntax of the Match function is:
+//
+//	MATCH(lookup_value,lookup_array,[match_type])
+func (fn *formulaFuncs) MATCH(argsList *list.List) formulaArg {
+	if argsList.Len() != 2 && argsList.Len() != 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "MATCH requires 1 or 2 arguments")
+	}
+	var (
+		matchType      = 1
+		lookupArray    []formulaArg
+		lookupArrayArg = argsList.Front().Next().Value.(formulaArg)
+		lookupArrayErr = "MATCH arguments lookup_array should be one-dimensional array"
+	)
+	if argsList.Len() == 3 {
+		matchTypeArg := argsList.Back().Value.(formulaArg).ToNumber()
+		if matchTypeArg.Type != ArgNumber {
+			return newErrorFormulaArg(formulaErrorVALUE, "MATCH requires numeric match_type argument")
+		}
+		if matchTypeArg.Number == -1 || matchTypeArg.Number == 0 {
+			matchType = int(matchTypeArg.Number)
+		}
+	}
+	switch lookupArrayArg.Type {
+	case ArgMatrix:
+		if len(lookupArrayArg.Matrix) != 1 && len(lookupArrayArg.Matrix[0]) != 1 {
+			return newErrorFormulaArg(formulaErrorNA, lookupArrayErr)
+		}
+		lookupArray = lookupArrayArg.ToList()
+	default:
+		return newErrorFormulaArg(formulaErrorNA, lookupArrayErr)
+	}
+	return calcMatch(matchType, formulaCriteriaParser(argsList.Front().Value.(formulaArg)), lookupArray)
+}
+
+// TAKE function returns a specified number of contiguous rows or columns
+// from the start or end of an array. The syntax of the function is:
+//
+//	TAKE(array,rows,[columns])
+func (fn *formulaFuncs) TAKE(argsList *list.List) formulaArg {
+	mtx, args, errArg := getTakeDropArgs("TAKE", argsList, true)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	if args[0] == 0 || args[1] == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	rows, cols := getMatrixSize(mtx)
+	fromRow, toRow, fromCol, toCol := 0, rows, 0, cols
+	if args[0] > 0 && args[0] < rows {
+		toRow = args[0]
+	}
+	if args[0] < 0 && -args[0] < rows {
+		fromRow = rows + args[0]
+	}
+	if args[1] > 0 && args[1] < cols {
+		toCol = args[1]
+	}
+	if args[1] < 0 && -args[1] < cols {
+		fromCol = cols + args[1]
+	}
+	var result [][]formulaArg
+	for _, row := range mtx[fromRow:toRow] {
+		result = append(result, row[fromCol:toCol])
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// toVector returns the values of the array as a list by given arguments of
+// the formula functions TOCOL and TOROW.
+func toVector(name string, argsList *list.List) ([]formulaArg, formulaArg) {
+	if argsList.Len() < 1 {
+		return nil, newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires at least 1 argument", name))
+	}
+	if argsList.Len() > 3 {
+		return nil, newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s allows at most 3 arguments", name))
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	rows, cols := getMatrixSize(mtx)
+	mtx = padMatrix(mtx, rows, cols, newEmptyFormulaArg())
+	opts := list.New()
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		opts.PushBack(arg.Value.(formulaArg))
+	}
+	args, errArg := toNumberArgs(opts, 0, 0)
+	if errArg.Type == ArgError {
+		return nil, errArg
+	}
+	ignore := int(args[0])
+	if ignore < 0 || ignore > 3 {
+		return nil, newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if args[1] != 0 {
+		mtx = transposeMatrix(mtx)
+	}
+	var vector []formulaArg
+	for _, row := range mtx {
+		for _, cell := range row {
+			if (ignore&1 != 0 && cell.Type == ArgEmpty) || (ignore&2 != 0 && cell.Type == ArgError) {
+				continue
+			}
+			vector = append(vector, cell)
+		}
+	}
+	if len(vector) == 0 {
+		return nil, newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	return vector, newEmptyFormulaArg()
 }
 
-// MATCH function looks up a value in an array, and returns the position of
-// the value within the array. The user can specify that the function should
-// only return a result if an exact match is found, or that the function
-// should return the position of the closest match (above or below), if an
-// exact match is not found. The syntax of the Match function is:
+// TOCOL function returns the array in a single column. The syntax of the
+// function is:
 //
-//	MATCH(lookup_value,lookup_array,[match_type])
-func (fn *formulaFuncs) MATCH(argsList *list.List) formulaArg {
-	if argsList.Len() != 2 && argsList.Len() != 3 {
-		return newErrorFormulaArg(formulaErrorVALUE, "MATCH requires 1 or 2 arguments")
+//	TOCOL(array,[ignore],[scan_by_column])
+func (fn *formulaFuncs) TOCOL(argsList *list.List) formulaArg {
+	vector, errArg := toVector("TOCOL", argsList)
+	if errArg.Type == ArgError {
+		return errArg
 	}
-	var (
-		matchType      = 1
-		lookupArray    []formulaArg
-		lookupArrayArg = argsList.Front().Next().Value.(formulaArg)
-		lookupArrayErr = "MATCH arguments lookup_array should be one-dimensional array"
-	)
-	if argsList.Len() == 3 {
-		matchTypeArg := argsList.Back().Value.(formulaArg).ToNumber()
-		if matchTypeArg.Type != ArgNumber {
-			return newErrorFormulaArg(formulaErrorVALUE, "MATCH requires numeric match_type argument")
-		}
-		if matchTypeArg.Number == -1 || matchTypeArg.Number == 0 {
-			matchType = int(matchTypeArg.Number)
-		}
+	result := make([][]formulaArg, len(vector))
+	for r, cell := range vector {
+		result[r] = []formulaArg{cell}
 	}
-	switch lookupArrayArg.Type {
-	case ArgMatrix:
-		if len(lookupArrayArg.Matrix) != 1 && len(lookupArrayArg.Matrix[0]) != 1 {
-			return newErrorFormulaArg(formulaErrorNA, lookupArrayErr)
-		}
-		lookupArray = lookupArrayArg.ToList()
-	default:
-		return newErrorFormulaArg(formulaErrorNA, lookupArrayErr)
+	return newMatrixFormulaArg(result)
+}
+
+// TOROW function returns the array in a single row. The syntax of the
+// function is:
+//
+//	TOROW(array,[ignore],[scan_by_column])
+func (fn *formulaFuncs) TOROW(argsList *list.List) formulaArg {
+	vector, errArg := toVector("TOROW", argsList)
+	if errArg.Type == ArgError {
+		return errArg
 	}
-	return calcMatch(matchType, formulaCriteriaParser(argsList.Front().Value.(formulaArg)), lookupArray)
+	return newMatrixFormulaArg([][]formulaArg{vector})
 }
 
 // TRANSPOSE function 'transposes' an array of cells (i.e. the function copies
@@ -15098,6 +17286,99 @@ func (fn *formulaFuncs) TRANSPOSE(argsList *list.List) formulaArg {
 	return newMatrixFormulaArg(mtx)
 }
 
+// VSTACK function appends arrays vertically and in sequence to return a
+// larger array, the missing elements will be filled with #N/A error. The
+// syntax of the function is:
+//
+//	VSTACK(array1,[array2],...)
+func (fn *formulaFuncs) VSTACK(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "VSTACK requires at least 1 argument")
+	}
+	var arrays [][][]formulaArg
+	var cols int
+	for arg := argsList.Front(); arg != nil; arg = arg.Next() {
+		mtx := formulaArgToMatrix(arg.Value.(formulaArg))
+		rows, width := getMatrixSize(mtx)
+		if width > cols {
+			cols = width
+		}
+		arrays = append(arrays, padMatrix(mtx, rows, width, newEmptyFormulaArg()))
+	}
+	var result [][]formulaArg
+	for _, mtx := range arrays {
+		result = append(result, padMatrix(mtx, len(mtx), cols, newErrorFormulaArg(formulaErrorNA, formulaErrorNA))...)
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// wrapVector wraps the vector by rows or columns by given arguments of the
+// formula functions WRAPROWS and WRAPCOLS.
+func wrapVector(name string, argsList *list.List, byCol bool) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s requires at least 2 arguments", name))
+	}
+	if argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, fmt.Sprintf("%s allows at most 3 arguments", name))
+	}
+	mtx := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	rows, cols := getMatrixSize(mtx)
+	if rows > 1 && cols > 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	var vector []formulaArg
+	for _, row := range padMatrix(mtx, rows, cols, newEmptyFormulaArg()) {
+		vector = append(vector, row...)
+	}
+	count := argsList.Front().Next().Value.(formulaArg).ToNumber()
+	if count.Type != ArgNumber {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if count.Number < 1 {
+		return newErrorFormulaArg(formulaErrorNUM, formulaErrorNUM)
+	}
+	pad := newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	if argsList.Len() == 3 {
+		pad = argsList.Back().Value.(formulaArg)
+	}
+	size := int(count.Number)
+	resultRows, resultCols := math.Ceil(float64(len(vector))/float64(size)), float64(size)
+	if byCol {
+		resultRows, resultCols = resultCols, resultRows
+	}
+	if isArraySizeExceeded(resultRows, resultCols) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	var result [][]formulaArg
+	for i := 0; i < len(vector); i += size {
+		end := int(math.Min(float64(i+size), float64(len(vector))))
+		result = append(result, vector[i:end:end])
+	}
+	result = padMatrix(result, len(result), size, pad)
+	if byCol {
+		result = transposeMatrix(result)
+	}
+	return newMatrixFormulaArg(result)
+}
+
+// WRAPCOLS function wraps the provided row or column of values by columns
+// after a specified number of elements to form a new array. The syntax of
+// the function is:
+//
+//	WRAPCOLS(vector,wrap_count,[pad_with])
+func (fn *formulaFuncs) WRAPCOLS(argsList *list.List) formulaArg {
+	return wrapVector("WRAPCOLS", argsList, true)
+}
+
+// WRAPROWS function wraps the provided row or column of values by rows after
+// a specified number of elements to form a new array. The syntax of the
+// function is:
+//
+//	WRAPROWS(vector,wrap_count,[pad_with])
+func (fn *formulaFuncs) WRAPROWS(argsList *list.List) formulaArg {
+	return wrapVector("WRAPROWS", argsList, false)
+}
+
 // lookupLinearSearch sequentially checks each look value of the lookup array until
 // a match is found or the whole list has been searched.
 func lookupLinearSearch(vertical bool, lookupValue, lookupArray, matchMode, searchMode formulaArg) (int, bool) {
@@ -15137,6 +17418,61 @@ start:
 	return matchIdx, wasExact
 }
 
+// UNIQUE function returns a list of unique values in a list or range. The
+// syntax of the function is:
+//
+//	UNIQUE(array,[by_col],[exactly_once])
+func (fn *formulaFuncs) UNIQUE(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "UNIQUE requires at least 1 argument")
+	}
+	if argsList.Len() > 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "UNIQUE allows at most 3 arguments")
+	}
+	array := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	opts := list.New()
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		opts.PushBack(arg.Value)
+	}
+	args, errArg := toNumberArgs(opts, 0, 0)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	byCol, exactlyOnce := args[0] != 0, args[1] != 0
+	if byCol {
+		array = transposeMatrix(array)
+	}
+	var (
+		keys   []string
+		counts = map[string]int{}
+		rows   = map[string][]formulaArg{}
+	)
+	for _, row := range array {
+		var key strings.Builder
+		for _, cell := range row {
+			key.WriteString(fmt.Sprintf("%d:%s\x00", sortValueRank(cell), strings.ToLower(cell.Value())))
+		}
+		if _, ok := counts[key.String()]; !ok {
+			keys, rows[key.String()] = append(keys, key.String()), row
+		}
+		counts[key.String()]++
+	}
+	var mtx [][]formulaArg
+	for _, key := range keys {
+		if exactlyOnce && counts[key] != 1 {
+			continue
+		}
+		mtx = append(mtx, rows[key])
+	}
+	if len(mtx) == 0 {
+		return newErrorFormulaArg(formulaErrorCALC, formulaErrorCALC)
+	}
+	if byCol {
+		mtx = transposeMatrix(mtx)
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
 // VLOOKUP function 'looks up' a given value in the left-hand column of a
 // data array (or table), and returns the corresponding value from another
 // column of the array. The syntax of the function is:
@@ -15431,6 +17767,84 @@ func (fn *formulaFuncs) XLOOKUP(argsList *list.List) formulaArg {
 	return fn.xlookup(lookupRows, lookupCols, returnArrayRows, returnArrayCols, matchIdx, condition1, condition2, condition3, condition4, returnArray)
 }
 
+// XMATCH function searches for a specified item in an array or range of
+// cells, and then returns the item's relative position. The syntax of the
+// function is:
+//
+//	XMATCH(lookup_value,lookup_array,[match_mode],[search_mode])
+func (fn *formulaFuncs) XMATCH(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "XMATCH requires at least 2 arguments")
+	}
+	if argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "XMATCH allows at most 4 arguments")
+	}
+	lookupValue := argsList.Front().Value.(formulaArg)
+	lookupArray := newMatrixFormulaArg(formulaArgToMatrix(argsList.Front().Next().Value.(formulaArg)))
+	matchMode, searchMode := newNumberFormulaArg(matchModeExact), newNumberFormulaArg(searchModeLinear)
+	if argsList.Len() > 2 {
+		if matchMode = argsList.Front().Next().Next().Value.(formulaArg).ToNumber(); matchMode.Type != ArgNumber {
+			return matchMode
+		}
+	}
+	if argsList.Len() > 3 {
+		if searchMode = argsList.Back().Value.(formulaArg).ToNumber(); searchMode.Type != ArgNumber {
+			return searchMode
+		}
+	}
+	if !validateMatchMode(matchMode.Number) || !validateSearchMode(searchMode.Number) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	lookupRows, lookupCols := getMatrixSize(lookupArray.Matrix)
+	if lookupRows != 1 && lookupCols != 1 {
+		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	}
+	verticalLookup := lookupRows >= lookupCols
+	var matchIdx int
+	switch {
+	case searchMode.Number == searchModeAscBinary || searchMode.Number == searchModeDescBinary:
+		matchIdx, _ = lookupBinarySearch(verticalLookup, lookupValue, lookupArray, matchMode, searchMode)
+	case matchMode.Number == matchModeMinGreater || matchMode.Number == matchModeMaxLess:
+		matchIdx = xmatchApproximate(lookupValue, lookupArray.ToList(), matchMode, searchMode)
+	default:
+		matchIdx, _ = lookupLinearSearch(verticalLookup, lookupValue, lookupArray, matchMode, searchMode)
+	}
+	if matchIdx == -1 {
+		return newErrorFormulaArg(formulaErrorNA, formulaErrorNA)
+	}
+	return newNumberFormulaArg(float64(matchIdx + 1))
+}
+
+// xmatchApproximate returns the index of the exact match item in the
+// unsorted lookup vector, or the next larger or smaller item by given match
+// mode if not found, for the formula function XMATCH.
+func xmatchApproximate(lookupValue formulaArg, lookupVector []formulaArg, matchMode, searchMode formulaArg) int {
+	if lookupValue.Type == ArgString {
+		if num := lookupValue.ToNumber(); num.Type == ArgNumber {
+			lookupValue = num
+		}
+	}
+	matchIdx, nearest := -1, criteriaG
+	if matchMode.Number == matchModeMaxLess {
+		nearest = criteriaL
+	}
+	for i, cell := range lookupVector {
+		if searchMode.Number == searchModeReverseLinear {
+			i = len(lookupVector) - 1 - i
+			cell = lookupVector[i]
+		}
+		switch compareFormulaArg(cell, lookupValue, matchMode, false) {
+		case criteriaEq:
+			return i
+		case nearest:
+			if matchIdx == -1 || compareFormulaArg(cell, lookupVector[matchIdx], matchMode, false) != nearest {
+				matchIdx = i
+			}
+		}
+	}
+	return matchIdx
+}
+
 // INDEX function returns a reference to a cell that lies in a specified row
 // and column of a range of cells. The syntax of the function is:
 //
@@ -15576,6 +17990,73 @@ func lookupCol(arr formulaArg, idx int) []formulaArg {
 	return col
 }
 
+// OFFSET function returns a reference to a range of cells that is offset
+// from a starting cell or range of cells, by a specified number of rows and
+// columns. The syntax of the function is:
+//
+//	OFFSET(reference,rows,cols,[height],[width])
+func (fn *formulaFuncs) OFFSET(argsList *list.List) formulaArg {
+	if argsList.Len() < 3 {
+		return newErrorFormulaArg(formulaErrorVALUE, "OFFSET requires at least 3 arguments")
+	}
+	if argsList.Len() > 5 {
+		return newErrorFormulaArg(formulaErrorVALUE, "OFFSET allows at most 5 arguments")
+	}
+	reference := argsList.Front().Value.(formulaArg)
+	var from, to cellRef
+	if reference.cellRanges != nil && reference.cellRanges.Len() > 0 {
+		cr := reference.cellRanges.Front().Value.(cellRange)
+		from, to = cr.From, cr.To
+	} else if reference.cellRefs != nil && reference.cellRefs.Len() > 0 {
+		from = reference.cellRefs.Front().Value.(cellRef)
+		to = from
+	} else {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	rng := []int{from.Col, from.Row, to.Col, to.Row}
+	_ = sortCoordinates(rng)
+	opts := list.New()
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		opts.PushBack(arg.Value.(formulaArg))
+	}
+	args, errArg := toNumberArgs(opts, 0, 0, float64(rng[3]-rng[1]+1), float64(rng[2]-rng[0]+1))
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	row, col, height, width := rng[1]+int(args[0]), rng[0]+int(args[1]), int(args[2]), int(args[3])
+	if height == 0 || width == 0 {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	// the negative height or width extends the reference upward or leftward
+	if height < 0 {
+		row, height = row+height+1, -height
+	}
+	if width < 0 {
+		col, width = col+width+1, -width
+	}
+	if row < 1 || col < 1 || row+height-1 > TotalRows || col+width-1 > MaxColumns {
+		return newErrorFormulaArg(formulaErrorREF, formulaErrorREF)
+	}
+	sheet := from.Sheet
+	if sheet == "" {
+		sheet = fn.sheet
+	}
+	cellRefs, cellRanges := list.New(), list.New()
+	if height == 1 && width == 1 {
+		cellRefs.PushBack(cellRef{Col: col, Row: row, Sheet: sheet})
+	} else {
+		cellRanges.PushBack(cellRange{
+			From: cellRef{Col: col, Row: row, Sheet: sheet},
+			To:   cellRef{Col: col + width - 1, Row: row + height - 1, Sheet: sheet},
+		})
+	}
+	result, err := fn.f.rangeResolver(fn.ctx, cellRefs, cellRanges)
+	if err != nil {
+		return newErrorFormulaArg(formulaErrorVALUE, err.Error())
+	}
+	return result
+}
+
 // ROW function returns the first row number within a supplied reference or
 // the number of the current row. The syntax of the function is:
 //
@@ -15619,6 +18100,183 @@ func (fn *formulaFuncs) ROWS(argsList *list.List) formulaArg {
 	return newNumberFormulaArg(float64(result))
 }
 
+// sortValueRank returns the rank of the formula argument type in the sort
+// order: numbers, text, logical values, errors, and blank cells.
+func sortValueRank(arg formulaArg) int {
+	switch arg.Type {
+	case ArgNumber:
+		if arg.Boolean {
+			return 2
+		}
+		return 0
+	case ArgString:
+		return 1
+	case ArgError:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// compareSortValue compares two formula arguments by the sort order of the
+// formula functions SORT and SORTBY, the blank cells always be placed at the
+// end.
+func compareSortValue(lhs, rhs formulaArg, descending bool) bool {
+	lRank, rRank := sortValueRank(lhs), sortValueRank(rhs)
+	if lRank == 4 || rRank == 4 {
+		return lRank < rRank
+	}
+	var cmp int
+	switch {
+	case lRank != rRank:
+		cmp = lRank - rRank
+	case lRank == 0 || lRank == 2:
+		if lhs.Number < rhs.Number {
+			cmp = -1
+		} else if lhs.Number > rhs.Number {
+			cmp = 1
+		}
+	case lRank == 1:
+		cmp = strings.Compare(strings.ToLower(lhs.String), strings.ToLower(rhs.String))
+	}
+	if descending {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// transposeMatrix returns the transposed matrix of the given matrix.
+func transposeMatrix(mtx [][]formulaArg) [][]formulaArg {
+	if len(mtx) == 0 {
+		return mtx
+	}
+	result := make([][]formulaArg, len(mtx[0]))
+	for c := range result {
+		result[c] = make([]formulaArg, len(mtx))
+		for r := range mtx {
+			if c < len(mtx[r]) {
+				result[c][r] = mtx[r][c]
+				continue
+			}
+			result[c][r] = newEmptyFormulaArg()
+		}
+	}
+	return result
+}
+
+// SORT function sorts the contents of a range or array in ascending or
+// descending order. The syntax of the function is:
+//
+//	SORT(array,[sort_index],[sort_order],[by_col])
+func (fn *formulaFuncs) SORT(argsList *list.List) formulaArg {
+	if argsList.Len() < 1 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SORT requires at least 1 argument")
+	}
+	if argsList.Len() > 4 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SORT allows at most 4 arguments")
+	}
+	array := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	opts := list.New()
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		opts.PushBack(arg.Value)
+	}
+	args, errArg := toNumberArgs(opts, 1, 1, 0)
+	if errArg.Type == ArgError {
+		return errArg
+	}
+	sortIndex, sortOrder, byCol := int(args[0]), args[1], args[2] != 0
+	if sortOrder != 1 && sortOrder != -1 {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	if byCol {
+		array = transposeMatrix(array)
+	}
+	if sortIndex < 1 || sortIndex > len(array[0]) {
+		return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+	}
+	mtx := make([][]formulaArg, len(array))
+	copy(mtx, array)
+	sort.SliceStable(mtx, func(i, j int) bool {
+		return compareSortValue(mtx[i][sortIndex-1], mtx[j][sortIndex-1], sortOrder == -1)
+	})
+	if byCol {
+		mtx = transposeMatrix(mtx)
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
+// SORTBY function sorts the contents of a range or array based on the values
+// in a corresponding range or array. The syntax of the function is:
+//
+//	SORTBY(array,by_array1,[sort_order1],[by_array2,sort_order2],...)
+func (fn *formulaFuncs) SORTBY(argsList *list.List) formulaArg {
+	if argsList.Len() < 2 {
+		return newErrorFormulaArg(formulaErrorVALUE, "SORTBY requires at least 2 arguments")
+	}
+	array := formulaArgToMatrix(argsList.Front().Value.(formulaArg))
+	type sortKey struct {
+		values     []formulaArg
+		descending bool
+	}
+	var (
+		keys  []sortKey
+		byCol bool
+	)
+	for arg := argsList.Front().Next(); arg != nil; arg = arg.Next() {
+		byArray := formulaArgToMatrix(arg.Value.(formulaArg))
+		key := sortKey{}
+		switch {
+		case len(byArray) == len(array) && len(byArray[0]) == 1 && (len(keys) == 0 || !byCol):
+			for _, row := range byArray {
+				key.values = append(key.values, row[0])
+			}
+		case len(byArray) == 1 && len(byArray[0]) == len(array[0]) && (len(keys) == 0 || byCol):
+			byCol, key.values = true, byArray[0]
+		default:
+			return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+		}
+		if arg.Next() != nil {
+			arg = arg.Next()
+			order := arg.Value.(formulaArg).ToNumber()
+			if order.Type != ArgNumber {
+				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			if order.Number != 1 && order.Number != -1 {
+				return newErrorFormulaArg(formulaErrorVALUE, formulaErrorVALUE)
+			}
+			key.descending = order.Number == -1
+		}
+		keys = append(keys, key)
+	}
+	if byCol {
+		array = transposeMatrix(array)
+	}
+	idx := make([]int, len(array))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		for _, key := range keys {
+			lhs, rhs := key.values[idx[i]], key.values[idx[j]]
+			if compareSortValue(lhs, rhs, key.descending) {
+				return true
+			}
+			if compareSortValue(rhs, lhs, key.descending) {
+				return false
+			}
+		}
+		return false
+	})
+	mtx := make([][]formulaArg, len(array))
+	for i, j := range idx {
+		mtx[i] = array[j]
+	}
+	if byCol {
+		mtx = transposeMatrix(mtx)
+	}
+	return newMatrixFormulaArg(mtx)
+}
+
 // Web Functions
 
 // ENCODEURL function returns a URL-encoded string, replacing certain
@@ -2,9 +2,13 @@ package excelize
 
 import (
 	"container/list"
+	"encoding/xml"
+	"fmt"
 	"math"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1410,12 +1414,35 @@ func TestCalcCellValue(t *testing.T) {
 		"=WEIBULL.DIST(1,3,1,FALSE)":  "1.10363832351433",
 		"=WEIBULL.DIST(2,5,1.5,TRUE)": "0.985212776817482",
 		// Information Functions
+		// CELL
+		"=CELL(\"address\",A1)":           "$A$1",
+		"=CELL(\"address\",Sheet1!B2:C3)": "$B$2",
+		"=CELL(\"col\",B3)":               "2",
+		"=CELL(\"row\",B3)":               "3",
+		"=CELL(\"row\")":                  "1",
+		"=CELL(\"contents\",D2)":          "Jan",
+		"=CELL(\"type\",D2)":              "l",
+		"=CELL(\"type\",A1)":              "v",
+		"=CELL(\"type\",C5)":              "b",
+		"=CELL(\"format\",A1)":            "G",
+		"=CELL(\"color\",A1)":             "0",
+		"=CELL(\"parentheses\",A1)":       "0",
+		"=CELL(\"prefix\",A1)":            "",
+		"=CELL(\"prefix\",D1)":            "'",
+		"=CELL(\"protect\",A1)":           "1",
+		"=CELL(\"width\",A1)":             "8",
+		"=CELL(\"filename\",A1)":          "",
 		// ERROR.TYPE
 		"=ERROR.TYPE(1/0)":           "2",
 		"=ERROR.TYPE(COT(0))":        "2",
 		"=ERROR.TYPE(XOR(\"text\"))": "3",
 		"=ERROR.TYPE(HEX2BIN(2,1))":  "6",
 		"=ERROR.TYPE(NA())":          "7",
+		// INFO
+		"=INFO(\"directory\")": "",
+		"=INFO(\"numfile\")":   "1",
+		"=INFO(\"origin\")":    "$A:$A$1",
+		"=INFO(\"recalc\")":    "Automatic",
 		// ISBLANK
 		"=ISBLANK(A1)": "FALSE",
 		"=ISBLANK(A5)": "TRUE",
@@ -1812,6 +1839,12 @@ func TestCalcCellValue(t *testing.T) {
 		"=MIDB(\"你好World\",5,1)":       "W",
 		"=MIDB(\"\u30AA\u30EA\u30B8\u30CA\u30EB\u30C6\u30AD\u30B9\u30C8\",6,4)": "\u30B8\u30CA",
 		"=MIDB(\"\u30AA\u30EA\u30B8\u30CA\u30EB\u30C6\u30AD\u30B9\u30C8\",3,5)": "\u30EA\u30B8\xe3",
+		// NUMBERVALUE
+		"=NUMBERVALUE(\"2.500,27\",\",\",\".\")": "2500.27",
+		"=NUMBERVALUE(\" 1 000.5 \")":            "1000.5",
+		"=NUMBERVALUE(\"3.5%\")":                 "0.035",
+		"=NUMBERVALUE(\"9%%\")":                  "0.0009",
+		"=NUMBERVALUE(\"\")":                     "0",
 		// PROPER
 		"=PROPER(\"this is a test sentence\")": "This Is A Test Sentence",
 		"=PROPER(\"THIS IS A TEST SENTENCE\")": "This Is A Test Sentence",
@@ -1898,6 +1931,14 @@ func TestCalcCellValue(t *testing.T) {
 		"=TEXTJOIN(\",\",FALSE,A1:C2)":   "1,4,,2,5,",
 		"=TEXTJOIN(\",\",TRUE,A1:C2)":    "1,4,2,5",
 		"=TEXTJOIN(\",\",TRUE,MUNIT(2))": "1,0,0,1",
+		// TEXTSPLIT
+		"=TEXTSPLIT(\"a,b,c\",\",\")":                                "a",
+		"=INDEX(TEXTSPLIT(\"a,b;c,d\",\",\",\";\"),2,2)":             "d",
+		"=INDEX(TEXTSPLIT(\"a-b_c\",{\"-\",\"_\"}),1,3)":             "c",
+		"=INDEX(TEXTSPLIT(\"aXbxc\",\"x\",\"\",FALSE,1),1,3)":        "c",
+		"=COUNTA(TEXTSPLIT(\"a,,b\",\",\",\"\",TRUE))":               "2",
+		"=INDEX(TEXTSPLIT(\"a,,b\",\",\"),1,3)":                      "b",
+		"=INDEX(TEXTSPLIT(\"a,b;c\",\",\",\";\",FALSE,0,\"-\"),2,2)": "-",
 		// TRIM
 		"=TRIM(\" trim text \")": "trim text",
 		"=TRIM(0)":               "0",
@@ -3598,9 +3639,18 @@ func TestCalcCellValue(t *testing.T) {
 		"=ZTEST(A1,1)":      {"#DIV/0!", "#DIV/0!"},
 		"=ZTEST(A1,1,\"\")": {"#VALUE!", "strconv.ParseFloat: parsing \"\": invalid syntax"},
 		// Information Functions
+		// CELL
+		"=CELL()":               {"#VALUE!", "CELL requires at least 1 argument"},
+		"=CELL(\"row\",A1,A1)":  {"#VALUE!", "CELL allows at most 2 arguments"},
+		"=CELL(\"x\",A1)":       {"#VALUE!", "#VALUE!"},
+		"=CELL(\"row\",\"A1\")": {"#VALUE!", "#VALUE!"},
 		// ERROR.TYPE
 		"=ERROR.TYPE()":  {"#VALUE!", "ERROR.TYPE requires 1 argument"},
 		"=ERROR.TYPE(1)": {"#N/A", "#N/A"},
+		// INFO
+		"=INFO()":           {"#VALUE!", "INFO requires 1 argument"},
+		"=INFO(\"x\")":      {"#VALUE!", "#VALUE!"},
+		"=INFO(\"totmem\")": {"#N/A", "#N/A"},
 		// ISBLANK
 		"=ISBLANK(A1,A2)": {"#VALUE!", "ISBLANK requires 1 argument"},
 		// ISERR
@@ -3896,6 +3946,15 @@ func TestCalcCellValue(t *testing.T) {
 		"=MIDB(\"\",1,-1)":   {"#VALUE!", "#VALUE!"},
 		"=MIDB(\"\",\"\",1)": {"#VALUE!", "strconv.ParseFloat: parsing \"\": invalid syntax"},
 		"=MIDB(\"\",1,\"\")": {"#VALUE!", "strconv.ParseFloat: parsing \"\": invalid syntax"},
+		// NUMBERVALUE
+		"=NUMBERVALUE()":                    {"#VALUE!", "NUMBERVALUE requires at least 1 argument"},
+		"=NUMBERVALUE(1,\".\",\",\",1)":     {"#VALUE!", "NUMBERVALUE allows at most 3 arguments"},
+		"=NUMBERVALUE(\"1.2.3\")":           {"#VALUE!", "#VALUE!"},
+		"=NUMBERVALUE(\"1.5,2\")":           {"#VALUE!", "#VALUE!"},
+		"=NUMBERVALUE(\"1,2\",\",\",\",\")": {"#VALUE!", "#VALUE!"},
+		"=NUMBERVALUE(\"x\")":               {"#VALUE!", "#VALUE!"},
+		"=NUMBERVALUE(NA())":                {"#N/A", "#N/A"},
+		"=NUMBERVALUE(1,NA())":              {"#N/A", "#N/A"},
 		// PROPER
 		"=PROPER()":    {"#VALUE!", "PROPER requires 1 argument"},
 		"=PROPER(1,2)": {"#VALUE!", "PROPER requires 1 argument"},
@@ -3956,6 +4015,16 @@ func TestCalcCellValue(t *testing.T) {
 		"=TEXTJOIN(\"\",TRUE,NA())": {"#N/A", "#N/A"},
 		"=TEXTJOIN(\"\",TRUE," + strings.Repeat("0,", 250) + ",0)": {"#VALUE!", "TEXTJOIN accepts at most 252 arguments"},
 		"=TEXTJOIN(\",\",FALSE,REPT(\"*\",32768))":                 {"#VALUE!", "TEXTJOIN function exceeds 32767 characters"},
+		// TEXTSPLIT
+		"=TEXTSPLIT()":                         {"#VALUE!", "TEXTSPLIT requires at least 2 arguments"},
+		"=TEXTSPLIT(\"a\",1,1,1,1,1,1)":        {"#VALUE!", "TEXTSPLIT allows at most 6 arguments"},
+		"=TEXTSPLIT(\"a\",\"\")":               {"#VALUE!", "#VALUE!"},
+		"=TEXTSPLIT(\"a\",\",\",\"\",FALSE,2)": {"#VALUE!", "#VALUE!"},
+		"=TEXTSPLIT(\"a\",\",\",\"\",\"x\")":   {"#VALUE!", "#VALUE!"},
+		"=TEXTSPLIT(\",\",\",\",\"\",TRUE)":    {"#CALC!", "#CALC!"},
+		"=TEXTSPLIT(NA(),\",\")":               {"#N/A", "#N/A"},
+		"=TEXTSPLIT(\"a\",NA())":               {"#N/A", "#N/A"},
+		"=TEXTSPLIT(\"a\",\",\",NA())":         {"#N/A", "#N/A"},
 		// TRIM
 		"=TRIM()":    {"#VALUE!", "TRIM requires 1 argument"},
 		"=TRIM(1,2)": {"#VALUE!", "TRIM requires 1 argument"},
@@ -4708,6 +4777,166 @@ func TestCalcCellValue(t *testing.T) {
 	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestCalcCellValue.xlsx")))
 }
 
+func TestRecalculateAll(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for cell, value := range map[string]interface{}{"A1": 1, "A2": 2, "A3": "x"} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	for cell, formula := range map[string]string{
+		"B1": "SUM(A1:A2)",
+		"B2": "B1*2",
+		"B3": "A3&\"y\"",
+		"B4": "1/0",
+		"B5": "A1>0",
+		"B6": "Sheet2!A1+B2",
+		"B7": "2/3",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	assert.NoError(t, f.SetCellFormula("Sheet2", "A1", "Sheet1!B1+1"))
+	f.CalcChain = &xlsxCalcChain{C: []xlsxCalcChainC{{R: "B6", I: 1}, {R: "A1", I: 2}, {R: "Z1"}}}
+	assert.NoError(t, f.RecalculateAll())
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	for cell, expected := range map[string][2]string{
+		"B1": {"", "3"},
+		"B2": {"", "6"},
+		"B3": {"str", "xy"},
+		"B4": {"e", "#DIV/0!"},
+		"B5": {"b", "1"},
+		"B6": {"", "10"},
+		"B7": {"", "0.666666666666667"},
+	} {
+		c, _, _, err := ws.(*xlsxWorksheet).prepareCell(cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, [2]string{c.T, c.V}, cell)
+	}
+	for sheet, cells := range map[string]map[string]string{
+		"Sheet1": {"B2": "6", "B3": "xy", "B4": "#DIV/0!", "B5": "TRUE"},
+		"Sheet2": {"A1": "4"},
+	} {
+		for cell, expected := range cells {
+			result, err := f.GetCellValue(sheet, cell)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result, cell)
+		}
+	}
+	// Test recalculate with formula cells reference each other
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "C2+1"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C2", "C1+1"))
+	assert.NoError(t, f.RecalculateAll())
+	for cell, expected := range map[string][2]string{"C1": {"", "2"}, "C2": {"", "1"}} {
+		c, _, _, err := ws.(*xlsxWorksheet).prepareCell(cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, [2]string{c.T, c.V}, cell)
+		result, err := f.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected[1], result, cell)
+	}
+	// Test recalculate with an invalid formula keeps the cached value
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D1", "SUM("))
+	c, _, _, err := ws.(*xlsxWorksheet).prepareCell("D1")
+	assert.NoError(t, err)
+	c.V = "cached"
+	assert.NoError(t, f.RecalculateAll())
+	result, err := f.GetCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", result)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestRecalculateAll.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test recalculate with the array formula
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]int{1, 2, 3}))
+	formulaType, ref := STCellFormulaTypeArray, "A2:C2"
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "A1:C1*2", FormulaOpts{Type: &formulaType, Ref: &ref}))
+	assert.NoError(t, f.RecalculateAll())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2", "4", "6"}, rows[1])
+	// Test recalculate with unsupported charset calculation chain
+	f.CalcChain = nil
+	f.Pkg.Store(defaultXMLPathCalcChain, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.RecalculateAll(), "XML syntax error on line 1: invalid UTF-8")
+	// Test recalculate with unsupported charset worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	f.formulaChecked = false
+	assert.EqualError(t, f.RecalculateAll(), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestCalcStructuredReference(t *testing.T) {
+	f := NewFile()
+	for idx, row := range [][]interface{}{
+		{"Item", "Unit Price", "Qty", "Amount"},
+		{"Apple", 2, 3},
+		{"Banana", 4, 5},
+		{"Total"},
+	} {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", idx+1), &row))
+	}
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "A1:D4", Name: "Sales"}))
+	// Set the totals row for the table
+	tbl, err := f.getTableXML("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	tbl.TotalsRowCount = 1
+	output, err := xml.Marshal(tbl)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/tables/table1.xml", output)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D2", "[@[Unit Price]]*[@Qty]"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "Sales[[#This Row],[Unit Price]]*Sales[[#This Row],[Qty]]"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D4", "SUBTOTAL(109,[Amount])"))
+	for formula, expected := range map[string]string{
+		"SUM(Sales[Qty])":                          "8",
+		"SUM(Sales[[#Data],[Unit Price]:[Qty]])":   "14",
+		"SUMPRODUCT(Sales[Unit Price],Sales[Qty])": "26",
+		"Sales[[#Headers],[Qty]]":                  "Qty",
+		"INDEX(Sales[#Headers],1,2)":               "Unit Price",
+		"Sales[[#Totals],[Amount]]":                "26",
+		"Sales[[#Totals],[Item]]":                  "Total",
+		"ROWS(Sales[#All])":                        "4",
+		"ROWS(Sales)":                              "2",
+		"COLUMNS(sales[])":                         "4",
+		"SUM(Sales)":                               "40",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		result, err := f.CalcCellValue("Sheet1", "F1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for _, cell := range []string{"D2", "D3", "D4"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err, cell)
+		assert.Equal(t, map[string]string{"D2": "6", "D3": "20", "D4": "26"}[cell], result, cell)
+	}
+	for formula, expected := range map[string]string{
+		"Sales[Price]":             formulaErrorREF,
+		"SUM(Sales[Price])":        formulaErrorREF,
+		"Orders[Qty]":              formulaErrorREF,
+		"[@Qty]":                   formulaErrorREF,
+		"Sales[[#This Row],[Qty]]": formulaErrorVALUE,
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "F1", formula))
+		_, err := f.CalcCellValue("Sheet1", "F1")
+		assert.EqualError(t, err, expected, formula)
+	}
+	// Test calculate the structured reference in the defined name
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Quantity", RefersTo: "Sales[Qty]"}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "SUM(Quantity)"))
+	result, err := f.CalcCellValue("Sheet1", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, "8", result)
+	// Test calculate the structured reference with unsupported charset table
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "F1", "SUM(Sales[Qty])"))
+	_, err = f.CalcCellValue("Sheet1", "F1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+}
+
 func TestCalcWithDefinedName(t *testing.T) {
 	cellData := [][]interface{}{
 		{"A1_as_string", "B1_as_string", 123, nil},
@@ -4961,6 +5190,118 @@ func TestCalcTRANSPOSE(t *testing.T) {
 	assert.NoError(t, err, formula)
 }
 
+func TestCalcArrayShapingFunctions(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, nil, 9},
+	}
+	calcSpill := func(formula, ref string) [][]string {
+		f := prepareCalcData(cellData)
+		formulaType := STCellFormulaTypeArray
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula,
+			FormulaOpts{Ref: &ref, Type: &formulaType}))
+		coordinates, err := rangeRefToCoordinates(ref)
+		assert.NoError(t, err)
+		var results [][]string
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			var result []string
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				cell, err := CoordinatesToCellName(col, row)
+				assert.NoError(t, err)
+				value, _ := f.CalcCellValue("Sheet1", cell)
+				result = append(result, value)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+	for _, c := range []struct {
+		formula, ref string
+		expected     [][]string
+	}{
+		{"=_xlfn.VSTACK(A1:B1,A3:C3)", "E1:G2", [][]string{{"1", "2", "#N/A"}, {"7", "", "9"}}},
+		{"=_xlfn.VSTACK({1;2})", "E1:E2", [][]string{{"1"}, {"2"}}},
+		{"=_xlfn.HSTACK(A1:A2,B1:C1)", "E1:G2", [][]string{{"1", "2", "3"}, {"4", "#N/A", "#N/A"}}},
+		{"=_xlfn.TAKE(A1:C3,2)", "E1:G2", [][]string{{"1", "2", "3"}, {"4", "5", "6"}}},
+		{"=_xlfn.TAKE(A1:C3,-1,-2)", "E1:F1", [][]string{{"", "9"}}},
+		{"=_xlfn.TAKE(A1:C3,5,1)", "E1:E3", [][]string{{"1"}, {"4"}, {"7"}}},
+		{"=_xlfn.DROP(A1:C3,1,1)", "E1:F2", [][]string{{"5", "6"}, {"", "9"}}},
+		{"=_xlfn.DROP(A1:C3,-2)", "E1:G1", [][]string{{"1", "2", "3"}}},
+		{"=_xlfn.DROP(A1:C3,0,-2)", "E1:E3", [][]string{{"1"}, {"4"}, {"7"}}},
+		{"=_xlfn.CHOOSECOLS(A1:C3,3,-3)", "E1:F3", [][]string{{"3", "1"}, {"6", "4"}, {"9", "7"}}},
+		{"=_xlfn.CHOOSECOLS(A1:C3,{2,2})", "E1:F1", [][]string{{"2", "2"}}},
+		{"=_xlfn.CHOOSEROWS(A1:C3,-1,1)", "E1:G2", [][]string{{"7", "", "9"}, {"1", "2", "3"}}},
+		{"=_xlfn.TOCOL(A2:C3)", "E1:E6", [][]string{{"4"}, {"5"}, {"6"}, {"7"}, {""}, {"9"}}},
+		{"=_xlfn.TOCOL(A2:C3,1,TRUE)", "E1:E5", [][]string{{"4"}, {"7"}, {"5"}, {"6"}, {"9"}}},
+		{"=_xlfn.TOROW(A1:B2)", "E1:H1", [][]string{{"1", "2", "4", "5"}}},
+		{"=_xlfn.TOROW(A1:B2,0,TRUE)", "E1:H1", [][]string{{"1", "4", "2", "5"}}},
+		{"=_xlfn.WRAPROWS(A1:C1,2)", "E1:F2", [][]string{{"1", "2"}, {"3", "#N/A"}}},
+		{"=_xlfn.WRAPROWS(A1:A3,2,0)", "E1:F2", [][]string{{"1", "4"}, {"7", "0"}}},
+		{"=_xlfn.WRAPCOLS(A1:C1,2,\"x\")", "E1:F2", [][]string{{"1", "3"}, {"2", "x"}}},
+		{"=_xlfn.EXPAND(A1:B1,2)", "E1:F2", [][]string{{"1", "2"}, {"#N/A", "#N/A"}}},
+		{"=_xlfn.EXPAND(A1,2,2,\"-\")", "E1:F2", [][]string{{"1", "-"}, {"-", "-"}}},
+	} {
+		assert.Equal(t, c.expected, calcSpill(c.formula, c.ref), c.formula)
+	}
+	for formula, expected := range map[string][]string{
+		"=_xlfn.VSTACK()":                    {"#VALUE!", "VSTACK requires at least 1 argument"},
+		"=_xlfn.HSTACK()":                    {"#VALUE!", "HSTACK requires at least 1 argument"},
+		"=_xlfn.TAKE(A1:C3)":                 {"#VALUE!", "TAKE requires at least 2 arguments"},
+		"=_xlfn.TAKE(A1:C3,1,1,1)":           {"#VALUE!", "TAKE allows at most 3 arguments"},
+		"=_xlfn.TAKE(A1:C3,\"x\")":           {"#VALUE!", "#VALUE!"},
+		"=_xlfn.TAKE(A1:C3,0)":               {"#CALC!", "#CALC!"},
+		"=_xlfn.DROP(A1:C3)":                 {"#VALUE!", "DROP requires at least 2 arguments"},
+		"=_xlfn.DROP(A1:C3,1,1,1)":           {"#VALUE!", "DROP allows at most 3 arguments"},
+		"=_xlfn.DROP(A1:C3,\"x\")":           {"#VALUE!", "#VALUE!"},
+		"=_xlfn.DROP(A1:C3,3)":               {"#CALC!", "#CALC!"},
+		"=_xlfn.DROP(A1:C3,0,-3)":            {"#CALC!", "#CALC!"},
+		"=_xlfn.CHOOSECOLS(A1:C3)":           {"#VALUE!", "CHOOSECOLS requires at least 2 arguments"},
+		"=_xlfn.CHOOSECOLS(A1:C3,0)":         {"#VALUE!", "#VALUE!"},
+		"=_xlfn.CHOOSECOLS(A1:C3,4)":         {"#VALUE!", "#VALUE!"},
+		"=_xlfn.CHOOSECOLS(A1:C3,\"x\")":     {"#VALUE!", "#VALUE!"},
+		"=_xlfn.CHOOSEROWS(A1:C3)":           {"#VALUE!", "CHOOSEROWS requires at least 2 arguments"},
+		"=_xlfn.CHOOSEROWS(A1:C3,-4)":        {"#VALUE!", "#VALUE!"},
+		"=_xlfn.TOCOL()":                     {"#VALUE!", "TOCOL requires at least 1 argument"},
+		"=_xlfn.TOCOL(A1:C3,0,0,0)":          {"#VALUE!", "TOCOL allows at most 3 arguments"},
+		"=_xlfn.TOCOL(A1:C3,4)":              {"#VALUE!", "#VALUE!"},
+		"=_xlfn.TOCOL(A1:C3,\"x\")":          {"#VALUE!", "#VALUE!"},
+		"=_xlfn.TOROW()":                     {"#VALUE!", "TOROW requires at least 1 argument"},
+		"=_xlfn.TOROW(D1:D2,1)":              {"#CALC!", "#CALC!"},
+		"=_xlfn.WRAPROWS(A1:C1)":             {"#VALUE!", "WRAPROWS requires at least 2 arguments"},
+		"=_xlfn.WRAPROWS(A1:C1,1,1,1)":       {"#VALUE!", "WRAPROWS allows at most 3 arguments"},
+		"=_xlfn.WRAPROWS(A1:C3,2)":           {"#VALUE!", "#VALUE!"},
+		"=_xlfn.WRAPROWS(A1:C1,\"x\")":       {"#VALUE!", "#VALUE!"},
+		"=_xlfn.WRAPCOLS(A1:C1,\"x\")":       {"#VALUE!", "#VALUE!"},
+		"=_xlfn.WRAPROWS(A1:C1,0)":           {"#NUM!", "#NUM!"},
+		"=_xlfn.WRAPCOLS(A1:C1)":             {"#VALUE!", "WRAPCOLS requires at least 2 arguments"},
+		"=_xlfn.WRAPROWS(A1:C1,16385)":       {"#VALUE!", "#VALUE!"},
+		"=_xlfn.WRAPCOLS(A1:C1,1048577)":     {"#VALUE!", "#VALUE!"},
+		"=_xlfn.EXPAND(A1:C3)":               {"#VALUE!", "EXPAND requires at least 2 arguments"},
+		"=_xlfn.EXPAND(A1:C3,3,3,0,0)":       {"#VALUE!", "EXPAND allows at most 4 arguments"},
+		"=_xlfn.EXPAND(A1:C3,2)":             {"#VALUE!", "#VALUE!"},
+		"=_xlfn.EXPAND(A1:C3,\"x\")":         {"#VALUE!", "#VALUE!"},
+		"=_xlfn.EXPAND(A1,1048577)":          {"#VALUE!", "#VALUE!"},
+		"=_xlfn.EXPAND(A1,1,16385)":          {"#VALUE!", "#VALUE!"},
+		"=_xlfn.EXPAND(A1,1048576,16384)":    {"#VALUE!", "#VALUE!"},
+		"=SUM(_xlfn.VSTACK(A1:C1,A2:C2))":    {"21", ""},
+		"=INDEX(_xlfn.TAKE(A1:C3,-1),1,3)":   {"9", ""},
+		"=SUM(_xlfn.CHOOSECOLS(A1:C3,1))":    {"12", ""},
+		"=SUM(_xlfn.WRAPCOLS(A1:A3,2,0))":    {"12", ""},
+		"=INDEX(_xlfn.HSTACK(A1,A1:A2),2,1)": {"#N/A", "#N/A"},
+	} {
+		f := prepareCalcData(cellData)
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.Equal(t, expected[0], result, formula)
+		if expected[1] == "" {
+			assert.NoError(t, err, formula)
+			continue
+		}
+		assert.EqualError(t, err, expected[1], formula)
+	}
+}
+
 func TestCalcVLOOKUP(t *testing.T) {
 	cellData := [][]interface{}{
 		{nil, nil, nil, nil, nil, nil},
@@ -5705,6 +6046,112 @@ func TestCalcXLOOKUP(t *testing.T) {
 	}
 }
 
+func TestCalcXMATCH(t *testing.T) {
+	cellData := [][]interface{}{
+		{"Salesperson", "Item", "Amont"},
+		{"B", "Apples", 30},
+		{"L", "Oranges", 25},
+		{"C", "Grapes", 15},
+		{"L", "Lemons", 50},
+		{"L", "Oranges", 45},
+		{"C", "Peaches", 18},
+	}
+	f := prepareCalcData(cellData)
+	formulaList := map[string]string{
+		"=XMATCH(\"Grapes\",B2:B7)":      "3",
+		"=XMATCH(\"grapes\",B2:B7)":      "3",
+		"=XMATCH(\"L\",A2:A7,0,-1)":      "5",
+		"=XMATCH(\"*p*\",B2:B7,2)":       "1",
+		"=XMATCH(20,C2:C7,1)":            "2",
+		"=XMATCH(20,C2:C7,-1,-1)":        "6",
+		"=XMATCH(\"15\",C2:C7,1)":        "3",
+		"=XMATCH(100,C2:C7,-1)":          "4",
+		"=XMATCH(\"Amont\",A1:C1)":       "3",
+		"=XMATCH(2,{1,2,3})":             "2",
+		"=INDEX(C2:C7,XMATCH(45,C2:C7))": "45",
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	calcError := map[string][]string{
+		"=XMATCH()":               {"#VALUE!", "XMATCH requires at least 2 arguments"},
+		"=XMATCH(1,C2:C7,0,1,1)":  {"#VALUE!", "XMATCH allows at most 4 arguments"},
+		"=XMATCH(100,C2:C7,1)":    {"#N/A", "#N/A"},
+		"=XMATCH(1,C2:C7)":        {"#N/A", "#N/A"},
+		"=XMATCH(1,A2:C7)":        {"#N/A", "#N/A"},
+		"=XMATCH(1,C2:C7,3)":      {"#VALUE!", "#VALUE!"},
+		"=XMATCH(1,C2:C7,0,0)":    {"#VALUE!", "#VALUE!"},
+		"=XMATCH(1,C2:C7,\"\")":   {"#VALUE!", "strconv.ParseFloat: parsing \"\": invalid syntax"},
+		"=XMATCH(1,C2:C7,0,\"\")": {"#VALUE!", "strconv.ParseFloat: parsing \"\": invalid syntax"},
+	}
+	for formula, expected := range calcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.Equal(t, expected[0], result, formula)
+		assert.EqualError(t, err, expected[1], formula)
+	}
+}
+
+func TestCalcOFFSET(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}
+	f := prepareCalcData(cellData)
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellValue("Sheet2", "B2", "Sheet2"))
+	formulaList := map[string]string{
+		"=OFFSET(A1,1,1)":                   "5",
+		"=OFFSET(A1:B2,1,1)":                "5",
+		"=SUM(OFFSET(A1,1,1,2,2))":          "28",
+		"=SUM(OFFSET(A1:C1,1,0))":           "15",
+		"=SUM(OFFSET(C3,0,0,-2,-2))":        "28",
+		"=SUM(OFFSET(A1,0,0,3))":            "12",
+		"=AVERAGE(OFFSET(B1,0,0,3,1))":      "5",
+		"=ROWS(OFFSET(A1,0,0,3,2))":         "3",
+		"=COLUMNS(OFFSET(A1,0,0,3,2))":      "2",
+		"=ISREF(OFFSET(A1,1,1))":            "TRUE",
+		"=OFFSET(Sheet2!A1,1,1)":            "Sheet2",
+		"=CELL(\"address\",OFFSET(A1,2,1))": "$B$3",
+	}
+	for formula, expected := range formulaList {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	calcError := map[string][]string{
+		"=OFFSET()":                {"#VALUE!", "OFFSET requires at least 3 arguments"},
+		"=OFFSET(A1,1,1,1,1,1)":    {"#VALUE!", "OFFSET allows at most 5 arguments"},
+		"=OFFSET(1,1,1)":           {"#VALUE!", "#VALUE!"},
+		"=OFFSET(A1,\"x\",1)":      {"#VALUE!", "#VALUE!"},
+		"=OFFSET(A1,-1,0)":         {"#REF!", "#REF!"},
+		"=OFFSET(A1,0,0,0)":        {"#REF!", "#REF!"},
+		"=OFFSET(A1,0,16384)":      {"#REF!", "#REF!"},
+		"=SUM(OFFSET(A1,0,0,2,0))": {"#REF!", "#REF!"},
+	}
+	for formula, expected := range calcError {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.Equal(t, expected[0], result, formula)
+		assert.EqualError(t, err, expected[1], formula)
+	}
+	// Test spill the range reference returned by the OFFSET function
+	ref, formulaType := "E1:F2", STCellFormulaTypeArray
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "=OFFSET(A1,1,1,2,2)",
+		FormulaOpts{Ref: &ref, Type: &formulaType}))
+	for cell, expected := range map[string]string{"E1": "5", "F1": "6", "E2": "8", "F2": "9"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+}
+
 func TestCalcXNPV(t *testing.T) {
 	cellData := [][]interface{}{
 		{nil, 0.05},
@@ -6442,7 +6889,398 @@ func TestEvalInfixExp(t *testing.T) {
 
 func TestParseToken(t *testing.T) {
 	f := NewFile()
-	assert.Equal(t, formulaErrorNAME, f.parseToken(nil, "Sheet1",
+	assert.Equal(t, formulaErrorNAME, f.parseToken(nil, "Sheet1", "",
 		efp.Token{TSubType: efp.TokenSubTypeRange, TValue: "1A"}, nil, nil,
 	).Error())
 }
+
+func TestCalcDynamicArrayFunctions(t *testing.T) {
+	cellData := [][]interface{}{
+		{"Name", "Score", "Pass"},
+		{"b", 3, true},
+		{"a", 1, false},
+		{"c", 2, true},
+		{"a", 1, false},
+	}
+	calcSpill := func(formula, ref string) [][]string {
+		f := prepareCalcData(cellData)
+		formulaType := STCellFormulaTypeArray
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula,
+			FormulaOpts{Ref: &ref, Type: &formulaType}))
+		coordinates, err := rangeRefToCoordinates(ref)
+		assert.NoError(t, err)
+		var results [][]string
+		for row := coordinates[1]; row <= coordinates[3]; row++ {
+			var result []string
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				cell, err := CoordinatesToCellName(col, row)
+				assert.NoError(t, err)
+				value, err := f.CalcCellValue("Sheet1", cell)
+				assert.NoError(t, err, formula)
+				result = append(result, value)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+	for formula, expected := range map[string][][]string{
+		"=_xlfn._xlws.FILTER(A2:B5,C2:C5)":            {{"b", "3"}, {"c", "2"}},
+		"=_xlfn._xlws.FILTER(A2:A5,B2:B5>1)":          {{"b"}, {"c"}},
+		"=_xlfn._xlws.FILTER(A1:C1,{1,0,1})":          {{"Name", "Pass"}},
+		"=_xlfn._xlws.FILTER(A2:A5,B2:B5>5,\"none\")": {{"none"}},
+		"=_xlfn._xlws.SORT(A2:B5)":                    {{"a", "1"}, {"a", "1"}, {"b", "3"}, {"c", "2"}},
+		"=_xlfn._xlws.SORT(A2:B5,2,-1)":               {{"b", "3"}, {"c", "2"}, {"a", "1"}, {"a", "1"}},
+		"=_xlfn._xlws.SORT(A1:C1,1,1,TRUE)":           {{"Name", "Pass", "Score"}},
+		"=_xlfn.SORTBY(A2:A5,B2:B5)":                  {{"a"}, {"a"}, {"c"}, {"b"}},
+		"=_xlfn.SORTBY(A2:A5,A2:A5,1,B2:B5,-1)":       {{"a"}, {"a"}, {"b"}, {"c"}},
+		"=_xlfn.UNIQUE(A2:A5)":                        {{"b"}, {"a"}, {"c"}},
+		"=_xlfn.UNIQUE(A2:B5,FALSE,TRUE)":             {{"b", "3"}, {"c", "2"}},
+		"=_xlfn.UNIQUE({1,1,2},TRUE)":                 {{"1", "2"}},
+		"=_xlfn.SEQUENCE(2,3)":                        {{"1", "2", "3"}, {"4", "5", "6"}},
+		"=_xlfn.SEQUENCE(3,1,10,-5)":                  {{"10"}, {"5"}, {"0"}},
+		"=_xlfn.SEQUENCE(2)*2":                        {{"2"}, {"4"}},
+		"=_xlfn.SEQUENCE(2,1,1,1)":                    {{"1"}, {"2"}},
+	} {
+		ref := fmt.Sprintf("E1:%s", func() string {
+			cell, _ := CoordinatesToCellName(4+len(expected[0]), len(expected))
+			return cell
+		}())
+		assert.Equal(t, expected, calcSpill(formula, ref), formula)
+	}
+	// Test spill range larger than the result array
+	assert.Equal(t, [][]string{{"1"}, {"2"}, {"#N/A"}}, calcSpill("=_xlfn.SEQUENCE(2)", "E1:E3"))
+	// Test generate the array which has more elements than the worksheet rows
+	assert.Equal(t, [][]string{{"1049600"}}, calcSpill("=COUNT(_xlfn.SEQUENCE(1024,1025))", "E1:E1"))
+	// Test RANDARRAY spill result
+	for _, row := range calcSpill("=_xlfn.RANDARRAY(2,2,1,6,TRUE)", "E1:F2") {
+		for _, value := range row {
+			num, err := strconv.Atoi(value)
+			assert.NoError(t, err)
+			assert.True(t, num >= 1 && num <= 6)
+		}
+	}
+	for formula, expected := range map[string][]string{
+		"=_xlfn._xlws.FILTER()":                   {"#VALUE!", "FILTER requires at least 2 arguments"},
+		"=_xlfn._xlws.FILTER(A1,A1,A1,A1)":        {"#VALUE!", "FILTER allows at most 3 arguments"},
+		"=_xlfn._xlws.FILTER(A2:B5,C2:C3)":        {"#VALUE!", "#VALUE!"},
+		"=_xlfn._xlws.FILTER(A2:B5,A2:A5)":        {"#VALUE!", "#VALUE!"},
+		"=_xlfn._xlws.FILTER(A2:B5,B2:B5>5)":      {"#CALC!", "#CALC!"},
+		"=_xlfn._xlws.FILTER(A2:A3,{1;\"\"})":     {"b", ""},
+		"=_xlfn._xlws.SORT()":                     {"#VALUE!", "SORT requires at least 1 argument"},
+		"=_xlfn._xlws.SORT(A1,1,1,1,1)":           {"#VALUE!", "SORT allows at most 4 arguments"},
+		"=_xlfn._xlws.SORT(A2:B5,\"x\")":          {"#VALUE!", "#VALUE!"},
+		"=_xlfn._xlws.SORT(A2:B5,3)":              {"#VALUE!", "#VALUE!"},
+		"=_xlfn._xlws.SORT(A2:B5,1,0)":            {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SORTBY(A2:A5)":                    {"#VALUE!", "SORTBY requires at least 2 arguments"},
+		"=_xlfn.SORTBY(A2:A5,B2:B3)":              {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SORTBY(A2:A5,B2:B5,\"x\")":        {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SORTBY(A2:A5,B2:B5,2)":            {"#VALUE!", "#VALUE!"},
+		"=_xlfn.UNIQUE()":                         {"#VALUE!", "UNIQUE requires at least 1 argument"},
+		"=_xlfn.UNIQUE(A1,1,1,1)":                 {"#VALUE!", "UNIQUE allows at most 3 arguments"},
+		"=_xlfn.UNIQUE(A1,\"x\")":                 {"#VALUE!", "#VALUE!"},
+		"=_xlfn.UNIQUE({1,1},TRUE,TRUE)":          {"#CALC!", "#CALC!"},
+		"=_xlfn.SEQUENCE()":                       {"#VALUE!", "SEQUENCE requires at least 1 argument"},
+		"=_xlfn.SEQUENCE(1,1,1,1,1)":              {"#VALUE!", "SEQUENCE allows at most 4 arguments"},
+		"=_xlfn.SEQUENCE(\"x\")":                  {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SEQUENCE(-1)":                     {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SEQUENCE(0)":                      {"#CALC!", "#CALC!"},
+		"=_xlfn.SEQUENCE(1048577)":                {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SEQUENCE(1,16385)":                {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SEQUENCE(1048576,16384)":          {"#VALUE!", "#VALUE!"},
+		"=_xlfn.SEQUENCE(2048,2049)":              {"#VALUE!", "#VALUE!"},
+		"=_xlfn.RANDARRAY(1,1,1,1,1,1)":           {"#VALUE!", "RANDARRAY allows at most 5 arguments"},
+		"=_xlfn.RANDARRAY(\"x\")":                 {"#VALUE!", "#VALUE!"},
+		"=_xlfn.RANDARRAY(1,1,2,1)":               {"#VALUE!", "#VALUE!"},
+		"=_xlfn.RANDARRAY(0)":                     {"#CALC!", "#CALC!"},
+		"=_xlfn.RANDARRAY(1048576,16384)":         {"#VALUE!", "#VALUE!"},
+		"=_xlfn.RANDARRAY(1,1,0.5,1,TRUE)":        {"#VALUE!", "#VALUE!"},
+		"=SUM(_xlfn._xlws.FILTER(B2:B5,C2:C5))":   {"5", ""},
+		"=SUM(--(B2:B5>1))":                       {"2", ""},
+		"=SUM(_xlfn.SEQUENCE(2,2)*{1,2})":         {"16", ""},
+		"=INDEX(_xlfn.SEQUENCE(2,2)*{1,2,3},1,3)": {"#N/A", "#N/A"},
+		"=INDEX(-(A2:B2),1,1)":                    {"#VALUE!", "#VALUE!"},
+		"=INDEX(-(A2:B2),1,2)":                    {"-3", ""},
+	} {
+		f := prepareCalcData(cellData)
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.Equal(t, expected[0], result, formula)
+		if expected[1] == "" {
+			assert.NoError(t, err, formula)
+			continue
+		}
+		assert.EqualError(t, err, expected[1], formula)
+	}
+}
+
+func TestGetArrayFormulaAnchor(t *testing.T) {
+	f := NewFile()
+	ref, formulaType := "A1:B2", STCellFormulaTypeArray
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "=_xlfn.SEQUENCE(2,2)", FormulaOpts{Ref: &ref, Type: &formulaType}))
+	ctx := &calcContext{}
+	anchor, formula, err := f.getArrayFormulaAnchor(ctx, "Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "A1", anchor)
+	assert.Equal(t, "=_xlfn.SEQUENCE(2,2)", formula)
+	assert.Len(t, ctx.arrayFormulas["Sheet1"], 1)
+	// Test the array formula ranges are indexed once in the calculation context
+	ctx.arrayFormulas["Sheet1"] = nil
+	anchor, _, err = f.getArrayFormulaAnchor(ctx, "Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Empty(t, anchor)
+	// Test get array formula anchor with invalid cell reference
+	_, _, err = f.getArrayFormulaAnchor(ctx, "Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test get array formula anchor on not exists worksheet
+	_, _, err = f.getArrayFormulaAnchor(ctx, "SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+}
+
+func TestCalcLETAndLAMBDA(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, 2},
+		{3, 4},
+		{5, 6},
+	}
+	prepareData := func() *File {
+		f := prepareCalcData(cellData)
+		assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "DOUBLE", RefersTo: "LAMBDA(x,x*2)"}))
+		assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "FACTORIAL", RefersTo: "LAMBDA(n,IF(n<=1,1,n*FACTORIAL(n-1)))"}))
+		assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "ENDLESS", RefersTo: "LAMBDA(n,ENDLESS(n+1))"}))
+		assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "TAX", RefersTo: "0.1"}))
+		return f
+	}
+	for formula, expected := range map[string][]string{
+		// LET
+		"=_xlfn.LET(_xlpm.x,2,_xlpm.x+1)":     {"3", ""},
+		"=LET(x,2,y,x*3,x+y)":                 {"8", ""},
+		"=LET(x,A1:A3,SUM(x))":                {"9", ""},
+		"=LET(x,2,IF(x>1,\"big\",\"small\"))": {"big", ""},
+		"=LET(x,1,LET(x,x+1,x*10))":           {"20", ""},
+		"=LET(x,100,x)+LET(y,1,y*A1)":         {"101", ""},
+		"=LET(x,1)":                           {"#VALUE!", "LET requires an odd number of arguments and at least 3 arguments"},
+		"=LET(A1,1,A1)":                       {"#NAME?", "LET requires a valid name"},
+		"=LET(1,1,1)":                         {"#NAME?", "LET requires a valid name"},
+		// LAMBDA
+		"=_xlfn.LAMBDA(_xlpm.a,_xlpm.b,_xlpm.a*_xlpm.b)(3,4)": {"12", ""},
+		"=LAMBDA(x,x+1)(5)+1":                                {"7", ""},
+		"=LAMBDA(x,x+1)":                                     {"#CALC!", "#CALC!"},
+		"=LAMBDA(x,x)(1,2)":                                  {"#VALUE!", "LAMBDA requires 1 arguments"},
+		"=LAMBDA(x,x,x)(1,2)":                                {"#VALUE!", "LAMBDA requires a valid parameter name"},
+		"=LAMBDA()":                                          {"#VALUE!", "LAMBDA requires at least 1 argument"},
+		"=LET(f,LAMBDA(x,x*10),f(4))":                        {"40", ""},
+		"=LET(a,5,g,LAMBDA(x,x+a),LET(a,100,g(1)))":          {"6", ""},
+		"=LET(a,5,g,LAMBDA(x,LAMBDA(y,x+y+a)),h,g(10),h(1))": {"16", ""},
+		"=DOUBLE(21)":                                        {"42", ""},
+		"=SUM(1,DOUBLE(A2))":                                 {"7", ""},
+		"=FACTORIAL(5)":                                      {"120", ""},
+		"=ENDLESS(1)":                                        {"#NUM!", "#NUM!"},
+		"=A1*TAX":                                            {"0.1", ""},
+		// Lambda helper functions
+		"=SUM(_xlfn.MAP(A1:B3,_xlfn.LAMBDA(_xlpm.x,_xlpm.x*_xlpm.x)))": {"91", ""},
+		"=SUM(MAP(A1:A3,B1:B3,LAMBDA(a,b,a*b)))":                       {"44", ""},
+		"=SUM(MAP(A1:A3,DOUBLE))":                                      {"18", ""},
+		"=MAP(A1:A3,LAMBDA(x,A1:B1))":                                  {"#CALC!", ""},
+		"=REDUCE(0,A1:B3,LAMBDA(acc,x,acc+x))":                         {"21", ""},
+		"=REDUCE(1,A1:A3,LAMBDA(acc,x,acc*x))":                         {"15", ""},
+		"=INDEX(SCAN(0,A1:B3,LAMBDA(acc,x,acc+x)),2,2)":                {"10", ""},
+		"=INDEX(BYROW(A1:B3,LAMBDA(r,SUM(r))),3)":                      {"11", ""},
+		"=INDEX(BYCOL(A1:B3,LAMBDA(c,MAX(c))),1,2)":                    {"6", ""},
+		"=SUM(MAKEARRAY(2,3,LAMBDA(r,c,r*c)))":                         {"18", ""},
+		"=MAKEARRAY(0,1,LAMBDA(r,c,r))":                                {"#VALUE!", "#VALUE!"},
+		"=MAKEARRAY(\"x\",1,LAMBDA(r,c,r))":                            {"#VALUE!", "#VALUE!"},
+		"=MAKEARRAY(1048577,1,LAMBDA(r,c,r))":                          {"#VALUE!", "#VALUE!"},
+		"=MAKEARRAY(1,16385,LAMBDA(r,c,r))":                            {"#VALUE!", "#VALUE!"},
+		"=MAKEARRAY(1048576,16384,LAMBDA(r,c,r))":                      {"#VALUE!", "#VALUE!"},
+		"=MAP(A1)":                    {"#VALUE!", "MAP requires at least 2 arguments"},
+		"=MAP(A1:A3,1)":               {"#VALUE!", "MAP requires a LAMBDA function as the last argument"},
+		"=MAP(A1:A3,LAMBDA(a,b,a))":   {"#VALUE!", "MAP requires a LAMBDA function with 1 parameters"},
+		"=REDUCE(0,A1:A3)":            {"#VALUE!", "REDUCE requires 3 arguments"},
+		"=REDUCE(0,A1:A3,1)":          {"#VALUE!", "REDUCE requires a LAMBDA function as the last argument"},
+		"=SCAN(0,A1:A3)":              {"#VALUE!", "SCAN requires 3 arguments"},
+		"=SCAN(0,A1:A3,LAMBDA(x,x))":  {"#VALUE!", "SCAN requires a LAMBDA function with 2 parameters"},
+		"=BYROW(A1:A3)":               {"#VALUE!", "BYROW requires 2 arguments"},
+		"=BYROW(A1:A3,1)":             {"#VALUE!", "BYROW requires a LAMBDA function as the last argument"},
+		"=BYCOL(A1:A3)":               {"#VALUE!", "BYCOL requires 2 arguments"},
+		"=BYCOL(A1:A3,1)":             {"#VALUE!", "BYCOL requires a LAMBDA function as the last argument"},
+		"=MAKEARRAY(1,1)":             {"#VALUE!", "MAKEARRAY requires 3 arguments"},
+		"=MAKEARRAY(1,1,LAMBDA(x,x))": {"#VALUE!", "MAKEARRAY requires a LAMBDA function with 2 parameters"},
+	} {
+		f := prepareData()
+		assert.NoError(t, f.SetCellFormula("Sheet1", "E1", formula))
+		result, err := f.CalcCellValue("Sheet1", "E1")
+		assert.Equal(t, expected[0], result, formula)
+		if expected[1] == "" {
+			assert.NoError(t, err, formula)
+			continue
+		}
+		assert.EqualError(t, err, expected[1], formula)
+	}
+	// Test spill the lambda helper function result
+	f := prepareData()
+	ref, formulaType := "E1:F2", STCellFormulaTypeArray
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "=MAKEARRAY(2,2,LAMBDA(r,c,r*10+c))",
+		FormulaOpts{Ref: &ref, Type: &formulaType}))
+	for cell, expected := range map[string]string{"E1": "11", "F1": "12", "E2": "21", "F2": "22"} {
+		result, err := f.CalcCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+}
+
+func TestRegisterFormulaFunc(t *testing.T) {
+	cellData := [][]interface{}{
+		{1, "a"},
+		{true, nil},
+	}
+	f := prepareCalcData(cellData)
+	assert.NoError(t, f.RegisterFormulaFunc("FXRATE", 2, 2, func(args []FormulaArg) FormulaArg {
+		if args[0].String == "USD" && args[1].String == "EUR" {
+			return FormulaArg{Type: ArgNumber, Number: 0.92}
+		}
+		return FormulaArg{Type: ArgError, Error: formulaErrorNA}
+	}))
+	assert.NoError(t, f.RegisterFormulaFunc("_xludf.ArgTypes", 0, -1, func(args []FormulaArg) FormulaArg {
+		var types []string
+		for _, arg := range args {
+			switch arg.Type {
+			case ArgNumber:
+				types = append(types, fmt.Sprintf("number:%g:%t", arg.Number, arg.Boolean))
+			case ArgString:
+				types = append(types, "string:"+arg.String)
+			case ArgError:
+				types = append(types, "error:"+arg.Error)
+			case ArgMatrix:
+				types = append(types, fmt.Sprintf("matrix:%dx%d:%s", len(arg.Matrix), len(arg.Matrix[0]), exportFormulaArg(importFormulaArg(arg.Matrix[0][1])).String))
+			default:
+				types = append(types, "empty")
+			}
+		}
+		return FormulaArg{Type: ArgString, String: strings.Join(types, ",")}
+	}))
+	assert.NoError(t, f.RegisterFormulaFunc("Result.Of", 1, 1, func(args []FormulaArg) FormulaArg {
+		switch args[0].String {
+		case "bool":
+			return FormulaArg{Type: ArgNumber, Number: 1, Boolean: true}
+		case "matrix":
+			return FormulaArg{Type: ArgMatrix, Matrix: [][]FormulaArg{
+				{{Type: ArgNumber, Number: 1}, {Type: ArgNumber, Number: 2}},
+				{{Type: ArgNumber, Number: 3}, {Type: ArgEmpty}},
+			}}
+		case "error":
+			return FormulaArg{Type: ArgError}
+		case "empty":
+			return FormulaArg{Type: ArgEmpty}
+		}
+		return FormulaArg{}
+	}))
+	for formula, expected := range map[string]string{
+		"=FXRATE(\"USD\",\"EUR\")*100":         "92",
+		"=_xludf.FXRATE(\"USD\",\"EUR\")":      "0.92",
+		"=IFERROR(FXRATE(\"USD\",\"JPY\"),1)":  "1",
+		"=ARGTYPES(1,TRUE(),\"x\",NA(),A1:B2)": "number:1:false,number:1:true,string:x,error:#N/A,matrix:2x2:a",
+		"=ARGTYPES(A1,B2)":                     "number:1:false,empty",
+		"=ARGTYPES()":                          "",
+		"=RESULT.OF(\"bool\")":                 "TRUE",
+		"=SUM(RESULT.OF(\"matrix\"))":          "6",
+		"=RESULT.OF(\"empty\")":                "",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.NoError(t, err, formula)
+		assert.Equal(t, expected, result, formula)
+	}
+	for formula, expected := range map[string][]string{
+		"=fxrate(\"USD\",\"JPY\")":     {"#N/A", "#N/A"},
+		"=FXRATE(\"USD\")":             {"#VALUE!", "FXRATE requires at least 2 arguments"},
+		"=FXRATE(\"USD\",\"EUR\",1)":   {"#VALUE!", "FXRATE allows at most 2 arguments"},
+		"=RESULT.OF()":                 {"#VALUE!", "RESULT.OF requires at least 1 argument"},
+		"=RESULT.OF(\"error\",1)":      {"#VALUE!", "RESULT.OF allows at most 1 argument"},
+		"=RESULT.OF(\"error\")":        {"#VALUE!", "#VALUE!"},
+		"=RESULT.OF(\"unknown\")":      {"#VALUE!", "#VALUE!"},
+		"=_xludf.UNKNOWN(\"unknown\")": {"#VALUE!", "not support _xludfdotUNKNOWN function"},
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", "C1", formula))
+		result, err := f.CalcCellValue("Sheet1", "C1")
+		assert.EqualError(t, err, expected[1], formula)
+		assert.Equal(t, expected[0], result, formula)
+	}
+	// Test override the built-in formula function
+	assert.NoError(t, f.SetCellFormula("Sheet1", "C1", "SUM(1,2)"))
+	assert.NoError(t, f.RegisterFormulaFunc("sum", 0, -1, func(args []FormulaArg) FormulaArg {
+		return FormulaArg{Type: ArgNumber, Number: 42}
+	}))
+	result, err := f.CalcCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "42", result)
+	f.UnregisterFormulaFunc("_xlfn.SUM")
+	result, err = f.CalcCellValue("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, "3", result)
+	// Test register formula function with invalid parameters
+	fn := func(args []FormulaArg) FormulaArg { return FormulaArg{} }
+	assert.Equal(t, ErrParameterInvalid, f.RegisterFormulaFunc("FN", 0, 0, nil))
+	assert.Equal(t, ErrParameterInvalid, f.RegisterFormulaFunc("FN", -1, 0, fn))
+	assert.Equal(t, ErrParameterInvalid, f.RegisterFormulaFunc("FN", 2, 1, fn))
+	for _, name := range []string{"", "_xludf.", "1FN", "FN 1", "FN-1"} {
+		assert.Equal(t, newInvalidNameError(name), f.RegisterFormulaFunc(name, 0, 0, fn), name)
+	}
+	assert.NoError(t, f.Close())
+
+	// Test the formula which using the registered function not be cached
+	f = NewFile(Options{CalcCache: true})
+	rate := 0.92
+	assert.NoError(t, f.RegisterFormulaFunc("FXRATE", 0, 0, func(args []FormulaArg) FormulaArg {
+		return FormulaArg{Type: ArgNumber, Number: rate}
+	}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "FXRATE()*100"))
+	result, err = f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "92", result)
+	rate = 1.5
+	result, err = f.CalcCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "150", result)
+	assert.NoError(t, f.Close())
+}
+
+func TestEvaluateFormulaSteps(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A2", "A1*3"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A3", "SUM(A1:A2,-1)+IF(A2>5,10%,0)&\"x\""))
+	steps, err := f.EvaluateFormulaSteps("Sheet1", "A3")
+	assert.NoError(t, err)
+	number := func(n float64) FormulaArg { return FormulaArg{Type: ArgNumber, Number: n} }
+	assert.Equal(t, []FormulaStep{
+		{Cell: "Sheet1!A2", Type: FormulaStepOperand, Token: "A1", Result: number(2)},
+		{Cell: "Sheet1!A2", Type: FormulaStepOperator, Token: "*", Operands: []FormulaArg{number(2), number(3)}, Result: number(6)},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperand, Token: "A1:A2", Result: FormulaArg{Type: ArgMatrix, Matrix: [][]FormulaArg{{number(2)}, {number(6)}}}},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperator, Token: "-", Operands: []FormulaArg{number(1)}, Result: number(-1)},
+		{Cell: "Sheet1!A3", Type: FormulaStepFunction, Token: "SUM", Operands: []FormulaArg{{Type: ArgMatrix, Matrix: [][]FormulaArg{{number(2)}, {number(6)}}}, number(-1)}, Result: number(7)},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperand, Token: "A2", Result: number(6)},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperator, Token: ">", Operands: []FormulaArg{number(6), number(5)}, Result: FormulaArg{Type: ArgNumber, Number: 1, Boolean: true}},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperator, Token: "%", Operands: []FormulaArg{number(10)}, Result: number(0.1)},
+		{Cell: "Sheet1!A3", Type: FormulaStepFunction, Token: "IF", Operands: []FormulaArg{{Type: ArgNumber, Number: 1, Boolean: true}, number(0.1), number(0)}, Result: number(0.1)},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperator, Token: "+", Operands: []FormulaArg{number(7), number(0.1)}, Result: number(7.1)},
+		{Cell: "Sheet1!A3", Type: FormulaStepOperator, Token: "&", Operands: []FormulaArg{number(7.1), {Type: ArgString, String: "x"}}, Result: FormulaArg{Type: ArgString, String: "7.1x"}},
+	}, steps)
+	// Test evaluate formula steps with the error result
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A4", "A1/0"))
+	steps, err = f.EvaluateFormulaSteps("Sheet1", "A4")
+	assert.EqualError(t, err, formulaErrorDIV)
+	assert.Len(t, steps, 2)
+	assert.Equal(t, FormulaArg{Type: ArgError, Error: formulaErrorDIV}, steps[1].Result)
+	// Test evaluate formula steps with the cached formula result
+	f.options.CalcCache = true
+	result, err := f.CalcCellValue("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Equal(t, "7.1x", result)
+	steps, err = f.EvaluateFormulaSteps("Sheet1", "A3")
+	assert.NoError(t, err)
+	assert.Len(t, steps, 11)
+	// Test evaluate formula steps on not exists worksheet
+	steps, err = f.EvaluateFormulaSteps("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.Empty(t, steps)
+}
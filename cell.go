@@ -68,7 +68,7 @@ var cellTypes = map[string]CellType{
 // values will be the same in a merged range.
 func (f *File) GetCellValue(sheet, cell string, opts ...Options) (string, error) {
 	return f.getCellStringFunc(sheet, cell, func(x *xlsxWorksheet, c *xlsxC) (string, bool, error) {
-		sst, err := f.sharedStringsReader()
+		sst, err := f.sharedStringsValueReader()
 		if err != nil {
 			return "", true, err
 		}
@@ -180,6 +180,10 @@ func (c *xlsxC) hasValue() bool {
 
 // removeFormula delete formula for the cell.
 func (f *File) removeFormula(c *xlsxC, ws *xlsxWorksheet, sheet string) error {
+	f.invalidateCellCalcCache(sheet, c.R)
+	if c.F != nil {
+		f.resetDependencyGraph()
+	}
 	if c.F != nil && c.Vm == nil {
 		sheetID := f.getSheetID(sheet)
 		if err := f.deleteCalcChain(sheetID, c.R); err != nil {
@@ -240,6 +244,7 @@ func (f *File) setCellTimeFunc(sheet, cell string, value time.Time) error {
 	if err != nil {
 		return err
 	}
+	f.invalidateCellCalcCache(sheet, cell)
 	ws.mu.Lock()
 	c.S = ws.prepareCellStyle(col, row, c.S)
 	ws.mu.Unlock()
@@ -454,6 +459,10 @@ func (f *File) setCellString(value string) (t, v string, err error) {
 func (f *File) sharedStringsLoader() (err error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	if err = f.sharedStringsIdx.close(); err != nil {
+		return
+	}
+	f.sharedStringsIdx = nil
 	if path, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok {
 		f.Pkg.Store(defaultXMLPathSharedStrings, f.readBytes(defaultXMLPathSharedStrings))
 		f.tempFiles.Delete(defaultXMLPathSharedStrings)
@@ -467,8 +476,7 @@ func (f *File) sharedStringsLoader() (err error) {
 			return err
 		}
 		f.tempFiles.Delete(defaultTempFileSST)
-		f.sharedStringItem, err = nil, os.Remove(f.sharedStringTemp.Name())
-		f.sharedStringTemp = nil
+		f.sharedStringTemp, err = nil, os.Remove(f.sharedStringTemp.Name())
 	}
 	return
 }
@@ -609,7 +617,7 @@ func (c *xlsxC) getValueFrom(f *File, d *xlsxSST, raw bool) (string, error) {
 	case "s":
 		if c.V != "" {
 			xlsxSI, _ := strconv.Atoi(strings.TrimSpace(c.V))
-			if _, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok {
+			if _, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok || d == nil {
 				return f.formattedValue(&xlsxC{S: c.S, V: f.getFromStringItem(xlsxSI)}, raw, CellTypeSharedString)
 			}
 			d.mu.Lock()
@@ -785,6 +793,11 @@ func (f *File) SetCellFormula(sheet, cell, formula string, opts ...FormulaOpts)
 	if err != nil {
 		return err
 	}
+	f.invalidateCellCalcCache(sheet, cell)
+	f.resetDependencyGraph()
+	if len(opts) > 0 {
+		f.clearCalcCache()
+	}
 	if formula == "" {
 		c.F = nil
 		return f.deleteCalcChain(f.getSheetID(sheet), cell)
@@ -1091,10 +1104,17 @@ func (f *File) GetCellRichText(sheet, cell string) (runs []RichTextRun, err erro
 	if err != nil || c.T != "s" {
 		return
 	}
-	sst, err := f.sharedStringsReader()
+	sst, err := f.sharedStringsValueReader()
 	if err != nil {
 		return
 	}
+	if sst == nil {
+		var si *xlsxSI
+		if si, err = f.getSharedStringItem(siIdx); err == nil && si != nil {
+			runs = getCellRichText(si)
+		}
+		return
+	}
 	if len(sst.SI) <= siIdx || siIdx < 0 {
 		return
 	}
@@ -1317,6 +1337,7 @@ func (f *File) SetCellRichText(sheet, cell string, runs []RichTextRun) error {
 	if err := f.sharedStringsLoader(); err != nil {
 		return err
 	}
+	f.invalidateCellCalcCache(sheet, cell)
 	c.S = ws.prepareCellStyle(col, row, c.S)
 	si := xlsxSI{}
 	sst, err := f.sharedStringsReader()
@@ -1662,22 +1683,29 @@ func getSharedFormula(ws *xlsxWorksheet, si int, cell string) string {
 		for column := 0; column < len(r.C); column++ {
 			c := &r.C[column]
 			if c.F != nil && c.F.Ref != "" && c.F.T == STCellFormulaTypeShared && c.F.Si != nil && *c.F.Si == si {
-				col, row, _ := CellNameToCoordinates(cell)
-				sharedCol, sharedRow, _ := CellNameToCoordinates(c.R)
-				dCol := col - sharedCol
-				dRow := row - sharedRow
-				orig := []byte(c.F.Content)
-				res, start := parseSharedFormula(dCol, dRow, orig)
-				if start < len(orig) {
-					res += string(orig[start:])
-				}
-				return res
+				return shiftSharedFormula(c.R, c.F.Content, cell)
 			}
 		}
 	}
 	return ""
 }
 
+// shiftSharedFormula returns the formula of the cell in the shared formula
+// range by given anchor cell reference and formula of the shared formula, and
+// the cell reference.
+func shiftSharedFormula(anchor, formula, cell string) string {
+	col, row, _ := CellNameToCoordinates(cell)
+	sharedCol, sharedRow, _ := CellNameToCoordinates(anchor)
+	dCol := col - sharedCol
+	dRow := row - sharedRow
+	orig := []byte(formula)
+	res, start := parseSharedFormula(dCol, dRow, orig)
+	if start < len(orig) {
+		res += string(orig[start:])
+	}
+	return res
+}
+
 // shiftCell returns the cell shifted according to dCol and dRow taking into
 // consideration absolute references with dollar sign ($)
 func shiftCell(cellID string, dCol, dRow int) string {
@@ -32,6 +32,16 @@ func (c *xlsxC) getRichValueFrom(f *File, d *xlsxSST, raw bool) (RichText, error
 			//if _, ok := f.tempFiles.Load(defaultXMLPathSharedStrings); ok {
 			//	return f.formattedValue(&xlsxC{S: c.S, V: f.getFromStringItem(xlsxSI)}, raw, CellTypeSharedString)
 			//}
+			if d == nil {
+				if shared, err := f.getSharedStringItem(xlsxSI); err == nil && shared != nil {
+					if shared.R == nil {
+						return f.formattedRichValue(&xlsxC{S: c.S, V: shared.String()}, raw, CellTypeSharedString)
+					}
+					return RichText{f.getCellRichText(shared)}, nil
+				}
+				val, err := f.formattedValue(c, raw, CellTypeSharedString)
+				return newRichText(val), err
+			}
 			d.mu.Lock()
 			defer d.mu.Unlock()
 			if len(d.SI) > xlsxSI {
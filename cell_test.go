@@ -1,6 +1,7 @@
 package excelize
 
 import (
+	"encoding/binary"
 	"fmt"
 	_ "image/jpeg"
 	"math"
@@ -1072,7 +1073,11 @@ func TestSharedStringsError(t *testing.T) {
 			_, err := rows.Columns()
 			assert.NoError(t, err)
 			// Test get cell value from string item with invalid offset
-			f.sharedStringItem[1] = []uint{maxUint16 - 1, maxUint16}
+			buf := make([]byte, 16)
+			binary.LittleEndian.PutUint64(buf[:8], maxUint16-1)
+			binary.LittleEndian.PutUint64(buf[8:], maxUint16)
+			_, err = f.sharedStringTemp.WriteAt(buf, 16)
+			assert.NoError(t, err)
 			assert.Equal(t, "1", f.getFromStringItem(1))
 			break
 		}
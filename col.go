@@ -93,7 +93,7 @@ func (cols *Cols) Rows(opts ...Options) ([]string, error) {
 		return rowIterator.cells, rowIterator.err
 	}
 	cols.rawCellValue = cols.f.getOptions(opts...).RawCellValue
-	if cols.sst, rowIterator.err = cols.f.sharedStringsReader(); rowIterator.err != nil {
+	if cols.sst, rowIterator.err = cols.f.sharedStringsValueReader(); rowIterator.err != nil {
 		return rowIterator.cells, rowIterator.err
 	}
 	decoder := cols.f.xmlNewDecoder(bytes.NewReader(cols.sheetXML))
@@ -0,0 +1,275 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// CSVOptions directly maps the settings of importing and exporting the
+// worksheet data in the comma-separated values (CSV) format.
+//
+// Delimiter specifies the field delimiter, the default value is comma (,).
+//
+// LazyQuotes specifies if a quote may appear in an unquoted field and a
+// non-doubled quote may appear in a quoted field on import.
+//
+// QuoteAll specifies if enclose all fields in double quotes on export, the
+// fields will be quoted only if necessary by default.
+//
+// UseCRLF specifies if use \r\n as the line terminator on export, the default
+// line terminator is \n.
+//
+// Charset specifies the character encoding of the CSV data, such as GBK or
+// Shift_JIS, the data will be decoded by the CharsetReader transcoder of the
+// workbook on import, and encoded by the encoding which looked up by the
+// charset label on export. The default character encoding is UTF-8.
+//
+// Cell specifies the top-left cell reference which the CSV data will be
+// imported to, the default value is A1.
+//
+// InferTypes specifies if convert the fields which look like numbers, boolean
+// values or dates to the corresponding cell types on import, the fields will
+// be imported as strings by default.
+//
+// DateLayouts specifies the time layouts for recognizing the date fields when
+// inferring types, the default layouts are the ISO 8601 date and time formats,
+// such as 2006-01-02 and 2006-01-02 15:04:05.
+//
+// RawCellValue specifies if export the raw cell values without applying the
+// number formats.
+type CSVOptions struct {
+	Delimiter    rune
+	LazyQuotes   bool
+	QuoteAll     bool
+	UseCRLF      bool
+	Charset      string
+	Cell         string
+	InferTypes   bool
+	DateLayouts  []string
+	RawCellValue bool
+}
+
+// getCSVOptions provides a function to parse the optional settings for
+// importing and exporting CSV data.
+func getCSVOptions(opts ...CSVOptions) (*CSVOptions, error) {
+	options := CSVOptions{}
+	if len(opts) > 0 {
+		options = opts[len(opts)-1]
+	}
+	if options.Delimiter == 0 {
+		options.Delimiter = ','
+	}
+	if options.Delimiter == '"' || options.Delimiter == '\r' || options.Delimiter == '\n' ||
+		!utf8.ValidRune(options.Delimiter) || options.Delimiter == utf8.RuneError {
+		return &options, ErrParameterInvalid
+	}
+	if options.Cell == "" {
+		options.Cell = "A1"
+	}
+	if options.DateLayouts == nil {
+		options.DateLayouts = defaultDateLayouts
+	}
+	return &options, nil
+}
+
+// ImportCSV provides a function to import the comma-separated values (CSV)
+// data from the reader into the worksheet by given worksheet name, the data
+// will be imported row by row start from the top-left cell which specified by
+// the Cell option. The records in the CSV data could have a variable number
+// of fields. For example, import the CSV file which encoded in GBK into
+// Sheet1, and convert the numeric, boolean and date fields to the
+// corresponding cell types:
+//
+//	file, err := os.Open("data.csv")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	err = f.ImportCSV("Sheet1", file, excelize.CSVOptions{
+//	    Charset:    "gbk",
+//	    InferTypes: true,
+//	})
+func (f *File) ImportCSV(sheet string, reader io.Reader, opts ...CSVOptions) error {
+	options, err := getCSVOptions(opts...)
+	if err != nil {
+		return err
+	}
+	col, row, err := CellNameToCoordinates(options.Cell)
+	if err != nil {
+		return err
+	}
+	if !options.isUTF8() {
+		if reader, err = f.CharsetReader(options.Charset, reader); err != nil {
+			return err
+		}
+	}
+	br := bufio.NewReader(reader)
+	bom, err := br.Peek(3)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(len(bom))
+	}
+	r := csv.NewReader(br)
+	r.Comma, r.LazyQuotes, r.FieldsPerRecord = options.Delimiter, options.LazyQuotes, -1
+	for ; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(record))
+		for i, field := range record {
+			values[i] = options.inferCSVValue(field)
+		}
+		cell, err := CoordinatesToCellName(col, row)
+		if err != nil {
+			return err
+		}
+		if err = f.SetSheetRow(sheet, cell, &values); err != nil {
+			return err
+		}
+	}
+}
+
+// isUTF8 returns if the character encoding of the CSV data is UTF-8.
+func (opts *CSVOptions) isUTF8() bool {
+	return opts.Charset == "" || strings.EqualFold(opts.Charset, "utf-8") || strings.EqualFold(opts.Charset, "utf8")
+}
+
+// inferCSVValue returns the cell value of the CSV field, the field will be
+// converted to number, boolean or time when the InferTypes option is enabled.
+// The numeric field with leading zeros will be kept as text, such as the zip
+// codes and identifiers.
+func (opts *CSVOptions) inferCSVValue(field string) interface{} {
+	if !opts.InferTypes || field == "" {
+		return field
+	}
+	if ok, _, _ := isNumeric(field); ok && !hasLeadingZeros(field) {
+		if num, err := strconv.ParseFloat(field, 64); err == nil && !math.IsInf(num, 0) && !math.IsNaN(num) {
+			return num
+		}
+	}
+	if strings.EqualFold(field, "TRUE") || strings.EqualFold(field, "FALSE") {
+		return strings.EqualFold(field, "TRUE")
+	}
+	for _, layout := range opts.DateLayouts {
+		if t, err := time.Parse(layout, field); err == nil {
+			return t
+		}
+	}
+	return field
+}
+
+// hasLeadingZeros returns if the integer part of the numeric text has leading
+// zeros, for example "007" and "-00.5".
+func hasLeadingZeros(field string) bool {
+	field = strings.TrimLeft(field, "+-")
+	return len(field) > 1 && field[0] == '0' && '0' <= field[1] && field[1] <= '9'
+}
+
+// ExportCSV provides a function to export the worksheet data in the
+// comma-separated values (CSV) format to the writer by given worksheet name.
+// The worksheet data will be read by the streaming rows iterator, so the
+// large worksheet could be exported without loading it into memory. Each
+// row of the worksheet will be written as a record, and the empty cells in
+// the tail of the row will be skipped as the GetRows function does. The cell
+// values will be formatted by the number formats unless the RawCellValue
+// option is enabled, and the data will be encoded by the Charset option. For
+// example, export Sheet1 with semicolon delimiter:
+//
+//	var buf bytes.Buffer
+//	err := f.ExportCSV("Sheet1", &buf, excelize.CSVOptions{Delimiter: ';'})
+func (f *File) ExportCSV(sheet string, writer io.Writer, opts ...CSVOptions) error {
+	options, err := getCSVOptions(opts...)
+	if err != nil {
+		return err
+	}
+	var encoder io.WriteCloser
+	if !options.isUTF8() {
+		enc, err := htmlindex.Get(options.Charset)
+		if err != nil {
+			return fmt.Errorf("unsupported charset: %q", options.Charset)
+		}
+		encoder = transform.NewWriter(writer, enc.NewEncoder())
+		writer = encoder
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(writer)
+	for rows.Next() {
+		record, err := rows.Columns(Options{RawCellValue: options.RawCellValue})
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if err = options.writeCSVRecord(w, record); err != nil {
+			_ = rows.Close()
+			return err
+		}
+	}
+	if err = rows.Close(); err != nil {
+		return err
+	}
+	if err = w.Flush(); err != nil || encoder == nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// writeCSVRecord writes a single CSV record to the writer, the field will be
+// enclosed in double quotes if the QuoteAll option is enabled or the field
+// contains the delimiter, double quotes, line breaks or the leading space.
+func (opts *CSVOptions) writeCSVRecord(w *bufio.Writer, record []string) error {
+	for i, field := range record {
+		if i > 0 {
+			if _, err := w.WriteRune(opts.Delimiter); err != nil {
+				return err
+			}
+		}
+		if !opts.QuoteAll && !strings.ContainsAny(field, string(opts.Delimiter)+"\"\r\n") &&
+			!strings.HasPrefix(field, " ") && !strings.HasPrefix(field, "\t") {
+			if _, err := w.WriteString(field); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := w.WriteString("\"" + strings.ReplaceAll(field, "\"", "\"\"") + "\""); err != nil {
+			return err
+		}
+	}
+	lineTerminator := "\n"
+	if opts.UseCRLF {
+		lineTerminator = "\r\n"
+	}
+	_, err := w.WriteString(lineTerminator)
+	return err
+}
@@ -0,0 +1,164 @@
+package excelize
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportCSV(t *testing.T) {
+	f := NewFile()
+	data := "Name,Qty,Price,Paid,Date\n" +
+		"\"Apple, red\",3,1.5,true,2024-01-02\n" +
+		"\"Say \"\"Hi\"\"\",007,1e3,FALSE,2024-01-02 15:04:05\n" +
+		"Inf,,-2,yes,00123\n" +
+		"0,0.5,-0.25,-007,0e1\n"
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader(data), CSVOptions{InferTypes: true}))
+	rows, err := f.GetRows("Sheet1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name", "Qty", "Price", "Paid", "Date"},
+		{"Apple, red", "3", "1.5", "1", "45293"},
+		{"Say \"Hi\"", "007", "1000", "0", "45293.62783564815"},
+		{"Inf", "", "-2", "yes", "00123"},
+		{"0", "0.5", "-0.25", "-007", "0"},
+	}, rows)
+	for cell, expected := range map[string]CellType{
+		"A2": CellTypeSharedString, "B2": CellTypeUnset, "D2": CellTypeBool, "A4": CellTypeSharedString,
+		"B3": CellTypeSharedString, "E4": CellTypeSharedString, "A5": CellTypeUnset, "D5": CellTypeSharedString,
+	} {
+		cellType, err := f.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cellType, cell)
+	}
+	value, err := f.GetCellValue("Sheet1", "E2")
+	assert.NoError(t, err)
+	assert.Equal(t, "1/2/24 00:00", value)
+
+	// Test import CSV without type inference, with custom delimiter and start cell
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("1;TRUE;2024-01-02\nx;\"a;b\"\n"), CSVOptions{Delimiter: ';', Cell: "B3"}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{nil, nil, {"", "1", "TRUE", "2024-01-02"}, {"", "x", "a;b"}}, rows)
+	cellType, err := f.GetCellType("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeSharedString, cellType)
+
+	// Test import CSV with UTF-8 byte order mark
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("\uFEFFName,Qty\nApple,3\n"), CSVOptions{InferTypes: true}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name", "Qty"}, {"Apple", "3"}}, rows)
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("\uFEFF"), CSVOptions{Cell: "A3"}))
+
+	// Test import CSV with custom date layouts and lazy quotes
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("01/02/2024,a \"b\" c\n"), CSVOptions{
+		InferTypes: true, LazyQuotes: true, DateLayouts: []string{"01/02/2006"},
+	}))
+	rows, err = f.GetRows("Sheet1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"45293", "a \"b\" c"}}, rows)
+
+	// Test import CSV with character encoding
+	f = NewFile()
+	assert.NoError(t, f.ImportCSV("Sheet1", bytes.NewReader(MacintoshCyrillicCharset), CSVOptions{Charset: "x-mac-cyrillic"}))
+	value, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Привет мир", value)
+	assert.NoError(t, f.ImportCSV("Sheet1", strings.NewReader("UTF-8"), CSVOptions{Charset: "UTF-8", Cell: "A2"}))
+	value, err = f.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "UTF-8", value)
+	// Test import CSV with unsupported character encoding
+	assert.EqualError(t, f.ImportCSV("Sheet1", strings.NewReader(""), CSVOptions{Charset: "unknown"}), "unsupported charset: \"unknown\"")
+	// Test import CSV with invalid options
+	assert.Equal(t, ErrParameterInvalid, f.ImportCSV("Sheet1", strings.NewReader(""), CSVOptions{Delimiter: '"'}))
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.ImportCSV("Sheet1", strings.NewReader(""), CSVOptions{Cell: "A"}))
+	// Test import CSV with invalid CSV data
+	assert.EqualError(t, f.ImportCSV("Sheet1", strings.NewReader("a\"b\n")), "parse error on line 1, column 2: bare \" in non-quoted-field")
+	// Test import CSV with the reader error
+	assert.EqualError(t, f.ImportCSV("Sheet1", errReader{}), "read error")
+	// Test import CSV exceeds the maximum rows
+	assert.Equal(t, ErrMaxRows, f.ImportCSV("Sheet1", strings.NewReader("a\nb\n"), CSVOptions{Cell: "A1048576"}))
+	// Test import CSV on not exists worksheet
+	assert.EqualError(t, f.ImportCSV("SheetN", strings.NewReader("a")), "sheet SheetN does not exist")
+}
+
+// errReader defined the reader which always returns an error on reading.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("read error") }
+
+func TestExportCSV(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Price", "C1": "Date",
+		"A2": "Apple, red", "B2": 1.5, "C2": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"A3": "Say \"Hi\"", "B3": 1000,
+		"A5": " leading space", "B5": "multi\nline",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	style, err := f.NewStyle(&Style{NumFmt: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B3", "B3", style))
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf))
+	assert.Equal(t, "Name,Price,Date\n\"Apple, red\",1.5,1/2/24 00:00\n\"Say \"\"Hi\"\"\",\"1,000.00\"\n\n\" leading space\",\"multi\nline\"\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{Delimiter: ';', RawCellValue: true, UseCRLF: true}))
+	assert.Equal(t, "Name;Price;Date\r\nApple, red;1.5;45293\r\n\"Say \"\"Hi\"\"\";1000\r\n\r\n\" leading space\";\"multi\nline\"\r\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, f.ExportCSV("Sheet1", &buf, CSVOptions{QuoteAll: true, RawCellValue: true}))
+	assert.Equal(t, "\"Name\",\"Price\",\"Date\"\n\"Apple, red\",\"1.5\",\"45293\"\n\"Say \"\"Hi\"\"\",\"1000\"\n\n\" leading space\",\"multi\nline\"\n", buf.String())
+
+	// Test export CSV and import it back
+	f2 := NewFile()
+	assert.NoError(t, f2.ImportCSV("Sheet1", &buf, CSVOptions{InferTypes: true}))
+	rows, err := f2.GetRows("Sheet1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Name", "Price", "Date"}, {"Apple, red", "1.5", "45293"}, {"Say \"Hi\"", "1000"}, {" leading space", "multi\nline"}}, rows)
+
+	// Test export CSV with character encoding and import it back
+	f2 = NewFile()
+	assert.NoError(t, f2.SetCellValue("Sheet1", "A1", "Привет мир"))
+	buf.Reset()
+	assert.NoError(t, f2.ExportCSV("Sheet1", &buf, CSVOptions{Charset: "x-mac-cyrillic"}))
+	assert.Equal(t, append(MacintoshCyrillicCharset, '\n'), buf.Bytes())
+	assert.NoError(t, f2.ImportCSV("Sheet1", &buf, CSVOptions{Charset: "x-mac-cyrillic", Cell: "A2"}))
+	value, err := f2.GetCellValue("Sheet1", "A2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Привет мир", value)
+	// Test export CSV with the characters which not supported by the charset
+	assert.NoError(t, f2.SetCellValue("Sheet1", "A1", "中文"))
+	assert.EqualError(t, f2.ExportCSV("Sheet1", io.Discard, CSVOptions{Charset: "x-mac-cyrillic"}), "encoding: rune not supported by encoding.")
+	// Test export CSV with unsupported character encoding
+	assert.EqualError(t, f2.ExportCSV("Sheet1", &buf, CSVOptions{Charset: "unknown"}), "unsupported charset: \"unknown\"")
+	assert.NoError(t, f2.Close())
+
+	// Test export CSV with invalid options
+	assert.Equal(t, ErrParameterInvalid, f.ExportCSV("Sheet1", &buf, CSVOptions{Delimiter: '\n'}))
+	// Test export CSV on not exists worksheet
+	assert.EqualError(t, f.ExportCSV("SheetN", &buf), "sheet SheetN does not exist")
+	// Test export CSV with the writer error
+	assert.EqualError(t, f.ExportCSV("Sheet1", errWriter{}), "write error")
+	// Test export CSV with unsupported charset shared strings table
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportCSV("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+}
+
+// errWriter defined the writer which always returns an error on writing.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("write error") }
@@ -0,0 +1,693 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xuri/efp"
+)
+
+// dependencyNode directly maps a formula cell in the dependency graph.
+type dependencyNode struct {
+	sheet, cell string
+	col, row    int
+}
+
+// dependencyGraph directly maps the formula dependency graph of the workbook,
+// the references of each formula cell are extracted from the formula tokens,
+// the defined names and the structured references will be converted to the
+// cell ranges. The cellDependents and rangeDependents are the reverse index of
+// the references, which maps the referenced cells and ranges on each
+// worksheet to the index of the formula cells refer to them.
+type dependencyGraph struct {
+	f               *File
+	sheets          []string
+	nodes           []dependencyNode
+	index           map[string]int
+	precedents      [][]cellRange
+	cellDependents  map[cellRef][]int
+	rangeDependents map[string]map[cellRange][]int
+}
+
+// getDependencyGraph provides a function to get the formula dependency graph
+// of all formula cells in the workbook, the graph will be built once and
+// cached until the formulas, defined names, tables or worksheets changed.
+func (f *File) getDependencyGraph() (*dependencyGraph, error) {
+	f.calcCache.mu.Lock()
+	g := f.calcCache.graph
+	f.calcCache.mu.Unlock()
+	if g != nil {
+		return g, nil
+	}
+	g, err := f.newDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	f.calcCache.mu.Lock()
+	f.calcCache.graph = g
+	f.calcCache.mu.Unlock()
+	return g, nil
+}
+
+// newDependencyGraph provides a function to build the formula dependency
+// graph of all formula cells in the workbook.
+func (f *File) newDependencyGraph() (*dependencyGraph, error) {
+	cells, err := f.getFormulaCells()
+	if err != nil {
+		return nil, err
+	}
+	g := &dependencyGraph{
+		f:          f,
+		sheets:     f.GetSheetList(),
+		index:      make(map[string]int, len(cells)),
+		precedents: make([][]cellRange, len(cells)),
+	}
+	for i, cell := range cells {
+		col, row, err := CellNameToCoordinates(cell[1])
+		if err != nil {
+			return nil, err
+		}
+		g.nodes = append(g.nodes, dependencyNode{sheet: cell[0], cell: cell[1], col: col, row: row})
+		g.index[fmt.Sprintf("%s!%s", cell[0], cell[1])] = i
+	}
+	for i, node := range g.nodes {
+		formula, err := f.getCellFormula(node.sheet, node.cell, true)
+		if err != nil {
+			return nil, err
+		}
+		g.precedents[i] = g.getFormulaReferences(node.sheet, node.cell, formula, map[string]bool{})
+	}
+	g.indexDependents()
+	return g, nil
+}
+
+// indexDependents provides a function to build the reverse index of the
+// references in the dependency graph, the single cell references and the
+// range references will be indexed separately.
+func (g *dependencyGraph) indexDependents() {
+	g.cellDependents, g.rangeDependents = map[cellRef][]int{}, map[string]map[cellRange][]int{}
+	for i, refs := range g.precedents {
+		for _, cr := range refs {
+			if cr.From.Col == cr.To.Col && cr.From.Row == cr.To.Row {
+				ref := cellRef{Col: cr.From.Col, Row: cr.From.Row, Sheet: cr.From.Sheet}
+				g.cellDependents[ref] = append(g.cellDependents[ref], i)
+				continue
+			}
+			ranges, ok := g.rangeDependents[cr.From.Sheet]
+			if !ok {
+				ranges = map[cellRange][]int{}
+				g.rangeDependents[cr.From.Sheet] = ranges
+			}
+			ranges[cr] = append(ranges[cr], i)
+		}
+	}
+}
+
+// getSheetName returns the worksheet name in the workbook by given case
+// insensitive worksheet name.
+func (g *dependencyGraph) getSheetName(name string) (string, bool) {
+	for _, sheet := range g.sheets {
+		if strings.EqualFold(sheet, name) {
+			return sheet, true
+		}
+	}
+	return name, false
+}
+
+// getFormulaReferences returns the cell ranges which referenced in the formula
+// by given worksheet name, cell reference and formula.
+func (g *dependencyGraph) getFormulaReferences(sheet, cell, formula string, definedNames map[string]bool) []cellRange {
+	var refs []cellRange
+	ps := efp.ExcelParser()
+	tokens := mergeStructuredReferenceTokens(ps.Parse(strings.TrimPrefix(formula, "=")))
+	localNames := getFormulaLocalNames(tokens)
+	for _, token := range tokens {
+		if token.TType != efp.TokenTypeOperand || token.TSubType != efp.TokenSubTypeRange || localNames[localName(token.TValue)] {
+			continue
+		}
+		refs = append(refs, g.getOperandReferences(sheet, cell, token.TValue, definedNames)...)
+	}
+	return refs
+}
+
+// getFormulaLocalNames returns the local names which defined by the LET and
+// LAMBDA formula functions in the formula tokens.
+func getFormulaLocalNames(tokens []efp.Token) map[string]bool {
+	names := make(map[string]bool)
+	for i, token := range tokens {
+		if !isFunctionStartToken(token) {
+			continue
+		}
+		fn := strings.ToUpper(strings.TrimPrefix(token.TValue, "_xlfn."))
+		if fn != "LET" && fn != "LAMBDA" {
+			continue
+		}
+		_, args := getFunctionArgTokens(tokens, i)
+		for j := 0; j < len(args)-1; j++ {
+			if fn == "LET" && j%2 == 1 {
+				continue
+			}
+			if name, ok := getLocalNameToken(args[j]); ok {
+				names[name] = true
+			}
+		}
+	}
+	return names
+}
+
+// getOperandReferences returns the cell ranges by given range operand, the
+// operand could be a cell reference, a range reference, a 3-D reference, a
+// defined name or a structured reference.
+func (g *dependencyGraph) getOperandReferences(sheet, cell, operand string, definedNames map[string]bool) []cellRange {
+	if refTo := g.f.getDefinedNameRefTo(operand, sheet); refTo != "" {
+		if definedNames[refTo] {
+			return nil
+		}
+		definedNames[refTo] = true
+		defer delete(definedNames, refTo)
+		return g.getFormulaReferences(sheet, cell, refTo, definedNames)
+	}
+	if strings.HasSuffix(operand, "]") {
+		ref, err := g.f.parseStructuredReference(sheet, cell, operand)
+		if err != nil {
+			return nil
+		}
+		operand = ref
+	}
+	if idx := strings.LastIndex(operand, "!"); idx != -1 && strings.Contains(operand[:idx], ":") {
+		var refs []cellRange
+		sheets := strings.SplitN(operand[:idx], ":", 2)
+		from, to := inStrSlice(g.sheets, sheets[0], false), inStrSlice(g.sheets, sheets[1], false)
+		if from == -1 || to == -1 {
+			return nil
+		}
+		if from > to {
+			from, to = to, from
+		}
+		for _, name := range g.sheets[from : to+1] {
+			refs = append(refs, g.getOperandReferences(sheet, cell, name+operand[idx:], definedNames)...)
+		}
+		return refs
+	}
+	cr, _, err := parseReferenceRange(sheet, operand)
+	if err != nil || cr.From.Col == 0 || cr.From.Row == 0 {
+		return nil
+	}
+	var ok bool
+	if cr.From.Sheet, ok = g.getSheetName(cr.From.Sheet); !ok {
+		return nil
+	}
+	cr.To.Sheet = cr.From.Sheet
+	return []cellRange{cr}
+}
+
+// contains determine if the cell range contains the cell by given worksheet
+// name and cell coordinates.
+func (cr cellRange) contains(sheet string, col, row int) bool {
+	return cr.From.Sheet == sheet && col >= cr.From.Col && col <= cr.To.Col &&
+		row >= cr.From.Row && row <= cr.To.Row
+}
+
+// String returns the range reference of the cell range with the worksheet
+// name, such as Sheet1!A1 or Sheet1!A1:B2.
+func (cr cellRange) String() string {
+	from, _ := CoordinatesToCellName(cr.From.Col, cr.From.Row)
+	if cr.From.Col == cr.To.Col && cr.From.Row == cr.To.Row {
+		return fmt.Sprintf("%s!%s", cr.From.Sheet, from)
+	}
+	to, _ := CoordinatesToCellName(cr.To.Col, cr.To.Row)
+	return fmt.Sprintf("%s!%s:%s", cr.From.Sheet, from, to)
+}
+
+// getFormulaCellsInRange returns the index of formula cells in the dependency
+// graph which in the given cell range.
+func (g *dependencyGraph) getFormulaCellsInRange(cr cellRange) []int {
+	var cells []int
+	for i, node := range g.nodes {
+		if cr.contains(node.sheet, node.col, node.row) {
+			cells = append(cells, i)
+		}
+	}
+	return cells
+}
+
+// getPrecedents returns the references which the formula cell refers to by
+// given index of the formula cell in the dependency graph.
+func (g *dependencyGraph) getPrecedents(idx int, recursive bool) []string {
+	var (
+		refs    []string
+		queue   = []int{idx}
+		seen    = map[string]bool{}
+		visited = map[int]bool{idx: true}
+	)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, cr := range g.precedents[node] {
+			if ref := cr.String(); !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+			if !recursive {
+				continue
+			}
+			for _, cell := range g.getFormulaCellsInRange(cr) {
+				if !visited[cell] {
+					visited[cell] = true
+					queue = append(queue, cell)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// getDependents returns the formula cells which refer to the cell by given
+// worksheet name and cell coordinates.
+func (g *dependencyGraph) getDependents(sheet string, col, row int, recursive bool) []string {
+	var (
+		cells   []string
+		queue   = []dependencyNode{{sheet: sheet, col: col, row: row}}
+		visited = map[int]bool{}
+	)
+	for len(queue) > 0 {
+		target := queue[0]
+		queue = queue[1:]
+		dependents := append([]int{}, g.cellDependents[cellRef{Col: target.col, Row: target.row, Sheet: target.sheet}]...)
+		for cr, idx := range g.rangeDependents[target.sheet] {
+			if cr.contains(target.sheet, target.col, target.row) {
+				dependents = append(dependents, idx...)
+			}
+		}
+		sort.Ints(dependents)
+		for _, i := range dependents {
+			if visited[i] {
+				continue
+			}
+			visited[i] = true
+			node := g.nodes[i]
+			cells = append(cells, fmt.Sprintf("%s!%s", node.sheet, node.cell))
+			if recursive {
+				queue = append(queue, node)
+			}
+		}
+	}
+	return cells
+}
+
+// getCycles returns the formula cells of each circular reference in the
+// dependency graph by the Tarjan's strongly connected components algorithm.
+func (g *dependencyGraph) getCycles() [][]string {
+	var (
+		cycles  [][]string
+		stack   []int
+		counter int
+		edges   = make([][]int, len(g.nodes))
+		index   = make([]int, len(g.nodes))
+		lowLink = make([]int, len(g.nodes))
+		onStack = make([]bool, len(g.nodes))
+		visit   func(node int)
+	)
+	for i := range g.nodes {
+		for _, cr := range g.precedents[i] {
+			edges[i] = append(edges[i], g.getFormulaCellsInRange(cr)...)
+		}
+	}
+	visit = func(node int) {
+		counter++
+		index[node], lowLink[node] = counter, counter
+		stack, onStack[node] = append(stack, node), true
+		for _, next := range edges[node] {
+			if index[next] == 0 {
+				if visit(next); lowLink[next] < lowLink[node] {
+					lowLink[node] = lowLink[next]
+				}
+			} else if onStack[next] && index[next] < lowLink[node] {
+				lowLink[node] = index[next]
+			}
+		}
+		if lowLink[node] != index[node] {
+			return
+		}
+		var component []int
+		for {
+			top := stack[len(stack)-1]
+			stack, onStack[top] = stack[:len(stack)-1], false
+			if component = append(component, top); top == node {
+				break
+			}
+		}
+		if len(component) == 1 {
+			selfRef := false
+			for _, next := range edges[node] {
+				selfRef = selfRef || next == node
+			}
+			if !selfRef {
+				return
+			}
+		}
+		sort.Ints(component)
+		var cycle []string
+		for _, cell := range component {
+			cycle = append(cycle, fmt.Sprintf("%s!%s", g.nodes[cell].sheet, g.nodes[cell].cell))
+		}
+		cycles = append(cycles, cycle)
+	}
+	for i := range g.nodes {
+		if index[i] == 0 {
+			visit(i)
+		}
+	}
+	return cycles
+}
+
+// getCellDependencyArgs checks and returns the worksheet name, cell
+// coordinates and options by given worksheet name, cell reference and
+// options.
+func (f *File) getCellDependencyArgs(sheet, cell string, opts ...DependencyOptions) (string, int, int, *DependencyOptions, error) {
+	options := &DependencyOptions{}
+	for _, opt := range opts {
+		options = &opt
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return sheet, col, row, options, err
+	}
+	idx, err := f.GetSheetIndex(sheet)
+	if err != nil {
+		return sheet, col, row, options, err
+	}
+	if idx == -1 {
+		return sheet, col, row, options, ErrSheetNotExist{sheet}
+	}
+	return f.GetSheetList()[idx], col, row, options, err
+}
+
+// GetCellPrecedents provides a function to get the references which the
+// formula of the cell refers to by given worksheet name and cell reference.
+// The defined names and the structured references in the formula will be
+// converted to the range references, and the returned references are
+// qualified with the worksheet name, such as Sheet1!A1 or Sheet1!A1:B2. Set
+// the Recursive field of the options to get the indirect precedents in
+// addition to the direct precedents. For example, get all precedents of the
+// cell D10 on Sheet1:
+//
+//	refs, err := f.GetCellPrecedents("Sheet1", "D10", excelize.DependencyOptions{Recursive: true})
+func (f *File) GetCellPrecedents(sheet, cell string, opts ...DependencyOptions) ([]string, error) {
+	sheet, col, row, options, err := f.getCellDependencyArgs(sheet, cell, opts...)
+	if err != nil {
+		return nil, err
+	}
+	g, err := f.getDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	cell, _ = CoordinatesToCellName(col, row)
+	idx, ok := g.index[fmt.Sprintf("%s!%s", sheet, cell)]
+	if !ok {
+		return nil, err
+	}
+	return g.getPrecedents(idx, options.Recursive), err
+}
+
+// GetCellDependents provides a function to get the formula cells which refer
+// to the cell by given worksheet name and cell reference. The returned cells
+// are qualified with the worksheet name, such as Sheet1!A1. Set the Recursive
+// field of the options to get the indirect dependents in addition to the
+// direct dependents. For example, get all cells which affected if the cell B2
+// on Sheet1 changes:
+//
+//	cells, err := f.GetCellDependents("Sheet1", "B2", excelize.DependencyOptions{Recursive: true})
+func (f *File) GetCellDependents(sheet, cell string, opts ...DependencyOptions) ([]string, error) {
+	sheet, col, row, options, err := f.getCellDependencyArgs(sheet, cell, opts...)
+	if err != nil {
+		return nil, err
+	}
+	g, err := f.getDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	return g.getDependents(sheet, col, row, options.Recursive), err
+}
+
+// GetCircularReferences provides a function to detect the circular references
+// in the workbook. Each circular reference is returned as the formula cells
+// which refer to each other directly or indirectly, the cells are qualified
+// with the worksheet name, such as Sheet1!A1. For example:
+//
+//	cycles, err := f.GetCircularReferences()
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for _, cells := range cycles {
+//	    fmt.Println(strings.Join(cells, " -> "))
+//	}
+func (f *File) GetCircularReferences() ([][]string, error) {
+	g, err := f.getDependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+	return g.getCycles(), err
+}
+
+// volatileFunctions defined the formula functions which the calculated result
+// could be changed in each calculation, or which the references could not be
+// determined by the formula tokens, the result of the formula which using
+// these functions will not be cached.
+var volatileFunctions = map[string]bool{
+	"CELL":        true,
+	"INDIRECT":    true,
+	"INFO":        true,
+	"NOW":         true,
+	"OFFSET":      true,
+	"RAND":        true,
+	"RANDARRAY":   true,
+	"RANDBETWEEN": true,
+	"TODAY":       true,
+}
+
+// calcCache defines the cache of the calculated results of the formula cells,
+// which enabled by the CalcCache field of the options.
+type calcCache struct {
+	mu     sync.Mutex
+	values map[string]*calcCacheEntry
+	graph  *dependencyGraph
+}
+
+// calcCacheEntry directly maps the calculated result and the references of a
+// formula cell in the calculation cache.
+type calcCacheEntry struct {
+	node    dependencyNode
+	formula string
+	result  formulaArg
+	err     error
+	refs    []cellRange
+}
+
+// isVolatileFormula determine if the formula or the formula of the defined
+// names in it using the volatile functions.
+func (g *dependencyGraph) isVolatileFormula(sheet, formula string, definedNames map[string]bool) bool {
+	ps := efp.ExcelParser()
+	for _, token := range ps.Parse(strings.TrimPrefix(formula, "=")) {
+		if isFunctionStartToken(token) {
+			if _, ok := g.f.getUserFormulaFunc(token.TValue); ok || volatileFunctions[normalizeFormulaFuncName(token.TValue)] {
+				return true
+			}
+		}
+		if token.TType != efp.TokenTypeOperand || token.TSubType != efp.TokenSubTypeRange {
+			continue
+		}
+		if refTo := g.f.getDefinedNameRefTo(token.TValue, sheet); refTo != "" && !definedNames[refTo] {
+			definedNames[refTo] = true
+			if g.isVolatileFormula(sheet, refTo, definedNames) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadCalcCache returns the cached calculated result of the formula cell by
+// given worksheet name and cell reference.
+func (f *File) loadCalcCache(sheet, cell string) (*calcCacheEntry, bool) {
+	if !f.options.CalcCache {
+		return nil, false
+	}
+	f.calcCache.mu.Lock()
+	defer f.calcCache.mu.Unlock()
+	entry, ok := f.calcCache.values[fmt.Sprintf("%s!%s", sheet, cell)]
+	return entry, ok
+}
+
+// addCalcCache provides a function to add the calculated result of the formula
+// cell to the calculation context, the results will be stored in the
+// calculation cache after the calculation completed.
+func (f *File) addCalcCache(ctx *calcContext, sheet, cell, formula string, result formulaArg, err error) {
+	if !f.options.CalcCache {
+		return
+	}
+	col, row, cellErr := CellNameToCoordinates(cell)
+	if cellErr != nil {
+		return
+	}
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.calcCache == nil {
+		ctx.calcCache = make(map[string]*calcCacheEntry)
+	}
+	ctx.calcCache[fmt.Sprintf("%s!%s", sheet, cell)] = &calcCacheEntry{
+		node:    dependencyNode{sheet: sheet, cell: cell, col: col, row: row},
+		formula: formula,
+		result:  result,
+		err:     err,
+	}
+}
+
+// storeCalcCache provides a function to store the calculated results of the
+// formula cells in the calculation context to the calculation cache, the
+// results will not be stored if the calculation has circular references.
+func (f *File) storeCalcCache(ctx *calcContext) {
+	if !f.options.CalcCache || ctx.circular || len(ctx.calcCache) == 0 {
+		return
+	}
+	g := &dependencyGraph{f: f, sheets: f.GetSheetList()}
+	f.calcCache.mu.Lock()
+	defer f.calcCache.mu.Unlock()
+	if f.calcCache.values == nil {
+		f.calcCache.values = make(map[string]*calcCacheEntry)
+	}
+	for _, entry := range ctx.calcCache {
+		var ok bool
+		if entry.node.sheet, ok = g.getSheetName(entry.node.sheet); !ok ||
+			g.isVolatileFormula(entry.node.sheet, entry.formula, map[string]bool{}) {
+			continue
+		}
+		entry.refs = g.getFormulaReferences(entry.node.sheet, entry.node.cell, entry.formula, map[string]bool{})
+		f.calcCache.values[fmt.Sprintf("%s!%s", entry.node.sheet, entry.node.cell)] = entry
+	}
+}
+
+// clearCalcCache provides a function to remove all cached calculated results.
+func (f *File) clearCalcCache() {
+	f.calcCache.mu.Lock()
+	defer f.calcCache.mu.Unlock()
+	f.calcCache.values, f.calcCache.graph = nil, nil
+}
+
+// resetDependencyGraph provides a function to remove the cached formula
+// dependency graph when the formulas changed.
+func (f *File) resetDependencyGraph() {
+	f.calcCache.mu.Lock()
+	defer f.calcCache.mu.Unlock()
+	f.calcCache.graph = nil
+}
+
+// invalidateCalcCache provides a function to remove the cached calculated
+// results of the formula cells which affected by the change directly, which
+// determined by the given function, and the formula cells depend on them.
+func (f *File) invalidateCalcCache(fn func(entry *calcCacheEntry) bool) {
+	f.calcCache.mu.Lock()
+	defer f.calcCache.mu.Unlock()
+	var queue []*calcCacheEntry
+	for ref, entry := range f.calcCache.values {
+		if fn(entry) {
+			delete(f.calcCache.values, ref)
+			queue = append(queue, entry)
+		}
+	}
+	for len(queue) > 0 {
+		target := queue[0].node
+		queue = queue[1:]
+		for ref, entry := range f.calcCache.values {
+			for _, cr := range entry.refs {
+				if cr.contains(target.sheet, target.col, target.row) {
+					delete(f.calcCache.values, ref)
+					queue = append(queue, entry)
+					break
+				}
+			}
+		}
+	}
+}
+
+// getCalcCacheSheetName returns the worksheet name in the workbook by given
+// case insensitive worksheet name, and returns false if the calculation
+// cache is empty.
+func (f *File) getCalcCacheSheetName(sheet string) (string, bool) {
+	f.calcCache.mu.Lock()
+	empty := len(f.calcCache.values) == 0
+	f.calcCache.mu.Unlock()
+	if empty {
+		return sheet, false
+	}
+	g := &dependencyGraph{sheets: f.GetSheetList()}
+	return g.getSheetName(sheet)
+}
+
+// invalidateCellCalcCache provides a function to remove the cached calculated
+// results of the formula cells which depend on the cell by given worksheet
+// name and cell reference.
+func (f *File) invalidateCellCalcCache(sheet, cell string) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return
+	}
+	var ok bool
+	if sheet, ok = f.getCalcCacheSheetName(sheet); !ok {
+		return
+	}
+	f.invalidateCalcCache(func(entry *calcCacheEntry) bool {
+		if entry.node.sheet == sheet && entry.node.col == col && entry.node.row == row {
+			return true
+		}
+		for _, cr := range entry.refs {
+			if cr.contains(sheet, col, row) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// invalidateAdjustCalcCache provides a function to remove the cached
+// calculated results of the formula cells which affected by inserting or
+// deleting rows or columns by given worksheet name, adjust direction and the
+// row or column number.
+func (f *File) invalidateAdjustCalcCache(sheet string, dir adjustDirection, num int) {
+	f.resetDependencyGraph()
+	var ok bool
+	if sheet, ok = f.getCalcCacheSheetName(sheet); !ok {
+		return
+	}
+	affected := func(col, row int) bool {
+		if dir == rows {
+			return row >= num
+		}
+		return col >= num
+	}
+	f.invalidateCalcCache(func(entry *calcCacheEntry) bool {
+		if entry.node.sheet == sheet && affected(entry.node.col, entry.node.row) {
+			return true
+		}
+		for _, cr := range entry.refs {
+			if cr.From.Sheet == sheet && affected(cr.To.Col, cr.To.Row) {
+				return true
+			}
+		}
+		return false
+	})
+}
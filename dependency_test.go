@@ -0,0 +1,256 @@
+package excelize
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCellPrecedentsAndDependents(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	for cell, value := range map[string]interface{}{"A1": 1, "A2": 2, "C1": 3} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetSheetRow("Sheet2", "D1", &[]interface{}{"Name", "Amount", "Double"}))
+	assert.NoError(t, f.SetSheetRow("Sheet2", "D2", &[]interface{}{"a", 10}))
+	assert.NoError(t, f.SetSheetRow("Sheet2", "D3", &[]interface{}{"b", 20}))
+	assert.NoError(t, f.AddTable("Sheet2", &Table{Range: "D1:F3", Name: "Sales"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Rate", RefersTo: "Sheet1!$A$2"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Total", RefersTo: "=Sheet1!$B$1*Rate"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Loop", RefersTo: "=Loop+1"}))
+	for cell, formula := range map[string]string{
+		"Sheet1!B1": "SUM(A1:A2)",
+		"Sheet1!B2": "B1*2+Sheet2!A1",
+		"Sheet1!B3": "Total+Loop",
+		"Sheet1!B4": "LET(x,A1,x*2)",
+		"Sheet1!B5": "SUM(Sheet1:Sheet2!C1)",
+		"Sheet1!B6": "SUM(Sales[Amount])+Sales[[#Headers],[Name]]",
+		"Sheet1!B7": "SUM(NoTable[Amount],SheetN!A1)",
+		"Sheet2!A1": "sheet1!A1+1",
+		"Sheet2!F2": "[@Amount]*2",
+		"Sheet2!F3": "Sales[@Amount]*2",
+	} {
+		ref := strings.Split(cell, "!")
+		assert.NoError(t, f.SetCellFormula(ref[0], ref[1], formula))
+	}
+	for cell, expected := range map[string][]string{
+		"B1": {"Sheet1!A1:A2"},
+		"B2": {"Sheet1!B1", "Sheet2!A1"},
+		"B3": {"Sheet1!B1", "Sheet1!A2"},
+		"B4": {"Sheet1!A1"},
+		"B5": {"Sheet1!C1", "Sheet2!C1"},
+		"B6": {"Sheet2!E2:E3", "Sheet2!D1"},
+		"B7": nil,
+		"A1": nil,
+	} {
+		refs, err := f.GetCellPrecedents("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, refs, cell)
+	}
+	refs, err := f.GetCellPrecedents("Sheet2", "F2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet2!E2"}, refs)
+	refs, err = f.GetCellPrecedents("Sheet2", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet2!E3"}, refs)
+	refs, err = f.GetCellPrecedents("Sheet1", "B2", DependencyOptions{Recursive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet2!A1", "Sheet1!A1:A2", "Sheet1!A1"}, refs)
+
+	cells, err := f.GetCellDependents("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet1!B4", "Sheet2!A1"}, cells)
+	cells, err = f.GetCellDependents("sheet1", "A1", DependencyOptions{Recursive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet1!B4", "Sheet2!A1", "Sheet1!B2", "Sheet1!B3"}, cells)
+	cells, err = f.GetCellDependents("Sheet2", "E3")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1!B6", "Sheet2!F3"}, cells)
+	cells, err = f.GetCellDependents("Sheet1", "Z100")
+	assert.NoError(t, err)
+	assert.Nil(t, cells)
+
+	// Test the dependency graph is cached until the formulas changed
+	g := f.calcCache.graph
+	assert.NotNil(t, g)
+	_, err = f.GetCircularReferences()
+	assert.NoError(t, err)
+	assert.Equal(t, g, f.calcCache.graph)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 2))
+	assert.Equal(t, g, f.calcCache.graph)
+	assert.NoError(t, f.SetCellValue("Sheet1", "B4", 2))
+	assert.Nil(t, f.calcCache.graph)
+	cells, err = f.GetCellDependents("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet2!A1"}, cells)
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B4", "A1"))
+	assert.Nil(t, f.calcCache.graph)
+	cells, err = f.GetCellDependents("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet1!B4", "Sheet2!A1"}, cells)
+	assert.NoError(t, f.InsertRows("Sheet1", 10, 1))
+	assert.Nil(t, f.calcCache.graph)
+
+	// Test get precedents and dependents with invalid cell reference
+	_, err = f.GetCellPrecedents("Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	_, err = f.GetCellDependents("Sheet1", "A")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test get precedents and dependents with not exist worksheet
+	_, err = f.GetCellPrecedents("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	_, err = f.GetCellDependents("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test get precedents and dependents with invalid worksheet name
+	_, err = f.GetCellPrecedents("Sheet:1", "A1")
+	assert.Equal(t, ErrSheetNameInvalid, err)
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestGetCellPrecedentsAndDependents.xlsx")))
+	assert.NoError(t, f.Close())
+
+	// Test get precedents and dependents with unsupported charset worksheet
+	f = NewFile()
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	f.checked = sync.Map{}
+	_, err = f.GetCellPrecedents("Sheet1", "A1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetCellDependents("Sheet1", "A1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.GetCircularReferences()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestGetCircularReferences(t *testing.T) {
+	f := NewFile()
+	for cell, formula := range map[string]string{
+		"A1": "B1+1",
+		"B1": "C1+1",
+		"C1": "A1+1",
+		"D1": "D1+1",
+		"E1": "A1+1",
+		"F1": "SUM(F2:F3)",
+		"F2": "1",
+		"F3": "F1",
+	} {
+		assert.NoError(t, f.SetCellFormula("Sheet1", cell, formula))
+	}
+	cycles, err := f.GetCircularReferences()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Sheet1!A1", "Sheet1!B1", "Sheet1!C1"},
+		{"Sheet1!D1"},
+		{"Sheet1!F1", "Sheet1!F3"},
+	}, cycles)
+	assert.NoError(t, f.Close())
+
+	f = NewFile()
+	cycles, err = f.GetCircularReferences()
+	assert.NoError(t, err)
+	assert.Nil(t, cycles)
+	assert.NoError(t, f.Close())
+}
+
+func TestCalcCache(t *testing.T) {
+	f := NewFile(Options{CalcCache: true})
+	_, err := f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetSheetCol("Sheet1", "A1", &[]int{1, 2}))
+	for cell, formula := range map[string]string{
+		"Sheet1!B1": "SUM(A1:A2)",
+		"Sheet1!B2": "B1*10",
+		"Sheet1!C1": "A2+1",
+		"Sheet1!D1": "RAND()",
+		"Sheet1!E1": "E2",
+		"Sheet1!E2": "E1",
+		"Sheet2!A1": "Sheet1!B2+1",
+	} {
+		ref := strings.Split(cell, "!")
+		assert.NoError(t, f.SetCellFormula(ref[0], ref[1], formula))
+	}
+	cachedCells := func() []string {
+		var cells []string
+		for _, sheet := range f.GetSheetList() {
+			for _, cell := range []string{"A1", "B1", "B2", "C1", "D1", "E1", "E2"} {
+				if _, ok := f.loadCalcCache(sheet, cell); ok {
+					cells = append(cells, sheet+"!"+cell)
+				}
+			}
+		}
+		return cells
+	}
+	calc := func(sheet, cell, expected string) {
+		result, err := f.CalcCellValue(sheet, cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result, cell)
+	}
+	calc("Sheet1", "B2", "30")
+	calc("Sheet1", "C1", "3")
+	_, err = f.CalcCellValue("Sheet1", "D1")
+	assert.NoError(t, err)
+	calc("Sheet1", "E1", "")
+	calc("Sheet2", "A1", "31")
+	// Test the formula cells with volatile function and circular references not be cached
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet1!B2", "Sheet1!C1", "Sheet2!A1"}, cachedCells())
+
+	// Test get the cached result without evaluate the formula again
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	c, _, _, err := ws.(*xlsxWorksheet).prepareCell("C1")
+	assert.NoError(t, err)
+	c.F.Content = "A2+100"
+	calc("Sheet1", "C1", "3")
+
+	// Test invalidate the dependent cells only after setting cell value
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 5))
+	assert.Equal(t, []string{"Sheet1!C1"}, cachedCells())
+	calc("Sheet2", "A1", "71")
+	assert.NoError(t, f.SetCellValue("sheet1", "A2", 10))
+	assert.Nil(t, cachedCells())
+	calc("Sheet1", "C1", "110")
+	calc("Sheet2", "A1", "151")
+
+	// Test invalidate the dependent cells only after inserting and removing rows
+	assert.NoError(t, f.InsertRows("Sheet1", 3, 1))
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet1!B2", "Sheet1!C1", "Sheet2!A1"}, cachedCells())
+	assert.NoError(t, f.RemoveRow("Sheet1", 2))
+	assert.Nil(t, cachedCells())
+	calc("Sheet2", "A1", "6")
+	assert.NoError(t, f.InsertCols("Sheet1", "C", 1))
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet2!A1"}, cachedCells())
+
+	// Test invalidate the dependent cells after setting cell formula
+	assert.NoError(t, f.SetCellFormula("Sheet1", "B1", "A1*2"))
+	assert.Nil(t, cachedCells())
+	calc("Sheet2", "A1", "11")
+
+	// Test clear the cache after setting the defined name
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "Amount", RefersTo: "Sheet1!$A$1"}))
+	assert.Nil(t, cachedCells())
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "Amount*3"))
+	calc("Sheet1", "E1", "15")
+	assert.Equal(t, []string{"Sheet1!E1"}, cachedCells())
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", 1))
+	calc("Sheet1", "E1", "3")
+
+	// Test recalculate all formula cells with circular references
+	assert.NoError(t, f.RecalculateAll())
+	assert.Equal(t, []string{"Sheet1!E1"}, cachedCells())
+	// Test recalculate all formula cells with the calculation cache
+	assert.NoError(t, f.SetCellValue("Sheet1", "F1", nil))
+	assert.NoError(t, f.RecalculateAll())
+	assert.Equal(t, []string{"Sheet1!B1", "Sheet1!D1", "Sheet1!E1", "Sheet2!A1"}, cachedCells())
+	assert.NoError(t, f.Close())
+
+	// Test calculate formula without the calculation cache
+	f = NewFile()
+	assert.NoError(t, f.SetCellFormula("Sheet1", "A1", "1+2"))
+	calc("Sheet1", "A1", "3")
+	assert.Nil(t, cachedCells())
+	assert.NoError(t, f.Close())
+}
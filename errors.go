@@ -246,6 +246,12 @@ func newInvalidNameError(name string) error {
 	return fmt.Errorf("invalid name %q, the name should be starts with a letter or underscore, can not include a space or character, and can not conflict with an existing name in the workbook", name)
 }
 
+// newInvalidODSValueError defined the error message on receiving the invalid
+// OpenDocument duration value.
+func newInvalidODSValueError(value string) error {
+	return fmt.Errorf("invalid OpenDocument duration value %q", value)
+}
+
 // newInvalidRowNumberError defined the error message on receiving the invalid
 // row number.
 func newInvalidRowNumberError(row int) error {
@@ -264,6 +270,12 @@ func newInvalidStyleID(styleID int) error {
 	return fmt.Errorf("invalid style ID %d", styleID)
 }
 
+// newMarshalFieldError defined the error message on failing to convert the
+// struct field into the cell value.
+func newMarshalFieldError(field string, err error) error {
+	return fmt.Errorf("cannot marshal field %s: %v", field, err)
+}
+
 // newNoExistTableError defined the error message on receiving the non existing
 // table name.
 func newNoExistTableError(name string) error {
@@ -300,12 +312,30 @@ func newUnknownFilterTokenError(token string) error {
 	return fmt.Errorf("unknown operator: %s", token)
 }
 
+// newUnmarshalCellError defined the error message on failing to convert the
+// cell value into the struct field.
+func newUnmarshalCellError(cell, field string, err error) error {
+	return fmt.Errorf("cannot unmarshal cell %s into field %s: %v", cell, field, err)
+}
+
 // newUnsupportedChartType defined the error message on receiving the chart
 // type are unsupported.
 func newUnsupportedChartType(chartType ChartType) error {
 	return fmt.Errorf("unsupported chart type %d", chartType)
 }
 
+// newUnsupportedFieldTypeError defined the error message on receiving the
+// unsupported struct field type for mapping the worksheet rows.
+func newUnsupportedFieldTypeError(typ string) error {
+	return fmt.Errorf("unsupported field type %s", typ)
+}
+
+// newUnsupportedTextFormatError defined the error message on receiving the
+// unsupported text table format.
+func newUnsupportedTextFormatError(format string) error {
+	return fmt.Errorf("unsupported text format %q", format)
+}
+
 // newUnzipSizeLimitError defined the error message on unzip size exceeds the
 // limit.
 func newUnzipSizeLimitError(unzipSizeLimit int64) error {
@@ -32,12 +32,14 @@ type File struct {
 	checked          sync.Map
 	formulaChecked   bool
 	options          *Options
-	sharedStringItem [][]uint
+	calcCache        calcCache
 	sharedStringsMap map[string]int
 	sharedStringTemp *os.File
+	sharedStringsIdx *sharedStringsIndex
 	sheetMap         map[string]string
 	streams          map[string]*StreamWriter
 	tempFiles        sync.Map
+	userFormulaFuncs sync.Map
 	xmlAttr          sync.Map
 	CalcChain        *xlsxCalcChain
 	CharsetReader    charsetTranscoderFn
@@ -97,6 +99,21 @@ type charsetTranscoderFn func(charset string, input io.Reader) (rdr io.Reader, e
 //
 // CultureInfo specifies the country code for applying built-in language number
 // format code these effect by the system's local language settings.
+//
+// CalcCache specifies if cache the calculated results of the formula cells,
+// the cached results will be reused by the formula calculation functions, and
+// will be invalidated when the referenced cells changed, such as setting cell
+// value or formula, inserting or deleting rows and columns. The results of the
+// formulas which using volatile functions such as NOW, RAND and INDIRECT will
+// not be cached. The default value is false.
+//
+// LowMemory specifies if reading the shared string table in low memory mode,
+// the shared string table will not be loaded into memory when reading cell
+// values, the byte offsets of the shared string items will be indexed into
+// the system temporary directory, and the items will be read from the shared
+// string table part on demand with a least recently used cache. The shared
+// string table will be loaded into memory once the cell values are set. The
+// default value is false.
 type Options struct {
 	MaxCalcIterations uint
 	Password          string
@@ -107,6 +124,8 @@ type Options struct {
 	LongDatePattern   string
 	LongTimePattern   string
 	CultureInfo       CultureName
+	CalcCache         bool
+	LowMemory         bool
 }
 
 // OpenFile take the name of a spreadsheet file and returns a populated
@@ -115,6 +134,15 @@ type Options struct {
 //
 //	f, err := excelize.OpenFile("Book1.xlsx", excelize.Options{Password: "password"})
 //
+// The Excel binary workbook (.xlsb) could be opened for reading, the binary
+// workbook, worksheets, shared strings table and styles parts will be
+// converted into the XML parts, the other binary parts such as calculation
+// chain, comments and tables will be dropped, and the workbook could be saved
+// as XLSX workbook by the SaveAs function. The Excel 97-2003 workbook (.xls) could
+// be opened in the same way, the sheet names, cell values, shared strings,
+// number formats, cell styles and merged cells will be read, and the cached
+// values of the formula cells will be read without formulas.
+//
 // Close the file by Close function after opening the spreadsheet.
 func OpenFile(filename string, opts ...Options) (*File, error) {
 	file, err := os.Open(filepath.Clean(filename))
@@ -184,19 +212,7 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	if err = f.checkOpenReaderOptions(); err != nil {
 		return nil, err
 	}
-	if bytes.Contains(b, oleIdentifier) {
-		if b, err = Decrypt(b, f.options); err != nil {
-			return nil, ErrWorkbookFileFormat
-		}
-	}
-	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
-	if err != nil {
-		if len(f.options.Password) > 0 {
-			return nil, ErrWorkbookPassword
-		}
-		return nil, err
-	}
-	file, sheetCount, err := f.ReadZipReader(zr)
+	file, sheetCount, err := f.readPackage(b)
 	if err != nil {
 		return nil, err
 	}
@@ -217,6 +233,34 @@ func OpenReader(r io.Reader, opts ...Options) (*File, error) {
 	return f, err
 }
 
+// readPackage provides a function to read the parts of the spreadsheet by
+// given file content. The Excel 97-2003 workbook (.xls) and the Excel binary
+// workbook (.xlsb) will be converted into the Office Open XML parts. It
+// returns the parts and the number of worksheets.
+func (f *File) readPackage(b []byte) (map[string][]byte, int, error) {
+	var err error
+	if bytes.Contains(b, oleIdentifier) {
+		if stream := getLegacyWorkbookStream(b); stream != nil {
+			return f.readLegacyWorkbook(stream)
+		}
+		if b, err = Decrypt(b, f.options); err != nil {
+			return nil, 0, ErrWorkbookFileFormat
+		}
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		if len(f.options.Password) > 0 {
+			return nil, 0, ErrWorkbookPassword
+		}
+		return nil, 0, err
+	}
+	file, sheetCount, err := f.ReadZipReader(zr)
+	if err != nil {
+		return nil, 0, err
+	}
+	return file, sheetCount, f.readBinaryWorkbook(file)
+}
+
 // getOptions provides a function to parse the optional settings for open
 // and reading spreadsheet.
 func (f *File) getOptions(opts ...Options) *Options {
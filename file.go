@@ -91,6 +91,9 @@ func (f *File) Close() error {
 			return err
 		}
 	}
+	if err := f.sharedStringsIdx.close(); err != nil {
+		return err
+	}
 	f.tempFiles.Range(func(k, v interface{}) bool {
 		if err = os.Remove(v.(string)); err != nil {
 			return false
@@ -0,0 +1,370 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSONOptions directly maps the settings of exporting the worksheet data in
+// the JSON format.
+//
+// Range specifies the range reference of the worksheet to be exported, such
+// as A1:D10, the first row of the range is the header row. The used range of
+// the worksheet will be exported if both the Range and Table are empty.
+//
+// Table specifies the name of the table on the worksheet to be exported, the
+// Range option will be ignored if the table name is specified.
+//
+// NDJSON specifies if write each row as a JSON object on a separate line in
+// the newline delimited JSON format, instead of an array of objects.
+//
+// TypedValues specifies if export the numeric and boolean cells as the JSON
+// numbers and booleans, and the date and time cells as the ISO 8601 strings.
+// The formatted cell values will be exported as strings by default.
+//
+// RawCellValue specifies if export the raw cell values as strings without
+// applying the number formats, which will be ignored if the TypedValues
+// option is enabled.
+//
+// Indent specifies the indentation of the JSON objects in the array, the
+// objects will be written in the compact format by default. This option will
+// be ignored if the NDJSON option is enabled.
+type JSONOptions struct {
+	Range        string
+	Table        string
+	NDJSON       bool
+	TypedValues  bool
+	RawCellValue bool
+	Indent       string
+}
+
+// jsonExporter directly maps the state of exporting the worksheet range in
+// the JSON format.
+type jsonExporter struct {
+	f           *File
+	sheet       string
+	opts        *JSONOptions
+	date1904    bool
+	keys        []string
+	numFmtTypes map[int]string
+}
+
+// ExportJSON provides a function to export the worksheet range or table in
+// the JSON format to the writer by given worksheet name. Each row after the
+// header row will be written as a JSON object keyed by the header cells of
+// the columns, the keys will be ordered as the columns. The columns with
+// empty header will be keyed by the column names, and the duplicate keys will
+// be suffixed with the index of the occurrence, such as "Name_2". When the
+// header cells in the first row of the range were merged across multiple
+// rows, all these rows will be treated as the header rows, and the key of
+// each column will be the distinct header values of the column joined by
+// dot, such as "Address.City". The values of the merged cells will be applied
+// to all cells in the merged range, and the empty rows will be skipped, the
+// empty cells will be written as null. For example, export the table named
+// Table1 on Sheet1 with typed values as the newline delimited JSON:
+//
+//	var buf bytes.Buffer
+//	err := f.ExportJSON("Sheet1", &buf, excelize.JSONOptions{
+//	    Table:       "Table1",
+//	    NDJSON:      true,
+//	    TypedValues: true,
+//	})
+func (f *File) ExportJSON(sheet string, writer io.Writer, opts ...JSONOptions) error {
+	options := JSONOptions{}
+	if len(opts) > 0 {
+		options = opts[len(opts)-1]
+	}
+	coordinates, err := f.jsonRangeCoordinates(sheet, &options)
+	if err != nil {
+		return err
+	}
+	date1904, err := f.getDate1904()
+	if err != nil {
+		return err
+	}
+	e := &jsonExporter{f: f, sheet: sheet, opts: &options, date1904: date1904, numFmtTypes: make(map[int]string)}
+	headerRows, err := e.headerRows(coordinates)
+	if err != nil {
+		return err
+	}
+	if e.keys, err = e.parseKeys(coordinates, headerRows); err != nil {
+		return err
+	}
+	w := bufio.NewWriter(writer)
+	var count int
+	for row := coordinates[1] + headerRows; row <= coordinates[3]; row++ {
+		values, ok, err := e.rowValues(coordinates[0], row)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err = e.writeObject(w, values, count); err != nil {
+			return err
+		}
+		count++
+	}
+	if !options.NDJSON {
+		closing := "]"
+		if count == 0 {
+			closing = "[]"
+		} else if options.Indent != "" {
+			closing = "\n]"
+		}
+		if _, err = w.WriteString(closing + "\n"); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// jsonRangeCoordinates provides a function to get the coordinates of the
+// range to be exported by given worksheet name and export options.
+func (f *File) jsonRangeCoordinates(sheet string, opts *JSONOptions) ([]int, error) {
+	rangeRef := opts.Range
+	if opts.Table != "" {
+		tables, err := f.GetTables(sheet)
+		if err != nil {
+			return nil, err
+		}
+		rangeRef = ""
+		for _, table := range tables {
+			if strings.EqualFold(table.Name, opts.Table) {
+				rangeRef = table.Range
+				break
+			}
+		}
+		if rangeRef == "" {
+			return nil, newNoExistTableError(opts.Table)
+		}
+	}
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if rangeRef == "" {
+		return ws.usedRange()
+	}
+	if !strings.Contains(rangeRef, ":") {
+		rangeRef += ":" + rangeRef
+	}
+	coordinates, err := rangeRefToCoordinates(rangeRef)
+	if err != nil {
+		return nil, err
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, err
+}
+
+// headerRows provides a function to get the number of the header rows by
+// given range coordinates, the header rows will be extended to the bottom of
+// the merged cells which start from the first row of the range.
+func (e *jsonExporter) headerRows(coordinates []int) (int, error) {
+	headerRows := 1
+	mergeCells, err := e.f.GetMergeCells(e.sheet)
+	if err != nil {
+		return headerRows, err
+	}
+	for _, mergeCell := range mergeCells {
+		rect, err := rangeRefToCoordinates(mergeCell[0])
+		if err != nil {
+			return headerRows, err
+		}
+		_ = sortCoordinates(rect)
+		if rect[1] != coordinates[1] || rect[2] < coordinates[0] || rect[0] > coordinates[2] {
+			continue
+		}
+		if rows := rect[3] - rect[1] + 1; rows > headerRows {
+			headerRows = rows
+		}
+	}
+	if maxRows := coordinates[3] - coordinates[1] + 1; headerRows > maxRows {
+		headerRows = maxRows
+	}
+	return headerRows, err
+}
+
+// parseKeys provides a function to get the keys of the columns by given
+// range coordinates and number of the header rows.
+func (e *jsonExporter) parseKeys(coordinates []int, headerRows int) ([]string, error) {
+	var keys []string
+	occurrences := map[string]int{}
+	for col := coordinates[0]; col <= coordinates[2]; col++ {
+		var parts []string
+		for row := coordinates[1]; row < coordinates[1]+headerRows; row++ {
+			cell, _ := CoordinatesToCellName(col, row)
+			value, err := e.f.GetCellValue(e.sheet, cell)
+			if err != nil {
+				return keys, err
+			}
+			if value = strings.TrimSpace(value); value != "" && (len(parts) == 0 || parts[len(parts)-1] != value) {
+				parts = append(parts, value)
+			}
+		}
+		key := strings.Join(parts, ".")
+		if key == "" {
+			key, _ = ColumnNumberToName(col)
+		}
+		if occurrences[key]++; occurrences[key] > 1 {
+			key += "_" + strconv.Itoa(occurrences[key])
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// rowValues provides a function to get the JSON values of the row by given
+// first column number and row number, returns false if all cells in the row
+// are empty.
+func (e *jsonExporter) rowValues(col, row int) ([]interface{}, bool, error) {
+	var ok bool
+	values := make([]interface{}, len(e.keys))
+	for i := range e.keys {
+		cell, _ := CoordinatesToCellName(col+i, row)
+		value, err := e.value(cell)
+		if err != nil {
+			return values, ok, err
+		}
+		values[i], ok = value, ok || value != nil
+	}
+	return values, ok, nil
+}
+
+// value provides a function to get the JSON value of the cell by given cell
+// reference, returns nil if the cell is empty.
+func (e *jsonExporter) value(cell string) (interface{}, error) {
+	raw, err := e.f.GetCellValue(e.sheet, cell, Options{RawCellValue: true})
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	if !e.opts.TypedValues {
+		if e.opts.RawCellValue {
+			return raw, err
+		}
+		return e.f.GetCellValue(e.sheet, cell)
+	}
+	cellType, err := e.f.GetCellType(e.sheet, cell)
+	if err != nil {
+		return nil, err
+	}
+	switch cellType {
+	case CellTypeBool:
+		return raw == "1" || strings.EqualFold(raw, "TRUE"), err
+	case CellTypeUnset, CellTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return raw, nil
+		}
+		styleID, err := e.f.GetCellStyle(e.sheet, cell)
+		if err != nil {
+			return nil, err
+		}
+		layout := e.timeLayout(styleID)
+		if layout == "" {
+			return json.Number(strconv.FormatFloat(number, 'f', -1, 64)), err
+		}
+		t, err := ExcelDateToTime(number, e.date1904)
+		if err != nil {
+			return raw, nil
+		}
+		return t.Format(layout), nil
+	}
+	return raw, err
+}
+
+// timeLayout provides a function to get the ISO 8601 time layout of the
+// number format by given cell style index, returns empty string if the
+// number format is not a date or time format.
+func (e *jsonExporter) timeLayout(styleID int) string {
+	if layout, ok := e.numFmtTypes[styleID]; ok {
+		return layout
+	}
+	var layout string
+	if code := e.f.getNumFmtCode(styleID); code != "" {
+		hasDate, hasTime := numFmtDateTimeParts(code)
+		if hasDate || hasTime {
+			layout = "2006-01-02T15:04:05"
+		}
+		if hasDate && !hasTime {
+			layout = "2006-01-02"
+		}
+	}
+	e.numFmtTypes[styleID] = layout
+	return layout
+}
+
+// writeObject provides a function to write the values of the row as the
+// JSON object by given writer, values and the index of the object.
+func (e *jsonExporter) writeObject(w *bufio.Writer, values []interface{}, idx int) error {
+	var buf bytes.Buffer
+	indent, sep := e.opts.Indent, ":"
+	if e.opts.NDJSON {
+		indent = ""
+	}
+	if !e.opts.NDJSON {
+		buf.WriteString(",")
+		if idx == 0 {
+			buf.Reset()
+			buf.WriteString("[")
+		}
+		if indent != "" {
+			buf.WriteString("\n" + indent)
+		}
+	}
+	buf.WriteString("{")
+	for i, key := range e.keys {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		if indent != "" {
+			buf.WriteString("\n" + indent + indent)
+			sep = ": "
+		}
+		if err := writeJSONValue(&buf, key); err != nil {
+			return err
+		}
+		buf.WriteString(sep)
+		if err := writeJSONValue(&buf, values[i]); err != nil {
+			return err
+		}
+	}
+	if indent != "" && len(e.keys) > 0 {
+		buf.WriteString("\n" + indent)
+	}
+	buf.WriteString("}")
+	if e.opts.NDJSON {
+		buf.WriteString("\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeJSONValue provides a function to write the JSON encoding of the value
+// into the buffer without escaping the HTML characters.
+func writeJSONValue(buf *bytes.Buffer, value interface{}) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
@@ -0,0 +1,112 @@
+package excelize
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportJSON(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", "Price", "Paid", "Date", "", "Name", "Time"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"<Apple> & \"Pear\"", 1234.5, true, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), nil, "x", 0.5}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"Orange", 0.25, false, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)}))
+	numStyle, err := f.NewStyle(&Style{NumFmt: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B4", numStyle))
+	timeStyle, err := f.NewStyle(&Style{NumFmt: 21})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "G2", "G2", timeStyle))
+	dateTimeStyle, err := f.NewStyle(&Style{NumFmt: 22})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D4", "D4", dateTimeStyle))
+	dateStyle, err := f.NewStyle(&Style{NumFmt: 14})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D2", "D2", dateStyle))
+
+	// Test export the used range with formatted values
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf))
+	assert.Equal(t, `[{"Name":"<Apple> & \"Pear\"","Price":"1,234.50","Paid":"TRUE","Date":"01-02-24","E":null,"Name_2":"x","Time":"12:00:00"},`+
+		`{"Name":"Orange","Price":"0.25","Paid":"FALSE","Date":"1/2/24 15:04","E":null,"Name_2":null,"Time":null}]`+"\n", buf.String())
+
+	// Test export the range with typed values as newline delimited JSON
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "A1:G4", NDJSON: true, TypedValues: true}))
+	assert.Equal(t, `{"Name":"<Apple> & \"Pear\"","Price":1234.5,"Paid":true,"Date":"2024-01-02","E":null,"Name_2":"x","Time":"1899-12-30T12:00:00"}`+"\n"+
+		`{"Name":"Orange","Price":0.25,"Paid":false,"Date":"2024-01-02T15:04:05","E":null,"Name_2":null,"Time":null}`+"\n", buf.String())
+
+	// Test export the range with raw cell values and indent
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "C2:B1", RawCellValue: true, Indent: "  "}))
+	assert.Equal(t, "[\n  {\n    \"Price\": \"1234.5\",\n    \"Paid\": \"1\"\n  }\n]\n", buf.String())
+
+	// Test export the single cell range and header only range
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "A1"}))
+	assert.Equal(t, "[]\n", buf.String())
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "A1:A1", NDJSON: true}))
+	assert.Empty(t, buf.String())
+
+	// Test export the range with merged header cells
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B2", &[]interface{}{"ID", "Address", nil, "Note"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B3", &[]interface{}{nil, "City", "Zip"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B4", &[]interface{}{1, "Paris", "75001", "a"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B5", &[]interface{}{2, "Berlin", 10115}))
+	assert.NoError(t, f.MergeCell("Sheet1", "B2", "B3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C2", "D2"))
+	assert.NoError(t, f.MergeCell("Sheet1", "E2", "E3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A7", "A9"))
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "B2:E5", TypedValues: true}))
+	assert.Equal(t, `[{"ID":1,"Address.City":"Paris","Address.Zip":"75001","Note":"a"},{"ID":2,"Address.City":"Berlin","Address.Zip":10115,"Note":null}]`+"\n", buf.String())
+
+	// Test export the table by the case-insensitive table name
+	assert.NoError(t, f.SetSheetRow("Sheet1", "G2", &[]interface{}{"Name", "Score"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "G3", &[]interface{}{"Ana", 9.5}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Name: "Scores", Range: "G2:H3"}))
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "scores", TypedValues: true}))
+	assert.Equal(t, `[{"Name":"Ana","Score":9.5}]`+"\n", buf.String())
+
+	// Test export the range which only contains the header rows
+	buf.Reset()
+	assert.NoError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "A7:B8"}))
+	assert.Equal(t, "[]\n", buf.String())
+
+	// Test export JSON with not exists table and worksheet
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf, JSONOptions{Table: "TableN"}), "table TableN does not exist")
+	assert.EqualError(t, f.ExportJSON("SheetN", &buf, JSONOptions{Table: "TableN"}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.ExportJSON("SheetN", &buf), "sheet SheetN does not exist")
+	// Test export JSON with invalid range reference
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "A:B"}))
+	// Test export JSON with invalid merged cell reference
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells.Cells[0].Ref = "B2:B"
+	assert.Equal(t, newCellNameToCoordinatesError("B", newInvalidCellNameError("B")), f.ExportJSON("Sheet1", &buf, JSONOptions{Range: "B2:E5"}))
+	ws.(*xlsxWorksheet).MergeCells = nil
+	ws.(*xlsxWorksheet).SheetData.Row[1].C[1].R = "B"
+	assert.Equal(t, newCellNameToCoordinatesError("B", newInvalidCellNameError("B")), f.ExportJSON("Sheet1", &buf))
+	// Test export JSON with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	// Test export JSON with unsupported charset shared strings table
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name", 1}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "x"))
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportJSON("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	// Test export JSON with the writer error
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Name"}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", "x"))
+	assert.EqualError(t, f.ExportJSON("Sheet1", errWriter{}), "write error")
+}
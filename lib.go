@@ -24,6 +24,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ReadZipReader extract spreadsheet with given options.
@@ -772,6 +773,17 @@ func isNumeric(s string) (bool, int, float64) {
 	return true, len(strings.ReplaceAll(noScientificNotation, ".", "")), flt
 }
 
+// defaultDateLayouts defined the default time layouts for parsing the date
+// and time text, such as the date fields in the CSV data, the date cells and
+// the text values of the time fields in the struct.
+var defaultDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
 var (
 	bstrExp       = regexp.MustCompile(`_x[a-fA-F\d]{4}_`)
 	bstrEscapeExp = regexp.MustCompile(`x[a-fA-F\d]{4}_`)
@@ -0,0 +1,648 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"encoding"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structField directly maps the exported field of the struct which mapped
+// to the worksheet column, the name is the header of the column, and the
+// format is the number format code of the column.
+type structField struct {
+	index  []int
+	name   string
+	format string
+}
+
+// rowsDecoder directly maps the settings of converting the worksheet rows
+// into the structs, the columns is the field index of each column which
+// matched by the header row, -1 means the column has no matched field.
+type rowsDecoder struct {
+	fields   []structField
+	columns  []int
+	date1904 bool
+}
+
+// parseStructFields provides a function to parse the exported fields of the
+// struct type which mapped to the worksheet columns by the xlsx struct tags.
+// The value of the tag is the header name of the column, and the format
+// option specifies the number format code of the column, which should be the
+// last option of the tag, for example:
+//
+//	Price float64 `xlsx:"Unit Price,format=#,##0.00"`
+//
+// The field name will be used as the header name if the tag name is empty,
+// the field will be ignored if the tag name is "-", and the fields of the
+// embedded structs without tags will be flattened.
+func parseStructFields(typ reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, hasTag := field.Tag.Lookup("xlsx")
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		if field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct && field.Type != timeType {
+			fields = append(fields, parseStructFields(field.Type, fieldIndex)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		sf := structField{index: fieldIndex, name: field.Name}
+		name, options, _ := strings.Cut(tag, ",")
+		if name != "" {
+			sf.name = name
+		}
+		for options != "" {
+			if strings.HasPrefix(options, "format=") {
+				sf.format = strings.TrimPrefix(options, "format=")
+				break
+			}
+			_, options, _ = strings.Cut(options, ",")
+		}
+		fields = append(fields, sf)
+	}
+	return fields
+}
+
+// structSliceType returns the struct type of the slice element by given
+// slice type, the element of the slice could be the struct or pointer to the
+// struct, and returns if the element is a pointer.
+func structSliceType(typ reflect.Type) (reflect.Type, bool, error) {
+	if typ.Kind() != reflect.Slice {
+		return nil, false, ErrParameterInvalid
+	}
+	elem, isPtr := typ.Elem(), false
+	if elem.Kind() == reflect.Ptr {
+		elem, isPtr = elem.Elem(), true
+	}
+	if elem.Kind() != reflect.Struct || elem == timeType {
+		return nil, false, ErrParameterInvalid
+	}
+	return elem, isPtr, nil
+}
+
+// getDate1904 returns if the workbook uses the 1904 date system.
+func (f *File) getDate1904() (bool, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return false, err
+	}
+	return wb != nil && wb.WorkbookPr != nil && wb.WorkbookPr.Date1904, err
+}
+
+// UnmarshalRows provides a function to convert the rows of the worksheet into
+// the slice of structs which pointed to by v. The first row with values in
+// the worksheet will be used as the header row, and the columns will be
+// matched to the struct fields by the header names which specified by the
+// xlsx struct tags, the empty rows will be skipped. The cell values will be
+// converted to the type of the fields, the supported field types are string,
+// integers, floats, bool, time.Time, the types which implement the
+// encoding.TextUnmarshaler interface, and the pointers to these types, the
+// pointer fields will be nil if the cells are empty. The time fields could be
+// converted from the date serial number cells or the ISO 8601 text cells. The
+// string fields will be set with the formatted cell values, and the format
+// option of the tag specifies the number format code for formatting the cell
+// values of the string fields. For example:
+//
+//	type Employee struct {
+//	    ID       int       `xlsx:"Employee ID"`
+//	    Name     string    `xlsx:"Name"`
+//	    Salary   *float64  `xlsx:"Salary"`
+//	    HireDate time.Time `xlsx:"Hire Date"`
+//	    Note     string    `xlsx:"-"`
+//	}
+//
+//	var employees []Employee
+//	if err := f.UnmarshalRows("Sheet1", &employees); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) UnmarshalRows(sheet string, v interface{}, opts ...Options) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return ErrParameterInvalid
+	}
+	elem, isPtr, err := structSliceType(val.Elem().Type())
+	if err != nil {
+		return err
+	}
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	slice := reflect.MakeSlice(val.Elem().Type(), 0, 0)
+	for rows.Next() {
+		item := reflect.New(elem)
+		if err = rows.unmarshalRow(item, opts...); err != nil {
+			if err == io.EOF {
+				break
+			}
+			_ = rows.Close()
+			return err
+		}
+		if rows.empty {
+			continue
+		}
+		if isPtr {
+			slice = reflect.Append(slice, item)
+			continue
+		}
+		slice = reflect.Append(slice, item.Elem())
+	}
+	val.Elem().Set(slice)
+	return rows.Close()
+}
+
+// UnmarshalRow provides a function to convert the current row of the rows
+// iterator into the struct which pointed to by v. The columns will be matched
+// to the struct fields by the header row as the UnmarshalRows function does,
+// the first row with values read by the rows iterator will be used as the
+// header row, so the rows iterator will be moved to the next row after
+// reading the header row when the function was called for the first time, and
+// returns io.EOF if there are no more rows after the header row. For example:
+//
+//	rows, err := f.Rows("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for rows.Next() {
+//	    var employee Employee
+//	    if err := rows.UnmarshalRow(&employee); err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    fmt.Println(employee)
+//	}
+//	if err = rows.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (rows *Rows) UnmarshalRow(v interface{}, opts ...Options) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct || val.Elem().Type() == timeType {
+		return ErrParameterInvalid
+	}
+	return rows.unmarshalRow(val, opts...)
+}
+
+// unmarshalRow provides a function to convert the current row of the rows
+// iterator into the struct by given pointer value of the struct, the header
+// row will be read if the columns have not been matched.
+func (rows *Rows) unmarshalRow(val reflect.Value, opts ...Options) error {
+	if rows.structDecoder == nil || rows.structDecoder.fields == nil {
+		date1904, err := rows.getDate1904()
+		if err != nil {
+			return err
+		}
+		rows.structDecoder = &rowsDecoder{fields: parseStructFields(val.Elem().Type(), nil), date1904: date1904}
+	}
+	options := rows.f.getOptions(opts...)
+	rows.rawCellValue = options.RawCellValue
+	rowIterator := rowXMLIterator{keepRaw: true}
+	rows.columns(&rowIterator)
+	if rowIterator.err != nil {
+		return rowIterator.err
+	}
+	if rows.empty = len(rowIterator.cells) == 0; rows.structDecoder.columns == nil {
+		if rows.empty {
+			return nil
+		}
+		rows.structDecoder.matchColumns(rowIterator.cells)
+		rows.empty = true
+		if !rows.Next() {
+			return io.EOF
+		}
+		return rows.unmarshalRow(val, opts...)
+	}
+	return rows.structDecoder.decode(val.Elem(), rows.curRow, rowIterator.cells, rowIterator.rawCells, options)
+}
+
+// matchColumns provides a function to match the columns to the struct fields
+// by given header row, the header names will be matched case-insensitively
+// if there are no exactly matched fields.
+func (d *rowsDecoder) matchColumns(header []string) {
+	d.columns = make([]int, len(header))
+	matched := map[int]bool{}
+	for col, name := range header {
+		d.columns[col] = -1
+		name = strings.TrimSpace(name)
+		for _, equal := range []func(a, b string) bool{
+			func(a, b string) bool { return a == b }, strings.EqualFold,
+		} {
+			for i, field := range d.fields {
+				if !matched[i] && equal(field.name, name) {
+					d.columns[col], matched[i] = i, true
+					break
+				}
+			}
+			if d.columns[col] != -1 {
+				break
+			}
+		}
+	}
+}
+
+// decode provides a function to convert the cell values of the row into the
+// struct by given struct value, row number, formatted and raw cell values.
+func (d *rowsDecoder) decode(val reflect.Value, row int, cells, rawCells []string, opts *Options) error {
+	for col, raw := range rawCells {
+		if col >= len(d.columns) || d.columns[col] == -1 || raw == "" {
+			continue
+		}
+		field := d.fields[d.columns[col]]
+		text := cells[col]
+		if field.format != "" {
+			cellType := CellTypeInlineString
+			if _, err := strconv.ParseFloat(raw, 64); err == nil {
+				cellType = CellTypeNumber
+			}
+			text = format(raw, field.format, d.date1904, cellType, opts)
+		}
+		if err := d.setField(val.FieldByIndex(field.index), text, raw); err != nil {
+			cell, _ := CoordinatesToCellName(col+1, row)
+			return newUnmarshalCellError(cell, field.name, err)
+		}
+	}
+	return nil
+}
+
+// setField provides a function to set the value of the struct field by given
+// formatted and raw cell value.
+func (d *rowsDecoder) setField(field reflect.Value, text, raw string) error {
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		if err := d.setField(value.Elem(), text, raw); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+	if field.Type() == timeType {
+		t, err := d.parseTime(raw)
+		if err == nil {
+			field.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			if n, err = parseIntegralFloat(raw); err != nil {
+				return err
+			}
+		}
+		if field.OverflowInt(n) {
+			return &strconv.NumError{Func: "ParseInt", Num: raw, Err: strconv.ErrRange}
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			i, err := parseIntegralFloat(raw)
+			if err != nil || i < 0 {
+				return &strconv.NumError{Func: "ParseUint", Num: raw, Err: strconv.ErrSyntax}
+			}
+			n = uint64(i)
+		}
+		if field.OverflowUint(n) {
+			return &strconv.NumError{Func: "ParseUint", Num: raw, Err: strconv.ErrRange}
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		if field.OverflowFloat(n) {
+			return &strconv.NumError{Func: "ParseFloat", Num: raw, Err: strconv.ErrRange}
+		}
+		field.SetFloat(n)
+	default:
+		return newUnsupportedFieldTypeError(field.Type().String())
+	}
+	return nil
+}
+
+// parseIntegralFloat provides a function to parse the number which has no
+// fractional part into the integer, such as 1E+3.
+func parseIntegralFloat(raw string) (int64, error) {
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: raw, Err: strconv.ErrSyntax}
+	}
+	return int64(f), nil
+}
+
+// parseTime provides a function to convert the raw cell value into the time,
+// the value could be the date serial number or the ISO 8601 date and time
+// text.
+func (d *rowsDecoder) parseTime(raw string) (time.Time, error) {
+	if n, err := strconv.ParseFloat(raw, 64); err == nil {
+		return ExcelDateToTime(n, d.date1904)
+	}
+	var (
+		t   time.Time
+		err error
+	)
+	for _, layout := range defaultDateLayouts {
+		if t, err = time.Parse(layout, raw); err == nil {
+			return t, err
+		}
+	}
+	return t, err
+}
+
+// MarshalRows provides a function to write the slice of structs into the
+// worksheet by given worksheet name and the slice, the element of the slice
+// could be the struct or pointer to the struct. The header row will be
+// written in the first row by the header names which specified by the xlsx
+// struct tags, and each struct will be written as a row starting from the
+// second row. The format option of the tag specifies the number format code
+// of the column. The types which implement the encoding.TextMarshaler
+// interface will be written as the text, and the nil pointer fields and zero
+// time fields will be written as the empty cells. Only the header row will be
+// written if the slice is empty. For example:
+//
+//	type Employee struct {
+//	    ID       int       `xlsx:"Employee ID"`
+//	    Name     string    `xlsx:"Name"`
+//	    Salary   float64   `xlsx:"Salary,format=#,##0.00"`
+//	    HireDate time.Time `xlsx:"Hire Date,format=yyyy-mm-dd"`
+//	}
+//
+//	err := f.MarshalRows("Sheet1", []Employee{
+//	    {ID: 1, Name: "Ana", Salary: 4200, HireDate: time.Now()},
+//	})
+func (f *File) MarshalRows(sheet string, v interface{}) error {
+	fields, header, records, err := marshalRecords(v)
+	if err != nil {
+		return err
+	}
+	if err = f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+	for i, record := range records {
+		cell, _ := CoordinatesToCellName(1, i+2)
+		if err = f.SetSheetRow(sheet, cell, &record); err != nil {
+			return err
+		}
+	}
+	if len(records) == 0 {
+		return err
+	}
+	for col, field := range fields {
+		if field.format == "" {
+			continue
+		}
+		styleID, err := f.NewStyle(&Style{CustomNumFmt: &field.format})
+		if err != nil {
+			return err
+		}
+		topLeftCell, _ := CoordinatesToCellName(col+1, 2)
+		bottomRightCell, _ := CoordinatesToCellName(col+1, len(records)+1)
+		if err = f.SetCellStyle(sheet, topLeftCell, bottomRightCell, styleID); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// MarshalRows provides a function to write the slice of structs into the
+// worksheet by stream writer as the File.MarshalRows function does, the
+// header row will be written in the row of the given cell reference, and the
+// structs will be written in the following rows. Only the header row will be
+// written if the slice is empty, and the MarshalRow function could be
+// used to write the structs row by row for the large amounts of data. Note
+// that the rows should be written in ascending order.
+func (sw *StreamWriter) MarshalRows(cell string, v interface{}) error {
+	fields, header, records, err := marshalRecords(v)
+	if err != nil {
+		return err
+	}
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return err
+	}
+	if row+len(records) > TotalRows {
+		return ErrMaxRows
+	}
+	if err = sw.SetRow(cell, header); err != nil {
+		return err
+	}
+	styles, err := sw.structFieldStyles(fields)
+	if err != nil {
+		return err
+	}
+	for i, record := range records {
+		cell, _ := CoordinatesToCellName(col, row+i+1)
+		if err = sw.setStructRow(cell, styles, record); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// MarshalRow provides a function to write the struct as a row into the
+// worksheet by stream writer by given cell reference, the v could be the
+// struct or pointer to the struct. The header row will not be written, and
+// the format options of the xlsx struct tags will be applied to the cells.
+// For example, write the header row and the structs row by row:
+//
+//	if err := sw.MarshalRows("A1", []Employee{}); err != nil {
+//	    fmt.Println(err)
+//	}
+//	for i, employee := range employees {
+//	    cell, _ := excelize.CoordinatesToCellName(1, i+2)
+//	    if err := sw.MarshalRow(cell, employee); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	}
+func (sw *StreamWriter) MarshalRow(cell string, v interface{}) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct || val.Type() == timeType {
+		return ErrParameterInvalid
+	}
+	// Copy the struct into an addressable value, so that the fields which
+	// implement the encoding.TextMarshaler with the pointer receiver will be
+	// marshaled in the same way as the MarshalRows function
+	if !val.CanAddr() {
+		addr := reflect.New(val.Type()).Elem()
+		addr.Set(val)
+		val = addr
+	}
+	fields := parseStructFields(val.Type(), nil)
+	record, err := marshalRecord(val, fields)
+	if err != nil {
+		return err
+	}
+	styles, ok := sw.structStyles[val.Type()]
+	if !ok {
+		if styles, err = sw.structFieldStyles(fields); err != nil {
+			return err
+		}
+		if sw.structStyles == nil {
+			sw.structStyles = make(map[reflect.Type][]int)
+		}
+		sw.structStyles[val.Type()] = styles
+	}
+	return sw.setStructRow(cell, styles, record)
+}
+
+// structFieldStyles provides a function to create the styles by the number
+// formats of the struct fields, and returns the style IDs of the fields. The
+// style ID will be 0 if the number format of the field is empty.
+func (sw *StreamWriter) structFieldStyles(fields []structField) ([]int, error) {
+	styles := make([]int, len(fields))
+	for i, field := range fields {
+		if field.format == "" {
+			continue
+		}
+		styleID, err := sw.file.NewStyle(&Style{CustomNumFmt: &field.format})
+		if err != nil {
+			return styles, err
+		}
+		styles[i] = styleID
+	}
+	return styles, nil
+}
+
+// setStructRow provides a function to write the values of the struct as a
+// row by stream writer, and apply the style IDs of the fields.
+func (sw *StreamWriter) setStructRow(cell string, styles []int, record []interface{}) error {
+	for i, styleID := range styles {
+		if styleID == 0 || record[i] == nil {
+			continue
+		}
+		record[i] = Cell{StyleID: styleID, Value: record[i]}
+	}
+	return sw.SetRow(cell, record)
+}
+
+// marshalRecords provides a function to convert the slice of structs into
+// the header row and the cell values of the rows.
+func marshalRecords(v interface{}) ([]structField, []interface{}, [][]interface{}, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Slice {
+		return nil, nil, nil, ErrParameterInvalid
+	}
+	elem, _, err := structSliceType(val.Type())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	fields := parseStructFields(elem, nil)
+	header := make([]interface{}, len(fields))
+	for i, field := range fields {
+		header[i] = field.name
+	}
+	records := make([][]interface{}, 0, val.Len())
+	for i := 0; i < val.Len(); i++ {
+		item := val.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				records = append(records, make([]interface{}, len(fields)))
+				continue
+			}
+			item = item.Elem()
+		}
+		record, err := marshalRecord(item, fields)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		records = append(records, record)
+	}
+	return fields, header, records, nil
+}
+
+// marshalRecord provides a function to convert the struct into the cell
+// values of the row by given struct value and fields.
+func marshalRecord(val reflect.Value, fields []structField) ([]interface{}, error) {
+	record := make([]interface{}, len(fields))
+	for i, field := range fields {
+		value, err := marshalField(val.FieldByIndex(field.index))
+		if err != nil {
+			return record, newMarshalFieldError(field.name, err)
+		}
+		record[i] = value
+	}
+	return record, nil
+}
+
+// marshalField provides a function to convert the struct field into the cell
+// value, the nil pointer and zero time will be converted into nil.
+func marshalField(field reflect.Value) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+	if field.Type() == timeType {
+		if field.Interface().(time.Time).IsZero() {
+			return nil, nil
+		}
+		return field.Interface(), nil
+	}
+	if field.Type().Implements(textMarshalerType) || (field.CanAddr() && field.Addr().Type().Implements(textMarshalerType)) {
+		marshaler, ok := field.Interface().(encoding.TextMarshaler)
+		if !ok {
+			marshaler = field.Addr().Interface().(encoding.TextMarshaler)
+		}
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), nil
+	case reflect.Bool:
+		return field.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return field.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return field.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return field.Float(), nil
+	}
+	return nil, newUnsupportedFieldTypeError(field.Type().String())
+}
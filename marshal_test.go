@@ -0,0 +1,286 @@
+package excelize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testLevel is a custom type which implements the encoding.TextMarshaler and
+// encoding.TextUnmarshaler interfaces for testing.
+type testLevel int
+
+func (l testLevel) MarshalText() ([]byte, error) {
+	if l < 0 {
+		return nil, errors.New("invalid level")
+	}
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	if strings.Trim(string(text), "*") != "" {
+		return fmt.Errorf("invalid level %q", text)
+	}
+	*l = testLevel(len(text))
+	return nil
+}
+
+// testCode is a custom type which implements the encoding.TextMarshaler
+// interface with the pointer receiver for testing.
+type testCode string
+
+func (c *testCode) MarshalText() ([]byte, error) {
+	return []byte("#" + string(*c)), nil
+}
+
+type testProduct struct {
+	Code testCode `xlsx:"Code"`
+}
+
+type testAudit struct {
+	Updated time.Time `xlsx:"Updated,format=yyyy-mm-dd"`
+}
+
+type testEmployee struct {
+	ID      int     `xlsx:"Employee ID"`
+	Name    string  `xlsx:"Name"`
+	Salary  float64 `xlsx:"Salary,format=#,##0.00"`
+	Bonus   *float64
+	Active  bool      `xlsx:"Active"`
+	Age     uint8     `xlsx:"Age"`
+	Level   testLevel `xlsx:"Level"`
+	Note    string    `xlsx:"-"`
+	private string
+	testAudit
+}
+
+func TestMarshalRows(t *testing.T) {
+	f := NewFile()
+	bonus := 100.5
+	updated := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	employees := []testEmployee{
+		{ID: 1, Name: "Ana", Salary: 4200.5, Bonus: &bonus, Active: true, Age: 30, Level: 2, Note: "x", testAudit: testAudit{Updated: updated}},
+		{ID: 2, Name: "Bob", Salary: 3100, Age: 41, Level: 0, testAudit: testAudit{Updated: updated}},
+	}
+	assert.NoError(t, f.MarshalRows("Sheet1", employees))
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Employee ID", "Name", "Salary", "Bonus", "Active", "Age", "Level", "Updated"},
+		{"1", "Ana", "4,200.50", "100.5", "TRUE", "30", "**", "2024-01-02"},
+		{"2", "Bob", "3,100.00", "", "FALSE", "41", "", "2024-01-02"},
+	}, rows)
+
+	// Test unmarshal rows into the slice of structs and pointers
+	var result []testEmployee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &result))
+	employees[0].Note = ""
+	assert.Equal(t, employees, result)
+	var ptrs []*testEmployee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &ptrs, Options{RawCellValue: true}))
+	assert.Len(t, ptrs, 2)
+	assert.Equal(t, "Bob", ptrs[1].Name)
+
+	// Test unmarshal rows with the header row not in the first row, empty rows,
+	// case-insensitive and unknown headers, and formatted string fields
+	type record struct {
+		Name   string
+		Amount string `xlsx:"amount,format=0.0%"`
+		Date   string `xlsx:"Date"`
+		When   *time.Time
+		Count  int64
+		Ratio  float32
+	}
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B3", &[]interface{}{"NAME", "Unknown", "Amount", "Date", "When", "Count", "Ratio"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B4", &[]interface{}{"A", "x", 0.25, updated, "2024-01-02T15:04:05", 1e3, 0.5}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B6", &[]interface{}{"B", "y", "text", "", 45293.5, -2, nil}))
+	var records []record
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &records))
+	when := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	when2 := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []record{
+		{Name: "A", Amount: "25.0%", Date: "1/2/24 00:00", When: &when, Count: 1000, Ratio: 0.5},
+		{Name: "B", Amount: "text", When: &when2, Count: -2},
+	}, records)
+
+	// Test unmarshal rows with the header row only and empty worksheet
+	f = NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", []*testEmployee{}))
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &result))
+	assert.Empty(t, result)
+	assert.EqualError(t, f.UnmarshalRows("Sheet2", &result), "sheet Sheet2 does not exist")
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.NoError(t, f.UnmarshalRows("Sheet2", &result))
+	assert.Empty(t, result)
+
+	// Test marshal rows with nil pointer element
+	assert.NoError(t, f.MarshalRows("Sheet2", &[]*testEmployee{nil, {Name: "C"}}))
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "C", "0", "", "FALSE", "0"}, rows[2])
+
+	// Test marshal and unmarshal rows with invalid parameters
+	for _, v := range []interface{}{nil, []int{1}, []time.Time{}, testEmployee{}, &[]int{}} {
+		assert.Equal(t, ErrParameterInvalid, f.MarshalRows("Sheet1", v))
+	}
+	for _, v := range []interface{}{nil, result, &[]int{}, (*[]testEmployee)(nil)} {
+		assert.Equal(t, ErrParameterInvalid, f.UnmarshalRows("Sheet1", v))
+	}
+	assert.EqualError(t, f.MarshalRows("SheetN", []testEmployee{}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.MarshalRows("SheetN", []testEmployee{}), "sheet SheetN does not exist")
+	assert.EqualError(t, f.UnmarshalRows("SheetN", &result), "sheet SheetN does not exist")
+	assert.EqualError(t, f.MarshalRows("Sheet1", []testEmployee{{Level: -1}}), "cannot marshal field Level: invalid level")
+	assert.EqualError(t, f.MarshalRows("Sheet1", []struct{ C complex64 }{{}}), "cannot marshal field C: unsupported field type complex64")
+
+	// Test unmarshal rows with invalid cell values
+	for _, c := range []struct {
+		values []interface{}
+		err    string
+	}{
+		{[]interface{}{"x"}, "cannot unmarshal cell A2 into field Employee ID: strconv.ParseInt: parsing \"x\": invalid syntax"},
+		{[]interface{}{1.5}, "cannot unmarshal cell A2 into field Employee ID: strconv.ParseInt: parsing \"1.5\": invalid syntax"},
+		{[]interface{}{1, nil, "x"}, "cannot unmarshal cell C2 into field Salary: strconv.ParseFloat: parsing \"x\": invalid syntax"},
+		{[]interface{}{1, nil, 1, "x"}, "cannot unmarshal cell D2 into field Bonus: strconv.ParseFloat: parsing \"x\": invalid syntax"},
+		{[]interface{}{1, nil, 1, 1, "x"}, "cannot unmarshal cell E2 into field Active: strconv.ParseBool: parsing \"x\": invalid syntax"},
+		{[]interface{}{1, nil, 1, 1, true, 256}, "cannot unmarshal cell F2 into field Age: strconv.ParseUint: parsing \"256\": value out of range"},
+		{[]interface{}{1, nil, 1, 1, true, -1}, "cannot unmarshal cell F2 into field Age: strconv.ParseUint: parsing \"-1\": invalid syntax"},
+		{[]interface{}{1, nil, 1, 1, true, 1.5}, "cannot unmarshal cell F2 into field Age: strconv.ParseUint: parsing \"1.5\": invalid syntax"},
+		{[]interface{}{1, nil, 1, 1, true, 1e3}, "cannot unmarshal cell F2 into field Age: strconv.ParseUint: parsing \"1000\": value out of range"},
+		{[]interface{}{1, nil, 1, 1, true, 1, "x"}, "cannot unmarshal cell G2 into field Level: invalid level \"x\""},
+		{[]interface{}{1, nil, 1, 1, true, 1, "*", "x"}, "cannot unmarshal cell H2 into field Updated: parsing time \"x\" as \"2006-01-02T15:04:05.999999999Z07:00\": cannot parse \"x\" as \"2006\""},
+		{[]interface{}{1, nil, 1, 1, true, 1, "*", -1}, "cannot unmarshal cell H2 into field Updated: invalid date value -1.000000, negative values are not supported"},
+	} {
+		f = NewFile()
+		assert.NoError(t, f.MarshalRows("Sheet1", []testEmployee{}))
+		assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &c.values))
+		assert.EqualError(t, f.UnmarshalRows("Sheet1", &result), c.err)
+	}
+	f = NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"C", "I", "F"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{1, 1e300, 1e300}))
+	var invalid []struct {
+		C complex64
+		I int8
+		F float32
+	}
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &invalid), "cannot unmarshal cell A2 into field C: unsupported field type complex64")
+	assert.NoError(t, f.SetCellValue("Sheet1", "A2", nil))
+	assert.ErrorContains(t, f.UnmarshalRows("Sheet1", &invalid), "cannot unmarshal cell B2 into field I: strconv.ParseInt: parsing \"1000")
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", 128))
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &invalid), "cannot unmarshal cell B2 into field I: strconv.ParseInt: parsing \"128\": value out of range")
+	assert.NoError(t, f.SetCellValue("Sheet1", "B2", nil))
+	assert.ErrorContains(t, f.UnmarshalRows("Sheet1", &invalid), "cannot unmarshal cell C2 into field F: strconv.ParseFloat: parsing \"1000")
+
+	// Test unmarshal rows with unsupported charset workbook
+	f = NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", employees))
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &result), "XML syntax error on line 1: invalid UTF-8")
+	// Test unmarshal rows with unsupported charset shared strings table
+	f = NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", employees))
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.UnmarshalRows("Sheet1", &result), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestRowsUnmarshalRow(t *testing.T) {
+	f := NewFile()
+	employees := []testEmployee{{ID: 1, Name: "Ana"}, {ID: 2, Name: "Bob"}}
+	assert.NoError(t, f.MarshalRows("Sheet1", employees))
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var result []testEmployee
+	for rows.Next() {
+		var employee testEmployee
+		assert.NoError(t, rows.UnmarshalRow(&employee))
+		result = append(result, employee)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, []string{"Ana", "Bob"}, []string{result[0].Name, result[1].Name})
+
+	// Test unmarshal row with the header row only
+	f = NewFile()
+	assert.NoError(t, f.MarshalRows("Sheet1", []testEmployee{}))
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	var employee testEmployee
+	assert.Equal(t, io.EOF, rows.UnmarshalRow(&employee))
+	assert.Equal(t, ErrParameterInvalid, rows.UnmarshalRow(employee))
+	assert.Equal(t, ErrParameterInvalid, rows.UnmarshalRow(&time.Time{}))
+	assert.NoError(t, rows.Close())
+}
+
+func TestStreamWriterMarshalRows(t *testing.T) {
+	f := NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	updated := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, sw.MarshalRows("B2", []testEmployee{{ID: 1, Name: "Ana", Salary: 1234, testAudit: testAudit{Updated: updated}}}))
+	assert.NoError(t, sw.MarshalRow("B4", &testEmployee{ID: 2, Name: "Bob", Level: 1}))
+	assert.Equal(t, ErrParameterInvalid, sw.MarshalRow("B5", 1))
+	assert.Equal(t, ErrParameterInvalid, sw.MarshalRows("B5", 1))
+	assert.EqualError(t, sw.MarshalRow("B5", testEmployee{Level: -1}), "cannot marshal field Level: invalid level")
+	assert.Equal(t, newCellNameToCoordinatesError("B", newInvalidCellNameError("B")), sw.MarshalRows("B", []testEmployee{}))
+	assert.EqualError(t, sw.MarshalRows("A1048576", []testEmployee{{}}), ErrMaxRows.Error())
+	assert.NoError(t, sw.Flush())
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		nil,
+		{"", "Employee ID", "Name", "Salary", "Bonus", "Active", "Age", "Level", "Updated"},
+		{"", "1", "Ana", "1,234.00", "", "FALSE", "0", "", "2024-01-02"},
+		{"", "2", "Bob", "0", "", "FALSE", "0", "*"},
+	}, rows[:4])
+	var result []testEmployee
+	assert.NoError(t, f.UnmarshalRows("Sheet1", &result))
+	assert.Equal(t, "Bob", result[1].Name)
+	assert.NoError(t, f.Close())
+
+	// Test the style IDs of the fields are created once for each struct type
+	f = NewFile()
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.MarshalRow("A1", testEmployee{ID: 1}))
+	assert.NoError(t, sw.MarshalRow("A2", &testEmployee{ID: 2}))
+	assert.Len(t, sw.structStyles, 1)
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.Close())
+
+	// Test marshal the fields implement the encoding.TextMarshaler with the
+	// pointer receiver by the struct value and pointer
+	f = NewFile()
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, sw.MarshalRows("A1", []testProduct{{Code: "a"}}))
+	assert.NoError(t, sw.MarshalRow("A3", testProduct{Code: "b"}))
+	assert.NoError(t, sw.MarshalRow("A4", &testProduct{Code: "c"}))
+	assert.NoError(t, sw.Flush())
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Code"}, {"#a"}, {"#b"}, {"#c"}}, rows)
+	assert.NoError(t, f.Close())
+
+	// Test stream writer marshal rows with unsupported charset style sheet
+	f = NewFile()
+	sw, err = f.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, sw.MarshalRows("A1", []testEmployee{{ID: 1}}), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	assert.EqualError(t, sw.MarshalRow("A1", testEmployee{ID: 1}), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
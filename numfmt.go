@@ -4771,6 +4771,44 @@ func (f *File) getBuiltInNumFmtCode(numFmtID int) (string, bool) {
 	return "", false
 }
 
+// getNumFmtCode returns the number format code of the cell style by given
+// cell style index, returns empty string for the General number format.
+func (f *File) getNumFmtCode(styleID int) string {
+	var code string
+	if styleSheet, err := f.stylesReader(); err == nil && styleSheet.CellXfs != nil &&
+		styleID < len(styleSheet.CellXfs.Xf) && styleSheet.CellXfs.Xf[styleID].NumFmtID != nil {
+		numFmtID := *styleSheet.CellXfs.Xf[styleID].NumFmtID
+		if code, _ = styleSheet.getCustomNumFmtCode(numFmtID); code == "" && numFmtID != 0 {
+			code, _ = f.getBuiltInNumFmtCode(numFmtID)
+		}
+	}
+	return code
+}
+
+// numFmtDateTimeParts returns if the first section of the number format code
+// contains the date and time tokens.
+func numFmtDateTimeParts(code string) (hasDate, hasTime bool) {
+	p := nfp.NumberFormatParser()
+	for _, section := range p.Parse(code) {
+		for _, token := range section.Items {
+			if token.TType == nfp.TokenTypeElapsedDateTimes {
+				hasTime = true
+			}
+			if token.TType != nfp.TokenTypeDateTimes {
+				continue
+			}
+			value := strings.ToLower(token.TValue)
+			if strings.ContainsAny(value, "hs") || strings.Contains(value, "am/pm") {
+				hasTime = true
+				continue
+			}
+			hasDate = hasDate || strings.ContainsAny(value, "dmy")
+		}
+		break
+	}
+	return
+}
+
 // prepareNumberic split the number into two before and after parts by a
 // decimal point.
 func (nf *numberFormat) prepareNumberic(value string) {
@@ -0,0 +1,1806 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/xuri/nfp"
+)
+
+var (
+	// odsBorderStyles defined the OpenDocument border line width and style
+	// for the border styles of the cells, the index of the slice is the
+	// border style index of the cell style.
+	odsBorderStyles = []string{
+		"none", "0.75pt solid", "1.75pt solid", "0.75pt dashed", "0.75pt dotted",
+		"2.5pt solid", "2.25pt double", "0.5pt solid", "1.75pt dashed",
+		"0.75pt dashed", "1.75pt dashed", "0.75pt dashed", "1.75pt dashed",
+		"1.75pt dashed",
+	}
+	// odsLengthUnits defined the length units in points of the OpenDocument
+	// measurement values.
+	odsLengthUnits = map[string]float64{
+		"pt": 1, "cm": 72 / 2.54, "mm": 72 / 25.4, "in": 72, "pc": 12, "px": 0.75,
+	}
+	// odsFunctionPrefixes defined the prefixes of the functions in the Excel
+	// syntax formulas which introduced in the later versions of the Excel,
+	// the prefixes will be removed when exporting the formulas into the
+	// OpenFormula syntax, and added back when importing the formulas.
+	odsFunctionPrefixes = map[string]string{
+		"FILTER": "_xlfn._xlws.", "SORT": "_xlfn._xlws.",
+		"ACOT": "_xlfn.", "ACOTH": "_xlfn.", "AGGREGATE": "_xlfn.",
+		"ANCHORARRAY": "_xlfn.", "ARABIC": "_xlfn.", "ARRAYTOTEXT": "_xlfn.",
+		"BASE": "_xlfn.", "BETA.DIST": "_xlfn.", "BETA.INV": "_xlfn.",
+		"BINOM.DIST": "_xlfn.", "BINOM.DIST.RANGE": "_xlfn.",
+		"BINOM.INV": "_xlfn.", "BITAND": "_xlfn.", "BITLSHIFT": "_xlfn.",
+		"BITOR": "_xlfn.", "BITRSHIFT": "_xlfn.", "BITXOR": "_xlfn.",
+		"BYCOL": "_xlfn.", "BYROW": "_xlfn.", "CEILING.MATH": "_xlfn.",
+		"CEILING.PRECISE": "_xlfn.", "CHISQ.DIST": "_xlfn.",
+		"CHISQ.DIST.RT": "_xlfn.", "CHISQ.INV": "_xlfn.",
+		"CHISQ.INV.RT": "_xlfn.", "CHISQ.TEST": "_xlfn.",
+		"CHOOSECOLS": "_xlfn.", "CHOOSEROWS": "_xlfn.", "COMBINA": "_xlfn.",
+		"CONCAT": "_xlfn.", "CONFIDENCE.NORM": "_xlfn.",
+		"CONFIDENCE.T": "_xlfn.", "COT": "_xlfn.", "COTH": "_xlfn.",
+		"COVARIANCE.P": "_xlfn.", "COVARIANCE.S": "_xlfn.", "CSC": "_xlfn.",
+		"CSCH": "_xlfn.", "DAYS": "_xlfn.", "DECIMAL": "_xlfn.",
+		"DROP": "_xlfn.", "ERF.PRECISE": "_xlfn.", "ERFC.PRECISE": "_xlfn.",
+		"EXPAND": "_xlfn.", "EXPON.DIST": "_xlfn.", "F.DIST": "_xlfn.",
+		"F.DIST.RT": "_xlfn.", "F.INV": "_xlfn.", "F.INV.RT": "_xlfn.",
+		"F.TEST": "_xlfn.", "FILTERXML": "_xlfn.", "FLOOR.MATH": "_xlfn.",
+		"FLOOR.PRECISE": "_xlfn.", "FORECAST.ETS": "_xlfn.",
+		"FORECAST.ETS.CONFINT": "_xlfn.", "FORECAST.ETS.SEASONALITY": "_xlfn.",
+		"FORECAST.ETS.STAT": "_xlfn.", "FORECAST.LINEAR": "_xlfn.",
+		"FORMULATEXT": "_xlfn.", "GAMMA": "_xlfn.", "GAMMA.DIST": "_xlfn.",
+		"GAMMA.INV": "_xlfn.", "GAMMALN.PRECISE": "_xlfn.", "GAUSS": "_xlfn.",
+		"HSTACK": "_xlfn.", "HYPGEOM.DIST": "_xlfn.", "IFNA": "_xlfn.",
+		"IFS": "_xlfn.", "IMCOSH": "_xlfn.", "IMCOT": "_xlfn.",
+		"IMCSC": "_xlfn.", "IMCSCH": "_xlfn.", "IMSEC": "_xlfn.",
+		"IMSECH": "_xlfn.", "IMSINH": "_xlfn.", "IMTAN": "_xlfn.",
+		"ISFORMULA": "_xlfn.", "ISOMITTED": "_xlfn.", "ISOWEEKNUM": "_xlfn.",
+		"LAMBDA": "_xlfn.", "LET": "_xlfn.", "LOGNORM.DIST": "_xlfn.",
+		"LOGNORM.INV": "_xlfn.", "MAKEARRAY": "_xlfn.", "MAP": "_xlfn.",
+		"MAXIFS": "_xlfn.", "MINIFS": "_xlfn.", "MODE.MULT": "_xlfn.",
+		"MODE.SNGL": "_xlfn.", "MUNIT": "_xlfn.", "NEGBINOM.DIST": "_xlfn.",
+		"NETWORKDAYS.INTL": "_xlfn.", "NORM.DIST": "_xlfn.",
+		"NORM.INV": "_xlfn.", "NORM.S.DIST": "_xlfn.", "NORM.S.INV": "_xlfn.",
+		"NUMBERVALUE": "_xlfn.", "PDURATION": "_xlfn.",
+		"PERCENTILE.EXC": "_xlfn.", "PERCENTILE.INC": "_xlfn.",
+		"PERCENTRANK.EXC": "_xlfn.", "PERCENTRANK.INC": "_xlfn.",
+		"PERMUTATIONA": "_xlfn.", "PHI": "_xlfn.", "POISSON.DIST": "_xlfn.",
+		"QUARTILE.EXC": "_xlfn.", "QUARTILE.INC": "_xlfn.",
+		"RANDARRAY": "_xlfn.", "RANK.AVG": "_xlfn.", "RANK.EQ": "_xlfn.",
+		"REDUCE": "_xlfn.", "RRI": "_xlfn.", "SCAN": "_xlfn.", "SEC": "_xlfn.",
+		"SECH": "_xlfn.", "SEQUENCE": "_xlfn.", "SHEET": "_xlfn.",
+		"SHEETS": "_xlfn.", "SKEW.P": "_xlfn.", "SORTBY": "_xlfn.",
+		"STDEV.P": "_xlfn.", "STDEV.S": "_xlfn.", "SWITCH": "_xlfn.",
+		"T.DIST": "_xlfn.", "T.DIST.2T": "_xlfn.", "T.DIST.RT": "_xlfn.",
+		"T.INV": "_xlfn.", "T.INV.2T": "_xlfn.", "T.TEST": "_xlfn.",
+		"TAKE": "_xlfn.", "TEXTAFTER": "_xlfn.", "TEXTBEFORE": "_xlfn.",
+		"TEXTJOIN": "_xlfn.", "TEXTSPLIT": "_xlfn.", "TOCOL": "_xlfn.",
+		"TOROW": "_xlfn.", "UNICHAR": "_xlfn.", "UNICODE": "_xlfn.",
+		"UNIQUE": "_xlfn.", "VALUETOTEXT": "_xlfn.", "VAR.P": "_xlfn.",
+		"VAR.S": "_xlfn.", "VSTACK": "_xlfn.", "WEBSERVICE": "_xlfn.",
+		"WEIBULL.DIST": "_xlfn.", "WORKDAY.INTL": "_xlfn.",
+		"WRAPCOLS": "_xlfn.", "WRAPROWS": "_xlfn.", "XLOOKUP": "_xlfn.",
+		"XMATCH": "_xlfn.", "XOR": "_xlfn.", "Z.TEST": "_xlfn.",
+	}
+	odsCellRefRegexp   = regexp.MustCompile(`^\$?[A-Za-z]{1,3}\$?[1-9][0-9]*$`)
+	odsColumnRefRegexp = regexp.MustCompile(`^\$?[A-Za-z]{1,3}$`)
+	odsRowRefRegexp    = regexp.MustCompile(`^\$?[1-9][0-9]*$`)
+	odsDurationRegexp  = regexp.MustCompile(`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+)
+
+// odsStyles directly maps the styles which defined in the styles.xml and
+// content.xml parts of the OpenDocument Spreadsheet, and the cell style
+// index cache for the converted cell styles.
+type odsStyles struct {
+	styles     map[string]*decodeODSStyle
+	dataStyles map[string]*decodeODSDataStyle
+	cellStyles map[string]int
+}
+
+// odsWriter directly maps the settings for generating the content.xml part
+// of the OpenDocument Spreadsheet.
+type odsWriter struct {
+	f           *File
+	content     odsDocumentContent
+	date1904    bool
+	cellStyles  map[int]string
+	colStyles   map[string]string
+	rowStyles   map[string]string
+	tableStyle  string
+	dataStyles  map[string]string
+	valueTypes  map[string]string
+	numFmtCodes map[int]string
+}
+
+// OpenODS take the name of an OpenDocument Spreadsheet (.ods) file and
+// returns a populated spreadsheet file struct for it. The sheets, cell
+// values and types, formulas, basic cell styles, merged cells, column widths
+// and row heights in the OpenDocument Spreadsheet will be converted into the
+// workbook, the formulas will be converted from the OpenFormula syntax to
+// the Excel syntax. For example:
+//
+//	f, err := excelize.OpenODS("Book1.ods")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer func() {
+//	    if err := f.Close(); err != nil {
+//	        fmt.Println(err)
+//	    }
+//	}()
+//
+// The workbook could be saved as XLSX workbook by the SaveAs function, and
+// close the file by Close function after opening the spreadsheet.
+func OpenODS(filename string, opts ...Options) (*File, error) {
+	file, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+	f, err := OpenODSReader(file, opts...)
+	if err != nil {
+		if closeErr := file.Close(); closeErr != nil {
+			return f, closeErr
+		}
+		return f, err
+	}
+	return f, file.Close()
+}
+
+// OpenODSReader read the OpenDocument Spreadsheet (.ods) data stream from
+// io.Reader and return a populated spreadsheet file.
+func OpenODSReader(r io.Reader, opts ...Options) (*File, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f := NewFile(opts...)
+	if err = f.checkOpenReaderOptions(); err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, ErrWorkbookFileFormat
+	}
+	parts, err := f.readODSPackage(zr)
+	if err != nil {
+		return nil, err
+	}
+	var content, styleSheet decodeODSDocument
+	if err = f.xmlNewDecoder(bytes.NewReader(parts["content.xml"])).Decode(&content); err != nil {
+		return nil, err
+	}
+	if len(content.Tables) == 0 {
+		return nil, ErrWorkbookFileFormat
+	}
+	if data, ok := parts["styles.xml"]; ok {
+		if err = f.xmlNewDecoder(bytes.NewReader(data)).Decode(&styleSheet); err != nil {
+			return nil, err
+		}
+	}
+	styles := &odsStyles{
+		styles:     make(map[string]*decodeODSStyle),
+		dataStyles: make(map[string]*decodeODSDataStyle),
+		cellStyles: make(map[string]int),
+	}
+	for _, s := range []*decodeODSStyles{&styleSheet.Styles, &styleSheet.AutomaticStyles, &content.Styles, &content.AutomaticStyles} {
+		styles.add(s)
+	}
+	var hidden []string
+	for i := range content.Tables {
+		table := &content.Tables[i]
+		if i == 0 {
+			err = f.SetSheetName(f.GetSheetName(0), table.Name)
+		} else {
+			_, err = f.NewSheet(table.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err = f.readODSTable(table, styles); err != nil {
+			return nil, err
+		}
+		if s, ok := styles.styles["table:"+table.StyleName]; ok && s.TableProperties != nil && s.TableProperties.Display == "false" {
+			hidden = append(hidden, table.Name)
+		}
+	}
+	for _, sheet := range hidden {
+		if err = f.SetSheetVisible(sheet, false); err != nil {
+			return nil, err
+		}
+	}
+	return f, err
+}
+
+// readODSPackage provides a function to read the mimetype, content.xml and
+// styles.xml parts in the OpenDocument Spreadsheet package.
+func (f *File) readODSPackage(zr *zip.Reader) (map[string][]byte, error) {
+	var (
+		parts     = map[string][]byte{}
+		unzipSize int64
+	)
+	for _, file := range zr.File {
+		if unzipSize += file.FileInfo().Size(); unzipSize > f.options.UnzipSizeLimit {
+			return nil, newUnzipSizeLimitError(f.options.UnzipSizeLimit)
+		}
+		if file.Name != "mimetype" && file.Name != "content.xml" && file.Name != "styles.xml" {
+			continue
+		}
+		data, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parts[file.Name] = data
+	}
+	if mimeType, ok := parts["mimetype"]; ok && strings.TrimSpace(string(mimeType)) != ContentTypeOpenDocumentSpreadsheet {
+		return nil, ErrWorkbookFileFormat
+	}
+	if _, ok := parts["content.xml"]; !ok {
+		return nil, ErrWorkbookFileFormat
+	}
+	return parts, nil
+}
+
+// add provides a function to add the styles and data styles into the style
+// maps, the style:style elements will be indexed by the family and name.
+func (s *odsStyles) add(styles *decodeODSStyles) {
+	for i := range styles.Style {
+		style := &styles.Style[i]
+		s.styles[style.Family+":"+style.Name] = style
+	}
+	for i := range styles.DataStyles {
+		style := &styles.DataStyles[i]
+		if style.XMLName.Space == NameSpaceOpenDocumentDataStyle {
+			s.dataStyles[style.Name] = style
+		}
+	}
+}
+
+// flattenODSTableNodes returns the column and row elements of the table in
+// the document order, the elements in the header and group elements will be
+// flattened.
+func flattenODSTableNodes(nodes []decodeODSTableNode) []*decodeODSTableNode {
+	var items []*decodeODSTableNode
+	for i := range nodes {
+		switch nodes[i].XMLName.Local {
+		case "table-column", "table-row":
+			items = append(items, &nodes[i])
+		case "table-columns", "table-header-columns", "table-column-group",
+			"table-rows", "table-header-rows", "table-row-group":
+			items = append(items, flattenODSTableNodes(nodes[i].Children)...)
+		}
+	}
+	return items
+}
+
+// isEmpty returns if the table cell element is empty without value, formula
+// and merged cells.
+func (node *decodeODSTableNode) isEmpty() bool {
+	return node.ValueType == "" && node.Formula == "" && len(node.P) == 0 &&
+		node.ColumnsSpanned < 2 && node.RowsSpanned < 2
+}
+
+// readODSTable provides a function to convert the table:table element into
+// the worksheet by given table element and styles.
+func (f *File) readODSTable(table *decodeODSTableNode, styles *odsStyles) error {
+	col, row, colDefaultStyles := 1, 1, map[int]string{}
+	for _, node := range flattenODSTableNodes(table.Children) {
+		if node.XMLName.Local == "table-column" {
+			repeated := int(math.Max(float64(node.ColumnsRepeated), 1))
+			if col > MaxColumns {
+				continue
+			}
+			last := int(math.Min(float64(col+repeated-1), MaxColumns))
+			if err := f.readODSColumns(table.Name, col, last, node, styles); err != nil {
+				return err
+			}
+			for c := col; c <= last && node.DefaultCellStyleName != ""; c++ {
+				colDefaultStyles[c] = node.DefaultCellStyleName
+			}
+			col += repeated
+			continue
+		}
+		repeated := int(math.Max(float64(node.RowsRepeated), 1))
+		empty := true
+		for _, cell := range node.Children {
+			empty = empty && cell.isEmpty()
+		}
+		if empty && row+repeated-1 >= TotalRows {
+			break
+		}
+		if row+repeated-1 > TotalRows {
+			return ErrMaxRows
+		}
+		for r := row; r < row+repeated; r++ {
+			if err := f.readODSRow(table.Name, r, node, styles, colDefaultStyles); err != nil {
+				return err
+			}
+		}
+		row += repeated
+	}
+	return nil
+}
+
+// readODSColumns provides a function to set the width and visibility of the
+// columns by given worksheet name, column range and table:table-column
+// element.
+func (f *File) readODSColumns(sheet string, min, max int, node *decodeODSTableNode, styles *odsStyles) error {
+	minName, err := ColumnNumberToName(min)
+	if err != nil {
+		return err
+	}
+	maxName, err := ColumnNumberToName(max)
+	if err != nil {
+		return err
+	}
+	if s, ok := styles.styles["table-column:"+node.StyleName]; ok && s.TableColumnProperties != nil {
+		if width, ok := parseODSLength(s.TableColumnProperties.ColumnWidth); ok {
+			if err = f.SetColWidth(sheet, minName, maxName, odsPointsToColWidth(width)); err != nil {
+				return err
+			}
+		}
+	}
+	if node.Visibility == "collapse" || node.Visibility == "filter" {
+		return f.SetColVisible(sheet, minName+":"+maxName, false)
+	}
+	return nil
+}
+
+// readODSRow provides a function to set the height, visibility and cells of
+// the row by given worksheet name, row number and table:table-row element.
+func (f *File) readODSRow(sheet string, row int, node *decodeODSTableNode, styles *odsStyles, colDefaultStyles map[int]string) error {
+	if s, ok := styles.styles["table-row:"+node.StyleName]; ok && s.TableRowProperties != nil &&
+		s.TableRowProperties.UseOptimalRowHeight != "true" {
+		if height, ok := parseODSLength(s.TableRowProperties.RowHeight); ok {
+			if err := f.SetRowHeight(sheet, row, math.Round(height*100)/100); err != nil {
+				return err
+			}
+		}
+	}
+	if node.Visibility == "collapse" || node.Visibility == "filter" {
+		if err := f.SetRowVisible(sheet, row, false); err != nil {
+			return err
+		}
+	}
+	col := 1
+	for i := range node.Children {
+		cell := &node.Children[i]
+		if cell.XMLName.Local != "table-cell" && cell.XMLName.Local != "covered-table-cell" {
+			continue
+		}
+		repeated := int(math.Max(float64(cell.ColumnsRepeated), 1))
+		if cell.XMLName.Local == "covered-table-cell" || (cell.isEmpty() && (cell.StyleName == "" || col+repeated-1 >= MaxColumns)) {
+			col += repeated
+			continue
+		}
+		for c := col; c < col+repeated; c++ {
+			if err := f.readODSCell(sheet, c, row, cell, styles, colDefaultStyles[c]); err != nil {
+				return err
+			}
+		}
+		col += repeated
+	}
+	return nil
+}
+
+// readODSCell provides a function to set the value, formula, style and merged
+// range of the cell by given worksheet name, coordinates and table:table-cell
+// element.
+func (f *File) readODSCell(sheet string, col, row int, node *decodeODSTableNode, styles *odsStyles, defaultStyle string) error {
+	cell, err := CoordinatesToCellName(col, row)
+	if err != nil {
+		return err
+	}
+	var (
+		value     interface{}
+		text      []string
+		numFmtID  int
+		styleName = node.StyleName
+	)
+	for _, p := range node.P {
+		text = append(text, p.Text)
+	}
+	if styleName == "" {
+		styleName = defaultStyle
+	}
+	switch node.ValueType {
+	case "float", "percentage", "currency":
+		if value, err = strconv.ParseFloat(node.Value, 64); err != nil {
+			return err
+		}
+		numFmtID = map[string]int{"percentage": 10, "currency": 4}[node.ValueType]
+	case "date":
+		t, err := time.Parse("2006-01-02T15:04:05", node.DateValue)
+		if err != nil {
+			if t, err = time.Parse("2006-01-02", node.DateValue); err != nil {
+				return err
+			}
+		}
+		if value, err = timeToExcelTime(t, false); err != nil {
+			return err
+		}
+		if numFmtID = 14; t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 {
+			numFmtID = 22
+		}
+	case "time":
+		if value, err = parseODSDuration(node.TimeValue); err != nil {
+			return err
+		}
+		numFmtID = 21
+	case "boolean":
+		value = node.BooleanValue == "true"
+	default:
+		if node.StringValue != nil {
+			value = *node.StringValue
+		} else if len(text) > 0 {
+			value = strings.Join(text, "\n")
+		}
+	}
+	if value != nil {
+		if err = f.SetCellValue(sheet, cell, value); err != nil {
+			return err
+		}
+	}
+	if node.Formula != "" {
+		if err = f.setODSCellFormula(sheet, cell, node, value); err != nil {
+			return err
+		}
+	}
+	styleID, err := styles.cellStyleID(f, styleName, numFmtID)
+	if err != nil {
+		return err
+	}
+	if styleID != 0 {
+		if err = f.SetCellStyle(sheet, cell, cell, styleID); err != nil {
+			return err
+		}
+	}
+	if node.ColumnsSpanned > 1 || node.RowsSpanned > 1 {
+		endCell, err := CoordinatesToCellName(col+int(math.Max(float64(node.ColumnsSpanned), 1))-1,
+			row+int(math.Max(float64(node.RowsSpanned), 1))-1)
+		if err != nil {
+			return err
+		}
+		return f.MergeCell(sheet, cell, endCell)
+	}
+	return nil
+}
+
+// setODSCellFormula provides a function to set the formula of the cell by
+// given worksheet name, cell reference, table:table-cell element and the
+// cached value of the formula.
+func (f *File) setODSCellFormula(sheet, cell string, node *decodeODSTableNode, value interface{}) error {
+	if err := f.SetCellFormula(sheet, cell, odsFormulaToExcel(node.Formula)); err != nil {
+		return err
+	}
+	ws, err := f.workSheetReader(sheet)
+	if err != nil {
+		return err
+	}
+	c, _, _, err := ws.prepareCell(cell)
+	if err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case float64:
+		c.T, c.V = "", strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		c.T, c.V = "b", "0"
+		if v {
+			c.V = "1"
+		}
+	case string:
+		if c.T, c.V = "str", v; node.CalcExtValueType == "error" {
+			c.T = "e"
+		}
+	}
+	return err
+}
+
+// cellStyleID provides a function to get the cell style index by given cell
+// style name and the default number format for the value type of the cell,
+// the cell style will be created if not exists.
+func (s *odsStyles) cellStyleID(f *File, name string, numFmtID int) (int, error) {
+	key := fmt.Sprintf("%s|%d", name, numFmtID)
+	if styleID, ok := s.cellStyles[key]; ok {
+		return styleID, nil
+	}
+	var style Style
+	if dataStyle := s.resolveCellStyle(name, &style, 0); dataStyle != "" {
+		s.setNumFmt(&style, s.numFmtCode(dataStyle, 0))
+	} else {
+		style.NumFmt = numFmtID
+	}
+	var (
+		styleID int
+		err     error
+	)
+	if !reflect.DeepEqual(style, Style{}) {
+		if styleID, err = f.NewStyle(&style); err != nil {
+			return styleID, err
+		}
+	}
+	s.cellStyles[key] = styleID
+	return styleID, err
+}
+
+// resolveCellStyle provides a function to apply the formatting properties of
+// the cell style and its parent styles to the given style, and returns the
+// data style name of the cell style.
+func (s *odsStyles) resolveCellStyle(name string, style *Style, depth int) string {
+	odsStyle, ok := s.styles["table-cell:"+name]
+	if !ok || depth > 16 {
+		return ""
+	}
+	var dataStyle string
+	if odsStyle.ParentStyleName != "" {
+		dataStyle = s.resolveCellStyle(odsStyle.ParentStyleName, style, depth+1)
+	}
+	if odsStyle.DataStyleName != "" {
+		dataStyle = odsStyle.DataStyleName
+	}
+	if props := odsStyle.TableCellProperties; props != nil {
+		props.applyCellProperties(style)
+	}
+	if props := odsStyle.ParagraphProperties; props != nil {
+		if horizontal, ok := map[string]string{
+			"start": "left", "left": "left", "center": "center",
+			"end": "right", "right": "right", "justify": "justify",
+		}[props.TextAlign]; ok {
+			if style.Alignment == nil {
+				style.Alignment = &Alignment{}
+			}
+			style.Alignment.Horizontal = horizontal
+		}
+	}
+	if props := odsStyle.TextProperties; props != nil {
+		props.applyTextProperties(style)
+	}
+	return dataStyle
+}
+
+// applyCellProperties provides a function to apply the fill, border and
+// alignment settings of the style:table-cell-properties element to the
+// given style.
+func (props *decodeODSProperties) applyCellProperties(style *Style) {
+	if props.BackgroundColor == "transparent" {
+		style.Fill = Fill{}
+	}
+	if strings.HasPrefix(props.BackgroundColor, "#") {
+		style.Fill = Fill{Type: "pattern", Pattern: 1, Color: []string{strings.ToUpper(props.BackgroundColor[1:])}}
+	}
+	for _, border := range []struct{ typ, value string }{
+		{"left", props.Border}, {"right", props.Border}, {"top", props.Border}, {"bottom", props.Border},
+		{"left", props.BorderLeft}, {"right", props.BorderRight}, {"top", props.BorderTop},
+		{"bottom", props.BorderBottom}, {"diagonalDown", props.DiagonalTLBR}, {"diagonalUp", props.DiagonalBLTR},
+	} {
+		if border.value != "" {
+			setODSBorder(style, border.typ, border.value)
+		}
+	}
+	alignment := Alignment{}
+	if style.Alignment != nil {
+		alignment = *style.Alignment
+	}
+	if props.WrapOption != "" {
+		alignment.WrapText = props.WrapOption == "wrap"
+	}
+	if props.ShrinkToFit != "" {
+		alignment.ShrinkToFit = props.ShrinkToFit == "true"
+	}
+	if vertical, ok := map[string]string{
+		"top": "top", "middle": "center", "bottom": "bottom", "automatic": "",
+	}[props.VerticalAlign]; ok {
+		alignment.Vertical = vertical
+	}
+	if angle, err := strconv.ParseFloat(strings.TrimSuffix(props.RotationAngle, "deg"), 64); err == nil {
+		if angle = math.Mod(math.Round(angle), 360); angle <= 90 && angle >= 0 {
+			alignment.TextRotation = int(angle)
+		}
+		if angle >= 270 {
+			alignment.TextRotation = 450 - int(angle)
+		}
+	}
+	if alignment != (Alignment{}) {
+		style.Alignment = &alignment
+	}
+}
+
+// setODSBorder provides a function to set the border of the given style by
+// given border type and OpenDocument border value, such as "0.75pt solid
+// #000000".
+func setODSBorder(style *Style, typ, value string) {
+	for i := 0; i < len(style.Border); i++ {
+		if style.Border[i].Type == typ {
+			style.Border = append(style.Border[:i], style.Border[i+1:]...)
+			i--
+		}
+	}
+	border, width := Border{Type: typ, Color: "000000", Style: 1}, 0.0
+	for _, field := range strings.Fields(value) {
+		if strings.HasPrefix(field, "#") {
+			border.Color = strings.ToUpper(field[1:])
+			continue
+		}
+		if length, ok := parseODSLength(field); ok {
+			width = length
+			continue
+		}
+		switch field {
+		case "none", "hidden":
+			return
+		case "dashed":
+			if border.Style = 3; width >= 1.5 {
+				border.Style = 8
+			}
+		case "dotted":
+			border.Style = 4
+		case "double":
+			border.Style = 6
+		}
+	}
+	if border.Style == 1 && width >= 2.5 {
+		border.Style = 5
+	} else if border.Style == 1 && width >= 1.5 {
+		border.Style = 2
+	}
+	style.Border = append(style.Border, border)
+}
+
+// applyTextProperties provides a function to apply the font settings of the
+// style:text-properties element to the given style.
+func (props *decodeODSProperties) applyTextProperties(style *Style) {
+	font := Font{}
+	if style.Font != nil {
+		font = *style.Font
+	}
+	if family := strings.Trim(props.FontFamily, "'\""); family != "" {
+		font.Family = family
+	}
+	if props.FontName != "" {
+		font.Family = props.FontName
+	}
+	if size, ok := parseODSLength(props.FontSize); ok {
+		font.Size = size
+	}
+	if props.FontStyle != "" {
+		font.Italic = props.FontStyle == "italic" || props.FontStyle == "oblique"
+	}
+	if props.FontWeight != "" {
+		weight, _ := strconv.Atoi(props.FontWeight)
+		font.Bold = props.FontWeight == "bold" || weight >= 600
+	}
+	if strings.HasPrefix(props.Color, "#") {
+		font.Color = strings.ToUpper(props.Color[1:])
+	}
+	if props.TextUnderlineStyle != "" {
+		if font.Underline = ""; props.TextUnderlineStyle != "none" {
+			if font.Underline = "single"; props.TextUnderlineType == "double" {
+				font.Underline = "double"
+			}
+		}
+	}
+	if props.TextLineThroughStyle != "" {
+		font.Strike = props.TextLineThroughStyle != "none"
+	}
+	if font != (Font{}) {
+		style.Font = &font
+	}
+}
+
+// numFmtCode provides a function to convert the data style into the number
+// format code by given data style name, the conditional sections which
+// specified by the style:map elements will be converted into the sections of
+// the number format code.
+func (s *odsStyles) numFmtCode(name string, depth int) string {
+	dataStyle, ok := s.dataStyles[name]
+	if !ok || depth > 4 {
+		return ""
+	}
+	var (
+		sections []string
+		buf      strings.Builder
+		kind     = dataStyle.XMLName.Local
+	)
+	for _, item := range dataStyle.Items {
+		long := item.Style == "long"
+		switch item.XMLName.Local {
+		case "map":
+			if code := s.numFmtCode(item.ApplyStyleName, depth+1); code != "" {
+				sections = append(sections, code)
+			}
+		case "number":
+			if item.DecimalPlaces == "" && kind == "number-style" {
+				buf.WriteString("General")
+				continue
+			}
+			buf.WriteString(odsNumberFmtCode(item))
+		case "scientific-number":
+			digits, _ := strconv.Atoi(item.MinExponentDigits)
+			buf.WriteString(odsNumberFmtCode(item) + "E+" + strings.Repeat("0", int(math.Max(float64(digits), 2))))
+		case "fraction":
+			buf.WriteString("# ?/?")
+		case "text":
+			if kind == "percentage-style" && strings.TrimSpace(item.Value) == "%" {
+				buf.WriteString(item.Value)
+				continue
+			}
+			buf.WriteString(odsLiteralFmtCode(item.Value))
+		case "text-content":
+			buf.WriteString("@")
+		case "currency-symbol":
+			buf.WriteString("[$" + item.Value + "]")
+		case "year":
+			buf.WriteString(map[bool]string{true: "yyyy", false: "yy"}[long])
+		case "month":
+			code := map[bool]string{true: "mm", false: "m"}[long]
+			if item.Textual == "true" {
+				code = map[bool]string{true: "mmmm", false: "mmm"}[long]
+			}
+			buf.WriteString(code)
+		case "day":
+			buf.WriteString(map[bool]string{true: "dd", false: "d"}[long])
+		case "day-of-week":
+			buf.WriteString(map[bool]string{true: "dddd", false: "ddd"}[long])
+		case "hours":
+			code := map[bool]string{true: "hh", false: "h"}[long]
+			if dataStyle.TruncateOnOverflow == "false" {
+				code = "[" + code + "]"
+			}
+			buf.WriteString(code)
+		case "minutes":
+			buf.WriteString(map[bool]string{true: "mm", false: "m"}[long])
+		case "seconds":
+			buf.WriteString(map[bool]string{true: "ss", false: "s"}[long])
+			if places, _ := strconv.Atoi(item.DecimalPlaces); places > 0 {
+				buf.WriteString("." + strings.Repeat("0", places))
+			}
+		case "am-pm":
+			buf.WriteString("AM/PM")
+		case "boolean":
+			buf.WriteString("\"TRUE\";\"TRUE\";\"FALSE\"")
+		}
+	}
+	return strings.Join(append(sections, buf.String()), ";")
+}
+
+// odsNumberFmtCode returns the number format code of the number element by
+// given number:number or number:scientific-number element.
+func odsNumberFmtCode(item decodeODSDataStyleItem) string {
+	places, _ := strconv.Atoi(item.DecimalPlaces)
+	digits, _ := strconv.Atoi(item.MinIntegerDigits)
+	digits = int(math.Max(float64(digits), 1))
+	code := strings.Repeat("0", digits)
+	if item.Grouping == "true" {
+		var buf strings.Builder
+		code = strings.Repeat("#", int(math.Max(float64(4-digits), 0))) + code
+		for i, r := range code {
+			if i > 0 && (len(code)-i)%3 == 0 {
+				buf.WriteRune(',')
+			}
+			buf.WriteRune(r)
+		}
+		code = buf.String()
+	}
+	if places > 0 {
+		code += "." + strings.Repeat("0", places)
+	}
+	return code
+}
+
+// odsLiteralFmtCode returns the number format code of the literal text, the
+// characters which could be displayed without quotation marks will be kept,
+// and the others will be enclosed in quotation marks.
+func odsLiteralFmtCode(text string) string {
+	if text == "" || strings.Trim(text, "$-+/():!^&'~{}<>= ") == "" {
+		return text
+	}
+	var parts []string
+	for _, part := range strings.Split(text, "\"") {
+		if part != "" {
+			part = "\"" + part + "\""
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "\\\"")
+}
+
+// setNumFmt provides a function to set the number format of the style by
+// given number format code, the built-in number format index will be used
+// if the code matches a built-in number format.
+func (s *odsStyles) setNumFmt(style *Style, code string) {
+	if code == "" || strings.EqualFold(code, "General") {
+		return
+	}
+	for numFmtID := 1; numFmtID <= 49; numFmtID++ {
+		if builtInNumFmt[numFmtID] == code {
+			style.NumFmt = numFmtID
+			return
+		}
+	}
+	style.CustomNumFmt = &code
+}
+
+// parseODSLength provides a function to convert the OpenDocument length
+// value into points, such as 2.258cm and 0.1665in.
+func parseODSLength(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) < 3 {
+		return 0, false
+	}
+	unit, ok := odsLengthUnits[value[len(value)-2:]]
+	if !ok {
+		return 0, false
+	}
+	length, err := strconv.ParseFloat(value[:len(value)-2], 64)
+	if err != nil {
+		return 0, false
+	}
+	return length * unit, true
+}
+
+// parseODSDuration provides a function to convert the OpenDocument duration
+// value into the number of days, such as PT12H30M00S.
+func parseODSDuration(value string) (float64, error) {
+	matches := odsDurationRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, newInvalidODSValueError(value)
+	}
+	var duration float64
+	for i, unit := range []float64{1, 24, 24 * 60, 24 * 60 * 60} {
+		if n, err := strconv.ParseFloat(matches[i+2], 64); err == nil {
+			duration += n / unit
+		}
+	}
+	if matches[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
+
+// odsPointsToColWidth provides a function to convert the column width in
+// points into the character units.
+func odsPointsToColWidth(points float64) float64 {
+	pixels := points * 96 / 72
+	if pixels < 12 {
+		return math.Round(pixels/12*1e6) / 1e6
+	}
+	return math.Round((pixels-5)/7*1e6) / 1e6
+}
+
+// odsColWidthToPoints provides a function to convert the column width in
+// character units into points.
+func odsColWidthToPoints(width float64) float64 {
+	pixels := width*7 + 5
+	if width < 1 {
+		pixels = width * 12
+	}
+	return pixels * 72 / 96
+}
+
+// formatODSLength returns the OpenDocument length value in points by given
+// points.
+func formatODSLength(points float64) string {
+	return strconv.FormatFloat(math.Round(points*1e4)/1e4, 'f', -1, 64) + "pt"
+}
+
+// scanODSQuoted returns the index after the closing quotation mark by given
+// runes of the formula, the index of the opening quotation mark and the
+// quotation mark, the doubled quotation marks will be treated as escaped.
+func scanODSQuoted(rs []rune, i int, quote rune) int {
+	for i++; i < len(rs); i++ {
+		if rs[i] == quote {
+			if i+1 < len(rs) && rs[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(rs)
+}
+
+// odsFormulaToExcel provides a function to convert the formula in the
+// OpenFormula syntax into the Excel syntax, such as convert
+// of:=SUM([.A1:.B2];[$Sheet2.C3]) to SUM(A1:B2,Sheet2!C3).
+func odsFormulaToExcel(formula string) string {
+	if strings.HasPrefix(formula, "msoxl:") {
+		return strings.TrimPrefix(strings.TrimPrefix(formula, "msoxl:"), "=")
+	}
+	for _, prefix := range []string{"of:", "oooc:"} {
+		formula = strings.TrimPrefix(formula, prefix)
+	}
+	var (
+		buf strings.Builder
+		rs  = []rune(strings.TrimPrefix(formula, "="))
+	)
+	for i := 0; i < len(rs); i++ {
+		switch rs[i] {
+		case '"':
+			end := scanODSQuoted(rs, i, '"')
+			buf.WriteString(string(rs[i:end]))
+			i = end - 1
+		case '[':
+			end := i + 1
+			for end < len(rs) && rs[end] != ']' {
+				if rs[end] == '\'' {
+					end = scanODSQuoted(rs, end, '\'')
+					continue
+				}
+				end++
+			}
+			buf.WriteString(odsRefToExcel(string(rs[i+1 : int(math.Min(float64(end), float64(len(rs))))])))
+			i = end
+		case ';':
+			buf.WriteRune(',')
+		case '|':
+			buf.WriteRune(';')
+		default:
+			if !unicode.IsLetter(rs[i]) && rs[i] != '_' {
+				buf.WriteRune(rs[i])
+				continue
+			}
+			end := i
+			for end < len(rs) && (unicode.IsLetter(rs[end]) || unicode.IsDigit(rs[end]) || rs[end] == '.' || rs[end] == '_') {
+				end++
+			}
+			token := string(rs[i:end])
+			if end < len(rs) && rs[end] == '(' {
+				token = odsFunctionToExcel(token)
+			}
+			buf.WriteString(token)
+			i = end - 1
+		}
+	}
+	return buf.String()
+}
+
+// odsFunctionToExcel provides a function to convert the function name in the
+// OpenFormula syntax into the Excel syntax, the COM.MICROSOFT. namespace of
+// the function name will be removed, and the prefix will be added for the
+// functions which introduced in the later versions of the Excel, such as
+// convert COM.MICROSOFT.XLOOKUP to _xlfn.XLOOKUP.
+func odsFunctionToExcel(name string) string {
+	if strings.HasPrefix(strings.ToUpper(name), "COM.MICROSOFT.") {
+		name = name[len("COM.MICROSOFT."):]
+	}
+	return odsFunctionPrefixes[strings.ToUpper(name)] + name
+}
+
+// excelFunctionToODS provides a function to convert the function name in the
+// Excel syntax into the OpenFormula syntax, the _xlfn. and _xlws. prefixes of
+// the function name will be removed, such as convert _xlfn._xlws.SORT to
+// SORT.
+func excelFunctionToODS(name string) string {
+	for _, prefix := range []string{"_xlfn.", "_xlws."} {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			name = name[len(prefix):]
+		}
+	}
+	return name
+}
+
+// odsRefToExcel provides a function to convert the reference in the
+// OpenFormula syntax into the Excel syntax, such as convert
+// $'Sheet 1'.A1:.B2 to 'Sheet 1'!A1:B2.
+func odsRefToExcel(ref string) string {
+	if strings.Contains(ref, "#REF!") {
+		return "#REF!"
+	}
+	var (
+		sheets, cells []string
+		rs            = []rune(ref)
+		start         int
+	)
+	for i := 0; i <= len(rs); i++ {
+		if i < len(rs) && rs[i] == '\'' {
+			i = scanODSQuoted(rs, i, '\'') - 1
+			continue
+		}
+		if i < len(rs) && rs[i] != ':' {
+			continue
+		}
+		part := strings.TrimPrefix(string(rs[start:i]), "$")
+		var sheet string
+		if strings.HasPrefix(part, "'") {
+			end := scanODSQuoted([]rune(part), 0, '\'')
+			sheet = strings.ReplaceAll(string([]rune(part)[1:end-1]), "''", "'")
+			part = string([]rune(part)[end:])
+		} else if idx := strings.Index(part, "."); idx > 0 {
+			sheet, part = part[:idx], part[idx:]
+		}
+		if sheet != "" && (len(sheets) == 0 || sheets[0] != sheet) {
+			sheets = append(sheets, sheet)
+		}
+		cells, start = append(cells, strings.TrimPrefix(part, ".")), i+1
+	}
+	if len(sheets) > 0 {
+		sheet := strings.Join(sheets, ":")
+		for _, name := range sheets {
+			if escapeSheetName(name) != name {
+				sheet = "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+				break
+			}
+		}
+		return sheet + "!" + strings.Join(cells, ":")
+	}
+	return strings.Join(cells, ":")
+}
+
+// excelFormulaToODS provides a function to convert the formula in the Excel
+// syntax into the OpenFormula syntax, such as convert SUM(A1:B2,Sheet2!C3)
+// to of:=SUM([.A1:.B2];[$Sheet2.C3]).
+func excelFormulaToODS(formula string) string {
+	var (
+		buf        strings.Builder
+		arrayDepth int
+		rs         = []rune(strings.TrimPrefix(formula, "="))
+	)
+	isOperand := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.$!:\\'", r)
+	}
+	buf.WriteString("of:=")
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; {
+		case r == '"':
+			end := scanODSQuoted(rs, i, '"')
+			buf.WriteString(string(rs[i:end]))
+			i = end - 1
+		case r == '{' || r == '}':
+			if arrayDepth++; r == '}' {
+				arrayDepth -= 2
+			}
+			buf.WriteRune(r)
+		case r == ',':
+			buf.WriteRune(';')
+		case r == ';' && arrayDepth > 0:
+			buf.WriteRune('|')
+		case isOperand(r) && r != ':' && r != '.':
+			end := i
+			for end < len(rs) && isOperand(rs[end]) {
+				if rs[end] == '\'' {
+					end = scanODSQuoted(rs, end, '\'')
+					continue
+				}
+				end++
+			}
+			token := string(rs[i:end])
+			if end < len(rs) && rs[end] == '(' {
+				token = excelFunctionToODS(token)
+			} else if ref, ok := excelRefToODS(token); ok {
+				token = "[" + ref + "]"
+			}
+			buf.WriteString(token)
+			i = end - 1
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// excelRefToODS provides a function to convert the reference in the Excel
+// syntax into the OpenFormula syntax by given operand token, and returns if
+// the token is a valid reference.
+func excelRefToODS(token string) (string, bool) {
+	var sheets []string
+	ref := token
+	if idx := strings.LastIndex(token, "!"); idx != -1 {
+		sheet := token[:idx]
+		if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") && len(sheet) > 1 {
+			sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+		}
+		sheets, ref = strings.Split(sheet, ":"), token[idx+1:]
+	}
+	parts := strings.Split(ref, ":")
+	if len(parts) > 2 || len(sheets) > len(parts) {
+		return "", false
+	}
+	for _, re := range []*regexp.Regexp{odsCellRefRegexp, odsColumnRefRegexp, odsRowRefRegexp} {
+		valid := len(parts) == 2 || re == odsCellRefRegexp
+		for _, part := range parts {
+			valid = valid && re.MatchString(part)
+			if col := strings.TrimRight(strings.ReplaceAll(part, "$", ""), "0123456789"); valid && col != "" {
+				num, err := ColumnNameToNumber(col)
+				valid = err == nil && num <= MaxColumns
+			}
+		}
+		if !valid {
+			continue
+		}
+		for i, part := range parts {
+			if i < len(sheets) {
+				name := sheets[i]
+				if strings.IndexFunc(name, func(r rune) bool {
+					return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+				}) != -1 {
+					name = "'" + strings.ReplaceAll(name, "'", "''") + "'"
+				}
+				parts[i] = "$" + name + "." + part
+				continue
+			}
+			parts[i] = "." + part
+		}
+		return strings.Join(parts, ":"), true
+	}
+	return "", false
+}
+
+// SaveAsODS provides a function to save the spreadsheet as the OpenDocument
+// Spreadsheet (.ods) file by given path. The sheets, cell values and types,
+// formulas, basic cell styles, merged cells, column widths and row heights
+// will be converted into the OpenDocument Spreadsheet, and the formulas will
+// be converted from the Excel syntax to the OpenFormula syntax. For example:
+//
+//	if err := f.SaveAsODS("Book1.ods"); err != nil {
+//	    fmt.Println(err)
+//	}
+func (f *File) SaveAsODS(name string) error {
+	if len(name) > MaxFilePathLength {
+		return ErrMaxFilePathLength
+	}
+	file, err := os.OpenFile(filepath.Clean(name), os.O_WRONLY|os.O_TRUNC|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return f.WriteODS(file)
+}
+
+// WriteODS provides a function to write the spreadsheet in the OpenDocument
+// Spreadsheet (.ods) format to the io.Writer.
+func (f *File) WriteODS(w io.Writer) error {
+	content, err := f.odsContent()
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	fi, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err = fi.Write([]byte(ContentTypeOpenDocumentSpreadsheet)); err != nil {
+		return err
+	}
+	for _, part := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "META-INF/manifest.xml", data: []byte(xml.Header + templateODSManifest)},
+		{name: "styles.xml", data: []byte(xml.Header + templateODSStyles)},
+		{name: "content.xml", data: content},
+	} {
+		if fi, err = zw.Create(part.name); err != nil {
+			return err
+		}
+		if _, err = fi.Write(part.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// odsContent provides a function to generate the content.xml part of the
+// OpenDocument Spreadsheet for the workbook.
+func (f *File) odsContent() ([]byte, error) {
+	wb, err := f.workbookReader()
+	if err != nil {
+		return nil, err
+	}
+	w := &odsWriter{
+		f: f,
+		content: odsDocumentContent{
+			XMLNSOffice: NameSpaceOpenDocumentOffice,
+			XMLNSStyle:  NameSpaceOpenDocumentStyle,
+			XMLNSText:   NameSpaceOpenDocumentText,
+			XMLNSTable:  NameSpaceOpenDocumentTable,
+			XMLNSFo:     NameSpaceOpenDocumentFormattingObjects,
+			XMLNSNumber: NameSpaceOpenDocumentDataStyle,
+			XMLNSOf:     NameSpaceOpenDocumentFormula,
+			Version:     "1.2",
+		},
+		date1904:    wb.WorkbookPr != nil && wb.WorkbookPr.Date1904,
+		cellStyles:  make(map[int]string),
+		colStyles:   make(map[string]string),
+		rowStyles:   make(map[string]string),
+		dataStyles:  make(map[string]string),
+		valueTypes:  make(map[string]string),
+		numFmtCodes: make(map[int]string),
+	}
+	for _, sheet := range f.GetSheetList() {
+		if err = w.writeTable(sheet); err != nil {
+			return nil, err
+		}
+	}
+	content, err := xml.Marshal(w.content)
+	return append([]byte(xml.Header), content...), err
+}
+
+// writeTable provides a function to convert the worksheet into the
+// table:table element by given worksheet name.
+func (w *odsWriter) writeTable(sheet string) error {
+	w.f.mu.Lock()
+	ws, err := w.f.workSheetReader(sheet)
+	w.f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	table := odsTable{Name: sheet}
+	if visible, _ := w.f.GetSheetVisible(sheet); !visible {
+		table.StyleName = w.tableStyleName()
+	}
+	var (
+		maxCol, maxRow = 1, 0
+		cells          = map[int]map[int]*xlsxC{}
+		rows           = map[int]*xlsxRow{}
+		covered        = map[[2]int]bool{}
+		spans          = map[[2]int][2]int{}
+		lastCols       = map[int]int{}
+	)
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			coordinates, err := rangeRefToCoordinates(mergeCell.Ref)
+			if err != nil {
+				return err
+			}
+			_ = sortCoordinates(coordinates)
+			spans[[2]int{coordinates[0], coordinates[1]}] = [2]int{coordinates[2] - coordinates[0] + 1, coordinates[3] - coordinates[1] + 1}
+			for col := coordinates[0]; col <= coordinates[2]; col++ {
+				for row := coordinates[1]; row <= coordinates[3]; row++ {
+					if col != coordinates[0] || row != coordinates[1] {
+						covered[[2]int{col, row}] = true
+					}
+					lastCols[row] = int(math.Max(float64(lastCols[row]), float64(col)))
+				}
+			}
+			maxRow = int(math.Max(float64(maxRow), float64(coordinates[3])))
+		}
+	}
+	for i := range ws.SheetData.Row {
+		row := &ws.SheetData.Row[i]
+		rows[row.R] = row
+		if row.CustomHeight || row.Hidden {
+			maxRow = int(math.Max(float64(maxRow), float64(row.R)))
+		}
+		for j := range row.C {
+			c := &row.C[j]
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return err
+			}
+			if c.V == "" && c.F == nil && c.IS == nil && c.S == 0 {
+				continue
+			}
+			if cells[r] == nil {
+				cells[r] = map[int]*xlsxC{}
+			}
+			cells[r][col] = c
+			lastCols[r] = int(math.Max(float64(lastCols[r]), float64(col)))
+			maxRow = int(math.Max(float64(maxRow), float64(r)))
+		}
+	}
+	for _, col := range lastCols {
+		maxCol = int(math.Max(float64(maxCol), float64(col)))
+	}
+	table.Columns = w.tableColumns(ws, maxCol)
+	for r := 1; r <= int(math.Max(float64(maxRow), 1)); r++ {
+		tableRow := odsTableRow{}
+		if row, ok := rows[r]; ok {
+			if row.CustomHeight && row.Ht != nil {
+				tableRow.StyleName = w.rowStyleName(*row.Ht)
+			}
+			if row.Hidden {
+				tableRow.Visibility = "collapse"
+			}
+		}
+		for col := 1; col <= lastCols[r]; col++ {
+			if covered[[2]int{col, r}] {
+				tableRow.Cells = append(tableRow.Cells, odsTableCell{XMLName: xml.Name{Local: "table:covered-table-cell"}})
+				continue
+			}
+			span, spanned := spans[[2]int{col, r}]
+			c, ok := cells[r][col]
+			if !ok && !spanned {
+				tableRow.appendEmptyCell()
+				continue
+			}
+			var cell odsTableCell
+			if ok {
+				if cell, err = w.tableCell(sheet, c, col, r); err != nil {
+					return err
+				}
+			}
+			if spanned {
+				cell.ColumnsSpanned, cell.RowsSpanned = span[0], span[1]
+			}
+			tableRow.Cells = append(tableRow.Cells, cell)
+		}
+		if len(tableRow.Cells) == 0 {
+			tableRow.Cells = append(tableRow.Cells, odsTableCell{})
+		}
+		if last := len(table.Rows) - 1; last >= 0 && reflect.DeepEqual(tableRow, odsTableRow{Cells: []odsTableCell{{}}}) &&
+			reflect.DeepEqual(table.Rows[last].Cells, tableRow.Cells) && table.Rows[last].StyleName == "" && table.Rows[last].Visibility == "" {
+			table.Rows[last].Repeated = int(math.Max(float64(table.Rows[last].Repeated), 1)) + 1
+			continue
+		}
+		table.Rows = append(table.Rows, tableRow)
+	}
+	w.content.Tables = append(w.content.Tables, table)
+	return nil
+}
+
+// appendEmptyCell provides a function to append an empty cell into the row,
+// the consecutive empty cells will be merged by the repeated attribute.
+func (row *odsTableRow) appendEmptyCell() {
+	if last := len(row.Cells) - 1; last >= 0 && reflect.DeepEqual(row.Cells[last], odsTableCell{Repeated: row.Cells[last].Repeated}) {
+		row.Cells[last].Repeated = int(math.Max(float64(row.Cells[last].Repeated), 1)) + 1
+		return
+	}
+	row.Cells = append(row.Cells, odsTableCell{})
+}
+
+// tableColumns provides a function to generate the table:table-column
+// elements by given worksheet and the number of the used columns.
+func (w *odsWriter) tableColumns(ws *xlsxWorksheet, maxCol int) []odsTableColumn {
+	var columns []odsTableColumn
+	col := 1
+	if ws.Cols != nil {
+		cols := make([]xlsxCol, len(ws.Cols.Col))
+		copy(cols, ws.Cols.Col)
+		sort.Slice(cols, func(i, j int) bool { return cols[i].Min < cols[j].Min })
+		for _, c := range cols {
+			if c.Min < col || c.Min > MaxColumns {
+				continue
+			}
+			if c.Min > col {
+				columns = append(columns, odsTableColumn{Repeated: c.Min - col})
+			}
+			column := odsTableColumn{}
+			if c.Width != nil && (c.CustomWidth || *c.Width != defaultColWidth) {
+				column.StyleName = w.colStyleName(*c.Width)
+			}
+			if c.Hidden {
+				column.Visibility = "collapse"
+			}
+			if max := int(math.Min(float64(c.Max), MaxColumns)); max > c.Min {
+				column.Repeated = max - c.Min + 1
+			}
+			columns = append(columns, column)
+			col = int(math.Min(float64(c.Max), MaxColumns)) + 1
+		}
+	}
+	if col <= maxCol {
+		columns = append(columns, odsTableColumn{Repeated: maxCol - col + 1})
+	}
+	for i := range columns {
+		if columns[i].Repeated == 1 {
+			columns[i].Repeated = 0
+		}
+	}
+	return columns
+}
+
+// tableCell provides a function to convert the cell into the
+// table:table-cell element by given worksheet name, cell and coordinates.
+func (w *odsWriter) tableCell(sheet string, c *xlsxC, col, row int) (odsTableCell, error) {
+	var cell odsTableCell
+	ref, err := CoordinatesToCellName(col, row)
+	if err != nil {
+		return cell, err
+	}
+	if c.S != 0 {
+		if cell.StyleName, err = w.cellStyleName(c.S); err != nil {
+			return cell, err
+		}
+	}
+	if c.F != nil {
+		formula, err := w.f.GetCellFormula(sheet, ref)
+		if err != nil {
+			return cell, err
+		}
+		if formula != "" {
+			cell.Formula = excelFormulaToODS(formula)
+		}
+	}
+	text, err := w.f.GetCellValue(sheet, ref)
+	if err != nil {
+		return cell, err
+	}
+	switch c.T {
+	case "b":
+		cell.ValueType, cell.BooleanValue = "boolean", strconv.FormatBool(c.V == "1")
+	case "s", "inlineStr", "str", "e":
+		if text != "" || cell.Formula != "" {
+			cell.ValueType = "string"
+		}
+	case "d":
+		cell.ValueType, cell.DateValue = "date", strings.TrimSuffix(c.V, "Z")
+	default:
+		if c.V == "" {
+			break
+		}
+		value, err := strconv.ParseFloat(c.V, 64)
+		if err != nil {
+			cell.ValueType = "string"
+			break
+		}
+		cell.ValueType, cell.Value = "float", c.V
+		switch valueType := w.valueType(c.S); valueType {
+		case "date":
+			t, err := ExcelDateToTime(value, w.date1904)
+			if err != nil {
+				return cell, err
+			}
+			cell.ValueType, cell.Value, cell.DateValue = valueType, "", t.Format("2006-01-02T15:04:05")
+		case "time":
+			seconds := math.Round(math.Abs(value) * 86400)
+			cell.ValueType, cell.Value = valueType, ""
+			cell.TimeValue = fmt.Sprintf("PT%02dH%02dM%02dS", int(seconds)/3600, int(seconds)%3600/60, int(seconds)%60)
+			if value < 0 {
+				cell.TimeValue = "-" + cell.TimeValue
+			}
+		case "percentage":
+			cell.ValueType = valueType
+		}
+	}
+	if text != "" {
+		cell.P = strings.Split(text, "\n")
+	}
+	return cell, err
+}
+
+// numFmtCode provides a function to get the number format code of the cell
+// style by given cell style index.
+func (w *odsWriter) numFmtCode(styleID int) string {
+	if code, ok := w.numFmtCodes[styleID]; ok {
+		return code
+	}
+	code := w.f.getNumFmtCode(styleID)
+	w.numFmtCodes[styleID] = code
+	return code
+}
+
+// valueType provides a function to get the OpenDocument value type for the
+// numeric cell by given cell style index, such as date, time and percentage.
+func (w *odsWriter) valueType(styleID int) string {
+	if code := w.numFmtCode(styleID); code != "" {
+		if _, ok := w.dataStyles[code]; !ok {
+			w.dataStyleName(code)
+		}
+		return w.valueTypes[code]
+	}
+	return "float"
+}
+
+// dataStyleName provides a function to get the data style name by given
+// number format code, the data style will be created if not exists.
+func (w *odsWriter) dataStyleName(code string) string {
+	if name, ok := w.dataStyles[code]; ok {
+		return name
+	}
+	dataStyle, valueType := newODSDataStyle(code)
+	w.valueTypes[code] = valueType
+	if dataStyle == nil {
+		w.dataStyles[code] = ""
+		return ""
+	}
+	dataStyle.Name = fmt.Sprintf("N%d", len(w.content.AutomaticStyles.DataStyles)+1)
+	w.content.AutomaticStyles.DataStyles = append(w.content.AutomaticStyles.DataStyles, *dataStyle)
+	w.dataStyles[code] = dataStyle.Name
+	return dataStyle.Name
+}
+
+// newODSDataStyle provides a function to convert the positive section of the
+// number format code into the data style, and returns the data style and the
+// OpenDocument value type for the number format.
+func newODSDataStyle(code string) (*odsDataStyle, string) {
+	p := nfp.NumberFormatParser()
+	sections := p.Parse(code)
+	if len(sections) == 0 {
+		return nil, "float"
+	}
+	var (
+		items                  []odsDataStyleItem
+		hasDate, hasTime, perc bool
+		hasNumber, elapsed     bool
+		tokens                 = sections[0].Items
+	)
+	newItem := func(name string) odsDataStyleItem {
+		return odsDataStyleItem{XMLName: xml.Name{Local: "number:" + name}}
+	}
+	addText := func(text string) {
+		if last := len(items) - 1; last >= 0 && items[last].XMLName.Local == "number:text" {
+			items[last].Value += text
+			return
+		}
+		item := newItem("text")
+		item.Value = text
+		items = append(items, item)
+	}
+	if sections[0].Type == nfp.TokenSectionText {
+		return &odsDataStyle{
+			XMLName: xml.Name{Local: "number:text-style"},
+			Items:   []odsDataStyleItem{newItem("text-content")},
+		}, "string"
+	}
+	for i, token := range tokens {
+		switch token.TType {
+		case nfp.TokenTypeGeneral:
+			return nil, "float"
+		case nfp.TokenTypeDateTimes, nfp.TokenTypeElapsedDateTimes:
+			item, isDate := odsDateTimeItem(tokens, i)
+			hasDate, hasTime = hasDate || isDate, hasTime || !isDate
+			elapsed = elapsed || token.TType == nfp.TokenTypeElapsedDateTimes
+			items = append(items, item)
+		case nfp.TokenTypeLiteral:
+			addText(token.TValue)
+		case nfp.TokenTypePercent:
+			perc = true
+			addText("%")
+		case nfp.TokenTypeZeroPlaceHolder, nfp.TokenTypeHashPlaceHolder, nfp.TokenTypeDecimalPoint,
+			nfp.TokenTypeThousandsSeparator, nfp.TokenTypeDigitalPlaceHolder:
+			if hasNumber || hasDate || hasTime {
+				continue
+			}
+			hasNumber = true
+			decimal, _, _ := extractNumFmtDecimal(tokens)
+			item, minIntegerDigits := newItem("number"), 1
+			item.DecimalPlaces, item.MinIntegerDigits = &decimal, &minIntegerDigits
+			for _, t := range tokens {
+				item.Grouping = item.Grouping || t.TType == nfp.TokenTypeThousandsSeparator
+			}
+			items = append(items, item)
+		}
+	}
+	dataStyle := &odsDataStyle{Items: items}
+	switch {
+	case hasDate:
+		dataStyle.XMLName.Local = "number:date-style"
+		return dataStyle, "date"
+	case hasTime:
+		if dataStyle.XMLName.Local = "number:time-style"; elapsed {
+			dataStyle.TruncateOnOverflow = "false"
+		}
+		return dataStyle, "time"
+	case perc:
+		dataStyle.XMLName.Local = "number:percentage-style"
+		return dataStyle, "percentage"
+	case hasNumber:
+		dataStyle.XMLName.Local = "number:number-style"
+		return dataStyle, "float"
+	}
+	return nil, "float"
+}
+
+// odsDateTimeItem provides a function to convert the date and time token of
+// the number format code into the child element of the data style by given
+// tokens and the index of the token, and returns if the token is a date part.
+func odsDateTimeItem(tokens []nfp.Token, i int) (odsDataStyleItem, bool) {
+	value := strings.ToLower(tokens[i].TValue)
+	item := odsDataStyleItem{}
+	setItem := func(name string, long bool, isDate bool) (odsDataStyleItem, bool) {
+		item.XMLName = xml.Name{Local: "number:" + name}
+		if long {
+			item.Style = "long"
+		}
+		return item, isDate
+	}
+	isMinute := func() bool {
+		for j := i - 1; j >= 0; j-- {
+			if tokens[j].TType == nfp.TokenTypeDateTimes || tokens[j].TType == nfp.TokenTypeElapsedDateTimes {
+				if v := strings.ToLower(tokens[j].TValue); strings.HasPrefix(v, "h") {
+					return true
+				}
+				break
+			}
+		}
+		for j := i + 1; j < len(tokens); j++ {
+			if tokens[j].TType == nfp.TokenTypeDateTimes || tokens[j].TType == nfp.TokenTypeElapsedDateTimes {
+				return strings.HasPrefix(strings.ToLower(tokens[j].TValue), "s")
+			}
+		}
+		return false
+	}
+	switch {
+	case strings.HasPrefix(value, "y"), strings.HasPrefix(value, "e"):
+		return setItem("year", len(value) > 2 || value == "e", true)
+	case strings.HasPrefix(value, "m") && (tokens[i].TType == nfp.TokenTypeElapsedDateTimes || isMinute()):
+		return setItem("minutes", len(value) > 1, false)
+	case strings.HasPrefix(value, "m"):
+		if len(value) > 2 {
+			item.Textual = "true"
+		}
+		return setItem("month", len(value) == 2 || len(value) > 3, true)
+	case strings.HasPrefix(value, "d") && len(value) > 2:
+		return setItem("day-of-week", len(value) > 3, true)
+	case strings.HasPrefix(value, "d"):
+		return setItem("day", len(value) > 1, true)
+	case strings.HasPrefix(value, "h"):
+		return setItem("hours", len(value) > 1, false)
+	case strings.HasPrefix(value, "s"):
+		return setItem("seconds", len(value) > 1, false)
+	}
+	return setItem("am-pm", false, false)
+}
+
+// cellStyleName provides a function to get the automatic cell style name by
+// given cell style index, the cell style will be created if not exists.
+func (w *odsWriter) cellStyleName(styleID int) (string, error) {
+	if name, ok := w.cellStyles[styleID]; ok {
+		return name, nil
+	}
+	style, err := w.f.GetStyle(styleID)
+	if err != nil {
+		return "", err
+	}
+	odsStyle := odsStyle{
+		Name:            fmt.Sprintf("ce%d", len(w.cellStyles)+1),
+		Family:          "table-cell",
+		ParentStyleName: "Default",
+	}
+	if code := w.numFmtCode(styleID); code != "" {
+		odsStyle.DataStyleName = w.dataStyleName(code)
+	}
+	cellProps, paragraphProps, textProps := &odsProperties{}, &odsProperties{}, &odsProperties{}
+	if len(style.Fill.Color) > 0 && (style.Fill.Type == "gradient" || style.Fill.Pattern > 0) {
+		cellProps.BackgroundColor = odsColor(style.Fill.Color[0])
+	}
+	for _, border := range style.Border {
+		if border.Style < 1 || border.Style >= len(odsBorderStyles) {
+			continue
+		}
+		value := odsBorderStyles[border.Style] + " " + odsColor(border.Color)
+		switch border.Type {
+		case "left":
+			cellProps.BorderLeft = value
+		case "right":
+			cellProps.BorderRight = value
+		case "top":
+			cellProps.BorderTop = value
+		case "bottom":
+			cellProps.BorderBottom = value
+		case "diagonalDown":
+			cellProps.DiagonalTLBR = value
+		case "diagonalUp":
+			cellProps.DiagonalBLTR = value
+		}
+	}
+	if alignment := style.Alignment; alignment != nil {
+		if alignment.WrapText {
+			cellProps.WrapOption = "wrap"
+		}
+		if alignment.ShrinkToFit {
+			cellProps.ShrinkToFit = "true"
+		}
+		cellProps.VerticalAlign = map[string]string{"top": "top", "center": "middle", "bottom": "bottom"}[alignment.Vertical]
+		if alignment.TextRotation > 0 && alignment.TextRotation <= 90 {
+			cellProps.RotationAngle = strconv.Itoa(alignment.TextRotation)
+		}
+		if alignment.TextRotation > 90 && alignment.TextRotation <= 180 {
+			cellProps.RotationAngle = strconv.Itoa(450 - alignment.TextRotation)
+		}
+		paragraphProps.TextAlign = map[string]string{
+			"left": "start", "center": "center", "centerContinuous": "center",
+			"right": "end", "justify": "justify", "distributed": "justify",
+		}[alignment.Horizontal]
+	}
+	if font := style.Font; font != nil {
+		textProps.FontFamily, textProps.Color = font.Family, odsColor(font.Color)
+		if font.Color == "" {
+			textProps.Color = ""
+		}
+		if font.Size > 0 {
+			textProps.FontSize = formatODSLength(font.Size)
+		}
+		if font.Bold {
+			textProps.FontWeight = "bold"
+		}
+		if font.Italic {
+			textProps.FontStyle = "italic"
+		}
+		if font.Underline == "single" || font.Underline == "double" {
+			textProps.TextUnderlineStyle = "solid"
+			if font.Underline == "double" {
+				textProps.TextUnderlineType = "double"
+			}
+		}
+		if font.Strike {
+			textProps.TextLineThroughStyle = "solid"
+		}
+	}
+	for _, props := range []struct {
+		props *odsProperties
+		field **odsProperties
+	}{
+		{cellProps, &odsStyle.TableCellProperties},
+		{paragraphProps, &odsStyle.ParagraphProperties},
+		{textProps, &odsStyle.TextProperties},
+	} {
+		if *props.props != (odsProperties{}) {
+			*props.field = props.props
+		}
+	}
+	w.cellStyles[styleID] = odsStyle.Name
+	w.content.AutomaticStyles.Styles = append(w.content.AutomaticStyles.Styles, odsStyle)
+	return odsStyle.Name, err
+}
+
+// colStyleName provides a function to get the automatic column style name by
+// given column width, the column style will be created if not exists.
+func (w *odsWriter) colStyleName(width float64) string {
+	value := formatODSLength(odsColWidthToPoints(width))
+	if name, ok := w.colStyles[value]; ok {
+		return name
+	}
+	name := fmt.Sprintf("co%d", len(w.colStyles)+1)
+	w.colStyles[value] = name
+	w.content.AutomaticStyles.Styles = append(w.content.AutomaticStyles.Styles, odsStyle{
+		Name: name, Family: "table-column", TableColumnProperties: &odsProperties{ColumnWidth: value},
+	})
+	return name
+}
+
+// rowStyleName provides a function to get the automatic row style name by
+// given row height, the row style will be created if not exists.
+func (w *odsWriter) rowStyleName(height float64) string {
+	value := formatODSLength(height)
+	if name, ok := w.rowStyles[value]; ok {
+		return name
+	}
+	name := fmt.Sprintf("ro%d", len(w.rowStyles)+1)
+	w.rowStyles[value] = name
+	w.content.AutomaticStyles.Styles = append(w.content.AutomaticStyles.Styles, odsStyle{
+		Name: name, Family: "table-row", TableRowProperties: &odsProperties{RowHeight: value, UseOptimalRowHeight: "false"},
+	})
+	return name
+}
+
+// tableStyleName provides a function to get the automatic table style name
+// for the hidden tables, the table style will be created if not exists.
+func (w *odsWriter) tableStyleName() string {
+	if w.tableStyle == "" {
+		w.tableStyle = "ta1"
+		w.content.AutomaticStyles.Styles = append(w.content.AutomaticStyles.Styles, odsStyle{
+			Name: w.tableStyle, Family: "table", TableProperties: &odsProperties{Display: "false"},
+		})
+	}
+	return w.tableStyle
+}
+
+// odsColor returns the OpenDocument color value by given hex color, such as
+// convert FF0000 or FFFF0000 to #ff0000.
+func odsColor(color string) string {
+	if color = strings.TrimPrefix(color, "#"); len(color) == 8 {
+		color = color[2:]
+	}
+	if len(color) != 6 {
+		return "#000000"
+	}
+	return "#" + strings.ToLower(color)
+}
@@ -0,0 +1,395 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newODSPackage returns an OpenDocument Spreadsheet package by given parts.
+func newODSPackage(t *testing.T, parts map[string]string) []byte {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, name := range []string{"mimetype", "content.xml", "styles.xml"} {
+		data, ok := parts[name]
+		if !ok {
+			continue
+		}
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write([]byte(data))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestSaveAsODS(t *testing.T) {
+	f := NewFile()
+	_, err := f.NewSheet("Sheet 2")
+	assert.NoError(t, err)
+	for cell, value := range map[string]interface{}{
+		"A1": "Hello", "B1": 12.5, "C1": true, "D1": "Line 1\nLine 2",
+		"A2": 45293.5, "B2": 0.25, "C2": 0.5, "D2": -3,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet 2", "B3", 100))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E1", "SUM(B1:B2,'Sheet 2'!B3)*2"))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "E2", "IF(C1,\"a,b\",SUM({1,2;3,4}))"))
+	dateStyle, err := f.NewStyle(&Style{NumFmt: 22})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "A2", dateStyle))
+	percentStyle, err := f.NewStyle(&Style{NumFmt: 10})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", percentStyle))
+	timeStyle, err := f.NewStyle(&Style{NumFmt: 21})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C2", "C2", timeStyle))
+	numFmt := "#,##0.000"
+	style, err := f.NewStyle(&Style{
+		Font:         &Font{Bold: true, Italic: true, Family: "Arial", Size: 14, Color: "FF0000", Underline: "double", Strike: true},
+		Fill:         Fill{Type: "pattern", Pattern: 1, Color: []string{"FFFF00"}},
+		Border:       []Border{{Type: "left", Color: "0000FF", Style: 2}, {Type: "bottom", Style: 6}},
+		Alignment:    &Alignment{Horizontal: "center", Vertical: "top", WrapText: true, TextRotation: 45},
+		CustomNumFmt: &numFmt,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "D2", "D2", style))
+	assert.NoError(t, f.MergeCell("Sheet1", "A4", "B5"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A4", "Merged"))
+	assert.NoError(t, f.MergeCell("Sheet1", "F1", "G2"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "B", "C", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 2, 30))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 8, false))
+	assert.NoError(t, f.SetSheetVisible("Sheet 2", false))
+
+	assert.NoError(t, f.SaveAsODS(filepath.Join("test", "TestSaveAsODS.ods")))
+	ods, err := OpenODS(filepath.Join("test", "TestSaveAsODS.ods"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet 2"}, ods.GetSheetList())
+	for cell, expected := range map[string]string{
+		"A1": "Hello", "B1": "12.5", "C1": "1", "D1": "Line 1\nLine 2",
+		"A2": "45293.5", "B2": "0.25", "C2": "0.5", "D2": "-3", "A4": "Merged",
+	} {
+		value, err := ods.GetCellValue("Sheet1", cell, Options{RawCellValue: true})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	for cell, expected := range map[string]string{"A2": "1/2/24 12:00", "B2": "25.00%", "C2": "12:00:00", "D2": "-3.000"} {
+		value, err := ods.GetCellValue("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	cellType, err := ods.GetCellType("Sheet1", "C1")
+	assert.NoError(t, err)
+	assert.Equal(t, CellTypeBool, cellType)
+	for cell, expected := range map[string]string{
+		"E1": "SUM(B1:B2,'Sheet 2'!B3)*2", "E2": "IF(C1,\"a,b\",SUM({1,2;3,4}))",
+	} {
+		formula, err := ods.GetCellFormula("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	value, err := ods.GetCellValue("Sheet 2", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, "100", value)
+	visible, err := ods.GetSheetVisible("Sheet 2")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	mergeCells, err := ods.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 2)
+	sort.Slice(mergeCells, func(i, j int) bool { return mergeCells[i].GetStartAxis() < mergeCells[j].GetStartAxis() })
+	assert.Equal(t, []string{"A4", "B5"}, []string{mergeCells[0].GetStartAxis(), mergeCells[0].GetEndAxis()})
+	assert.Equal(t, []string{"F1", "G2"}, []string{mergeCells[1].GetStartAxis(), mergeCells[1].GetEndAxis()})
+	width, err := ods.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	width, err = ods.GetColWidth("Sheet1", "D")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultColWidth, width)
+	height, err := ods.GetRowHeight("Sheet1", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	rowVisible, err := ods.GetRowVisible("Sheet1", 8)
+	assert.NoError(t, err)
+	assert.False(t, rowVisible)
+	styleID, err := ods.GetCellStyle("Sheet1", "D2")
+	assert.NoError(t, err)
+	s, err := ods.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, &Font{Bold: true, Italic: true, Family: "Arial", Size: 14, Color: "FF0000", Underline: "double", Strike: true}, s.Font)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"FFFF00"}}, s.Fill)
+	assert.ElementsMatch(t, []Border{{Type: "left", Color: "0000FF", Style: 2}, {Type: "bottom", Color: "000000", Style: 6}}, s.Border)
+	assert.Equal(t, &Alignment{Horizontal: "center", Vertical: "top", WrapText: true, TextRotation: 45}, s.Alignment)
+	assert.Equal(t, numFmt, *s.CustomNumFmt)
+	assert.NoError(t, ods.Close())
+
+	// Test save as ODS with invalid path
+	assert.Error(t, f.SaveAsODS(filepath.Join("test", "Book1.ods", "Book1.ods")))
+	assert.Equal(t, ErrMaxFilePathLength, f.SaveAsODS(strings.Repeat("c", MaxFilePathLength+1)))
+	// Test write ODS with unsupported charset worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.WriteODS(new(bytes.Buffer)), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestOpenODSReader(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0" xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0" xmlns:calcext="urn:org:documentfoundation:names:experimental:calc:xmlns:calcext:1.0" office:version="1.2">
+<office:automatic-styles>
+<number:number-style style:name="N2P0" style:volatile="true"><number:number number:decimal-places="2" number:min-integer-digits="1" number:grouping="true"/></number:number-style>
+<number:number-style style:name="N2"><style:text-properties fo:color="#ff0000"/><number:text>-</number:text><number:number number:decimal-places="2" number:min-integer-digits="1" number:grouping="true"/><style:map style:condition="value()&gt;=0" style:apply-style-name="N2P0"/></number:number-style>
+<number:date-style style:name="N3"><number:year number:style="long"/><number:text>/</number:text><number:month number:textual="true"/><number:text>/</number:text><number:day number:style="long"/></number:date-style>
+<number:time-style style:name="N4" number:truncate-on-overflow="false"><number:hours/><number:text>:</number:text><number:minutes number:style="long"/></number:time-style>
+<style:style style:name="co1" style:family="table-column"><style:table-column-properties style:column-width="2.258cm"/></style:style>
+<style:style style:name="ro1" style:family="table-row"><style:table-row-properties style:row-height="0.6cm" style:use-optimal-row-height="false"/></style:style>
+<style:style style:name="ro2" style:family="table-row"><style:table-row-properties style:row-height="0.452cm" style:use-optimal-row-height="true"/></style:style>
+<style:style style:name="ce1" style:family="table-cell" style:parent-style-name="Accent" style:data-style-name="N2"><style:table-cell-properties fo:border="0.06pt solid #000000" fo:border-top="2.49pt solid #00ff00" style:rotation-angle="315" style:vertical-align="middle"/><style:paragraph-properties fo:text-align="end"/></style:style>
+<style:style style:name="ce2" style:family="table-cell" style:data-style-name="N3"/>
+<style:style style:name="ce3" style:family="table-cell" style:data-style-name="N4"><style:table-cell-properties fo:background-color="transparent" fo:wrap-option="wrap" fo:border-left="0.74pt dashed #0000ff"/></style:style>
+<style:style style:name="ta1" style:family="table"><style:table-properties table:display="true"/></style:style>
+<style:style style:name="ta2" style:family="table"><style:table-properties table:display="false"/></style:style>
+</office:automatic-styles>
+<office:body><office:spreadsheet>
+<table:table table:name="Data" table:style-name="ta1">
+<table:table-column table:style-name="co1" table:number-columns-repeated="2" table:default-cell-style-name="Default"/>
+<table:table-column table:visibility="collapse"/>
+<table:table-column table:number-columns-repeated="16381"/>
+<table:table-header-rows><table:table-row table:style-name="ro1">
+<table:table-cell office:value-type="string" calcext:value-type="string"><text:p>Name<text:s text:c="2"/>X<text:tab/>Y</text:p><office:annotation><text:p>Note</text:p></office:annotation></table:table-cell>
+<table:table-cell table:style-name="ce1" office:value-type="float" office:value="-1234.5"><text:p>-1,234.50</text:p></table:table-cell>
+<table:table-cell table:number-columns-spanned="2" table:number-rows-spanned="2" office:value-type="string"><text:p>A<text:span>B</text:span></text:p><text:p>C</text:p></table:table-cell>
+<table:covered-table-cell/>
+</table:table-row></table:table-header-rows>
+<table:table-row table:style-name="ro2">
+<table:table-cell table:style-name="ce2" office:value-type="date" office:date-value="2024-01-02"/>
+<table:table-cell table:style-name="ce3" office:value-type="time" office:time-value="PT36H30M00S"/>
+<table:covered-table-cell table:number-columns-repeated="2"/>
+</table:table-row>
+<table:table-row table:visibility="collapse">
+<table:table-cell table:formula="of:=SUM([.B1:.B2];[$'It''s'.A1];[$Other.$A$1:.B3])" office:value-type="float" office:value="3"/>
+<table:table-cell table:formula="of:=IF([.A1]=&quot;a;b&quot;;1;{1;2|3;4})" office:value-type="string" office:string-value="x"/>
+<table:table-cell table:formula="of:=1/0" office:value-type="string" calcext:value-type="error"><text:p>#DIV/0!</text:p></table:table-cell>
+<table:table-cell table:formula="of:=[.A1]&lt;&gt;&quot;&quot;" office:value-type="boolean" office:boolean-value="true"/>
+<table:table-cell table:number-columns-repeated="16380"/>
+</table:table-row>
+<table:table-row table:number-rows-repeated="2"><table:table-cell office:value-type="percentage" office:value="0.5"/></table:table-row>
+<table:table-row table:number-rows-repeated="1048571"><table:table-cell table:number-columns-repeated="16384"/></table:table-row>
+</table:table>
+<table:table table:name="It's" table:style-name="ta2"><table:table-row><table:table-cell office:value-type="currency" office:currency="USD" office:value="2"/></table:table-row></table:table>
+</office:spreadsheet></office:body>
+</office:document-content>`
+	styles := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0">
+<office:styles>
+<style:style style:name="Default" style:family="table-cell"/>
+<style:style style:name="Accent" style:family="table-cell" style:parent-style-name="Default"><style:table-cell-properties fo:background-color="#cccccc"/><style:text-properties style:font-name="Liberation Sans" fo:font-size="10pt" fo:font-weight="700" style:text-underline-style="solid" fo:font-style="italic"/></style:style>
+</office:styles>
+</office:document-styles>`
+	f, err := OpenODSReader(bytes.NewReader(newODSPackage(t, map[string]string{
+		"mimetype": ContentTypeOpenDocumentSpreadsheet, "content.xml": content, "styles.xml": styles,
+	})))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Data", "It's"}, f.GetSheetList())
+	rows, err := f.GetRows("Data", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Name  X\tY", "-1234.5", "AB\nC"},
+		{"45293", "1.5208333333333333"},
+		{"3", "x", "#DIV/0!", "1"},
+		{"0.5"},
+		{"0.5"},
+	}, rows)
+	for cell, expected := range map[string]string{"B1": "-1,234.50", "A2": "2024/Jan/02", "B2": "36:30", "A4": "50.00%"} {
+		value, err := f.GetCellValue("Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, cell)
+	}
+	for cell, expected := range map[string]string{
+		"A3": "SUM(B1:B2,'It''s'!A1,Other!$A$1:B3)", "B3": "IF(A1=\"a;b\",1,{1,2;3,4})",
+		"C3": "1/0", "D3": "A1<>\"\"",
+	} {
+		formula, err := f.GetCellFormula("Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, formula, cell)
+	}
+	for cell, expected := range map[string]CellType{"C3": CellTypeError, "D3": CellTypeBool, "B3": CellTypeFormula} {
+		cellType, err := f.GetCellType("Data", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cellType, cell)
+	}
+	mergeCells, err := f.GetMergeCells("Data")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, []string{"C1", "D2"}, []string{mergeCells[0].GetStartAxis(), mergeCells[0].GetEndAxis()})
+	width, err := f.GetColWidth("Data", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 11.47739, width)
+	visible, err := f.GetColVisible("Data", "C")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	height, err := f.GetRowHeight("Data", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 17.01, height)
+	height, err = f.GetRowHeight("Data", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRowHeight, height)
+	rowVisible, err := f.GetRowVisible("Data", 3)
+	assert.NoError(t, err)
+	assert.False(t, rowVisible)
+	styleID, err := f.GetCellStyle("Data", "B1")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, &Font{Bold: true, Italic: true, Family: "Liberation Sans", Size: 10, Underline: "single"}, style.Font)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"CCCCCC"}}, style.Fill)
+	assert.ElementsMatch(t, []Border{
+		{Type: "left", Color: "000000", Style: 1}, {Type: "right", Color: "000000", Style: 1},
+		{Type: "top", Color: "00FF00", Style: 2}, {Type: "bottom", Color: "000000", Style: 1},
+	}, style.Border)
+	assert.Equal(t, &Alignment{Horizontal: "right", Vertical: "center", TextRotation: 135}, style.Alignment)
+	assert.Equal(t, "#,##0.00;-#,##0.00", *style.CustomNumFmt)
+	styleID, err = f.GetCellStyle("Data", "B2")
+	assert.NoError(t, err)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Zero(t, style.Fill.Pattern)
+	assert.Equal(t, []Border{{Type: "left", Color: "0000FF", Style: 3}}, style.Border)
+	styleID, err = f.GetCellStyle("It's", "A1")
+	assert.NoError(t, err)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, style.NumFmt)
+	visible, err = f.GetSheetVisible("It's")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	assert.NoError(t, f.Close())
+
+	// Test open ODS with invalid package
+	for _, pkg := range [][]byte{
+		[]byte("ODS"),
+		newODSPackage(t, map[string]string{"mimetype": "application/zip", "content.xml": content}),
+		newODSPackage(t, map[string]string{"styles.xml": styles}),
+		newODSPackage(t, map[string]string{"content.xml": "<office:document-content/>"}),
+	} {
+		_, err = OpenODSReader(bytes.NewReader(pkg))
+		assert.Equal(t, ErrWorkbookFileFormat, err)
+	}
+	for parts, expected := range map[[2]string]string{
+		{"<a>", styles}:  "XML syntax error on line 1: unexpected EOF",
+		{content, "<a>"}: "XML syntax error on line 1: unexpected EOF",
+		{strings.Replace(content, `office:value="-1234.5"`, `office:value="x"`, 1), styles}:                             "strconv.ParseFloat: parsing \"x\": invalid syntax",
+		{strings.Replace(content, "2024-01-02", "x", 1), styles}:                                                        "parsing time \"x\" as \"2006-01-02\": cannot parse \"x\" as \"2006\"",
+		{strings.Replace(content, "PT36H30M00S", "x", 1), styles}:                                                       "invalid OpenDocument duration value \"x\"",
+		{strings.Replace(content, `table:name="Data"`, `table:name="Data:"`, 1), styles}:                                ErrSheetNameInvalid.Error(),
+		{strings.Replace(content, `table:number-rows-repeated="2"`, `table:number-rows-repeated="1048574"`, 1), styles}: ErrMaxRows.Error(),
+	} {
+		_, err = OpenODSReader(bytes.NewReader(newODSPackage(t, map[string]string{"content.xml": parts[0], "styles.xml": parts[1]})))
+		assert.EqualError(t, err, expected)
+	}
+	_, err = OpenODSReader(bytes.NewReader(newODSPackage(t, map[string]string{"content.xml": content})), Options{UnzipSizeLimit: 10})
+	assert.EqualError(t, err, newUnzipSizeLimitError(10).Error())
+	_, err = OpenODSReader(bytes.NewReader(nil), Options{UnzipSizeLimit: 1, UnzipXMLSizeLimit: 2})
+	assert.Equal(t, ErrOptionsUnzipSizeLimit, err)
+	_, err = OpenODS(filepath.Join("test", "NotExist.ods"))
+	assert.Error(t, err)
+	_, err = OpenODS(filepath.Join("test", "Book1.xlsx"))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+}
+
+func TestODSFormulaConversion(t *testing.T) {
+	for formula, expected := range map[string]string{
+		"of:=SUM([.A1:.B2];[$Sheet2.C3])":        "SUM(A1:B2,Sheet2!C3)",
+		"of:=[$'Sheet 1'.$A$1]&\"[.A1];\"":       "'Sheet 1'!$A$1&\"[.A1];\"",
+		"of:=SUM([$Sheet1.A1:$Sheet3.A1])":       "SUM(Sheet1:Sheet3!A1:A1)",
+		"of:=[.A:.A]+[.1:.2]":                    "A:A+1:2",
+		"of:=[#REF!]":                            "#REF!",
+		"of:=[$Sheet1.#REF!]":                    "#REF!",
+		"oooc:=[.A1]":                            "A1",
+		"msoxl:=SUM(A1,B1)":                      "SUM(A1,B1)",
+		"=MAX({1;2|3;4})":                        "MAX({1,2;3,4})",
+		"of:=[$'It''s'.A1:.B2]":                  "'It''s'!A1:B2",
+		"of:=\"unterminated":                     "\"unterminated",
+		"of:=[.A1":                               "A1",
+		"of:=XLOOKUP(1;[.A:.A];[.A:.A])":         "_xlfn.XLOOKUP(1,A:A,A:A)",
+		"of:=COM.MICROSOFT.CONCAT(\"a\";Name_1)": "_xlfn.CONCAT(\"a\",Name_1)",
+		"of:=SORT(FILTER([.A1:.A3];1))":          "_xlfn._xlws.SORT(_xlfn._xlws.FILTER(A1:A3,1))",
+		"of:=sum([.A1])+ceiling.math(1.5)":       "sum(A1)+_xlfn.ceiling.math(1.5)",
+	} {
+		assert.Equal(t, expected, odsFormulaToExcel(formula), formula)
+	}
+	for formula, expected := range map[string]string{
+		"=SUM(A1:B2,Sheet2!C3)":                         "of:=SUM([.A1:.B2];[$Sheet2.C3])",
+		"'Sheet 1'!$A$1&\"A1,\"":                        "of:=[$'Sheet 1'.$A$1]&\"A1,\"",
+		"SUM(Sheet1:Sheet3!A1:B1)":                      "of:=SUM([$Sheet1.A1:$Sheet3.B1])",
+		"SUM(A:A,1:2)+LOG10(A1)":                        "of:=SUM([.A:.A];[.1:.2])+LOG10([.A1])",
+		"MAX({1,2;3,4})+TRUE":                           "of:=MAX({1;2|3;4})+TRUE",
+		"'It''s'!A1+Name_1+XFE1+1.5":                    "of:=[$'It''s'.A1]+Name_1+XFE1+1.5",
+		"A1:B2:C3+Sheet1:Sheet2:Sheet3!A1":              "of:=A1:B2:C3+Sheet1:Sheet2:Sheet3!A1",
+		"_xlfn.XLOOKUP(1,A:A,A:A)":                      "of:=XLOOKUP(1;[.A:.A];[.A:.A])",
+		"_xlfn._xlws.SORT(_xlfn._xlws.FILTER(A1:A3,1))": "of:=SORT(FILTER([.A1:.A3];1))",
+		"_XLFN.CONCAT(\"_xlfn.\",A1)":                   "of:=CONCAT(\"_xlfn.\";[.A1])",
+	} {
+		assert.Equal(t, expected, excelFormulaToODS(formula), formula)
+	}
+}
+
+func TestODSDataStyles(t *testing.T) {
+	for code, expected := range map[string]string{
+		"0.00%":                       `<number:percentage-style style:name="N1"><number:number number:decimal-places="2" number:min-integer-digits="1"></number:number><number:text>%</number:text></number:percentage-style>`,
+		"@":                           `<number:text-style style:name="N1"><number:text-content></number:text-content></number:text-style>`,
+		"[h]:mm:ss":                   `<number:time-style style:name="N1" number:truncate-on-overflow="false"><number:hours></number:hours><number:text>:</number:text><number:minutes number:style="long"></number:minutes><number:text>:</number:text><number:seconds number:style="long"></number:seconds></number:time-style>`,
+		"dddd, mmm d yyyy h:mm AM/PM": `<number:date-style style:name="N1"><number:day-of-week number:style="long"></number:day-of-week><number:text>, </number:text><number:month number:textual="true"></number:month><number:text> </number:text><number:day></number:day><number:text> </number:text><number:year number:style="long"></number:year><number:text> </number:text><number:hours></number:hours><number:text>:</number:text><number:minutes number:style="long"></number:minutes><number:text> </number:text><number:am-pm></number:am-pm></number:date-style>`,
+		"General":                     "",
+		"\"Text\"":                    "",
+	} {
+		w := &odsWriter{dataStyles: make(map[string]string), valueTypes: make(map[string]string)}
+		w.dataStyleName(code)
+		var buf bytes.Buffer
+		for _, dataStyle := range w.content.AutomaticStyles.DataStyles {
+			output, err := xml.Marshal(dataStyle)
+			assert.NoError(t, err)
+			buf.Write(output)
+		}
+		assert.Equal(t, expected, buf.String(), code)
+	}
+	styles := &odsStyles{dataStyles: map[string]*decodeODSDataStyle{}}
+	var doc decodeODSDocument
+	assert.NoError(t, xml.Unmarshal([]byte(`<document-styles xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"><styles>
+<number:number-style style:name="N1"><number:scientific-number number:decimal-places="2" number:min-integer-digits="1" number:min-exponent-digits="3"/></number:number-style>
+<number:number-style style:name="N2"><number:number number:decimal-places="0" number:min-integer-digits="7" number:grouping="true"/><number:text>"x"</number:text></number:number-style>
+<number:currency-style style:name="N3"><number:currency-symbol>€</number:currency-symbol><number:number number:decimal-places="2" number:min-integer-digits="1"/></number:currency-style>
+<number:time-style style:name="N4"><number:hours number:style="long"/><number:text>:</number:text><number:minutes/><number:text>:</number:text><number:seconds number:style="long" number:decimal-places="2"/></number:time-style>
+<number:date-style style:name="N5"><number:day-of-week/><number:text> </number:text><number:month number:style="long" number:textual="true"/><number:year/></number:date-style>
+<number:number-style style:name="N6"><number:fraction/></number:number-style>
+<number:boolean-style style:name="N7"><number:boolean/></number:boolean-style>
+<number:number-style style:name="N8"><number:number/></number:number-style>
+</styles></document-styles>`), &doc))
+	styles.add(&doc.Styles)
+	for name, expected := range map[string]string{
+		"N1": "0.00E+000", "N2": "0,000,000\\\"\"x\"\\\"", "N3": "[$€]0.00", "N4": "hh:m:ss.00",
+		"N5": "ddd mmmmyy", "N6": "# ?/?", "N7": "\"TRUE\";\"TRUE\";\"FALSE\"", "N8": "General", "N9": "",
+	} {
+		assert.Equal(t, expected, styles.numFmtCode(name, 0), name)
+	}
+	assert.Equal(t, 4.5, odsColWidthToPoints(0.5))
+	assert.Equal(t, 0.5, odsPointsToColWidth(odsColWidthToPoints(0.5)))
+	_, ok := parseODSLength("1.5xx")
+	assert.False(t, ok)
+	_, ok = parseODSLength("abpt")
+	assert.False(t, ok)
+	duration, err := parseODSDuration("-P1DT12H")
+	assert.NoError(t, err)
+	assert.Equal(t, -1.5, duration)
+	assert.Equal(t, "#000000", odsColor("red"))
+	assert.Equal(t, "#ff0000", odsColor("FFFF0000"))
+}
@@ -0,0 +1,1448 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf16"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// PDFOptions directly maps the settings of exporting the worksheet in the PDF
+// format.
+//
+// RawCellValue specifies if export the raw cell values without applying the
+// number formats.
+//
+// Fonts specifies the TrueType or OpenType font files data by the font family
+// names, such as "Calibri", the font files will be embedded in the PDF
+// document. The bundled Go fonts will be embedded as the fallback font for the
+// font families which not been specified.
+type PDFOptions struct {
+	RawCellValue bool
+	Fonts        map[string][]byte
+}
+
+// pdfFont directly maps the font to be embedded in the PDF document, the
+// metrics and the widths of the glyphs are in font units.
+type pdfFont struct {
+	name            string
+	data            []byte
+	sfnt            *sfnt.Font
+	upem            float64
+	ascent, descent float64
+	glyphs          map[rune]sfnt.GlyphIndex
+	widths          map[sfnt.GlyphIndex]float64
+	used            map[sfnt.GlyphIndex]rune
+}
+
+// pdfImage directly maps the image XObject to be embedded in the PDF
+// document, the alpha channel is nil if the image is opaque.
+type pdfImage struct {
+	name          string
+	width, height int
+	pix, alpha    []byte
+}
+
+// pdfTextRun directly maps the text run with the same font in the PDF
+// document, the size, rise and width of the run are in points.
+type pdfTextRun struct {
+	text              string
+	font              *pdfFont
+	size              float64
+	color             string
+	underline, strike bool
+	rise              float64
+	width             float64
+}
+
+// pdfTextLine directly maps the line of the text runs in the PDF document,
+// the width, ascent and descent of the line are in points.
+type pdfTextLine struct {
+	runs                   []pdfTextRun
+	width, ascent, descent float64
+}
+
+// pdfSegment directly maps the continuous columns or rows placed on the page,
+// the from and to are the indexes of the first and after the last column or
+// row in the render range, and the offset is the position of the first column
+// or row on the page in points.
+type pdfSegment struct {
+	from, to int
+	offset   float64
+}
+
+// pdfPicture directly maps the picture placed over the cells in the print
+// area, the position and size of the picture are in points.
+type pdfPicture struct {
+	key        string
+	file       []byte
+	x, y, w, h float64
+}
+
+// pdfArea directly maps the print area to be exported, the positions of the
+// columns and rows are in points, the covered cells of the merged cells are
+// mapped to the indexes of the first cell of the merged cells.
+type pdfArea struct {
+	rng            *renderRange
+	colPos, rowPos []float64
+	covered        map[[2]int][2]int
+	pictures       []pdfPicture
+	scale          float64
+}
+
+// pdfPage directly maps the page of the PDF document, a page without area is
+// a blank page.
+type pdfPage struct {
+	area       *pdfArea
+	rows, cols []pdfSegment
+}
+
+// pdfExporter directly maps the state of exporting the worksheet in the PDF
+// format, the width and height of the paper are in points.
+type pdfExporter struct {
+	f                    *File
+	sheet                string
+	opts                 *PDFOptions
+	layout               PageLayoutOptions
+	margins              PageLayoutMarginsOptions
+	headerFooter         *HeaderFooterOptions
+	width, height        float64
+	fitToPage            bool
+	gridLines            bool
+	overThenDown         bool
+	rowBreaks, colBreaks []int
+	now                  time.Time
+	buf                  sfnt.Buffer
+	fonts                map[string]*pdfFont
+	fallbackFonts        [4]*pdfFont
+	usedFonts            []*pdfFont
+	images               map[string]*pdfImage
+	usedImages           []*pdfImage
+	imageCells           map[string]bool
+	pages                []*pdfPage
+}
+
+// pdfPointsPerMM defined the number of points per millimeter.
+const pdfPointsPerMM = 72 / 25.4
+
+// pdfPaperSizes defined the width and height of the papers in points by the
+// paper size index of the page layout.
+var pdfPaperSizes = map[int][2]float64{
+	1: {612, 792}, 2: {612, 792}, 3: {792, 1224}, 4: {1224, 792},
+	5: {612, 1008}, 6: {396, 612}, 7: {522, 756},
+	8:  {297 * pdfPointsPerMM, 420 * pdfPointsPerMM},
+	9:  {210 * pdfPointsPerMM, 297 * pdfPointsPerMM},
+	10: {210 * pdfPointsPerMM, 297 * pdfPointsPerMM},
+	11: {148 * pdfPointsPerMM, 210 * pdfPointsPerMM},
+	12: {250 * pdfPointsPerMM, 353 * pdfPointsPerMM},
+	13: {176 * pdfPointsPerMM, 250 * pdfPointsPerMM},
+	14: {612, 936},
+	15: {215 * pdfPointsPerMM, 275 * pdfPointsPerMM},
+	16: {720, 1008}, 17: {792, 1224}, 18: {612, 792}, 19: {279, 639},
+	20: {297, 684}, 21: {324, 747}, 22: {342, 792}, 23: {360, 828},
+	24: {1224, 1584}, 25: {1584, 2448}, 26: {2448, 3168},
+	27: {110 * pdfPointsPerMM, 220 * pdfPointsPerMM},
+	28: {162 * pdfPointsPerMM, 229 * pdfPointsPerMM},
+	29: {324 * pdfPointsPerMM, 458 * pdfPointsPerMM},
+	30: {229 * pdfPointsPerMM, 324 * pdfPointsPerMM},
+	31: {114 * pdfPointsPerMM, 162 * pdfPointsPerMM},
+	32: {114 * pdfPointsPerMM, 229 * pdfPointsPerMM},
+	33: {250 * pdfPointsPerMM, 353 * pdfPointsPerMM},
+	34: {176 * pdfPointsPerMM, 250 * pdfPointsPerMM},
+	35: {176 * pdfPointsPerMM, 125 * pdfPointsPerMM},
+	36: {110 * pdfPointsPerMM, 230 * pdfPointsPerMM},
+	37: {279, 540}, 38: {261, 468}, 39: {1071, 792}, 40: {612, 864},
+	41: {612, 936},
+	42: {250 * pdfPointsPerMM, 353 * pdfPointsPerMM},
+	43: {100 * pdfPointsPerMM, 148 * pdfPointsPerMM},
+	44: {648, 792}, 45: {720, 792}, 46: {1080, 792},
+	47: {220 * pdfPointsPerMM, 220 * pdfPointsPerMM},
+	50: {667.8, 864}, 51: {667.8, 1080}, 52: {841.68, 1296},
+	53: {236 * pdfPointsPerMM, 322 * pdfPointsPerMM},
+	54: {595.8, 792},
+	55: {210 * pdfPointsPerMM, 297 * pdfPointsPerMM},
+	56: {667.8, 864},
+	57: {227 * pdfPointsPerMM, 356 * pdfPointsPerMM},
+	58: {305 * pdfPointsPerMM, 487 * pdfPointsPerMM},
+	59: {612, 913.68},
+	60: {210 * pdfPointsPerMM, 330 * pdfPointsPerMM},
+	61: {148 * pdfPointsPerMM, 210 * pdfPointsPerMM},
+	62: {182 * pdfPointsPerMM, 257 * pdfPointsPerMM},
+	63: {322 * pdfPointsPerMM, 445 * pdfPointsPerMM},
+	64: {174 * pdfPointsPerMM, 235 * pdfPointsPerMM},
+	65: {201 * pdfPointsPerMM, 276 * pdfPointsPerMM},
+	66: {420 * pdfPointsPerMM, 594 * pdfPointsPerMM},
+	67: {297 * pdfPointsPerMM, 420 * pdfPointsPerMM},
+	68: {322 * pdfPointsPerMM, 445 * pdfPointsPerMM},
+	69: {200 * pdfPointsPerMM, 148 * pdfPointsPerMM},
+	70: {105 * pdfPointsPerMM, 148 * pdfPointsPerMM},
+	75: {792, 612},
+	76: {420 * pdfPointsPerMM, 297 * pdfPointsPerMM},
+	77: {297 * pdfPointsPerMM, 210 * pdfPointsPerMM},
+	78: {210 * pdfPointsPerMM, 148 * pdfPointsPerMM},
+	79: {364 * pdfPointsPerMM, 257 * pdfPointsPerMM},
+	80: {257 * pdfPointsPerMM, 182 * pdfPointsPerMM},
+}
+
+// ExportPDF provides a function to export the worksheet in the PDF format to
+// the writer by given worksheet name. The worksheet will be paginated by the
+// page layout settings, including the paper size, orientation, scaling, fit
+// to page, page order, margins, centering on the page and print grid lines,
+// the print area and the manual page breaks. The print titles rows and
+// columns will be repeated on each page, and the headers and footers will be
+// placed on the pages with the page numbers, date, time, file name and
+// worksheet name. The column widths, row heights, fills, borders, fonts,
+// alignments, merged cells and the pictures of the worksheet will be drawn,
+// and the hidden rows and columns will be omitted. The texts will be drawn by
+// the bundled Go fonts unless the font files have been specified by the Fonts
+// option, the used fonts will be embedded in the PDF document, so the
+// exporting could be run fully offline. For example, export Sheet1 into the
+// file invoice.pdf with the Calibri font:
+//
+//	file, err := os.Create("invoice.pdf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	calibri, err := os.ReadFile("calibri.ttf")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = f.ExportPDF("Sheet1", file, excelize.PDFOptions{
+//	    Fonts: map[string][]byte{"Calibri": calibri},
+//	})
+func (f *File) ExportPDF(sheet string, writer io.Writer, opts ...PDFOptions) error {
+	options := PDFOptions{}
+	if len(opts) > 0 {
+		options = opts[len(opts)-1]
+	}
+	e, err := f.newPDFExporter(sheet, &options)
+	if err != nil {
+		return err
+	}
+	areas, titles, err := e.printRanges()
+	if err != nil {
+		return err
+	}
+	for _, area := range areas {
+		if err = e.paginate(area, titles); err != nil {
+			return err
+		}
+	}
+	return e.write(writer)
+}
+
+// newPDFExporter provides a function to prepare the page layout settings for
+// exporting the worksheet in the PDF format by given worksheet name.
+func (f *File) newPDFExporter(sheet string, opts *PDFOptions) (*pdfExporter, error) {
+	e := &pdfExporter{
+		f: f, sheet: sheet, opts: opts, now: time.Now(),
+		fonts: make(map[string]*pdfFont), images: make(map[string]*pdfImage),
+	}
+	var err error
+	if e.layout, err = f.GetPageLayout(sheet); err != nil {
+		return nil, err
+	}
+	if e.margins, err = f.GetPageMargins(sheet); err != nil {
+		return nil, err
+	}
+	if e.headerFooter, err = f.GetHeaderFooter(sheet); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if ws.SheetPr != nil && ws.SheetPr.PageSetUpPr != nil {
+		e.fitToPage = ws.SheetPr.PageSetUpPr.FitToPage
+	}
+	if ws.PrintOptions != nil {
+		e.gridLines = ws.PrintOptions.GridLines
+	}
+	if ws.PageSetUp != nil {
+		e.overThenDown = ws.PageSetUp.PageOrder == "overThenDown"
+	}
+	if ws.RowBreaks != nil {
+		for _, brk := range ws.RowBreaks.Brk {
+			if brk != nil {
+				e.rowBreaks = append(e.rowBreaks, brk.ID+1)
+			}
+		}
+	}
+	if ws.ColBreaks != nil {
+		for _, brk := range ws.ColBreaks.Brk {
+			if brk != nil {
+				e.colBreaks = append(e.colBreaks, brk.ID+1)
+			}
+		}
+	}
+	size, ok := pdfPaperSizes[*e.layout.Size]
+	if !ok {
+		size = pdfPaperSizes[1]
+	}
+	if e.width, e.height = size[0], size[1]; *e.layout.Orientation == "landscape" {
+		e.width, e.height = e.height, e.width
+	}
+	imageCells, err := f.getImageCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	e.imageCells = make(map[string]bool, len(imageCells))
+	for _, cell := range imageCells {
+		e.imageCells[cell] = true
+	}
+	return e, e.parseFonts()
+}
+
+// parseFonts provides a function to parse the bundled fallback fonts and the
+// font files which specified by the Fonts option.
+func (e *pdfExporter) parseFonts() error {
+	if err := parseFallbackFonts(); err != nil {
+		return err
+	}
+	for i, fnt := range imageFallbackFonts {
+		e.fallbackFonts[i] = e.newFont(imageFallbackFontFiles[i], fnt)
+	}
+	for family, data := range e.opts.Fonts {
+		fnt, err := sfnt.Parse(data)
+		if err != nil {
+			return err
+		}
+		e.fonts[strings.ToLower(family)] = e.newFont(data, fnt)
+	}
+	return nil
+}
+
+// newFont provides a function to create the font to be embedded by given font
+// file data and the parsed font.
+func (e *pdfExporter) newFont(data []byte, fnt *sfnt.Font) *pdfFont {
+	pf := &pdfFont{
+		data: data, sfnt: fnt, upem: float64(fnt.UnitsPerEm()),
+		glyphs: make(map[rune]sfnt.GlyphIndex), widths: make(map[sfnt.GlyphIndex]float64),
+		used: make(map[sfnt.GlyphIndex]rune),
+	}
+	if metrics, err := fnt.Metrics(&e.buf, pf.ppem(), font.HintingNone); err == nil {
+		pf.ascent, pf.descent = float64(metrics.Ascent)/64, float64(metrics.Descent)/64
+	}
+	return pf
+}
+
+// ppem returns the number of pixels per em which equals to the units per em
+// of the font, to get the metrics of the font in font units.
+func (pf *pdfFont) ppem() fixed.Int26_6 {
+	return fixed.I(int(pf.upem))
+}
+
+// ascentAt returns the ascent of the font in points by given font size.
+func (pf *pdfFont) ascentAt(size float64) float64 {
+	return pf.ascent / pf.upem * size
+}
+
+// descentAt returns the descent of the font in points by given font size.
+func (pf *pdfFont) descentAt(size float64) float64 {
+	return pf.descent / pf.upem * size
+}
+
+// printRanges provides a function to get the coordinates of the print areas
+// and the print titles of the worksheet, the used range of the worksheet will
+// be exported if the print area has not been set. The print titles
+// coordinates are the first column, first row, last column and last row of
+// the title columns and rows, which will be 0 if not been set.
+func (e *pdfExporter) printRanges() ([][]int, []int, error) {
+	var areas [][]int
+	titles := make([]int, 4)
+	f := e.f
+	f.mu.Lock()
+	ws, err := f.workSheetReader(e.sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return areas, titles, err
+	}
+	used, err := ws.usedRange()
+	if err != nil {
+		return areas, titles, err
+	}
+	wb, err := f.workbookReader()
+	if err != nil {
+		return areas, titles, err
+	}
+	var definedNames []xlsxDefinedName
+	if wb.DefinedNames != nil {
+		definedNames = wb.DefinedNames.DefinedName
+	}
+	sheetIndex, _ := f.GetSheetIndex(e.sheet)
+	for _, dn := range definedNames {
+		if dn.LocalSheetID == nil || *dn.LocalSheetID != sheetIndex {
+			continue
+		}
+		for _, ref := range splitPrintRefs(dn.Data) {
+			coordinates, err := parsePrintRef(ref)
+			if err != nil {
+				return areas, titles, err
+			}
+			switch dn.Name {
+			case builtInDefinedNames[0]:
+				if coordinates[0] == 0 {
+					coordinates[0], coordinates[2] = 1, used[2]
+				}
+				if coordinates[1] == 0 {
+					coordinates[1], coordinates[3] = 1, used[3]
+				}
+				areas = append(areas, coordinates)
+			case builtInDefinedNames[1]:
+				if coordinates[0] == 0 {
+					titles[1], titles[3] = coordinates[1], coordinates[3]
+				}
+				if coordinates[1] == 0 {
+					titles[0], titles[2] = coordinates[0], coordinates[2]
+				}
+			}
+		}
+	}
+	if len(areas) == 0 {
+		areas = append(areas, used)
+	}
+	return areas, titles, err
+}
+
+// splitPrintRefs provides a function to split the references of the defined
+// name by the commas which not in the quoted worksheet names.
+func splitPrintRefs(refersTo string) []string {
+	var refs []string
+	var quoted bool
+	start := 0
+	for i, r := range refersTo {
+		if r == '\'' {
+			quoted = !quoted
+		}
+		if r == ',' && !quoted {
+			refs = append(refs, refersTo[start:i])
+			start = i + 1
+		}
+	}
+	return append(refs, refersTo[start:])
+}
+
+// parsePrintRef provides a function to get the coordinates of the reference
+// in the print area or print titles defined name, such as Sheet1!$A$1:$D$20,
+// Sheet1!$1:$2 or Sheet1!$A:$B. The columns or rows of the coordinates will
+// be 0 if the reference is entire rows or columns.
+func parsePrintRef(ref string) ([]int, error) {
+	if idx := strings.LastIndex(ref, "!"); idx != -1 {
+		ref = ref[idx+1:]
+	}
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(ref), "$", ""), ":")
+	if len(parts) == 1 {
+		parts = append(parts, parts[0])
+	}
+	coordinates := make([]int, 4)
+	for i, part := range parts[:2] {
+		if row, err := strconv.Atoi(part); err == nil {
+			coordinates[i*2+1] = row
+			continue
+		}
+		if col, err := ColumnNameToNumber(part); err == nil {
+			coordinates[i*2] = col
+			continue
+		}
+		col, row, err := CellNameToCoordinates(part)
+		if err != nil {
+			return coordinates, err
+		}
+		coordinates[i*2], coordinates[i*2+1] = col, row
+	}
+	_ = sortCoordinates(coordinates)
+	return coordinates, nil
+}
+
+// bodySize returns the width and height of the page body in points, which
+// excluded the page margins.
+func (e *pdfExporter) bodySize() (float64, float64) {
+	return e.width - (*e.margins.Left+*e.margins.Right)*72, e.height - (*e.margins.Top+*e.margins.Bottom)*72
+}
+
+// pdfPositions returns the positions of the columns or rows in points by
+// given sizes of the columns or rows, the last item is the end position.
+func pdfPositions(sizes []float64, toPoints func(float64) float64) []float64 {
+	positions := make([]float64, len(sizes)+1)
+	for i, size := range sizes {
+		positions[i+1] = positions[i] + toPoints(size)
+	}
+	return positions
+}
+
+// pdfBreaks returns the indexes of the columns or rows in the render range
+// which start new pages by given column or row numbers in the range and the
+// column or row numbers of the manual page breaks.
+func pdfBreaks(numbers, breaks []int) map[int]bool {
+	indexes := make(map[int]bool, len(breaks))
+	for _, brk := range breaks {
+		indexes[sort.SearchInts(numbers, brk)] = true
+	}
+	return indexes
+}
+
+// pdfPaginate provides a function to split the columns or rows into pages by
+// given positions of the columns or rows, the indexes of the first and last
+// column or row of the print area, the indexes range of the print titles, the
+// indexes which start new pages and the available length of the page body.
+// The print titles will be placed at the start of the pages which start after
+// the print titles.
+func pdfPaginate(positions []float64, first, last int, titles [2]int, breaks map[int]bool, length float64) [][]pdfSegment {
+	var pages [][]pdfSegment
+	for start := first; start <= last; {
+		var segments []pdfSegment
+		var used float64
+		if titles[1] > titles[0] && start >= titles[1] {
+			used = positions[titles[1]] - positions[titles[0]]
+			segments = append(segments, pdfSegment{from: titles[0], to: titles[1]})
+		}
+		end := start + 1
+		for end <= last && !breaks[end] && used+positions[end+1]-positions[start] <= length+1e-6 {
+			end++
+		}
+		pages = append(pages, append(segments, pdfSegment{from: start, to: end, offset: used}))
+		start = end
+	}
+	return pages
+}
+
+// paginate provides a function to split the print area into pages by given
+// coordinates of the print area and the print titles. The manual page breaks
+// will be ignored if the worksheet has been set to fit to pages, and the
+// scale will be reduced until the pages fit to the number of pages.
+func (e *pdfExporter) paginate(coordinates, titles []int) error {
+	bounds := append([]int{}, coordinates...)
+	for i := 0; i < 2; i++ {
+		if titles[i] > 0 {
+			bounds[i], bounds[i+2] = int(math.Min(float64(bounds[i]), float64(titles[i]))), int(math.Max(float64(bounds[i+2]), float64(titles[i+2])))
+		}
+	}
+	ref, err := coordinatesToRangeRef(bounds)
+	if err != nil {
+		return err
+	}
+	rng, err := e.f.newRenderRange(e.sheet, ref, e.opts.RawCellValue)
+	if err != nil {
+		return err
+	}
+	area := &pdfArea{
+		rng: rng, covered: make(map[[2]int][2]int), scale: float64(*e.layout.AdjustTo) / 100,
+		colPos: pdfPositions(rng.colWidths, func(width float64) float64 { return colWidthToPixels(width) * 0.75 }),
+		rowPos: pdfPositions(rng.rowHeights, func(height float64) float64 { return height }),
+	}
+	for i, cells := range rng.cells {
+		for j, cell := range cells {
+			for k := 0; cell != nil && k < cell.rowSpan*cell.colSpan; k++ {
+				if k > 0 {
+					area.covered[[2]int{i + k/cell.colSpan, j + k%cell.colSpan}] = [2]int{i, j}
+				}
+			}
+		}
+	}
+	firstCol, lastCol := sort.SearchInts(rng.cols, coordinates[0]), sort.SearchInts(rng.cols, coordinates[2]+1)-1
+	firstRow, lastRow := sort.SearchInts(rng.rows, coordinates[1]), sort.SearchInts(rng.rows, coordinates[3]+1)-1
+	if firstCol > lastCol || firstRow > lastRow {
+		return err
+	}
+	var titleCols, titleRows [2]int
+	if titles[0] > 0 {
+		titleCols = [2]int{sort.SearchInts(rng.cols, titles[0]), sort.SearchInts(rng.cols, titles[2]+1)}
+	}
+	if titles[1] > 0 {
+		titleRows = [2]int{sort.SearchInts(rng.rows, titles[1]), sort.SearchInts(rng.rows, titles[3]+1)}
+	}
+	rowBreaks, colBreaks := pdfBreaks(rng.rows, e.rowBreaks), pdfBreaks(rng.cols, e.colBreaks)
+	bodyWidth, bodyHeight := e.bodySize()
+	fitToWidth, fitToHeight := 1, 1
+	if e.fitToPage {
+		if e.layout.FitToWidth != nil {
+			fitToWidth = *e.layout.FitToWidth
+		}
+		if e.layout.FitToHeight != nil {
+			fitToHeight = *e.layout.FitToHeight
+		}
+		rowBreaks, colBreaks, area.scale = nil, nil, 1
+		if width := area.colPos[lastCol+1] - area.colPos[firstCol]; fitToWidth > 0 && width > 0 {
+			area.scale = math.Min(area.scale, bodyWidth*float64(fitToWidth)/width)
+		}
+		if height := area.rowPos[lastRow+1] - area.rowPos[firstRow]; fitToHeight > 0 && height > 0 {
+			area.scale = math.Min(area.scale, bodyHeight*float64(fitToHeight)/height)
+		}
+		area.scale = math.Max(area.scale, 0.1)
+	}
+	var rowPages, colPages [][]pdfSegment
+	for {
+		rowPages = pdfPaginate(area.rowPos, firstRow, lastRow, titleRows, rowBreaks, bodyHeight/area.scale)
+		colPages = pdfPaginate(area.colPos, firstCol, lastCol, titleCols, colBreaks, bodyWidth/area.scale)
+		if !e.fitToPage || area.scale <= 0.1 ||
+			((fitToWidth <= 0 || len(colPages) <= fitToWidth) && (fitToHeight <= 0 || len(rowPages) <= fitToHeight)) {
+			break
+		}
+		area.scale = math.Max(0.1, area.scale*0.95)
+	}
+	if area.pictures, err = e.pictures(area); err != nil {
+		return err
+	}
+	if e.overThenDown {
+		for _, rows := range rowPages {
+			for _, cols := range colPages {
+				e.pages = append(e.pages, &pdfPage{area: area, rows: rows, cols: cols})
+			}
+		}
+		return err
+	}
+	for _, cols := range colPages {
+		for _, rows := range rowPages {
+			e.pages = append(e.pages, &pdfPage{area: area, rows: rows, cols: cols})
+		}
+	}
+	return err
+}
+
+// pictures provides a function to get the pictures placed over the cells in
+// the print area, the positions of the pictures are relative to the first
+// cell of the render range.
+func (e *pdfExporter) pictures(area *pdfArea) ([]pdfPicture, error) {
+	var pictures []pdfPicture
+	anchors, err := e.f.getPictureAnchors(e.sheet)
+	if err != nil {
+		return pictures, err
+	}
+	cols, rows := area.rng.cols, area.rng.rows
+	position := func(numbers []int, positions []float64, number, offset int) float64 {
+		return positions[sort.SearchInts(numbers, number)] + float64(offset)/float64(EMU)*0.75
+	}
+	for idx, anchor := range anchors {
+		col, row := anchor.from.Col+1, anchor.from.Row+1
+		if col < cols[0] || col > cols[len(cols)-1] || row < rows[0] || row > rows[len(rows)-1] {
+			continue
+		}
+		picture := pdfPicture{
+			key: "picture" + strconv.Itoa(idx), file: anchor.file,
+			x: position(cols, area.colPos, col, anchor.from.ColOff),
+			y: position(rows, area.rowPos, row, anchor.from.RowOff),
+		}
+		if anchor.to == nil {
+			cfg, _, err := image.DecodeConfig(bytes.NewReader(anchor.file))
+			if err != nil {
+				continue
+			}
+			picture.w, picture.h = float64(cfg.Width)*0.75, float64(cfg.Height)*0.75
+			pictures = append(pictures, picture)
+			continue
+		}
+		picture.w = position(cols, area.colPos, anchor.to.Col+1, anchor.to.ColOff) - picture.x
+		picture.h = position(rows, area.rowPos, anchor.to.Row+1, anchor.to.RowOff) - picture.y
+		pictures = append(pictures, picture)
+	}
+	return pictures, err
+}
+
+// blackAndWhite returns if the worksheet should be printed in black and
+// white.
+func (e *pdfExporter) blackAndWhite() bool {
+	return e.layout.BlackAndWhite != nil && *e.layout.BlackAndWhite
+}
+
+// pdfNum returns the string of the number in the PDF content stream, which
+// rounded to 3 decimal places.
+func pdfNum(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
+
+// pdfColor returns the RGB color operands in the PDF content stream by given
+// hex color, returns false if the color is invalid.
+func pdfColor(hexColor string) (string, bool) {
+	c, ok := imageColor(hexColor)
+	if !ok {
+		return "", ok
+	}
+	return strings.Join([]string{pdfNum(float64(c.R) / 255), pdfNum(float64(c.G) / 255), pdfNum(float64(c.B) / 255)}, " "), ok
+}
+
+// pdfRect returns the rectangle operands in the PDF content stream by given
+// position and size of the rectangle from the top left corner, and the
+// vertical position of the origin in the PDF coordinate system.
+func pdfRect(x, y, w, h, originY float64) string {
+	return strings.Join([]string{pdfNum(x), pdfNum(originY - y - h), pdfNum(w), pdfNum(h)}, " ")
+}
+
+// font provides a function to get the font to be embedded and the font size
+// in points by given font settings, the fallback font will be used if the
+// font family has not been specified by the Fonts option.
+func (e *pdfExporter) font(fnt *Font) (*pdfFont, float64) {
+	size := fnt.Size
+	if size <= 0 {
+		size = 11
+	}
+	if fnt.VertAlign == "superscript" || fnt.VertAlign == "subscript" {
+		size = size * 2 / 3
+	}
+	if pf, ok := e.fonts[strings.ToLower(fnt.Family)]; ok {
+		return pf, size
+	}
+	idx := 0
+	if fnt.Bold {
+		idx++
+	}
+	if fnt.Italic {
+		idx += 2
+	}
+	return e.fallbackFonts[idx], size
+}
+
+// glyph provides a function to get the glyph index and the advance width of
+// the glyph in font units by given font and character.
+func (e *pdfExporter) glyph(pf *pdfFont, r rune) (sfnt.GlyphIndex, float64) {
+	idx, ok := pf.glyphs[r]
+	if !ok {
+		idx, _ = pf.sfnt.GlyphIndex(&e.buf, r)
+		pf.glyphs[r] = idx
+	}
+	width, ok := pf.widths[idx]
+	if !ok {
+		advance, _ := pf.sfnt.GlyphAdvance(&e.buf, idx, pf.ppem(), font.HintingNone)
+		width = float64(advance) / 64
+		pf.widths[idx] = width
+	}
+	return idx, width
+}
+
+// measure provides a function to get the width of the text in points by given
+// font and font size.
+func (e *pdfExporter) measure(pf *pdfFont, size float64, text string) float64 {
+	var width float64
+	for _, r := range text {
+		_, w := e.glyph(pf, r)
+		width += w
+	}
+	return width / pf.upem * size
+}
+
+// encode provides a function to encode the text as the hex string of the
+// glyph indexes by given font, the font will be added to the resources of the
+// document on the first use.
+func (e *pdfExporter) encode(pf *pdfFont, text string) string {
+	if pf.name == "" {
+		e.usedFonts = append(e.usedFonts, pf)
+		pf.name = "F" + strconv.Itoa(len(e.usedFonts))
+	}
+	var buf strings.Builder
+	for _, r := range text {
+		idx, _ := e.glyph(pf, r)
+		if _, ok := pf.used[idx]; !ok {
+			pf.used[idx] = r
+		}
+		fmt.Fprintf(&buf, "%04X", uint16(idx))
+	}
+	return buf.String()
+}
+
+// textRuns provides a function to get the text runs by given base font and
+// rich text runs, the fonts of the rich text runs will be inherited from the
+// base font. Returns the text runs, the base font and the base font size.
+func (e *pdfExporter) textRuns(base *Font, richText []RichTextRun) ([]pdfTextRun, *pdfFont, float64) {
+	baseFont, baseSize := e.font(base)
+	var runs []pdfTextRun
+	for _, rt := range richText {
+		fnt := inheritFont(base, rt.Font)
+		pf, size := e.font(&fnt)
+		run := pdfTextRun{text: rt.Text, font: pf, size: size, color: "0 0 0", strike: fnt.Strike}
+		if c, ok := pdfColor(e.f.fontColor(&fnt)); ok && !e.blackAndWhite() {
+			run.color = c
+		}
+		run.underline = fnt.Underline == "single" || fnt.Underline == "double" ||
+			fnt.Underline == "singleAccounting" || fnt.Underline == "doubleAccounting"
+		if fnt.VertAlign == "superscript" {
+			run.rise = baseFont.ascentAt(baseSize) / 3
+		}
+		if fnt.VertAlign == "subscript" {
+			run.rise = -baseFont.descentAt(baseSize)
+		}
+		runs = append(runs, run)
+	}
+	return runs, baseFont, baseSize
+}
+
+// wrapText provides a function to break the text runs into lines by the line
+// breaks, and wrap the lines by the maximum width in points if the wrap is
+// enabled.
+func (e *pdfExporter) wrapText(runs []pdfTextRun, baseFont *pdfFont, baseSize, maxWidth float64, wrap bool) []pdfTextLine {
+	lines := []pdfTextLine{{}}
+	appendRun := func(run pdfTextRun) {
+		line := &lines[len(lines)-1]
+		run.width = e.measure(run.font, run.size, run.text)
+		if wrap && line.width > 0 && line.width+run.width > maxWidth {
+			lines = append(lines, pdfTextLine{})
+			line = &lines[len(lines)-1]
+			run.text = strings.TrimLeft(run.text, " ")
+			run.width = e.measure(run.font, run.size, run.text)
+		}
+		line.runs, line.width = append(line.runs, run), line.width+run.width
+	}
+	for _, run := range runs {
+		for i, text := range strings.Split(strings.ReplaceAll(run.text, "\r\n", "\n"), "\n") {
+			if i > 0 {
+				lines = append(lines, pdfTextLine{})
+			}
+			words := []string{text}
+			if wrap {
+				words = strings.SplitAfter(text, " ")
+			}
+			for _, word := range words {
+				if word == "" {
+					continue
+				}
+				piece := run
+				piece.text = word
+				appendRun(piece)
+			}
+		}
+	}
+	for i := range lines {
+		line := &lines[i]
+		line.ascent, line.descent = baseFont.ascentAt(baseSize), baseFont.descentAt(baseSize)
+		for _, run := range line.runs {
+			line.ascent = math.Max(line.ascent, run.font.ascentAt(run.size)+run.rise)
+			line.descent = math.Max(line.descent, run.font.descentAt(run.size)-run.rise)
+		}
+	}
+	return lines
+}
+
+// drawTextLine provides a function to draw the line of the text runs by
+// given horizontal position and baseline position from the top left corner,
+// and the vertical position of the origin in the PDF coordinate system.
+func (e *pdfExporter) drawTextLine(c *bytes.Buffer, line pdfTextLine, x, baseline, originY float64) {
+	for _, run := range line.runs {
+		y := baseline - run.rise
+		hex := e.encode(run.font, run.text)
+		fmt.Fprintf(c, "BT /%s %s Tf %s rg %s %s Td <%s> Tj ET\n", run.font.name, pdfNum(run.size), run.color, pdfNum(x), pdfNum(originY-y), hex)
+		thickness := run.size / 16
+		if run.underline {
+			fmt.Fprintf(c, "%s rg %s re f\n", run.color, pdfRect(x, y+run.size/10, run.width, thickness, originY))
+		}
+		if run.strike {
+			fmt.Fprintf(c, "%s rg %s re f\n", run.color, pdfRect(x, y-run.font.ascentAt(run.size)/3, run.width, thickness, originY))
+		}
+		x += run.width
+	}
+}
+
+// renderPage provides a function to render the content stream of the page by
+// given page and the index of the page in the document.
+func (e *pdfExporter) renderPage(page *pdfPage, idx int) ([]byte, error) {
+	var c bytes.Buffer
+	e.drawHeaderFooter(&c, idx)
+	area := page.area
+	if area == nil {
+		return c.Bytes(), nil
+	}
+	lastRow, lastCol := page.rows[len(page.rows)-1], page.cols[len(page.cols)-1]
+	viewWidth := (lastCol.offset + area.colPos[lastCol.to] - area.colPos[lastCol.from]) * area.scale
+	viewHeight := (lastRow.offset + area.rowPos[lastRow.to] - area.rowPos[lastRow.from]) * area.scale
+	bodyWidth, bodyHeight := e.bodySize()
+	x, y := *e.margins.Left*72, *e.margins.Top*72
+	if e.margins.Horizontally != nil && *e.margins.Horizontally {
+		x += math.Max(0, bodyWidth-viewWidth) / 2
+	}
+	if e.margins.Vertically != nil && *e.margins.Vertically {
+		y += math.Max(0, bodyHeight-viewHeight) / 2
+	}
+	fmt.Fprintf(&c, "q %s 0 0 %s %s %s cm\n", pdfNum(area.scale), pdfNum(area.scale), pdfNum(x), pdfNum(e.height-y))
+	for _, rows := range page.rows {
+		for _, cols := range page.cols {
+			if err := e.drawBlock(&c, area, rows, cols); err != nil {
+				return c.Bytes(), err
+			}
+		}
+	}
+	c.WriteString("Q\n")
+	return c.Bytes(), nil
+}
+
+// drawHeaderFooter provides a function to draw the header and footer of the
+// page by given index of the page in the document.
+func (e *pdfExporter) drawHeaderFooter(c *bytes.Buffer, idx int) {
+	hf := e.headerFooter
+	if hf == nil {
+		return
+	}
+	header, footer := hf.OddHeader, hf.OddFooter
+	if hf.DifferentFirst && idx == 0 {
+		header, footer = hf.FirstHeader, hf.FirstFooter
+	} else if hf.DifferentOddEven && idx%2 == 1 {
+		header, footer = hf.EvenHeader, hf.EvenFooter
+	}
+	number := idx + int(*e.layout.FirstPageNumber)
+	for i, format := range []string{header, footer} {
+		for section, richText := range e.parseHeaderFooter(format, number) {
+			if len(richText) == 0 {
+				continue
+			}
+			runs, baseFont, baseSize := e.textRuns(&Font{}, richText)
+			lines := e.wrapText(runs, baseFont, baseSize, 0, false)
+			var height float64
+			for _, line := range lines {
+				height += line.ascent + line.descent
+			}
+			y := *e.margins.Header * 72
+			if i == 1 {
+				y = e.height - *e.margins.Footer*72 - height
+			}
+			for _, line := range lines {
+				x := *e.margins.Left * 72
+				switch section {
+				case 1:
+					x = (e.width - line.width) / 2
+				case 2:
+					x = e.width - *e.margins.Right*72 - line.width
+				}
+				y += line.ascent
+				e.drawTextLine(c, line, x, y, e.height)
+				y += line.descent
+			}
+		}
+	}
+}
+
+// parseHeaderFooter provides a function to parse the header or footer format
+// codes into the rich text runs of the left, center and right sections by
+// given format and the page number.
+func (e *pdfExporter) parseHeaderFooter(format string, number int) [3][]RichTextRun {
+	var sections [3][]RichTextRun
+	var text strings.Builder
+	section, fnt := 1, Font{}
+	flush := func() {
+		if text.Len() > 0 {
+			runFont := fnt
+			sections[section] = append(sections[section], RichTextRun{Text: text.String(), Font: &runFont})
+			text.Reset()
+		}
+	}
+	codes := []rune(format)
+	for i := 0; i < len(codes); i++ {
+		if codes[i] != '&' || i == len(codes)-1 {
+			text.WriteRune(codes[i])
+			continue
+		}
+		i++
+		switch code := codes[i]; code {
+		case 'L', 'C', 'R':
+			flush()
+			section = strings.IndexRune("LCR", code)
+		case 'P':
+			text.WriteString(strconv.Itoa(number))
+		case 'N':
+			text.WriteString(strconv.Itoa(len(e.pages)))
+		case 'D':
+			text.WriteString(e.now.Format("1/2/2006"))
+		case 'T':
+			text.WriteString(e.now.Format("3:04 PM"))
+		case 'A':
+			text.WriteString(e.sheet)
+		case 'F':
+			name := "Book1"
+			if e.f.Path != "" {
+				name = filepath.Base(e.f.Path)
+			}
+			text.WriteString(name)
+		case 'Z':
+			if e.f.Path != "" {
+				text.WriteString(filepath.Dir(e.f.Path) + string(filepath.Separator))
+			}
+		case '&':
+			text.WriteRune('&')
+		case 'B', 'I', 'U', 'E', 'S', 'X', 'Y':
+			flush()
+			e.toggleHeaderFooterFont(&fnt, code)
+		case '"':
+			flush()
+			end := i + 1
+			for end < len(codes) && codes[end] != '"' {
+				end++
+			}
+			parts := strings.SplitN(string(codes[i+1:end]), ",", 2)
+			if i = end; parts[0] != "-" {
+				fnt.Family = parts[0]
+			}
+			if len(parts) == 2 {
+				style := strings.ToLower(parts[1])
+				fnt.Bold, fnt.Italic = strings.Contains(style, "bold"), strings.Contains(style, "italic")
+			}
+		case 'K':
+			flush()
+			if end := i + 7; end <= len(codes) {
+				if _, ok := imageColor(string(codes[i+1 : end])); ok {
+					fnt.Color = string(codes[i+1 : end])
+				}
+				i = end - 1
+			}
+		default:
+			if !unicode.IsDigit(code) {
+				continue
+			}
+			flush()
+			end := i
+			for end < len(codes) && unicode.IsDigit(codes[end]) {
+				end++
+			}
+			size, _ := strconv.Atoi(string(codes[i:end]))
+			fnt.Size, i = float64(size), end-1
+		}
+	}
+	flush()
+	return sections
+}
+
+// toggleHeaderFooterFont provides a function to toggle the font style by
+// given font and the format code of the header or footer.
+func (e *pdfExporter) toggleHeaderFooterFont(fnt *Font, code rune) {
+	toggle := func(value, on string) string {
+		if value == on {
+			return ""
+		}
+		return on
+	}
+	switch code {
+	case 'B':
+		fnt.Bold = !fnt.Bold
+	case 'I':
+		fnt.Italic = !fnt.Italic
+	case 'U':
+		fnt.Underline = toggle(fnt.Underline, "single")
+	case 'E':
+		fnt.Underline = toggle(fnt.Underline, "double")
+	case 'S':
+		fnt.Strike = !fnt.Strike
+	case 'X':
+		fnt.VertAlign = toggle(fnt.VertAlign, "superscript")
+	case 'Y':
+		fnt.VertAlign = toggle(fnt.VertAlign, "subscript")
+	}
+}
+
+// blockCells returns the indexes of the cells to be drawn in the block of
+// given rows and columns segments, the merged cells which intersect with the
+// block will be included.
+func (area *pdfArea) blockCells(rows, cols pdfSegment) [][2]int {
+	var cells [][2]int
+	seen := map[[2]int]bool{}
+	for i := rows.from; i < rows.to; i++ {
+		for j := cols.from; j < cols.to; j++ {
+			pos, ok := area.covered[[2]int{i, j}]
+			if !ok {
+				pos = [2]int{i, j}
+			}
+			if seen[pos] || area.rng.cells[pos[0]][pos[1]] == nil {
+				continue
+			}
+			seen[pos] = true
+			cells = append(cells, pos)
+		}
+	}
+	return cells
+}
+
+// drawBlock provides a function to draw the cells and pictures in the block
+// of given rows and columns segments on the page, the contents will be
+// clipped by the block.
+func (e *pdfExporter) drawBlock(c *bytes.Buffer, area *pdfArea, rows, cols pdfSegment) error {
+	x, y := cols.offset, rows.offset
+	w, h := area.colPos[cols.to]-area.colPos[cols.from], area.rowPos[rows.to]-area.rowPos[rows.from]
+	dx, dy := x-area.colPos[cols.from], y-area.rowPos[rows.from]
+	cells := area.blockCells(rows, cols)
+	rect := func(pos [2]int) (*renderCell, float64, float64, float64, float64) {
+		cell := area.rng.cells[pos[0]][pos[1]]
+		return cell, area.colPos[pos[1]] + dx, area.rowPos[pos[0]] + dy,
+			area.colPos[pos[1]+cell.colSpan] - area.colPos[pos[1]], area.rowPos[pos[0]+cell.rowSpan] - area.rowPos[pos[0]]
+	}
+	fmt.Fprintf(c, "q %s re W n\n", pdfRect(x, y, w, h, 0))
+	for _, pos := range cells {
+		cell, cx, cy, cw, ch := rect(pos)
+		fill := cell.style.Fill
+		if len(fill.Color) == 0 || (fill.Type != "gradient" && fill.Pattern < 1) || e.blackAndWhite() {
+			continue
+		}
+		if rgb, ok := pdfColor(fill.Color[0]); ok {
+			fmt.Fprintf(c, "%s rg %s re f\n", rgb, pdfRect(cx, cy, cw, ch, 0))
+		}
+	}
+	c.WriteString("Q\n")
+	fmt.Fprintf(c, "q %s re W n\n", pdfRect(x-2, y-2, w+4, h+4, 0))
+	for _, pos := range cells {
+		cell, cx, cy, cw, ch := rect(pos)
+		if e.gridLines {
+			fmt.Fprintf(c, "0.831 0.831 0.831 RG 0.5 w [] 0 d %s re S\n", pdfRect(cx, cy, cw, ch, 0))
+		}
+		e.drawBorders(c, cell, cx, cy, cw, ch)
+	}
+	c.WriteString("Q\n")
+	fmt.Fprintf(c, "q %s re W n\n", pdfRect(x, y, w, h, 0))
+	for _, pos := range cells {
+		cell, cx, cy, cw, ch := rect(pos)
+		name, _ := CoordinatesToCellName(cell.col, cell.row)
+		if !e.imageCells[name] {
+			e.drawCellText(c, cell, cx, cy, cw, ch)
+			continue
+		}
+		pics, err := e.f.getCellImages(e.sheet, name)
+		if err != nil {
+			return err
+		}
+		for i, pic := range pics {
+			if img := e.image(name+"#"+strconv.Itoa(i), pic.File); img != nil {
+				ratio := math.Min(cw/float64(img.width), ch/float64(img.height))
+				iw, ih := float64(img.width)*ratio, float64(img.height)*ratio
+				e.drawImage(c, img, cx+(cw-iw)/2, cy+(ch-ih)/2, iw, ih)
+			}
+		}
+	}
+	for _, pic := range area.pictures {
+		px, py := pic.x+dx, pic.y+dy
+		if px >= x+w || py >= y+h || px+pic.w <= x || py+pic.h <= y {
+			continue
+		}
+		if img := e.image(pic.key, pic.file); img != nil {
+			e.drawImage(c, img, px, py, pic.w, pic.h)
+		}
+	}
+	c.WriteString("Q\n")
+	return nil
+}
+
+// drawBorders provides a function to draw the borders of the cell by given
+// position and size of the cell.
+func (e *pdfExporter) drawBorders(c *bytes.Buffer, cell *renderCell, x, y, w, h float64) {
+	for _, border := range cell.style.Border {
+		if border.Style < 1 || border.Style >= len(htmlBorderStyles) {
+			continue
+		}
+		rgb, ok := pdfColor(border.Color)
+		if !ok || e.blackAndWhite() {
+			rgb = "0 0 0"
+		}
+		fields := strings.Fields(htmlBorderStyles[border.Style])
+		px, _ := strconv.Atoi(strings.TrimSuffix(fields[0], "px"))
+		width, dash := float64(px)*0.75, "[]"
+		switch fields[1] {
+		case "dashed":
+			dash = "[2.25 0.75]"
+		case "dotted":
+			dash = "[0.75 0.75]"
+		}
+		lines := map[string][4]float64{
+			"top": {x, y, x + w, y}, "bottom": {x, y + h, x + w, y + h},
+			"left": {x, y, x, y + h}, "right": {x + w, y, x + w, y + h},
+			"diagonalDown": {x, y, x + w, y + h}, "diagonalUp": {x, y + h, x + w, y},
+		}
+		line, ok := lines[border.Type]
+		if !ok {
+			continue
+		}
+		offsets := []float64{0}
+		if fields[1] == "double" {
+			offsets, width = []float64{-width / 3, width / 3}, width/3
+		}
+		for _, offset := range offsets {
+			ox, oy := 0.0, offset
+			if line[0] == line[2] {
+				ox, oy = offset, 0
+			}
+			fmt.Fprintf(c, "%s RG %s w %s 0 d %s %s m %s %s l S\n", rgb, pdfNum(width), dash,
+				pdfNum(line[0]+ox), pdfNum(-line[1]-oy), pdfNum(line[2]+ox), pdfNum(-line[3]-oy))
+		}
+	}
+}
+
+// drawCellText provides a function to draw the text of the cell by the fonts
+// and alignments, the text will be clipped by the cell.
+func (e *pdfExporter) drawCellText(c *bytes.Buffer, cell *renderCell, x, y, w, h float64) {
+	if cell.text == "" {
+		return
+	}
+	base := cell.style.Font
+	if base == nil {
+		base = &Font{}
+	}
+	richText := []RichTextRun{{Text: cell.text}}
+	for _, run := range cell.runs {
+		if run.Font != nil {
+			richText = cell.runs
+			break
+		}
+	}
+	runs, baseFont, baseSize := e.textRuns(base, richText)
+	horizontal, vertical, indent, wrap, padding := "", "bottom", 0.0, false, 1.5
+	if alignment := cell.style.Alignment; alignment != nil {
+		horizontal, indent, wrap = alignment.Horizontal, float64(alignment.Indent)*6.75, alignment.WrapText
+		if alignment.Vertical != "" {
+			vertical = alignment.Vertical
+		}
+	}
+	switch htmlDefaultAlignment(cell) {
+	case "text-align:right;":
+		horizontal = "right"
+	case "text-align:center;":
+		horizontal = "center"
+	}
+	lines := e.wrapText(runs, baseFont, baseSize, w-padding*2-indent, wrap)
+	var height float64
+	for _, line := range lines {
+		height += line.ascent + line.descent
+	}
+	ty := y + h - padding - height
+	switch vertical {
+	case "top":
+		ty = y + padding
+	case "center", "justify", "distributed":
+		ty = y + (h-height)/2
+	}
+	fmt.Fprintf(c, "q %s re W n\n", pdfRect(x, y, w, h, 0))
+	for _, line := range lines {
+		tx := x + padding + indent
+		switch horizontal {
+		case "right":
+			tx = x + w - padding - line.width - indent
+		case "center", "centerContinuous", "distributed", "justify":
+			tx = x + (w-line.width)/2
+		}
+		ty += line.ascent
+		e.drawTextLine(c, line, tx, ty, 0)
+		ty += line.descent
+	}
+	c.WriteString("Q\n")
+}
+
+// image provides a function to get the image XObject by given key and the
+// picture file data, returns nil if the picture is in unsupported format. The
+// image will be added to the resources of the document on the first use.
+func (e *pdfExporter) image(key string, file []byte) *pdfImage {
+	if img, ok := e.images[key]; ok {
+		return img
+	}
+	src, _, err := image.Decode(bytes.NewReader(file))
+	if err != nil {
+		e.images[key] = nil
+		return nil
+	}
+	bounds := src.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), src, bounds.Min, draw.Src)
+	img := &pdfImage{
+		name: "Im" + strconv.Itoa(len(e.usedImages)+1), width: bounds.Dx(), height: bounds.Dy(),
+		pix: make([]byte, 0, len(nrgba.Pix)/4*3), alpha: make([]byte, 0, len(nrgba.Pix)/4),
+	}
+	opaque := true
+	for i := 0; i < len(nrgba.Pix); i += 4 {
+		img.pix, img.alpha = append(img.pix, nrgba.Pix[i:i+3]...), append(img.alpha, nrgba.Pix[i+3])
+		opaque = opaque && nrgba.Pix[i+3] == 0xFF
+	}
+	if opaque {
+		img.alpha = nil
+	}
+	e.images[key] = img
+	e.usedImages = append(e.usedImages, img)
+	return img
+}
+
+// drawImage provides a function to draw the image by given position and size
+// of the image from the top left corner.
+func (e *pdfExporter) drawImage(c *bytes.Buffer, img *pdfImage, x, y, w, h float64) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	fmt.Fprintf(c, "q %s 0 0 %s %s %s cm /%s Do Q\n", pdfNum(w), pdfNum(h), pdfNum(x), pdfNum(-y-h), img.name)
+}
+
+// pdfWriter directly maps the writer of the PDF objects, which tracks the
+// byte offsets of the objects for the cross-reference table.
+type pdfWriter struct {
+	w       *bufio.Writer
+	offset  int
+	offsets []int
+}
+
+// alloc provides a function to allocate the number of a new object.
+func (pw *pdfWriter) alloc() int {
+	pw.offsets = append(pw.offsets, 0)
+	return len(pw.offsets)
+}
+
+// printf provides a function to write the formatted content.
+func (pw *pdfWriter) printf(format string, args ...interface{}) {
+	n, _ := fmt.Fprintf(pw.w, format, args...)
+	pw.offset += n
+}
+
+// object provides a function to write the object by given object number and
+// the dictionary of the object.
+func (pw *pdfWriter) object(id int, dict string) {
+	pw.offsets[id-1] = pw.offset
+	pw.printf("%d 0 obj\n%s\nendobj\n", id, dict)
+}
+
+// stream provides a function to write the stream object compressed by the
+// Flate algorithm by given object number, the additional entries of the
+// stream dictionary and the stream data.
+func (pw *pdfWriter) stream(id int, entries string, data []byte) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+	pw.offsets[id-1] = pw.offset
+	pw.printf("%d 0 obj\n<< /Length %d /Filter /FlateDecode%s >>\nstream\n", id, buf.Len(), entries)
+	n, _ := pw.w.Write(buf.Bytes())
+	pw.offset += n
+	pw.printf("\nendstream\nendobj\n")
+}
+
+// close provides a function to write the cross-reference table and the
+// trailer by given object number of the document catalog.
+func (pw *pdfWriter) close(root int) error {
+	xref := pw.offset
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, offset := range pw.offsets {
+		pw.printf("%010d 00000 n \n", offset)
+	}
+	pw.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, root, xref)
+	return pw.w.Flush()
+}
+
+// write provides a function to write the pages, fonts and images into the
+// PDF document by given writer, a blank page will be written if the print
+// area has no visible cells.
+func (e *pdfExporter) write(writer io.Writer) error {
+	pw := &pdfWriter{w: bufio.NewWriter(writer)}
+	catalog, pages, resources := pw.alloc(), pw.alloc(), pw.alloc()
+	pw.printf("%%PDF-1.7\n%%\xE2\xE3\xCF\xD3\n")
+	pw.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	if len(e.pages) == 0 {
+		e.pages = append(e.pages, &pdfPage{})
+	}
+	kids := make([]string, len(e.pages))
+	for idx, page := range e.pages {
+		content, err := e.renderPage(page, idx)
+		if err != nil {
+			return err
+		}
+		contents, id := pw.alloc(), pw.alloc()
+		pw.stream(contents, "", content)
+		pw.object(id, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %d 0 R /Contents %d 0 R >>",
+			pages, pdfNum(e.width), pdfNum(e.height), resources, contents))
+		kids[idx] = fmt.Sprintf("%d 0 R", id)
+	}
+	pw.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	var fonts, images strings.Builder
+	for _, pf := range e.usedFonts {
+		fmt.Fprintf(&fonts, " /%s %d 0 R", pf.name, e.writeFont(pw, pf))
+	}
+	for _, img := range e.usedImages {
+		fmt.Fprintf(&images, " /%s %d 0 R", img.name, e.writeImage(pw, img))
+	}
+	pw.object(resources, fmt.Sprintf("<< /ProcSet [/PDF /Text /ImageC] /Font <<%s >> /XObject <<%s >> >>", fonts.String(), images.String()))
+	return pw.close(catalog)
+}
+
+// writeFont provides a function to write the font as the Type 0 composite
+// font with the Identity-H encoding, the font file will be embedded, and the
+// ToUnicode CMap will be written for the used glyphs to support extracting
+// the texts. Returns the object number of the font.
+func (e *pdfExporter) writeFont(pw *pdfWriter, pf *pdfFont) int {
+	id, cidFont, descriptor, fontFile, toUnicode := pw.alloc(), pw.alloc(), pw.alloc(), pw.alloc(), pw.alloc()
+	name, _ := pf.sfnt.Name(&e.buf, sfnt.NameIDPostScript)
+	name = strings.Map(func(r rune) rune {
+		if r < '!' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = pf.name
+	}
+	glyphs := make([]int, 0, len(pf.used))
+	for idx := range pf.used {
+		glyphs = append(glyphs, int(idx))
+	}
+	sort.Ints(glyphs)
+	scale := 1000 / pf.upem
+	var widths, cmap strings.Builder
+	for _, idx := range glyphs {
+		fmt.Fprintf(&widths, "%d [%s] ", idx, pdfNum(pf.widths[sfnt.GlyphIndex(idx)]*scale))
+	}
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for i := 0; i < len(glyphs); i += 100 {
+		chunk := glyphs[i:int(math.Min(float64(i+100), float64(len(glyphs))))]
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", len(chunk))
+		for _, idx := range chunk {
+			fmt.Fprintf(&cmap, "<%04X> <", idx)
+			for _, unit := range utf16.Encode([]rune{pf.used[sfnt.GlyphIndex(idx)]}) {
+				fmt.Fprintf(&cmap, "%04X", unit)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	bounds, _ := pf.sfnt.Bounds(&e.buf, pf.ppem(), font.HintingNone)
+	subtype, fileKey, fileEntries, cidToGID := "CIDFontType2", "FontFile2", fmt.Sprintf(" /Length1 %d", len(pf.data)), " /CIDToGIDMap /Identity"
+	if bytes.HasPrefix(pf.data, []byte("OTTO")) {
+		subtype, fileKey, fileEntries, cidToGID = "CIDFontType0", "FontFile3", " /Subtype /OpenType", ""
+	}
+	pw.object(id, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cidFont, toUnicode))
+	pw.object(cidFont, fmt.Sprintf("<< /Type /Font /Subtype /%s /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /W [%s]%s >>",
+		subtype, name, descriptor, widths.String(), cidToGID))
+	pw.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%s %s %s %s] /ItalicAngle 0 /Ascent %s /Descent %s /CapHeight %s /StemV 80 /%s %d 0 R >>",
+		name, pdfNum(float64(bounds.Min.X)/64*scale), pdfNum(-float64(bounds.Max.Y)/64*scale), pdfNum(float64(bounds.Max.X)/64*scale), pdfNum(-float64(bounds.Min.Y)/64*scale),
+		pdfNum(pf.ascent*scale), pdfNum(-pf.descent*scale), pdfNum(pf.ascent*scale), fileKey, fontFile))
+	pw.stream(fontFile, fileEntries, pf.data)
+	pw.stream(toUnicode, "", []byte(cmap.String()))
+	return id
+}
+
+// writeImage provides a function to write the image XObject with the soft
+// mask for the alpha channel. Returns the object number of the image.
+func (e *pdfExporter) writeImage(pw *pdfWriter, img *pdfImage) int {
+	id, sMask := pw.alloc(), ""
+	if img.alpha != nil {
+		mask := pw.alloc()
+		pw.stream(mask, fmt.Sprintf(" /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8", img.width, img.height), img.alpha)
+		sMask = fmt.Sprintf(" /SMask %d 0 R", mask)
+	}
+	pw.stream(id, fmt.Sprintf(" /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8%s", img.width, img.height, sMask), img.pix)
+	return id
+}
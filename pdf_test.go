@@ -0,0 +1,250 @@
+package excelize
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+func TestExportPDF(t *testing.T) {
+	f := NewFile()
+	for row := 1; row <= 60; row++ {
+		assert.NoError(t, f.SetSheetRow("Sheet1", fmt.Sprintf("A%d", row), &[]interface{}{fmt.Sprintf("Item %d", row), row * 10}))
+	}
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "Merged cell with wrapped text"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C3", "D4"))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "C5", []RichTextRun{
+		{Text: "Rich", Font: &Font{Bold: true, Color: "FF0000", Underline: "single"}},
+		{Text: "2", Font: &Font{Strike: true, VertAlign: "superscript"}},
+		{Text: "3", Font: &Font{VertAlign: "subscript"}},
+	}))
+	headerStyle, err := f.NewStyle(&Style{
+		Font:   &Font{Bold: true},
+		Fill:   Fill{Type: "pattern", Pattern: 1, Color: []string{"FFFF00"}},
+		Border: []Border{{Type: "bottom", Style: 6}, {Type: "top", Style: 3, Color: "0000FF"}, {Type: "left", Style: 4}, {Type: "diagonalUp", Style: 1}, {Type: "vertical", Style: 1}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "D1", headerStyle))
+	wrapStyle, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center", Vertical: "center", WrapText: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "C3", "C3", wrapStyle))
+	indentStyle, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "left", Vertical: "top", Indent: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", indentStyle))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A$1:$D$60", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", RefersTo: "Sheet1!$1:$1", Scope: "Sheet1"}))
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "A31"))
+	assert.NoError(t, f.SetPageLayout("Sheet1", &PageLayoutOptions{Size: intPtr(9), Orientation: stringPtr("landscape")}))
+	assert.NoError(t, f.SetPageMargins("Sheet1", &PageLayoutMarginsOptions{Horizontally: boolPtr(true), Vertically: boolPtr(true)}))
+	assert.NoError(t, f.SetHeaderFooter("Sheet1", &HeaderFooterOptions{
+		DifferentFirst: true,
+		FirstHeader:    "&CInvoice",
+		OddHeader:      "&L&\"-,Bold\"&14&KFF0000Report&RPage &P of &N",
+		OddFooter:      "&L&D &T&C&F &A&R&B&I&U&E&S&X&Y&Z&&&G&\"Arial,Regular\"&K01+000x",
+	}))
+
+	// Test export the print area with print titles, page breaks and headers
+	var buf bytes.Buffer
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf))
+	pages, content := parseTestPDF(t, buf.Bytes())
+	assert.Equal(t, 2, pages)
+	assert.Contains(t, buf.String(), "/MediaBox [0 0 841.89 595.276]")
+	assert.Contains(t, content, "1 1 0 rg")
+	assert.Contains(t, content, "0 0 1 RG 0.75 w [2.25 0.75] 0 d")
+	assert.Equal(t, 2, strings.Count(content, "<"+pdfTestHex(t, gobold.TTF, "Item 1")+">"))
+	assert.Equal(t, 1, strings.Count(content, "<"+pdfTestHex(t, goregular.TTF, "Item 31")+">"))
+	assert.Contains(t, content, "<"+pdfTestHex(t, goregular.TTF, "Invoice")+">")
+	assert.Contains(t, content, "/F2 14 Tf 1 0 0 rg")
+	assert.Contains(t, content, "<"+pdfTestHex(t, gobold.TTF, "Report")+">")
+	assert.Contains(t, content, "<"+pdfTestHex(t, goregular.TTF, "2")+">")
+	assert.Contains(t, content, "<"+pdfTestHex(t, goregular.TTF, "Book1 Sheet1")+">")
+	assert.Contains(t, content, "<"+pdfTestHex(t, goregular.TTF, "wrapped ")+">")
+	assert.Contains(t, content, "begincmap")
+
+	// Test export the worksheet to fit to page in black and white with grid lines
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{FitToPage: boolPtr(true)}))
+	assert.NoError(t, f.SetPageLayout("Sheet1", &PageLayoutOptions{FitToWidth: intPtr(1), FitToHeight: intPtr(1), BlackAndWhite: boolPtr(true)}))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).PrintOptions.GridLines = true
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf, PDFOptions{Fonts: map[string][]byte{"Calibri": gomono.TTF}}))
+	pages, content = parseTestPDF(t, buf.Bytes())
+	assert.Equal(t, 1, pages)
+	assert.Contains(t, buf.String(), "/BaseFont /GoMono")
+	assert.Contains(t, content, "0.831 0.831 0.831 RG 0.5 w")
+	assert.NotContains(t, content, "1 1 0 rg")
+
+	// Test export the columns over then down with the manual column breaks
+	assert.NoError(t, f.SetSheetProps("Sheet1", &SheetPropsOptions{FitToPage: boolPtr(false)}))
+	assert.NoError(t, f.SetPageLayout("Sheet1", &PageLayoutOptions{Size: intPtr(1), Orientation: stringPtr("portrait"), AdjustTo: uintPtr(200)}))
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Titles", RefersTo: "Sheet1!$A:$A,Sheet1!$1:$1", Scope: "Sheet1"}))
+	ws.(*xlsxWorksheet).PageSetUp.PageOrder = "overThenDown"
+	ws.(*xlsxWorksheet).RowBreaks = nil
+	assert.NoError(t, f.InsertPageBreak("Sheet1", "C1"))
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf, PDFOptions{RawCellValue: true}))
+	pages, _ = parseTestPDF(t, buf.Bytes())
+	assert.Equal(t, 6, pages)
+	assert.Contains(t, buf.String(), "/MediaBox [0 0 612 792]")
+
+	// Test export the worksheet with the print area of entire columns
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "_xlnm.Print_Area", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A:$A,'Sheet1'!$3:$3,Sheet1!$B$2", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetPageLayout("Sheet1", &PageLayoutOptions{AdjustTo: uintPtr(100)}))
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf))
+	pages, _ = parseTestPDF(t, buf.Bytes())
+	assert.Equal(t, 5, pages)
+
+	// Test export the worksheet with invalid print area and page breaks
+	assert.NoError(t, f.DeleteDefinedName(&DefinedName{Name: "_xlnm.Print_Area", Scope: "Sheet1"}))
+	assert.NoError(t, f.SetDefinedName(&DefinedName{Name: "_xlnm.Print_Area", RefersTo: "Sheet1!$A$1:$1A", Scope: "Sheet1"}))
+	assert.Equal(t, newCellNameToCoordinatesError("1A", newInvalidCellNameError("1A")), f.ExportPDF("Sheet1", &buf))
+	// Test export PDF with invalid font data, writer and worksheet
+	assert.EqualError(t, f.ExportPDF("Sheet2", &buf, PDFOptions{Fonts: map[string][]byte{"Arial": {0}}}), "sheet Sheet2 does not exist")
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	assert.EqualError(t, f.ExportPDF("Sheet2", &buf, PDFOptions{Fonts: map[string][]byte{"Arial": {0}}}), "sfnt: invalid bounds")
+	assert.EqualError(t, f.ExportPDF("Sheet2", errWriter{}), "write error")
+	assert.NoError(t, f.Close())
+
+	// Test export the worksheet without visible cells as a blank page
+	f = NewFile()
+	assert.NoError(t, f.SetRowVisible("Sheet1", 1, false))
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf))
+	pages, content = parseTestPDF(t, buf.Bytes())
+	assert.Equal(t, 1, pages)
+	assert.Empty(t, content)
+	// Test export PDF with invalid merged cell reference
+	assert.NoError(t, f.SetRowVisible("Sheet1", 1, true))
+	assert.NoError(t, f.MergeCell("Sheet1", "A1", "B2"))
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells.Cells[0].Ref = "A1:B"
+	assert.Equal(t, newCellNameToCoordinatesError("B", newInvalidCellNameError("B")), f.ExportPDF("Sheet1", &buf))
+	assert.NoError(t, f.Close())
+	// Test export PDF with unsupported charset workbook
+	f = NewFile()
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportPDF("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+
+	// Test export PDF with the pictures placed over and embedded in the cells
+	f = NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "E10", "End"))
+	assert.NoError(t, f.AddPicture("Sheet1", "A2", filepath.Join("test", "images", "excel.png"), &GraphicOptions{OffsetX: 5, OffsetY: 5, ScaleX: 0.1, ScaleY: 0.1}))
+	assert.NoError(t, f.AddPicture("Sheet1", "C5", filepath.Join("test", "images", "excel.jpg"), &GraphicOptions{Positioning: "oneCell"}))
+	assert.NoError(t, f.AddPicture("Sheet1", "D2", filepath.Join("test", "images", "excel.gif"), nil))
+	f.Pkg.Store("xl/media/image3.gif", []byte("unsupported"))
+	f.Pkg.Store(defaultXMLMetadata, []byte(`<metadata><valueMetadata count="1"><bk><rc t="1" v="0"/></bk></valueMetadata></metadata>`))
+	f.Pkg.Store(defaultXMLRdRichValuePart, []byte(`<rvData count="1"><rv s="0"><v>0</v><v>5</v></rv></rvData>`))
+	f.Pkg.Store(defaultXMLRdRichValueRel, []byte(`<richValueRels><rel r:id="rId1"/></richValueRels>`))
+	f.Pkg.Store(defaultXMLRdRichValueRelRels, []byte(fmt.Sprintf(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="%s" Target="../media/image1.png"/></Relationships>`, SourceRelationshipImage)))
+	ws, ok = f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row = append(ws.(*xlsxWorksheet).SheetData.Row, xlsxRow{R: 11, C: []xlsxC{{R: "B11", T: "e", V: formulaErrorVALUE, Vm: uintPtr(1)}}})
+	buf.Reset()
+	assert.NoError(t, f.ExportPDF("Sheet1", &buf))
+	pages, content = parseTestPDF(t, buf.Bytes())
+	assert.Equal(t, 1, pages)
+	assert.Equal(t, 3, strings.Count(buf.String(), "/DeviceRGB"))
+	assert.Contains(t, buf.String(), "/SMask")
+	assert.Contains(t, content, "/Im1 Do")
+	assert.Contains(t, content, "/Im2 Do")
+	// Test export PDF with unsupported charset rich value part
+	f.Pkg.Store(defaultXMLRdRichValuePart, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportPDF("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	// Test export PDF with unsupported charset drawing
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ExportPDF("Sheet1", &buf), "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+func TestPDFPaginate(t *testing.T) {
+	positions := []float64{0, 10, 20, 30, 40, 50, 60}
+	assert.Equal(t, [][]pdfSegment{
+		{{from: 0, to: 1}, {from: 1, to: 2, offset: 10}},
+		{{from: 0, to: 1}, {from: 2, to: 3, offset: 10}},
+		{{from: 0, to: 1}, {from: 3, to: 4, offset: 10}},
+		{{from: 0, to: 1}, {from: 4, to: 5, offset: 10}},
+	}, pdfPaginate(positions, 1, 4, [2]int{0, 1}, map[int]bool{3: true}, 25))
+	assert.Equal(t, [][]pdfSegment{
+		{{from: 0, to: 4}},
+		{{from: 0, to: 1}, {from: 4, to: 6, offset: 10}},
+	}, pdfPaginate(positions, 0, 5, [2]int{0, 1}, nil, 40))
+	assert.Nil(t, pdfPaginate(positions, 3, 2, [2]int{}, nil, 40))
+}
+
+// parseTestPDF returns the number of the pages and the decompressed content
+// streams of the PDF document, and checks the cross-reference table.
+func parseTestPDF(t *testing.T, data []byte) (int, string) {
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.7\n")))
+	matches := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if !assert.Len(t, matches, 2) {
+		return 0, ""
+	}
+	xref, err := strconv.Atoi(string(matches[1]))
+	assert.NoError(t, err)
+	lines := strings.Split(string(data[xref:]), "\n")
+	count, err := strconv.Atoi(strings.Fields(lines[1])[1])
+	assert.NoError(t, err)
+	var contents []string
+	for id := 1; id < count; id++ {
+		offset, err := strconv.Atoi(lines[2+id][:10])
+		assert.NoError(t, err)
+		object := data[offset:]
+		assert.True(t, bytes.HasPrefix(object, []byte(fmt.Sprintf("%d 0 obj\n", id))))
+		object = object[:bytes.Index(object, []byte("\nendobj\n"))]
+		if !bytes.Contains(object, []byte(" >>\nstream\n")) {
+			continue
+		}
+		dict := string(object[:bytes.Index(object, []byte("\nstream\n"))])
+		length, err := strconv.Atoi(regexp.MustCompile(`/Length (\d+)`).FindStringSubmatch(dict)[1])
+		assert.NoError(t, err)
+		stream := object[len(dict)+len("\nstream\n"):]
+		assert.Equal(t, "\nendstream", string(stream[length:]))
+		r, err := zlib.NewReader(bytes.NewReader(stream[:length]))
+		assert.NoError(t, err)
+		decoded, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		if strings.HasSuffix(dict, "/FlateDecode >>") {
+			contents = append(contents, string(decoded))
+		}
+	}
+	pages := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(string(data))
+	assert.Len(t, pages, 2)
+	n, _ := strconv.Atoi(pages[1])
+	return n, strings.Join(contents, "")
+}
+
+// pdfTestHex returns the hex string of the glyph indexes of the text by given
+// font file data.
+func pdfTestHex(t *testing.T, data []byte, text string) string {
+	fnt, err := sfnt.Parse(data)
+	assert.NoError(t, err)
+	var buf sfnt.Buffer
+	var hex strings.Builder
+	for _, r := range text {
+		idx, err := fnt.GlyphIndex(&buf, r)
+		assert.NoError(t, err)
+		fmt.Fprintf(&hex, "%04X", uint16(idx))
+	}
+	return hex.String()
+}
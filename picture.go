@@ -621,6 +621,66 @@ func (f *File) getPicture(row, col int, drawingXML, drawingRelationships string)
 	return
 }
 
+// pictureAnchor directly maps the position and file of the picture which
+// placed over the cells, the to position is nil for the one cell anchor.
+type pictureAnchor struct {
+	from xlsxFrom
+	to   *xlsxTo
+	file []byte
+}
+
+// getPictureAnchors returns the anchors of the pictures which placed over
+// the cells by given worksheet name.
+func (f *File) getPictureAnchors(sheet string) ([]pictureAnchor, error) {
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil || ws.Drawing == nil {
+		return nil, err
+	}
+	target := f.getSheetRelationshipsTargetByID(sheet, ws.Drawing.RID)
+	drawingXML := strings.TrimPrefix(strings.ReplaceAll(target, "..", "xl"), "/")
+	drawingRelationships := strings.ReplaceAll(
+		strings.ReplaceAll(target, "../drawings", "xl/drawings/_rels"), ".xml", ".xml.rels")
+	wsDr, _, err := f.drawingParser(drawingXML)
+	if err != nil {
+		return nil, err
+	}
+	wsDr.mu.Lock()
+	defer wsDr.mu.Unlock()
+	var anchors []pictureAnchor
+	load := func(target string) []byte {
+		buffer, _ := f.Pkg.Load(filepath.ToSlash(filepath.Clean("xl/drawings/" + target)))
+		file, _ := buffer.([]byte)
+		return file
+	}
+	cond := func(from *xlsxFrom) bool { return true }
+	cond2 := func(from *decodeFrom) bool { return true }
+	cb := func(a *xdrCellAnchor, r *xlsxRelationship) {
+		anchor := pictureAnchor{from: *a.From, file: load(r.Target)}
+		if a.To != nil {
+			to := *a.To
+			anchor.to = &to
+		}
+		anchors = append(anchors, anchor)
+	}
+	cb2 := func(a *decodeCellAnchor, r *xlsxRelationship) {
+		anchor := pictureAnchor{from: xlsxFrom(*a.From), file: load(r.Target)}
+		if a.To != nil {
+			to := xlsxTo(*a.To)
+			anchor.to = &to
+		}
+		anchors = append(anchors, anchor)
+	}
+	for _, anchor := range wsDr.TwoCellAnchor {
+		f.extractCellAnchor(anchor, drawingRelationships, cond, cb, cond2, cb2)
+	}
+	for _, anchor := range wsDr.OneCellAnchor {
+		f.extractCellAnchor(anchor, drawingRelationships, cond, cb, cond2, cb2)
+	}
+	return anchors, err
+}
+
 // extractCellAnchor extract drawing object from cell anchor by giving drawing
 // cell anchor, drawing relationships part path, conditional and callback
 // function.
@@ -0,0 +1,508 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// HTMLOptions directly maps the settings of rendering the worksheet range as
+// the HTML table.
+//
+// RawCellValue specifies if render the raw cell values without applying the
+// number formats.
+//
+// ShowGridLines specifies if render the light gray grid lines for the cells
+// which have no borders.
+type HTMLOptions struct {
+	RawCellValue  bool
+	ShowGridLines bool
+}
+
+// renderCell directly maps the cell to be rendered in the range, the text of
+// the merged cell will be placed in the first visible cell of the merged
+// range.
+type renderCell struct {
+	col, row         int
+	colSpan, rowSpan int
+	text             string
+	runs             []RichTextRun
+	styleID          int
+	style            *Style
+	cellType         CellType
+	numeric          bool
+}
+
+// renderRange directly maps the visible columns, rows and cells of the
+// worksheet range to be rendered, the widths of the columns are in character
+// units, and the heights of the rows are in points. The item of the cells is
+// nil if the cell has been covered by the merged cell.
+type renderRange struct {
+	cols       []int
+	rows       []int
+	colWidths  []float64
+	rowHeights []float64
+	cells      [][]*renderCell
+	styles     map[int]*Style
+	hiddenRows map[int]bool
+}
+
+// htmlBorderStyles defined the CSS border width and line style for the
+// border styles of the cells, the index of the slice is the border style
+// index of the cell style.
+var htmlBorderStyles = []string{
+	"none", "1px solid", "2px solid", "1px dashed", "1px dotted", "3px solid",
+	"3px double", "1px dotted", "2px dashed", "1px dashed", "2px dashed",
+	"1px dashed", "2px dashed", "2px dashed",
+}
+
+// usedRange returns the coordinates of the used range of the worksheet,
+// which contains all cells with values or styles, and the merged cells.
+func (ws *xlsxWorksheet) usedRange() ([]int, error) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	coordinates := []int{1, 1, 1, 1}
+	for _, row := range ws.SheetData.Row {
+		for _, c := range row.C {
+			if c.V == "" && c.F == nil && c.IS == nil && c.S == 0 {
+				continue
+			}
+			col, r, err := CellNameToCoordinates(c.R)
+			if err != nil {
+				return coordinates, err
+			}
+			coordinates[2] = int(math.Max(float64(coordinates[2]), float64(col)))
+			coordinates[3] = int(math.Max(float64(coordinates[3]), float64(r)))
+		}
+	}
+	if ws.MergeCells != nil {
+		for _, mergeCell := range ws.MergeCells.Cells {
+			if mergeCell == nil {
+				continue
+			}
+			rect, err := mergeCell.Rect()
+			if err != nil {
+				return coordinates, err
+			}
+			coordinates[2] = int(math.Max(float64(coordinates[2]), float64(rect[2])))
+			coordinates[3] = int(math.Max(float64(coordinates[3]), float64(rect[3])))
+		}
+	}
+	return coordinates, nil
+}
+
+// hiddenRows returns the row numbers of the hidden rows in the worksheet, the
+// rows which not exist in the worksheet are visible.
+func (ws *xlsxWorksheet) hiddenRows() map[int]bool {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	rows := map[int]bool{}
+	for _, r := range ws.SheetData.Row {
+		if r.Hidden {
+			rows[r.R] = true
+		}
+	}
+	return rows
+}
+
+// newRenderRange provides a function to prepare the visible columns, rows and
+// cells for rendering by given worksheet name and range reference, the used
+// range of the worksheet will be rendered if the range reference is empty.
+// The hidden rows and columns will be omitted.
+func (f *File) newRenderRange(sheet, rangeRef string, raw bool) (*renderRange, error) {
+	f.mu.Lock()
+	ws, err := f.workSheetReader(sheet)
+	f.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var coordinates []int
+	if rangeRef == "" {
+		if coordinates, err = ws.usedRange(); err != nil {
+			return nil, err
+		}
+	} else {
+		if !strings.Contains(rangeRef, ":") {
+			rangeRef += ":" + rangeRef
+		}
+		if coordinates, err = rangeRefToCoordinates(rangeRef); err != nil {
+			return nil, err
+		}
+		_ = sortCoordinates(coordinates)
+	}
+	rng := &renderRange{styles: make(map[int]*Style), hiddenRows: ws.hiddenRows()}
+	colIdx, rowIdx := map[int]int{}, map[int]int{}
+	for col := coordinates[0]; col <= coordinates[2]; col++ {
+		name, _ := ColumnNumberToName(col)
+		visible, err := f.GetColVisible(sheet, name)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			continue
+		}
+		width, err := f.GetColWidth(sheet, name)
+		if err != nil {
+			return nil, err
+		}
+		colIdx[col] = len(rng.cols)
+		rng.cols, rng.colWidths = append(rng.cols, col), append(rng.colWidths, width)
+	}
+	for row := coordinates[1]; row <= coordinates[3]; row++ {
+		if rng.hiddenRows[row] {
+			continue
+		}
+		height, err := f.GetRowHeight(sheet, row)
+		if err != nil {
+			return nil, err
+		}
+		rowIdx[row] = len(rng.rows)
+		rng.rows, rng.rowHeights = append(rng.rows, row), append(rng.rowHeights, height)
+	}
+	covered := map[[2]int]bool{}
+	rng.cells = make([][]*renderCell, len(rng.rows))
+	for i := range rng.cells {
+		rng.cells[i] = make([]*renderCell, len(rng.cols))
+	}
+	mergeCells, err := f.GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, mergeCell := range mergeCells {
+		rect, err := rangeRefToCoordinates(mergeCell[0])
+		if err != nil {
+			return nil, err
+		}
+		_ = sortCoordinates(rect)
+		var anchor *[2]int
+		colSpan, rowSpan := map[int]bool{}, map[int]bool{}
+		for row := math.Max(float64(rect[1]), float64(coordinates[1])); row <= math.Min(float64(rect[3]), float64(coordinates[3])); row++ {
+			for col := math.Max(float64(rect[0]), float64(coordinates[0])); col <= math.Min(float64(rect[2]), float64(coordinates[2])); col++ {
+				c, r := int(col), int(row)
+				if _, ok := colIdx[c]; !ok {
+					continue
+				}
+				if _, ok := rowIdx[r]; !ok {
+					continue
+				}
+				colSpan[c], rowSpan[r] = true, true
+				if anchor == nil {
+					anchor = &[2]int{c, r}
+					continue
+				}
+				covered[[2]int{c, r}] = true
+			}
+		}
+		if anchor == nil {
+			continue
+		}
+		cell, err := rng.newRenderCell(f, sheet, rect[0], rect[1], raw)
+		if err != nil {
+			return nil, err
+		}
+		cell.colSpan, cell.rowSpan = len(colSpan), len(rowSpan)
+		rng.cells[rowIdx[anchor[1]]][colIdx[anchor[0]]] = cell
+		covered[*anchor] = true
+	}
+	for i, row := range rng.rows {
+		for j, col := range rng.cols {
+			if covered[[2]int{col, row}] {
+				continue
+			}
+			if rng.cells[i][j], err = rng.newRenderCell(f, sheet, col, row, raw); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rng, err
+}
+
+// newRenderCell provides a function to get the formatted text, rich text
+// runs, type and style of the cell for rendering by given worksheet name and
+// cell coordinates.
+func (rng *renderRange) newRenderCell(f *File, sheet string, col, row int, raw bool) (*renderCell, error) {
+	cell := &renderCell{col: col, row: row, colSpan: 1, rowSpan: 1}
+	ref, err := CoordinatesToCellName(col, row)
+	if err != nil {
+		return cell, err
+	}
+	if cell.text, err = f.GetCellValue(sheet, ref, Options{RawCellValue: raw}); err != nil {
+		return cell, err
+	}
+	if cell.cellType, err = f.GetCellType(sheet, ref); err != nil {
+		return cell, err
+	}
+	switch cell.cellType {
+	case CellTypeUnset, CellTypeNumber, CellTypeDate:
+		value, err := f.GetCellValue(sheet, ref, Options{RawCellValue: true})
+		if err != nil {
+			return cell, err
+		}
+		_, err = strconv.ParseFloat(value, 64)
+		cell.numeric = err == nil
+	case CellTypeSharedString, CellTypeInlineString:
+		if cell.runs, err = f.GetCellRichText(sheet, ref); err != nil {
+			return cell, err
+		}
+	}
+	if cell.styleID, err = f.GetCellStyle(sheet, ref); err != nil {
+		return cell, err
+	}
+	var ok bool
+	if cell.style, ok = rng.styles[cell.styleID]; !ok {
+		if cell.style, err = f.GetStyle(cell.styleID); err != nil {
+			return cell, err
+		}
+		rng.styles[cell.styleID] = cell.style
+	}
+	return cell, err
+}
+
+// RenderHTML provides a function to render the worksheet range as the HTML
+// table by given worksheet name and range reference, the used range of the
+// worksheet will be rendered if the range reference is empty. The cell values
+// will be formatted by the number formats, and the fonts, fills, borders and
+// alignments of the cells will be translated into the inline CSS styles, so
+// the HTML table could be embedded in the email. The merged cells will be
+// rendered with the colspan and rowspan attributes, and the hidden rows and
+// columns will be omitted. For example, render the range A1:D10 on Sheet1
+// with grid lines:
+//
+//	table, err := f.RenderHTML("Sheet1", "A1:D10", excelize.HTMLOptions{
+//	    ShowGridLines: true,
+//	})
+func (f *File) RenderHTML(sheet, rangeRef string, opts ...HTMLOptions) (string, error) {
+	var options HTMLOptions
+	if len(opts) > 0 {
+		options = opts[len(opts)-1]
+	}
+	rng, err := f.newRenderRange(sheet, rangeRef, options.RawCellValue)
+	if err != nil {
+		return "", err
+	}
+	var buf strings.Builder
+	buf.WriteString("<table style=\"border-collapse:collapse\">\n<colgroup>")
+	for _, width := range rng.colWidths {
+		fmt.Fprintf(&buf, "<col style=\"width:%dpx\">", int(math.Round(colWidthToPixels(width))))
+	}
+	buf.WriteString("</colgroup>\n<tbody>\n")
+	styles := map[int]string{}
+	for i, cells := range rng.cells {
+		fmt.Fprintf(&buf, "<tr style=\"height:%spt\">", strconv.FormatFloat(rng.rowHeights[i], 'f', -1, 64))
+		for _, cell := range cells {
+			if cell == nil {
+				continue
+			}
+			buf.WriteString("<td")
+			if cell.colSpan > 1 {
+				fmt.Fprintf(&buf, " colspan=\"%d\"", cell.colSpan)
+			}
+			if cell.rowSpan > 1 {
+				fmt.Fprintf(&buf, " rowspan=\"%d\"", cell.rowSpan)
+			}
+			css, ok := styles[cell.styleID]
+			if !ok {
+				css = f.htmlCellStyle(cell.style, options.ShowGridLines)
+				styles[cell.styleID] = css
+			}
+			if css += htmlDefaultAlignment(cell); css != "" {
+				fmt.Fprintf(&buf, " style=\"%s\"", css)
+			}
+			buf.WriteString(">")
+			buf.WriteString(f.htmlCellText(cell))
+			buf.WriteString("</td>")
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</tbody>\n</table>")
+	return buf.String(), err
+}
+
+// colWidthToPixels provides a function to convert the column width in
+// character units into pixels.
+func colWidthToPixels(width float64) float64 {
+	if width < 1 {
+		return width * 12
+	}
+	return width*7 + 5
+}
+
+// htmlDefaultAlignment returns the CSS horizontal alignment for the cell
+// with the general horizontal alignment, the numbers are right-aligned, and
+// the boolean and error values are centered.
+func htmlDefaultAlignment(cell *renderCell) string {
+	if cell.style.Alignment != nil && cell.style.Alignment.Horizontal != "" && cell.style.Alignment.Horizontal != "general" {
+		return ""
+	}
+	if cell.numeric {
+		return "text-align:right;"
+	}
+	if cell.cellType == CellTypeBool || cell.cellType == CellTypeError {
+		return "text-align:center;"
+	}
+	return ""
+}
+
+// htmlColor returns the CSS color value by given hex color, an empty string
+// will be returned if the color is invalid.
+func htmlColor(color string) string {
+	if color = strings.TrimPrefix(color, "#"); len(color) == 8 {
+		color = color[2:]
+	}
+	if _, err := strconv.ParseUint(color, 16, 32); err != nil || len(color) != 6 {
+		return ""
+	}
+	return "#" + strings.ToUpper(color)
+}
+
+// fontColor returns the hex color of the font by given font settings, the
+// theme and indexed color of the font will be converted into the hex color.
+func (f *File) fontColor(font *Font) string {
+	if font.Color != "" {
+		return font.Color
+	}
+	if font.ColorTheme != nil {
+		return f.getThemeColor(&xlsxColor{Theme: font.ColorTheme, Tint: font.ColorTint})
+	}
+	if font.ColorIndexed > 0 && font.ColorIndexed < len(IndexedColorMapping) {
+		return f.getThemeColor(&xlsxColor{Indexed: font.ColorIndexed})
+	}
+	return ""
+}
+
+// htmlFontStyle returns the CSS declarations of the font by given font
+// settings.
+func (f *File) htmlFontStyle(font *Font) string {
+	if font == nil {
+		return ""
+	}
+	var css strings.Builder
+	if font.Family != "" {
+		fmt.Fprintf(&css, "font-family:'%s';", html.EscapeString(strings.ReplaceAll(font.Family, "'", "")))
+	}
+	if font.Size > 0 {
+		fmt.Fprintf(&css, "font-size:%spt;", strconv.FormatFloat(font.Size, 'f', -1, 64))
+	}
+	if font.Bold {
+		css.WriteString("font-weight:bold;")
+	}
+	if font.Italic {
+		css.WriteString("font-style:italic;")
+	}
+	var decorations []string
+	if font.Underline == "single" || font.Underline == "double" || font.Underline == "singleAccounting" || font.Underline == "doubleAccounting" {
+		decorations = append(decorations, "underline")
+	}
+	if font.Strike {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		fmt.Fprintf(&css, "text-decoration:%s;", strings.Join(decorations, " "))
+	}
+	if color := htmlColor(f.fontColor(font)); color != "" {
+		fmt.Fprintf(&css, "color:%s;", color)
+	}
+	if font.VertAlign == "superscript" {
+		css.WriteString("vertical-align:super;")
+	}
+	if font.VertAlign == "subscript" {
+		css.WriteString("vertical-align:sub;")
+	}
+	return css.String()
+}
+
+// htmlCellStyle returns the CSS declarations of the cell by given cell style
+// and if render the grid lines.
+func (f *File) htmlCellStyle(style *Style, showGridLines bool) string {
+	var css strings.Builder
+	if len(style.Fill.Color) > 0 && (style.Fill.Type == "gradient" || style.Fill.Pattern > 0) {
+		if color := htmlColor(style.Fill.Color[0]); color != "" {
+			fmt.Fprintf(&css, "background-color:%s;", color)
+		}
+	}
+	borders := map[string]string{}
+	for _, border := range style.Border {
+		if border.Style < 1 || border.Style >= len(htmlBorderStyles) {
+			continue
+		}
+		color := htmlColor(border.Color)
+		if color == "" {
+			color = "#000000"
+		}
+		borders[border.Type] = htmlBorderStyles[border.Style] + " " + color
+	}
+	for _, typ := range []string{"top", "right", "bottom", "left"} {
+		value, ok := borders[typ]
+		if !ok && showGridLines {
+			value, ok = "1px solid #D4D4D4", true
+		}
+		if ok {
+			fmt.Fprintf(&css, "border-%s:%s;", typ, value)
+		}
+	}
+	css.WriteString(f.htmlFontStyle(style.Font))
+	whiteSpace := "nowrap"
+	if alignment := style.Alignment; alignment != nil {
+		if textAlign, ok := map[string]string{
+			"left": "left", "center": "center", "centerContinuous": "center", "right": "right",
+			"justify": "justify", "distributed": "justify", "fill": "left",
+		}[alignment.Horizontal]; ok {
+			fmt.Fprintf(&css, "text-align:%s;", textAlign)
+		}
+		if verticalAlign, ok := map[string]string{
+			"top": "top", "center": "middle", "bottom": "bottom", "justify": "middle", "distributed": "middle",
+		}[alignment.Vertical]; ok {
+			fmt.Fprintf(&css, "vertical-align:%s;", verticalAlign)
+		}
+		if alignment.Indent > 0 {
+			fmt.Fprintf(&css, "padding-left:%dpx;", alignment.Indent*9)
+		}
+		if alignment.WrapText {
+			whiteSpace = "pre-wrap"
+		}
+	}
+	if style.Alignment == nil || style.Alignment.Vertical == "" {
+		css.WriteString("vertical-align:bottom;")
+	}
+	fmt.Fprintf(&css, "white-space:%s;", whiteSpace)
+	return css.String()
+}
+
+// htmlCellText returns the escaped HTML text of the cell, the rich text runs
+// will be rendered as the span elements with the inline CSS styles.
+func (f *File) htmlCellText(cell *renderCell) string {
+	escape := func(text string) string {
+		return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+	}
+	var (
+		buf   strings.Builder
+		plain = true
+	)
+	for _, run := range cell.runs {
+		plain = plain && run.Font == nil
+	}
+	if plain {
+		return escape(cell.text)
+	}
+	for _, run := range cell.runs {
+		if css := f.htmlFontStyle(run.Font); css != "" {
+			fmt.Fprintf(&buf, "<span style=\"%s\">%s</span>", css, escape(run.Text))
+			continue
+		}
+		buf.WriteString(escape(run.Text))
+	}
+	return buf.String()
+}
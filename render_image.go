@@ -0,0 +1,706 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	_ "image/gif"  // register GIF format decoder for rendering pictures
+	_ "image/jpeg" // register JPEG format decoder for rendering pictures
+	"image/png"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	_ "golang.org/x/image/bmp" // register BMP format decoder for rendering pictures
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	_ "golang.org/x/image/tiff" // register TIFF format decoder for rendering pictures
+)
+
+// ImageOptions directly maps the settings of rendering the worksheet range
+// as the image.
+//
+// RawCellValue specifies if render the raw cell values without applying the
+// number formats.
+//
+// ShowGridLines specifies if render the light gray grid lines for the cells
+// which have no borders.
+//
+// Scale specifies the zoom factor of the image, the default value is 1, which
+// renders the cells in the 96 DPI resolution.
+//
+// Fonts specifies the TrueType or OpenType font files data by the font family
+// names, such as "Calibri". The bundled Go fonts will be used as the fallback
+// font for the font families which not been specified.
+type ImageOptions struct {
+	RawCellValue  bool
+	ShowGridLines bool
+	Scale         float64
+	Fonts         map[string][]byte
+}
+
+// imageFaceKey directly maps the settings of the font face for rendering.
+type imageFaceKey struct {
+	family       string
+	size         float64
+	bold, italic bool
+}
+
+// imageTextRun directly maps the text run with the same font for rendering.
+type imageTextRun struct {
+	text      string
+	face      font.Face
+	color     color.RGBA
+	underline bool
+	strike    bool
+	shift     int
+	width     int
+}
+
+// imageTextLine directly maps the line of the text runs for rendering, the
+// ascent and descent of the line are in pixels.
+type imageTextLine struct {
+	runs            []imageTextRun
+	width           int
+	ascent, descent int
+}
+
+// imageRenderer directly maps the state of rendering the worksheet range as
+// the image, the positions of the columns and rows are in pixels.
+type imageRenderer struct {
+	f     *File
+	sheet string
+	opts  *ImageOptions
+	rng   *renderRange
+	img   *image.RGBA
+	colX  []int
+	rowY  []int
+	fonts map[string]*opentype.Font
+	faces map[imageFaceKey]font.Face
+	cells map[string]bool
+}
+
+var (
+	// imageFallbackFontFiles defined the bundled regular, bold, italic and
+	// bold italic Go fonts data for rendering the texts.
+	imageFallbackFontFiles = [4][]byte{goregular.TTF, gobold.TTF, goitalic.TTF, gobolditalic.TTF}
+	// imageFallbackFonts defined the parsed bundled fonts for rendering the
+	// texts, the order of the fonts is the same as the imageFallbackFontFiles.
+	imageFallbackFonts     [4]*opentype.Font
+	imageFallbackFontsOnce sync.Once
+	imageFallbackFontsErr  error
+	// imageGridLineColor defined the color of the grid lines in the images.
+	imageGridLineColor = color.RGBA{R: 0xD4, G: 0xD4, B: 0xD4, A: 0xFF}
+)
+
+// RenderImage provides a function to render the worksheet range as the image
+// by given worksheet name and range reference, the used range of the
+// worksheet will be rendered if the range reference is empty. The column
+// widths, row heights, fills, borders, fonts, alignments, merged cells and
+// the pictures of the worksheet will be rendered, and the hidden rows and
+// columns will be omitted. The texts will be rendered by the bundled Go fonts
+// unless the font files have been specified by the Fonts option, so the
+// rendering could be run fully offline. For example, render the range A1:D10
+// on Sheet1 in double resolution:
+//
+//	img, err := f.RenderImage("Sheet1", "A1:D10", excelize.ImageOptions{
+//	    Scale: 2,
+//	})
+func (f *File) RenderImage(sheet, rangeRef string, opts ...ImageOptions) (*image.RGBA, error) {
+	options := ImageOptions{}
+	if len(opts) > 0 {
+		options = opts[len(opts)-1]
+	}
+	if options.Scale <= 0 {
+		options.Scale = 1
+	}
+	rng, err := f.newRenderRange(sheet, rangeRef, options.RawCellValue)
+	if err != nil {
+		return nil, err
+	}
+	r := &imageRenderer{
+		f: f, sheet: sheet, opts: &options, rng: rng,
+		fonts: make(map[string]*opentype.Font), faces: make(map[imageFaceKey]font.Face),
+	}
+	defer r.close()
+	if err = r.parseFonts(); err != nil {
+		return nil, err
+	}
+	imageCells, err := f.getImageCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+	r.cells = make(map[string]bool, len(imageCells))
+	for _, cell := range imageCells {
+		r.cells[cell] = true
+	}
+	r.layout()
+	draw.Draw(r.img, r.img.Bounds(), image.White, image.Point{}, draw.Src)
+	r.drawFills()
+	if options.ShowGridLines {
+		r.drawGridLines()
+	}
+	r.drawBorders()
+	if err = r.drawTexts(); err != nil {
+		return nil, err
+	}
+	return r.img, r.drawPictures()
+}
+
+// RenderPNG provides a function to render the worksheet range as the image
+// in the PNG format to the writer by given worksheet name and range
+// reference, the settings are the same as the RenderImage function. For
+// example, render the used range of Sheet1 into the file report.png:
+//
+//	file, err := os.Create("report.png")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	defer file.Close()
+//	err = f.RenderPNG("Sheet1", "", file)
+func (f *File) RenderPNG(sheet, rangeRef string, writer io.Writer, opts ...ImageOptions) error {
+	img, err := f.RenderImage(sheet, rangeRef, opts...)
+	if err != nil {
+		return err
+	}
+	return png.Encode(writer, img)
+}
+
+// parseFallbackFonts provides a function to parse the bundled fallback fonts
+// only once.
+func parseFallbackFonts() error {
+	imageFallbackFontsOnce.Do(func() {
+		for i, data := range imageFallbackFontFiles {
+			if imageFallbackFonts[i], imageFallbackFontsErr = opentype.Parse(data); imageFallbackFontsErr != nil {
+				return
+			}
+		}
+	})
+	return imageFallbackFontsErr
+}
+
+// parseFonts provides a function to parse the bundled fallback fonts and the
+// font files which specified by the Fonts option.
+func (r *imageRenderer) parseFonts() error {
+	if err := parseFallbackFonts(); err != nil {
+		return err
+	}
+	for family, data := range r.opts.Fonts {
+		fnt, err := opentype.Parse(data)
+		if err != nil {
+			return err
+		}
+		r.fonts[strings.ToLower(family)] = fnt
+	}
+	return nil
+}
+
+// close provides a function to release the font faces of the renderer.
+func (r *imageRenderer) close() {
+	for _, face := range r.faces {
+		_ = face.Close()
+	}
+}
+
+// layout provides a function to calculate the positions of the columns and
+// rows in pixels, and create the image by the size of the range.
+func (r *imageRenderer) layout() {
+	var width, height float64
+	r.colX, r.rowY = []int{0}, []int{0}
+	for _, w := range r.rng.colWidths {
+		width += colWidthToPixels(w) * r.opts.Scale
+		r.colX = append(r.colX, int(math.Round(width)))
+	}
+	for _, h := range r.rng.rowHeights {
+		height += convertRowHeightToPixels(h) * r.opts.Scale
+		r.rowY = append(r.rowY, int(math.Round(height)))
+	}
+	r.img = image.NewRGBA(image.Rect(0, 0,
+		int(math.Max(1, float64(r.colX[len(r.colX)-1]))), int(math.Max(1, float64(r.rowY[len(r.rowY)-1])))))
+}
+
+// cellRect returns the rectangle of the cell in the image by given row and
+// column index of the cell in the range.
+func (r *imageRenderer) cellRect(i, j int, cell *renderCell) image.Rectangle {
+	return image.Rect(r.colX[j], r.rowY[i], r.colX[j+cell.colSpan], r.rowY[i+cell.rowSpan])
+}
+
+// eachCell provides a function to call the given function for each visible
+// cell in the range with the rectangle of the cell.
+func (r *imageRenderer) eachCell(fn func(cell *renderCell, rect image.Rectangle) error) error {
+	for i, cells := range r.rng.cells {
+		for j, cell := range cells {
+			if cell == nil {
+				continue
+			}
+			if err := fn(cell, r.cellRect(i, j, cell)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// imageColor returns the RGBA color by given hex color, returns false if the
+// color is invalid.
+func imageColor(hexColor string) (color.RGBA, bool) {
+	if hexColor = htmlColor(hexColor); hexColor == "" {
+		return color.RGBA{}, false
+	}
+	value, _ := strconv.ParseUint(hexColor[1:], 16, 32)
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 0xFF}, true
+}
+
+// drawFills provides a function to draw the background colors of the cells.
+func (r *imageRenderer) drawFills() {
+	_ = r.eachCell(func(cell *renderCell, rect image.Rectangle) error {
+		fill := cell.style.Fill
+		if len(fill.Color) > 0 && (fill.Type == "gradient" || fill.Pattern > 0) {
+			if c, ok := imageColor(fill.Color[0]); ok {
+				draw.Draw(r.img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+			}
+		}
+		return nil
+	})
+}
+
+// drawGridLines provides a function to draw the grid lines of the cells.
+func (r *imageRenderer) drawGridLines() {
+	_ = r.eachCell(func(cell *renderCell, rect image.Rectangle) error {
+		for _, side := range []string{"top", "right", "bottom", "left"} {
+			r.drawLine(rect, side, 1, "solid", imageGridLineColor)
+		}
+		return nil
+	})
+}
+
+// drawBorders provides a function to draw the borders of the cells by the
+// border styles.
+func (r *imageRenderer) drawBorders() {
+	_ = r.eachCell(func(cell *renderCell, rect image.Rectangle) error {
+		for _, border := range cell.style.Border {
+			if border.Style < 1 || border.Style >= len(htmlBorderStyles) {
+				continue
+			}
+			c, ok := imageColor(border.Color)
+			if !ok {
+				c = color.RGBA{A: 0xFF}
+			}
+			fields := strings.Fields(htmlBorderStyles[border.Style])
+			width, _ := strconv.Atoi(strings.TrimSuffix(fields[0], "px"))
+			r.drawLine(rect, border.Type, int(math.Max(1, math.Round(float64(width)*r.opts.Scale))), fields[1], c)
+		}
+		return nil
+	})
+}
+
+// drawLine provides a function to draw the line on the side of the rectangle
+// by given line width, line style and color.
+func (r *imageRenderer) drawLine(rect image.Rectangle, side string, width int, style string, c color.RGBA) {
+	dash, gap := 0, 0
+	switch style {
+	case "dashed":
+		dash, gap = int(math.Max(1, 3*r.opts.Scale)), int(math.Max(1, r.opts.Scale))
+	case "dotted":
+		dash, gap = int(math.Max(1, r.opts.Scale)), int(math.Max(1, r.opts.Scale))
+	}
+	for t := 0; t < width; t++ {
+		if style == "double" && t > 0 && t < width-1 {
+			continue
+		}
+		var from, to image.Point
+		switch side {
+		case "top":
+			from, to = image.Pt(rect.Min.X, rect.Min.Y+t), image.Pt(rect.Max.X, rect.Min.Y+t)
+		case "bottom":
+			from, to = image.Pt(rect.Min.X, rect.Max.Y-1-t), image.Pt(rect.Max.X, rect.Max.Y-1-t)
+		case "left":
+			from, to = image.Pt(rect.Min.X+t, rect.Min.Y), image.Pt(rect.Min.X+t, rect.Max.Y)
+		case "right":
+			from, to = image.Pt(rect.Max.X-1-t, rect.Min.Y), image.Pt(rect.Max.X-1-t, rect.Max.Y)
+		default:
+			return
+		}
+		for i, p := 0, from; p.X < to.X || p.Y < to.Y; i++ {
+			if dash == 0 || i%(dash+gap) < dash {
+				r.img.SetRGBA(p.X, p.Y, c)
+			}
+			if from.Y == to.Y {
+				p.X++
+				continue
+			}
+			p.Y++
+		}
+	}
+}
+
+// face provides a function to get the font face by given font settings, the
+// fallback font will be used if the font family has not been specified by
+// the Fonts option.
+func (r *imageRenderer) face(fnt *Font) (font.Face, error) {
+	key := imageFaceKey{family: strings.ToLower(fnt.Family), size: fnt.Size, bold: fnt.Bold, italic: fnt.Italic}
+	if key.size <= 0 {
+		key.size = 11
+	}
+	if fnt.VertAlign == "superscript" || fnt.VertAlign == "subscript" {
+		key.size = key.size * 2 / 3
+	}
+	if face, ok := r.faces[key]; ok {
+		return face, nil
+	}
+	fontFile, ok := r.fonts[key.family]
+	if !ok {
+		idx := 0
+		if key.bold {
+			idx++
+		}
+		if key.italic {
+			idx += 2
+		}
+		fontFile = imageFallbackFonts[idx]
+	}
+	face, err := opentype.NewFace(fontFile, &opentype.FaceOptions{Size: key.size, DPI: 96 * r.opts.Scale, Hinting: font.HintingFull})
+	if err != nil {
+		return face, err
+	}
+	r.faces[key] = face
+	return face, err
+}
+
+// textRuns provides a function to get the text runs of the cell for
+// rendering, the fonts of the rich text runs will be inherited from the font
+// of the cell style.
+func (r *imageRenderer) textRuns(cell *renderCell) ([]imageTextRun, font.Face, error) {
+	base := cell.style.Font
+	if base == nil {
+		base = &Font{}
+	}
+	baseFace, err := r.face(base)
+	if err != nil {
+		return nil, baseFace, err
+	}
+	richText := []RichTextRun{{Text: cell.text}}
+	for _, run := range cell.runs {
+		if run.Font != nil {
+			richText = cell.runs
+			break
+		}
+	}
+	var runs []imageTextRun
+	for _, rt := range richText {
+		fnt := inheritFont(base, rt.Font)
+		face, err := r.face(&fnt)
+		if err != nil {
+			return runs, baseFace, err
+		}
+		run := imageTextRun{text: rt.Text, face: face, color: color.RGBA{A: 0xFF}, strike: fnt.Strike}
+		if c, ok := imageColor(r.f.fontColor(&fnt)); ok {
+			run.color = c
+		}
+		run.underline = fnt.Underline == "single" || fnt.Underline == "double" ||
+			fnt.Underline == "singleAccounting" || fnt.Underline == "doubleAccounting"
+		if fnt.VertAlign == "superscript" {
+			run.shift = baseFace.Metrics().Ascent.Ceil() / 3
+		}
+		if fnt.VertAlign == "subscript" {
+			run.shift = -baseFace.Metrics().Descent.Ceil()
+		}
+		runs = append(runs, run)
+	}
+	return runs, baseFace, err
+}
+
+// inheritFont returns the font of the rich text run, the font family, size
+// and color will be inherited from the base font if not been specified.
+func inheritFont(base, runFont *Font) Font {
+	if runFont == nil {
+		return *base
+	}
+	fnt := *runFont
+	if fnt.Family == "" {
+		fnt.Family = base.Family
+	}
+	if fnt.Size == 0 {
+		fnt.Size = base.Size
+	}
+	if fnt.Color == "" && fnt.ColorTheme == nil && fnt.ColorIndexed == 0 {
+		fnt.Color, fnt.ColorTheme, fnt.ColorIndexed, fnt.ColorTint = base.Color, base.ColorTheme, base.ColorIndexed, base.ColorTint
+	}
+	return fnt
+}
+
+// wrapText provides a function to break the text runs into lines by the line
+// breaks, and wrap the lines by the maximum width if the wrap is enabled.
+func wrapText(runs []imageTextRun, baseFace font.Face, maxWidth int, wrap bool) []imageTextLine {
+	lines := []imageTextLine{{}}
+	appendRun := func(run imageTextRun) {
+		line := &lines[len(lines)-1]
+		run.width = font.MeasureString(run.face, run.text).Ceil()
+		if wrap && line.width > 0 && line.width+run.width > maxWidth {
+			lines = append(lines, imageTextLine{})
+			line = &lines[len(lines)-1]
+			run.text = strings.TrimLeft(run.text, " ")
+			run.width = font.MeasureString(run.face, run.text).Ceil()
+		}
+		line.runs, line.width = append(line.runs, run), line.width+run.width
+	}
+	for _, run := range runs {
+		for i, text := range strings.Split(strings.ReplaceAll(run.text, "\r\n", "\n"), "\n") {
+			if i > 0 {
+				lines = append(lines, imageTextLine{})
+			}
+			words := []string{text}
+			if wrap {
+				words = strings.SplitAfter(text, " ")
+			}
+			for _, word := range words {
+				if word == "" {
+					continue
+				}
+				piece := run
+				piece.text = word
+				appendRun(piece)
+			}
+		}
+	}
+	for i := range lines {
+		line := &lines[i]
+		line.ascent, line.descent = baseFace.Metrics().Ascent.Ceil(), baseFace.Metrics().Descent.Ceil()
+		for _, run := range line.runs {
+			line.ascent = int(math.Max(float64(line.ascent), float64(run.face.Metrics().Ascent.Ceil()+run.shift)))
+			line.descent = int(math.Max(float64(line.descent), float64(run.face.Metrics().Descent.Ceil()-run.shift)))
+		}
+	}
+	return lines
+}
+
+// drawTexts provides a function to draw the texts of the cells by the fonts
+// and alignments, the texts will be clipped by the rectangles of the cells.
+func (r *imageRenderer) drawTexts() error {
+	padding := int(math.Round(2 * r.opts.Scale))
+	return r.eachCell(func(cell *renderCell, rect image.Rectangle) error {
+		if name, _ := CoordinatesToCellName(cell.col, cell.row); cell.text == "" || r.cells[name] {
+			return nil
+		}
+		runs, baseFace, err := r.textRuns(cell)
+		if err != nil {
+			return err
+		}
+		horizontal, vertical, indent, wrap := "", "bottom", 0, false
+		if alignment := cell.style.Alignment; alignment != nil {
+			horizontal, indent, wrap = alignment.Horizontal, int(math.Round(float64(alignment.Indent*9)*r.opts.Scale)), alignment.WrapText
+			if alignment.Vertical != "" {
+				vertical = alignment.Vertical
+			}
+		}
+		switch htmlDefaultAlignment(cell) {
+		case "text-align:right;":
+			horizontal = "right"
+		case "text-align:center;":
+			horizontal = "center"
+		}
+		lines := wrapText(runs, baseFace, rect.Dx()-padding*2-indent, wrap)
+		var height int
+		for _, line := range lines {
+			height += line.ascent + line.descent
+		}
+		y := rect.Max.Y - padding - height
+		switch vertical {
+		case "top":
+			y = rect.Min.Y + padding
+		case "center", "justify", "distributed":
+			y = rect.Min.Y + (rect.Dy()-height)/2
+		}
+		dst := r.img.SubImage(rect).(*image.RGBA)
+		for _, line := range lines {
+			x := rect.Min.X + padding + indent
+			switch horizontal {
+			case "right":
+				x = rect.Max.X - padding - line.width - indent
+			case "center", "centerContinuous", "distributed", "justify":
+				x = rect.Min.X + (rect.Dx()-line.width)/2
+			}
+			y += line.ascent
+			for _, run := range line.runs {
+				r.drawTextRun(dst, run, x, y)
+				x += run.width
+			}
+			y += line.descent
+		}
+		return nil
+	})
+}
+
+// drawTextRun provides a function to draw the text run at the baseline
+// position with the underline and strikethrough decorations.
+func (r *imageRenderer) drawTextRun(dst *image.RGBA, run imageTextRun, x, baseline int) {
+	src := image.NewUniform(run.color)
+	d := font.Drawer{Dst: dst, Src: src, Face: run.face, Dot: fixed.P(x, baseline-run.shift)}
+	d.DrawString(run.text)
+	thickness := int(math.Max(1, math.Round(r.opts.Scale)))
+	metrics := run.face.Metrics()
+	if run.underline {
+		y := baseline - run.shift + int(math.Max(1, float64(metrics.Descent.Ceil()/3)))
+		draw.Draw(dst, image.Rect(x, y, x+run.width, y+thickness), src, image.Point{}, draw.Over)
+	}
+	if run.strike {
+		y := baseline - run.shift - metrics.Ascent.Ceil()/3
+		draw.Draw(dst, image.Rect(x, y, x+run.width, y+thickness), src, image.Point{}, draw.Over)
+	}
+}
+
+// columnX returns the horizontal position of the left edge of the column in
+// pixels by given column number, the position will be extrapolated for the
+// columns after the range.
+func (r *imageRenderer) columnX(col int) (int, error) {
+	cols := r.rng.cols
+	if last := cols[len(cols)-1]; col > last {
+		x := float64(r.colX[len(r.colX)-1])
+		for c := last + 1; c < col; c++ {
+			name, _ := ColumnNumberToName(c)
+			visible, err := r.f.GetColVisible(r.sheet, name)
+			if err != nil {
+				return 0, err
+			}
+			if !visible {
+				continue
+			}
+			width, err := r.f.GetColWidth(r.sheet, name)
+			if err != nil {
+				return 0, err
+			}
+			x += colWidthToPixels(width) * r.opts.Scale
+		}
+		return int(math.Round(x)), nil
+	}
+	return r.colX[sort.SearchInts(cols, col)], nil
+}
+
+// rowTop returns the vertical position of the top edge of the row in pixels by
+// given row number, the position will be extrapolated for the rows after the
+// range.
+func (r *imageRenderer) rowTop(row int) (int, error) {
+	rows := r.rng.rows
+	if last := rows[len(rows)-1]; row > last {
+		y := float64(r.rowY[len(r.rowY)-1])
+		for rr := last + 1; rr < row; rr++ {
+			if r.rng.hiddenRows[rr] {
+				continue
+			}
+			height, err := r.f.GetRowHeight(r.sheet, rr)
+			if err != nil {
+				return 0, err
+			}
+			y += convertRowHeightToPixels(height) * r.opts.Scale
+		}
+		return int(math.Round(y)), nil
+	}
+	return r.rowY[sort.SearchInts(rows, row)], nil
+}
+
+// drawPicture provides a function to draw the picture into the rectangle,
+// the pictures in the unsupported formats will be ignored.
+func (r *imageRenderer) drawPicture(file []byte, rect image.Rectangle, keepAspectRatio bool) {
+	src, _, err := image.Decode(bytes.NewReader(file))
+	if err != nil || rect.Empty() {
+		return
+	}
+	if keepAspectRatio {
+		bounds := src.Bounds()
+		ratio := math.Min(float64(rect.Dx())/float64(bounds.Dx()), float64(rect.Dy())/float64(bounds.Dy()))
+		w, h := int(float64(bounds.Dx())*ratio), int(float64(bounds.Dy())*ratio)
+		rect = image.Rect(rect.Min.X+(rect.Dx()-w)/2, rect.Min.Y+(rect.Dy()-h)/2, 0, 0)
+		rect.Max = rect.Min.Add(image.Pt(w, h))
+	}
+	draw.BiLinear.Scale(r.img, rect, src, src.Bounds(), draw.Over, nil)
+}
+
+// drawPictures provides a function to draw the pictures which placed over
+// the cells and embedded in the cells of the range.
+func (r *imageRenderer) drawPictures() error {
+	if len(r.rng.cols) == 0 || len(r.rng.rows) == 0 {
+		return nil
+	}
+	if err := r.eachCell(func(cell *renderCell, rect image.Rectangle) error {
+		name, _ := CoordinatesToCellName(cell.col, cell.row)
+		if !r.cells[name] {
+			return nil
+		}
+		pics, err := r.f.getCellImages(r.sheet, name)
+		for _, pic := range pics {
+			r.drawPicture(pic.File, rect, true)
+		}
+		return err
+	}); err != nil {
+		return err
+	}
+	anchors, err := r.f.getPictureAnchors(r.sheet)
+	if err != nil {
+		return err
+	}
+	for _, anchor := range anchors {
+		col, row := anchor.from.Col+1, anchor.from.Row+1
+		if col < r.rng.cols[0] || col > r.rng.cols[len(r.rng.cols)-1] ||
+			row < r.rng.rows[0] || row > r.rng.rows[len(r.rng.rows)-1] {
+			continue
+		}
+		if err = r.drawPictureAnchor(anchor); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// drawPictureAnchor provides a function to draw the picture which placed
+// over the cells by given picture anchor.
+func (r *imageRenderer) drawPictureAnchor(anchor pictureAnchor) error {
+	x, err := r.columnX(anchor.from.Col + 1)
+	if err != nil {
+		return err
+	}
+	y, err := r.rowTop(anchor.from.Row + 1)
+	if err != nil {
+		return err
+	}
+	rect := image.Rect(x, y, x, y).Add(image.Pt(
+		int(float64(anchor.from.ColOff)/float64(EMU)*r.opts.Scale),
+		int(float64(anchor.from.RowOff)/float64(EMU)*r.opts.Scale)))
+	if anchor.to == nil {
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(anchor.file)); err == nil {
+			rect.Max = rect.Min.Add(image.Pt(int(float64(cfg.Width)*r.opts.Scale), int(float64(cfg.Height)*r.opts.Scale)))
+		}
+		r.drawPicture(anchor.file, rect, false)
+		return err
+	}
+	if x, err = r.columnX(anchor.to.Col + 1); err != nil {
+		return err
+	}
+	if y, err = r.rowTop(anchor.to.Row + 1); err != nil {
+		return err
+	}
+	rect.Max = image.Pt(x+int(float64(anchor.to.ColOff)/float64(EMU)*r.opts.Scale),
+		y+int(float64(anchor.to.RowOff)/float64(EMU)*r.opts.Scale))
+	r.drawPicture(anchor.file, rect, false)
+	return err
+}
@@ -0,0 +1,131 @@
+package excelize
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/font/gofont/gomono"
+)
+
+func TestRenderImage(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Hi"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B1", 12.5))
+	assert.NoError(t, f.SetCellValue("Sheet1", "A3", "Merged cell with wrapped text"))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A2", []RichTextRun{
+		{Text: "Rich", Font: &Font{Bold: true, Italic: true, Color: "FF0000", Underline: "single"}},
+		{Text: " text\nline", Font: &Font{Strike: true, VertAlign: "superscript"}},
+		{Text: "2", Font: &Font{VertAlign: "subscript"}},
+	}))
+	fillStyle, err := f.NewStyle(&Style{
+		Font:   &Font{Bold: true},
+		Fill:   Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}},
+		Border: []Border{{Type: "bottom", Style: 1}, {Type: "top", Style: 3, Color: "0000FF"}, {Type: "left", Style: 6}, {Type: "right", Style: 4}, {Type: "diagonalUp", Style: 1}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "A1", fillStyle))
+	wrapStyle, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center", Vertical: "center", WrapText: true}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A3", "A3", wrapStyle))
+	indentStyle, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "left", Vertical: "top", Indent: 1}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B1", "B1", indentStyle))
+	assert.NoError(t, f.MergeCell("Sheet1", "A3", "B4"))
+	assert.NoError(t, f.AddPicture("Sheet1", "D2", filepath.Join("test", "images", "excel.png"), &GraphicOptions{OffsetX: 5, OffsetY: 5, ScaleX: 0.1, ScaleY: 0.1}))
+	assert.NoError(t, f.AddPicture("Sheet1", "D5", filepath.Join("test", "images", "excel.jpg"), nil))
+	f.Pkg.Store("xl/media/image2.jpg", []byte("unsupported"))
+
+	img, err := f.RenderImage("Sheet1", "A1:E6")
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 345, 108), img.Bounds())
+	// Test the fill color, borders and texts of the cells
+	assert.Equal(t, color.RGBA{R: 0xFF, A: 0xFF}, img.RGBAAt(60, 8))
+	assert.Equal(t, color.RGBA{A: 0xFF}, img.RGBAAt(30, 17))
+	assert.Equal(t, color.RGBA{B: 0xFF, A: 0xFF}, img.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{A: 0xFF}, img.RGBAAt(2, 8))
+	assert.True(t, hasNonWhitePixels(img, image.Rect(69, 0, 138, 18)))
+	assert.False(t, hasNonWhitePixels(img, image.Rect(138, 0, 207, 18)))
+	assert.True(t, hasNonWhitePixels(img, image.Rect(0, 36, 138, 72)))
+	// Test the picture placed over the cells
+	assert.True(t, hasNonWhitePixels(img, image.Rect(207, 18, 345, 72)))
+
+	// Test render the range with grid lines in double resolution
+	img, err = f.RenderImage("Sheet1", "A1:E6", ImageOptions{ShowGridLines: true, Scale: 2, Fonts: map[string][]byte{"Calibri": gomono.TTF}})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 690, 216), img.Bounds())
+	assert.Equal(t, imageGridLineColor, img.RGBAAt(137, 200))
+
+	// Test render the range with hidden columns and the picture beyond the range
+	assert.NoError(t, f.SetColVisible("Sheet1", "B", false))
+	assert.NoError(t, f.SetColVisible("Sheet1", "F", false))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 3, false))
+	img, err = f.RenderImage("Sheet1", "A1:D2", ImageOptions{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 207, 36), img.Bounds())
+
+	// Test render the range without visible cells
+	img, err = f.RenderImage("Sheet1", "B3")
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1, 1), img.Bounds())
+
+	// Test render the range as the PNG image
+	var buf bytes.Buffer
+	assert.NoError(t, f.RenderPNG("Sheet1", "A1:A2", &buf))
+	decoded, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 69, 36), decoded.Bounds())
+	assert.EqualError(t, f.RenderPNG("Sheet1", "A1", errWriter{}), "write error")
+
+	// Test render image with invalid range reference, font data and worksheet
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), f.RenderPNG("Sheet1", "A:B", &buf))
+	_, err = f.RenderImage("Sheet1", "A1", ImageOptions{Fonts: map[string][]byte{"Arial": {0}}})
+	assert.EqualError(t, err, "sfnt: invalid bounds")
+	_, err = f.RenderImage("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+
+	// Test render image with the picture embedded in the cell
+	f = NewFile()
+	assert.NoError(t, f.AddPicture("Sheet1", "A1", filepath.Join("test", "images", "excel.png"), nil))
+	f.Pkg.Store(defaultXMLMetadata, []byte(`<metadata><valueMetadata count="1"><bk><rc t="1" v="0"/></bk></valueMetadata></metadata>`))
+	f.Pkg.Store(defaultXMLRdRichValuePart, []byte(`<rvData count="1"><rv s="0"><v>0</v><v>5</v></rv></rvData>`))
+	f.Pkg.Store(defaultXMLRdRichValueRel, []byte(`<richValueRels><rel r:id="rId1"/></richValueRels>`))
+	f.Pkg.Store(defaultXMLRdRichValueRelRels, []byte(fmt.Sprintf(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="%s" Target="../media/image1.png"/></Relationships>`, SourceRelationshipImage)))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData = xlsxSheetData{Row: []xlsxRow{
+		{R: 2, C: []xlsxC{{R: "B2", T: "e", V: formulaErrorVALUE, Vm: uintPtr(1)}}},
+	}}
+	img, err = f.RenderImage("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.True(t, hasNonWhitePixels(img, img.Bounds()))
+	// Test render image with unsupported charset drawing
+	f.Drawings.Delete("xl/drawings/drawing1.xml")
+	f.Pkg.Store("xl/drawings/drawing1.xml", MacintoshCyrillicCharset)
+	_, err = f.RenderImage("Sheet1", "B2")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test render image with unsupported charset rich value part
+	f.Pkg.Store(defaultXMLRdRichValuePart, MacintoshCyrillicCharset)
+	_, err = f.RenderImage("Sheet1", "B2")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
+// hasNonWhitePixels returns if the rectangle of the image contains the
+// pixels which are not white.
+func hasNonWhitePixels(img *image.RGBA, rect image.Rectangle) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if img.RGBAAt(x, y) != (color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}) {
+				return true
+			}
+		}
+	}
+	return false
+}
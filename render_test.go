@@ -0,0 +1,123 @@
+package excelize
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderHTML(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Price", "C1": "Hidden", "D1": "Paid",
+		"A2": "<Apple> & \"Pear\"\nFruit", "B2": 1234.5, "C2": "x", "D2": true,
+		"A4": "Merged",
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A3", []RichTextRun{
+		{Text: "Bold", Font: &Font{Bold: true, Color: "FF0000"}},
+		{Text: " plain"},
+		{Text: "2", Font: &Font{VertAlign: "superscript", Underline: "single", Strike: true}},
+	}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "D3", "1/0"))
+	assert.NoError(t, f.SetCellValue("Sheet1", "B3", 0.25))
+	headerStyle, err := f.NewStyle(&Style{
+		Font:      &Font{Bold: true, Italic: true, Family: "Arial", Size: 12, Color: "FFFFFF"},
+		Fill:      Fill{Type: "pattern", Pattern: 1, Color: []string{"4472C4"}},
+		Border:    []Border{{Type: "bottom", Color: "000000", Style: 6}, {Type: "top", Style: 2}, {Type: "left", Color: "bad", Style: 20}},
+		Alignment: &Alignment{Horizontal: "center", Vertical: "center", WrapText: true, Indent: 1},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A1", "D1", headerStyle))
+	numStyle, err := f.NewStyle(&Style{NumFmt: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B2", "B2", numStyle))
+	percentStyle, err := f.NewStyle(&Style{NumFmt: 9, Alignment: &Alignment{Horizontal: "left"}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "B3", "B3", percentStyle))
+	assert.NoError(t, f.SetColVisible("Sheet1", "C", false))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 20))
+	assert.NoError(t, f.SetRowHeight("Sheet1", 1, 30))
+	assert.NoError(t, f.MergeCell("Sheet1", "A4", "D5"))
+	assert.NoError(t, f.SetRowVisible("Sheet1", 5, false))
+
+	table, err := f.RenderHTML("Sheet1", "A1:D5")
+	assert.NoError(t, err)
+	header := "border-top:2px solid #000000;border-bottom:3px double #000000;font-family:'Arial';font-size:12pt;font-weight:bold;font-style:italic;color:#FFFFFF;" +
+		"text-align:center;vertical-align:middle;padding-left:9px;white-space:pre-wrap;"
+	header = "background-color:#4472C4;" + header
+	general := "font-family:'Calibri';font-size:11pt;color:#000000;vertical-align:bottom;white-space:nowrap;"
+	assert.Equal(t, "<table style=\"border-collapse:collapse\">\n"+
+		"<colgroup><col style=\"width:145px\"><col style=\"width:69px\"><col style=\"width:69px\"></colgroup>\n<tbody>\n"+
+		"<tr style=\"height:30pt\"><td style=\""+header+"\">Name</td><td style=\""+header+"\">Price</td><td style=\""+header+"\">Paid</td></tr>\n"+
+		"<tr style=\"height:15pt\"><td style=\""+general+"\">&lt;Apple&gt; &amp; &#34;Pear&#34;<br>Fruit</td>"+
+		"<td style=\""+general+"text-align:right;\">1,234.50</td><td style=\""+general+"text-align:center;\">TRUE</td></tr>\n"+
+		"<tr style=\"height:15pt\"><td style=\""+general+"\"><span style=\"font-weight:bold;color:#FF0000;\">Bold</span> plain"+
+		"<span style=\"text-decoration:underline line-through;\">2</span></td>"+
+		"<td style=\"font-family:'Calibri';font-size:11pt;color:#000000;text-align:left;vertical-align:bottom;white-space:nowrap;\">25%</td><td style=\""+general+"\"></td></tr>\n"+
+		"<tr style=\"height:15pt\"><td colspan=\"3\" style=\""+general+"\">Merged</td></tr>\n"+
+		"</tbody>\n</table>", table)
+
+	// Test render the used range with raw cell values and grid lines
+	table, err = f.RenderHTML("Sheet1", "", HTMLOptions{RawCellValue: true, ShowGridLines: true})
+	assert.NoError(t, err)
+	assert.Contains(t, table, "<td style=\"border-top:1px solid #D4D4D4;border-right:1px solid #D4D4D4;border-bottom:1px solid #D4D4D4;border-left:1px solid #D4D4D4;"+general+"text-align:right;\">1234.5</td>")
+	assert.Equal(t, 4, strings.Count(table, "<tr "))
+
+	// Test render the single cell and the range with partial merged cells
+	table, err = f.RenderHTML("Sheet1", "B2")
+	assert.NoError(t, err)
+	assert.Equal(t, "<table style=\"border-collapse:collapse\">\n<colgroup><col style=\"width:69px\"></colgroup>\n<tbody>\n"+
+		"<tr style=\"height:15pt\"><td style=\""+general+"text-align:right;\">1,234.50</td></tr>\n</tbody>\n</table>", table)
+	table, err = f.RenderHTML("Sheet1", "D5:B4")
+	assert.NoError(t, err)
+	assert.Contains(t, table, "<td colspan=\"2\" style=\""+general+"\">Merged</td>")
+
+	// Test render the range with the rows which not exist in the worksheet
+	table, err = f.RenderHTML("Sheet1", "B6:B8")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(table, "<tr "))
+	table, err = f.RenderHTML("Sheet1", "B1:B8")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, strings.Count(table, "<tr "))
+
+	// Test render the range with the first merged column hidden
+	assert.NoError(t, f.SetColVisible("Sheet1", "A", false))
+	table, err = f.RenderHTML("Sheet1", "A4:A5")
+	assert.NoError(t, err)
+	assert.Equal(t, "<table style=\"border-collapse:collapse\">\n<colgroup></colgroup>\n<tbody>\n<tr style=\"height:15pt\"></tr>\n</tbody>\n</table>", table)
+
+	// Test render HTML with invalid range reference
+	_, err = f.RenderHTML("Sheet1", "A:B")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test render HTML on not exists worksheet
+	_, err = f.RenderHTML("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	// Test render HTML with invalid merged cell reference
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).MergeCells = &xlsxMergeCells{Cells: []*xlsxMergeCell{{Ref: "A1"}}}
+	_, err = f.RenderHTML("Sheet1", "A1")
+	assert.Equal(t, ErrParameterInvalid, err)
+	_, err = f.RenderHTML("Sheet1", "")
+	assert.Equal(t, ErrParameterInvalid, err)
+	ws.(*xlsxWorksheet).MergeCells = nil
+	ws.(*xlsxWorksheet).SheetData.Row[0].C[0].R = "A"
+	_, err = f.RenderHTML("Sheet1", "")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	assert.NoError(t, f.Close())
+
+	// Test render HTML with the theme colors and unsupported charset styles
+	f, err = OpenFile(filepath.Join("test", "Book1.xlsx"))
+	assert.NoError(t, err)
+	assert.Equal(t, "", f.fontColor(&Font{}))
+	assert.Equal(t, "FF0000", f.fontColor(&Font{ColorIndexed: 2}))
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	_, err = f.RenderHTML("Sheet1", "A1")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
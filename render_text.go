@@ -0,0 +1,365 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// TextOptions directly maps the settings of rendering the worksheet range as
+// the text table.
+//
+// Format specifies the format of the text table, the possible values are
+// "markdown", "ascii" and "box". The table will be rendered in the GitHub
+// Flavored Markdown format by default, the "ascii" format draws the borders
+// with the ASCII characters, and the "box" format draws the borders with the
+// Unicode box-drawing characters.
+//
+// RawCellValue specifies if render the raw cell values without applying the
+// number formats.
+type TextOptions struct {
+	Format       string
+	RawCellValue bool
+}
+
+// textCell directly maps the cell of the text table, the lines of the cell
+// are the lines of the cell value, and the cells covered by the merged cells
+// map to the first cell of the merged cells.
+type textCell struct {
+	row, col int
+	lines    []string
+	align    string
+}
+
+// textTable directly maps the cells and the widths of the columns of the
+// text table, the widths are in the number of the monospaced character
+// cells.
+type textTable struct {
+	cells  [][]*textCell
+	widths []int
+}
+
+// textBoxJunctions defined the box-drawing characters for the junctions of
+// the borders, the index of the slice is the bit mask of the up, down, left
+// and right arms of the junction.
+var textBoxJunctions = []rune{
+	' ', '─', '─', '─', '│', '┌', '┐', '┬', '│', '└', '┘', '┴', '│', '├', '┤', '┼',
+}
+
+// RenderText provides a function to render the worksheet range as the text
+// table by given worksheet name and range reference, the used range of the
+// worksheet will be rendered if the range reference is empty. The first row
+// of the range will be rendered as the header row. The cell values will be
+// formatted by the number formats, and aligned by the horizontal alignments
+// of the cell styles, the numbers are right-aligned by default. The merged
+// cells will be collapsed into a single cell which spans the merged columns
+// in the ASCII and box-drawing formats, and the value of the merged cell
+// will be placed in the first cell of the merged cells in the Markdown
+// format. The hidden rows and columns will be omitted. The widths of the
+// columns are calculated by the display widths of the cell values, the East
+// Asian wide characters occupy two character cells. For example, render the
+// range A1:D10 on Sheet1 with box-drawing characters:
+//
+//	table, err := f.RenderText("Sheet1", "A1:D10", excelize.TextOptions{
+//	    Format: "box",
+//	})
+func (f *File) RenderText(sheet, rangeRef string, opts ...TextOptions) (string, error) {
+	var options TextOptions
+	if len(opts) > 0 {
+		options = opts[len(opts)-1]
+	}
+	if options.Format == "" {
+		options.Format = "markdown"
+	}
+	if inStrSlice([]string{"markdown", "ascii", "box"}, options.Format, true) == -1 {
+		return "", newUnsupportedTextFormatError(options.Format)
+	}
+	rng, err := f.newRenderRange(sheet, rangeRef, options.RawCellValue)
+	if err != nil || len(rng.rows) == 0 || len(rng.cols) == 0 {
+		return "", err
+	}
+	t := newTextTable(rng, options.Format == "markdown")
+	if options.Format == "markdown" {
+		return t.markdown(), err
+	}
+	return t.grid(options.Format == "box"), err
+}
+
+// newTextTable provides a function to prepare the cells of the text table
+// by given render range, the line breaks and the pipe characters in the cell
+// values will be escaped for the Markdown table.
+func newTextTable(rng *renderRange, markdown bool) *textTable {
+	t := &textTable{cells: make([][]*textCell, len(rng.rows)), widths: make([]int, len(rng.cols))}
+	for i := range t.cells {
+		t.cells[i] = make([]*textCell, len(rng.cols))
+	}
+	var spans [][2]int
+	for i, cells := range rng.cells {
+		for j, cell := range cells {
+			if cell == nil {
+				continue
+			}
+			text := strings.NewReplacer("\r\n", "\n", "\r", "\n", "\t", " ").Replace(cell.text)
+			if markdown {
+				text = strings.NewReplacer("|", "\\|", "\n", "<br>").Replace(text)
+			}
+			tc := &textCell{row: i, col: j, lines: strings.Split(text, "\n"), align: textAlignment(cell)}
+			for k := 0; k < cell.rowSpan*cell.colSpan; k++ {
+				if markdown && k > 0 {
+					t.cells[i+k/cell.colSpan][j+k%cell.colSpan] = &textCell{row: i, col: j + k%cell.colSpan, lines: []string{""}}
+					continue
+				}
+				t.cells[i+k/cell.colSpan][j+k%cell.colSpan] = tc
+			}
+			if cell.colSpan > 1 && !markdown {
+				spans = append(spans, [2]int{i, j})
+				continue
+			}
+			for _, line := range tc.lines {
+				t.widths[j] = int(math.Max(float64(t.widths[j]), float64(textWidth(line))))
+			}
+		}
+	}
+	for _, span := range spans {
+		tc := t.cells[span[0]][span[1]]
+		last := span[1]
+		for last+1 < len(t.widths) && t.cells[span[0]][last+1] == tc {
+			last++
+		}
+		spanWidth := 3 * (last - span[1])
+		for j := span[1]; j <= last; j++ {
+			spanWidth += t.widths[j]
+		}
+		for _, line := range tc.lines {
+			if w := textWidth(line); w > spanWidth {
+				t.widths[last] += w - spanWidth
+				spanWidth = w
+			}
+		}
+	}
+	return t
+}
+
+// textAlignment returns the horizontal alignment of the cell in the text
+// table, the possible values are "left", "center" and "right", returns empty
+// string if the cell is left-aligned by default.
+func textAlignment(cell *renderCell) string {
+	if cell.style.Alignment != nil {
+		switch cell.style.Alignment.Horizontal {
+		case "left", "fill", "justify":
+			return "left"
+		case "center", "centerContinuous", "distributed":
+			return "center"
+		case "right":
+			return "right"
+		}
+	}
+	switch htmlDefaultAlignment(cell) {
+	case "text-align:right;":
+		return "right"
+	case "text-align:center;":
+		return "center"
+	}
+	return ""
+}
+
+// textWidth returns the display width of the text in the number of the
+// monospaced character cells, the East Asian wide and fullwidth characters
+// occupy two character cells, and the combining marks and the control
+// characters occupy none.
+func textWidth(text string) int {
+	var w int
+	for _, r := range text {
+		if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc) {
+			continue
+		}
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
+
+// textPad returns the text padded with spaces to the display width by given
+// horizontal alignment.
+func textPad(text string, w int, align string) string {
+	padding := w - textWidth(text)
+	if padding <= 0 {
+		return text
+	}
+	switch align {
+	case "right":
+		return strings.Repeat(" ", padding) + text
+	case "center":
+		return strings.Repeat(" ", padding/2) + text + strings.Repeat(" ", padding-padding/2)
+	}
+	return text + strings.Repeat(" ", padding)
+}
+
+// markdown provides a function to render the text table in the GitHub
+// Flavored Markdown format, the alignment of each column is determined by the
+// first non-empty cell after the header row in the column.
+func (t *textTable) markdown() string {
+	var buf strings.Builder
+	aligns := make([]string, len(t.widths))
+	for j := range t.widths {
+		t.widths[j] = int(math.Max(float64(t.widths[j]), 3))
+		aligns[j] = t.cells[0][j].align
+		for i := 1; i < len(t.cells); i++ {
+			if tc := t.cells[i][j]; tc.lines[0] != "" {
+				aligns[j] = tc.align
+				break
+			}
+		}
+	}
+	for i, cells := range t.cells {
+		buf.WriteString("|")
+		for j, tc := range cells {
+			buf.WriteString(" " + textPad(tc.lines[0], t.widths[j], tc.align) + " |")
+		}
+		buf.WriteString("\n")
+		if i > 0 {
+			continue
+		}
+		buf.WriteString("|")
+		for j, align := range aligns {
+			delimiter := strings.Repeat("-", t.widths[j])
+			switch align {
+			case "left":
+				delimiter = ":" + delimiter[1:]
+			case "center":
+				delimiter = ":" + delimiter[2:] + ":"
+			case "right":
+				delimiter = delimiter[1:] + ":"
+			}
+			buf.WriteString(" " + delimiter + " |")
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// grid provides a function to render the text table with the borders drawn
+// by the ASCII characters or the box-drawing characters, the border lines
+// will be drawn at the top, after the header row and at the bottom of the
+// table.
+func (t *textTable) grid(box bool) string {
+	var buf strings.Builder
+	vertical := "|"
+	if box {
+		vertical = "│"
+	}
+	t.writeBorder(&buf, 0, box)
+	for i, cells := range t.cells {
+		height := 1
+		for _, tc := range cells {
+			if tc.row == i {
+				height = int(math.Max(float64(height), float64(len(tc.lines))))
+			}
+		}
+		for k := 0; k < height; k++ {
+			buf.WriteString(vertical)
+			for j := 0; j < len(cells); {
+				tc, end, w := cells[j], j+1, t.widths[j]
+				for ; end < len(cells) && cells[end] == tc; end++ {
+					w += t.widths[end] + 3
+				}
+				var line string
+				if tc.row == i && k < len(tc.lines) {
+					line = tc.lines[k]
+				}
+				buf.WriteString(" " + textPad(line, w, tc.align) + " " + vertical)
+				j = end
+			}
+			buf.WriteString("\n")
+		}
+		if i == 0 && len(t.cells) > 1 {
+			t.writeBorder(&buf, 1, box)
+		}
+	}
+	t.writeBorder(&buf, len(t.cells), box)
+	return buf.String()
+}
+
+// writeBorder provides a function to write the horizontal border line above
+// the row by given row index, the border segments inside the merged cells
+// will be omitted.
+func (t *textTable) writeBorder(buf *strings.Builder, row int, box bool) {
+	for j := 0; j <= len(t.widths); j++ {
+		buf.WriteRune(t.junction(row, j, box))
+		if j == len(t.widths) {
+			break
+		}
+		segment := ' '
+		if t.hasHorizontal(row, j) {
+			segment = '-'
+			if box {
+				segment = '─'
+			}
+		}
+		buf.WriteString(strings.Repeat(string(segment), t.widths[j]+2))
+	}
+	buf.WriteString("\n")
+}
+
+// hasVertical returns if the vertical border exists on the left of the
+// column by given row and column index.
+func (t *textTable) hasVertical(row, col int) bool {
+	if row < 0 || row >= len(t.cells) {
+		return false
+	}
+	return col == 0 || col == len(t.widths) || t.cells[row][col-1] != t.cells[row][col]
+}
+
+// hasHorizontal returns if the horizontal border exists above the row by
+// given row and column index.
+func (t *textTable) hasHorizontal(row, col int) bool {
+	return row == 0 || row == len(t.cells) || t.cells[row-1][col] != t.cells[row][col]
+}
+
+// junction returns the border character at the junction of the horizontal
+// border above the row and the vertical border on the left of the column by
+// given row and column index.
+func (t *textTable) junction(row, col int, box bool) rune {
+	var mask int
+	if t.hasVertical(row-1, col) {
+		mask |= 8
+	}
+	if t.hasVertical(row, col) {
+		mask |= 4
+	}
+	if col > 0 && t.hasHorizontal(row, col-1) {
+		mask |= 2
+	}
+	if col < len(t.widths) && t.hasHorizontal(row, col) {
+		mask |= 1
+	}
+	if box {
+		return textBoxJunctions[mask]
+	}
+	switch {
+	case mask&12 != 0 && mask&3 != 0:
+		return '+'
+	case mask&3 != 0:
+		return '-'
+	case mask&12 != 0:
+		return '|'
+	}
+	return ' '
+}
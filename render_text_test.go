@@ -0,0 +1,103 @@
+package excelize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderText(t *testing.T) {
+	f := NewFile()
+	for cell, value := range map[string]interface{}{
+		"A1": "Name", "B1": "Qty", "C1": "Note",
+		"A2": "日本", "B2": 12, "C2": "a|b\nc",
+		"A3": "Total", "B3": 3.5, "C3": true,
+	} {
+		assert.NoError(t, f.SetCellValue("Sheet1", cell, value))
+	}
+	centerStyle, err := f.NewStyle(&Style{Alignment: &Alignment{Horizontal: "center"}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "A2", "A2", centerStyle))
+
+	// Test render the range in the Markdown format by default
+	table, err := f.RenderText("Sheet1", "A1:C3")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"| Name  | Qty | Note      |",
+		"| :---: | --: | --------- |",
+		"| 日本  |  12 | a\\|b<br>c |",
+		"| Total | 3.5 |   TRUE    |",
+	}, "\n")+"\n", table)
+
+	// Test render the used range with merged cells in all formats
+	assert.NoError(t, f.MergeCell("Sheet1", "A3", "B3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C2", "C3"))
+	table, err = f.RenderText("Sheet1", "", TextOptions{Format: "markdown"})
+	assert.NoError(t, err)
+	assert.Equal(t, "| Total |     |           |\n", table[strings.LastIndex(table[:len(table)-1], "\n")+1:])
+	table, err = f.RenderText("Sheet1", "", TextOptions{Format: "ascii"})
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"+------+-----+------+",
+		"| Name | Qty | Note |",
+		"+------+-----+------+",
+		"| 日本 |  12 | a|b  |",
+		"|      |     | c    |",
+		"| Total      |      |",
+		"+------------+------+",
+	}, "\n")+"\n", table)
+	table, err = f.RenderText("Sheet1", "", TextOptions{Format: "box"})
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"┌──────┬─────┬──────┐",
+		"│ Name │ Qty │ Note │",
+		"├──────┼─────┼──────┤",
+		"│ 日本 │  12 │ a|b  │",
+		"│      │     │ c    │",
+		"│ Total      │      │",
+		"└────────────┴──────┘",
+	}, "\n")+"\n", table)
+
+	// Test render the merged header cell across the header border
+	assert.NoError(t, f.MergeCell("Sheet1", "A1", "B1"))
+	table, err = f.RenderText("Sheet1", "A1:C2", TextOptions{Format: "box"})
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"┌───────────┬──────┐",
+		"│ Name      │ Note │",
+		"├──────┬────┼──────┤",
+		"│ 日本 │ 12 │ a|b  │",
+		"│      │    │ c    │",
+		"└──────┴────┴──────┘",
+	}, "\n")+"\n", table)
+	table, err = f.RenderText("Sheet1", "A1:B1", TextOptions{Format: "ascii"})
+	assert.NoError(t, err)
+	assert.Equal(t, "+------+\n| Name |\n+------+\n", table)
+
+	// Test render the range without visible cells
+	assert.NoError(t, f.SetRowVisible("Sheet1", 4, false))
+	table, err = f.RenderText("Sheet1", "A4:C4")
+	assert.NoError(t, err)
+	assert.Empty(t, table)
+
+	// Test render text with unsupported format, invalid range reference and worksheet
+	_, err = f.RenderText("Sheet1", "A1", TextOptions{Format: "csv"})
+	assert.EqualError(t, err, `unsupported text format "csv"`)
+	_, err = f.RenderText("Sheet1", "A:B")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	_, err = f.RenderText("SheetN", "A1")
+	assert.EqualError(t, err, "sheet SheetN does not exist")
+	assert.NoError(t, f.Close())
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 0, textWidth(""))
+	assert.Equal(t, 5, textWidth("Excel"))
+	assert.Equal(t, 4, textWidth("日本"))
+	assert.Equal(t, 6, textWidth("ＡＢＣ"))
+	assert.Equal(t, 1, textWidth("é"))
+	assert.Equal(t, "  ab  ", textPad("ab", 6, "center"))
+	assert.Equal(t, "   ab", textPad("ab", 5, "right"))
+	assert.Equal(t, "abc", textPad("abc", 2, ""))
+}
@@ -17,8 +17,11 @@ import (
 	"io"
 	"math"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mohae/deepcopy"
 )
@@ -93,6 +96,29 @@ type Rows struct {
 	decoder                 *xml.Decoder
 	token                   xml.Token
 	curRowOpts, seekRowOpts RowOpts
+	structDecoder           *rowsDecoder
+	empty                   bool
+	dateStyles              map[int]bool
+	date1904                *bool
+	cellRef                 string
+	sharedFormulas          map[int][2]string
+}
+
+// TypedCell directly maps the typed cell value of the row iterator. Type
+// specifies the cell data type as it is stored in the worksheet, Raw specifies
+// the raw cell value without number format applied, Value specifies the
+// native Go value of the cell, StyleID specifies the cell style index, and
+// Formula specifies the formula of the cell, the formula of the cells in the
+// shared formula range will be resolved from the anchor cell. The Value will be float64 for the number cells, time.Time for
+// the number cells with date and time number format and the date cells, bool
+// for the boolean cells, string for the other cells, and nil for the empty
+// cells.
+type TypedCell struct {
+	Type    CellType
+	Raw     string
+	Value   interface{}
+	StyleID int
+	Formula string
 }
 
 // Next will return true if it finds the next row element.
@@ -109,7 +135,8 @@ func (rows *Rows) Next() bool {
 		}
 		switch xmlElement := token.(type) {
 		case xml.StartElement:
-			if xmlElement.Name.Local == "row" {
+			switch xmlElement.Name.Local {
+			case "row":
 				rows.curRow++
 				if rowNum, _ := attrValToInt("r", xmlElement.Attr); rowNum != 0 {
 					rows.curRow = rowNum
@@ -117,6 +144,17 @@ func (rows *Rows) Next() bool {
 				rows.token = token
 				rows.curRowOpts = extractRowOpts(xmlElement.Attr)
 				return true
+			case "c":
+				rows.cellRef = ""
+				for _, attr := range xmlElement.Attr {
+					if attr.Name.Local == "r" {
+						rows.cellRef = attr.Value
+					}
+				}
+			case "f":
+				var formula xlsxF
+				_ = rows.decoder.DecodeElement(&formula, &xmlElement)
+				rows.setSharedFormula(rows.cellRef, &formula)
 			}
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
@@ -149,20 +187,84 @@ func (rows *Rows) Close() error {
 // data as a stream, returns each cell in a row as is, and will not skip empty
 // rows in the tail of the worksheet.
 func (rows *Rows) Columns(opts ...Options) ([]string, error) {
+	rows.rawCellValue = rows.f.getOptions(opts...).RawCellValue
+	rowIterator := rowXMLIterator{}
+	rows.columns(&rowIterator)
+	return rowIterator.cells, rowIterator.err
+}
+
+// TypedColumns return the current row's typed column values. This fetches the
+// worksheet data as a stream, returns each cell in a row with the cell data
+// type, raw value, native Go value, style index and formula, so the number,
+// boolean and date values could be used without parsing the formatted text.
+// The date serial numbers will be converted by the workbook's date system.
+// For example:
+//
+//	rows, err := f.Rows("Sheet1")
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	for rows.Next() {
+//	    cells, err := rows.TypedColumns()
+//	    if err != nil {
+//	        fmt.Println(err)
+//	        break
+//	    }
+//	    for _, cell := range cells {
+//	        switch v := cell.Value.(type) {
+//	        case float64:
+//	            fmt.Print(v*2, "\t")
+//	        case time.Time:
+//	            fmt.Print(v.Year(), "\t")
+//	        default:
+//	            fmt.Print(v, "\t")
+//	        }
+//	    }
+//	    fmt.Println()
+//	}
+//	if err = rows.Close(); err != nil {
+//	    fmt.Println(err)
+//	}
+func (rows *Rows) TypedColumns() ([]TypedCell, error) {
+	rowIterator := rowXMLIterator{typed: true}
+	if rowIterator.date1904, rowIterator.err = rows.getDate1904(); rowIterator.err != nil {
+		return nil, rowIterator.err
+	}
+	rows.columns(&rowIterator)
+	return rowIterator.typedCells, rowIterator.err
+}
+
+// getDate1904 provides a function to get if the workbook uses the 1904 date
+// system, the result will be cached in the rows iterator to avoid reading the
+// workbook properties for each row.
+func (rows *Rows) getDate1904() (bool, error) {
+	if rows.date1904 == nil {
+		date1904, err := rows.f.getDate1904()
+		if err != nil {
+			return false, err
+		}
+		rows.date1904 = &date1904
+	}
+	return *rows.date1904, nil
+}
+
+// columns provides a function to parse the current row's column values into
+// the row iterator, the raw cell values will also be collected if the
+// keepRaw option of the row iterator is enabled.
+func (rows *Rows) columns(rowIterator *rowXMLIterator) {
 	if rows.curRow > rows.seekRow {
-		return nil, nil
+		return
 	}
-	var rowIterator rowXMLIterator
 	var token xml.Token
-	rows.rawCellValue = rows.f.getOptions(opts...).RawCellValue
-	if rows.sst, rowIterator.err = rows.f.sharedStringsReader(); rowIterator.err != nil {
-		return rowIterator.cells, rowIterator.err
+	if rows.sst, rowIterator.err = rows.f.sharedStringsValueReader(); rowIterator.err != nil {
+		return
 	}
 	for {
 		if rows.token != nil {
 			token = rows.token
 		} else if token, _ = rows.decoder.Token(); token == nil {
-			break
+			return
 		}
 		switch xmlElement := token.(type) {
 		case xml.StartElement:
@@ -178,21 +280,20 @@ func (rows *Rows) Columns(opts ...Options) ([]string, error) {
 				rows.seekRowOpts = extractRowOpts(xmlElement.Attr)
 				if rows.curRow > rows.seekRow {
 					rows.token = nil
-					return rowIterator.cells, rowIterator.err
+					return
 				}
 			}
-			if rows.rowXMLHandler(&rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
+			if rows.rowXMLHandler(rowIterator, &xmlElement, rows.rawCellValue); rowIterator.err != nil {
 				rows.token = nil
-				return rowIterator.cells, rowIterator.err
+				return
 			}
 			rows.token = nil
 		case xml.EndElement:
 			if xmlElement.Name.Local == "sheetData" {
-				return rowIterator.cells, rowIterator.err
+				return
 			}
 		}
 	}
-	return rowIterator.cells, rowIterator.err
 }
 
 // extractRowOpts extract row element attributes.
@@ -224,6 +325,10 @@ type rowXMLIterator struct {
 	inElement        string
 	cellCol, cellRow int
 	cells            []string
+	keepRaw          bool
+	rawCells         []string
+	typed, date1904  bool
+	typedCells       []TypedCell
 }
 
 // rowXMLHandler parse the row XML element of the worksheet.
@@ -237,13 +342,102 @@ func (rows *Rows) rowXMLHandler(rowIterator *rowXMLIterator, xmlElement *xml.Sta
 				return
 			}
 		}
+		rows.setSharedFormula(colCell.R, colCell.F)
+		if rowIterator.typed {
+			if cell := rows.typedCell(&colCell, rowIterator.date1904); cell.Raw != "" || colCell.F != nil {
+				for len(rowIterator.typedCells) < rowIterator.cellCol-1 {
+					rowIterator.typedCells = append(rowIterator.typedCells, TypedCell{})
+				}
+				rowIterator.typedCells = append(rowIterator.typedCells, cell)
+			}
+			return
+		}
 		blank := rowIterator.cellCol - len(rowIterator.cells)
+		var rawVal string
+		if rowIterator.keepRaw {
+			rawVal, _ = colCell.getValueFrom(rows.f, rows.sst, true)
+		}
 		if val, _ := colCell.getValueFrom(rows.f, rows.sst, raw); val != "" || colCell.F != nil {
 			rowIterator.cells = append(appendSpace(blank, rowIterator.cells), val)
+			if rowIterator.keepRaw {
+				rowIterator.rawCells = append(appendSpace(blank, rowIterator.rawCells), rawVal)
+			}
 		}
 	}
 }
 
+// setSharedFormula provides a function to keep the anchor cell reference and
+// formula of the shared formula by given cell reference and formula of the
+// cell, which will be used for resolving the formula of the cells in the
+// shared formula range.
+func (rows *Rows) setSharedFormula(cell string, formula *xlsxF) {
+	if formula == nil || formula.T != STCellFormulaTypeShared || formula.Ref == "" || formula.Si == nil || cell == "" {
+		return
+	}
+	if rows.sharedFormulas == nil {
+		rows.sharedFormulas = make(map[int][2]string)
+	}
+	rows.sharedFormulas[*formula.Si] = [2]string{cell, formula.Content}
+}
+
+// typedCell provides a function to convert the cell into the typed cell
+// value by given cell and the date system of the workbook.
+func (rows *Rows) typedCell(c *xlsxC, date1904 bool) TypedCell {
+	cell := TypedCell{Type: cellTypes[c.T], StyleID: c.S}
+	if c.F != nil {
+		cell.Formula = c.F.Content
+		if c.F.T == STCellFormulaTypeShared && c.F.Si != nil && c.R != "" {
+			if anchor, ok := rows.sharedFormulas[*c.F.Si]; ok {
+				cell.Formula = shiftSharedFormula(anchor[0], anchor[1], c.R)
+			}
+		}
+	}
+	if cell.Raw, _ = c.getValueFrom(rows.f, rows.sst, true); cell.Raw == "" {
+		return cell
+	}
+	cell.Value = cell.Raw
+	switch cell.Type {
+	case CellTypeBool:
+		cell.Value = cell.Raw == "1" || strings.EqualFold(cell.Raw, "TRUE")
+	case CellTypeDate:
+		for _, layout := range defaultDateLayouts {
+			if t, err := time.Parse(layout, cell.Raw); err == nil {
+				cell.Value = t
+				break
+			}
+		}
+	case CellTypeUnset, CellTypeNumber:
+		number, err := strconv.ParseFloat(cell.Raw, 64)
+		if err != nil {
+			break
+		}
+		cell.Value = number
+		if rows.isDateStyle(c.S) {
+			if t, err := ExcelDateToTime(number, date1904); err == nil {
+				cell.Value = t
+			}
+		}
+	}
+	return cell
+}
+
+// isDateStyle returns if the number format of the cell style is the date or
+// time number format by given cell style index.
+func (rows *Rows) isDateStyle(styleID int) bool {
+	if rows.dateStyles == nil {
+		rows.dateStyles = make(map[int]bool)
+	}
+	isDate, ok := rows.dateStyles[styleID]
+	if !ok {
+		if code := rows.f.getNumFmtCode(styleID); code != "" {
+			hasDate, hasTime := numFmtDateTimeParts(code)
+			isDate = hasDate || hasTime
+		}
+		rows.dateStyles[styleID] = isDate
+	}
+	return isDate
+}
+
 // Rows returns a rows iterator, used for streaming reading data for a
 // worksheet with a large data. This function is concurrency safe. For
 // example:
@@ -288,54 +482,171 @@ func (f *File) Rows(sheet string) (*Rows, error) {
 	return &rows, err
 }
 
-// getFromStringItem build shared string item offset list from system temporary
-// file at one time, and return value by given to string index.
-func (f *File) getFromStringItem(index int) string {
-	if f.sharedStringTemp != nil {
-		if len(f.sharedStringItem) <= index {
-			return strconv.Itoa(index)
-		}
-		offsetRange := f.sharedStringItem[index]
-		buf := make([]byte, offsetRange[1]-offsetRange[0])
-		if _, err := f.sharedStringTemp.ReadAt(buf, int64(offsetRange[0])); err != nil {
-			return strconv.Itoa(index)
-		}
-		return string(buf)
+// ReadSheetsOptions directly maps the settings of reading worksheets
+// concurrently.
+//
+// Sheets specifies the worksheet names to be read, all worksheets in the
+// workbook will be read by default.
+//
+// Workers specifies the number of worksheets to be read at the same time, the
+// default value is the number of logical CPUs.
+//
+// RawCellValue specifies if apply the number format for the cell value or get
+// the raw value.
+type ReadSheetsOptions struct {
+	Sheets       []string
+	Workers      int
+	RawCellValue bool
+}
+
+// ReadSheets provides a function to read the rows of multiple worksheets
+// concurrently with a pool of workers. Each worksheet will be read by one
+// worker with its own streaming XML decoder, and the shared string table and
+// styles will be shared by the workers. The callback function will be called
+// for each row of the worksheets, the rows in the same worksheet will be
+// delivered in order, and the callback function may be called concurrently
+// for different worksheets. Reading will be stopped when the callback function
+// returns an error, and the first error will be returned. For example, read
+// all worksheets with 4 workers:
+//
+//	var mu sync.Mutex
+//	counts := map[string]int{}
+//	err := f.ReadSheets(func(sheet string, row int, columns []string) error {
+//	    mu.Lock()
+//	    defer mu.Unlock()
+//	    counts[sheet]++
+//	    return nil
+//	}, excelize.ReadSheetsOptions{Workers: 4})
+func (f *File) ReadSheets(fn func(sheet string, row int, columns []string) error, opts ...ReadSheetsOptions) error {
+	var options ReadSheetsOptions
+	for _, opt := range opts {
+		options = opt
+	}
+	sheets, err := f.prepareReadSheets(options.Sheets)
+	if err != nil || len(sheets) == 0 {
+		return err
 	}
-	needClose, decoder, tempFile, err := f.xmlDecoder(defaultXMLPathSharedStrings)
-	if needClose && err == nil {
-		defer func() {
-			err = tempFile.Close()
-		}()
+	if _, err = f.workbookReader(); err != nil {
+		return err
+	}
+	if _, err = f.stylesReader(); err != nil {
+		return err
+	}
+	if _, err = f.sharedStringsValueReader(); err != nil {
+		return err
+	}
+	workers := options.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(sheets) {
+		workers = len(sheets)
 	}
-	f.sharedStringItem = [][]uint{}
-	f.sharedStringTemp, _ = os.CreateTemp(os.TempDir(), "excelize-")
-	f.tempFiles.Store(defaultTempFileSST, f.sharedStringTemp.Name())
 	var (
-		inElement string
-		i, offset uint
+		wg   sync.WaitGroup
+		once sync.Once
+		jobs = make(chan string)
+		done = make(chan struct{})
 	)
-	for {
-		token, _ := decoder.Token()
-		if token == nil {
-			break
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sheet := range jobs {
+				if e := f.readSheetRows(sheet, fn, options.RawCellValue, done); e != nil {
+					once.Do(func() {
+						err = e
+						close(done)
+					})
+				}
+			}
+		}()
+	}
+	func() {
+		defer close(jobs)
+		for _, sheet := range sheets {
+			select {
+			case jobs <- sheet:
+			case <-done:
+				return
+			}
 		}
-		switch xmlElement := token.(type) {
-		case xml.StartElement:
-			inElement = xmlElement.Name.Local
-			if inElement == "si" {
-				si := xlsxSI{}
-				_ = decoder.DecodeElement(&si, &xmlElement)
-
-				startIdx := offset
-				n, _ := f.sharedStringTemp.WriteString(si.String())
-				offset += uint(n)
-				f.sharedStringItem = append(f.sharedStringItem, []uint{startIdx, offset})
-				i++
+	}()
+	wg.Wait()
+	return err
+}
+
+// prepareReadSheets provides a function to check the given worksheet names
+// for reading worksheets concurrently, and returns all worksheet names in the
+// workbook if no worksheet name was given.
+func (f *File) prepareReadSheets(sheets []string) ([]string, error) {
+	isWorksheet := func(name string) bool {
+		for _, sheetType := range []string{"xl/chartsheets", "xl/dialogsheet", "xl/macrosheet"} {
+			if strings.HasPrefix(name, sheetType) {
+				return false
+			}
+		}
+		return true
+	}
+	if len(sheets) == 0 {
+		var list []string
+		for _, sheet := range f.GetSheetList() {
+			if name, ok := f.getSheetXMLPath(sheet); ok && isWorksheet(name) {
+				list = append(list, sheet)
 			}
 		}
+		return list, nil
+	}
+	for _, sheet := range sheets {
+		if err := checkSheetName(sheet); err != nil {
+			return nil, err
+		}
+		name, ok := f.getSheetXMLPath(sheet)
+		if !ok {
+			return nil, ErrSheetNotExist{sheet}
+		}
+		if !isWorksheet(name) {
+			return nil, newNotWorksheetError(sheet)
+		}
+	}
+	return sheets, nil
+}
+
+// readSheetRows provides a function to read the rows of the worksheet by
+// given worksheet name in order, and call the callback function for each row
+// until all rows have been read or the done channel was closed.
+func (f *File) readSheetRows(sheet string, fn func(sheet string, row int, columns []string) error, raw bool, done <-chan struct{}) error {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		select {
+		case <-done:
+			return rows.Close()
+		default:
+		}
+		columns, err := rows.Columns(Options{RawCellValue: raw})
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+		if err = fn(sheet, rows.seekRow, columns); err != nil {
+			_ = rows.Close()
+			return err
+		}
+	}
+	return rows.Close()
+}
+
+// getFromStringItem build shared string item offset list from system temporary
+// file at one time, and return value by given to string index.
+func (f *File) getFromStringItem(index int) string {
+	si, err := f.getSharedStringItem(index)
+	if err != nil || si == nil {
+		return strconv.Itoa(index)
 	}
-	return f.getFromStringItem(index)
+	return si.String()
 }
 
 // xmlDecoder creates XML decoder by given path in the zip from memory data
@@ -635,6 +946,7 @@ func (f *File) DuplicateRow(sheet string, row int) error {
 // worksheet, it will cause a file error when you open it. The excelize only
 // partially updates these references currently.
 func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
+	f.clearCalcCache()
 	if row < 1 {
 		return newInvalidRowNumberError(row)
 	}
@@ -676,7 +988,7 @@ func (f *File) DuplicateRowTo(sheet string, row, row2 int) error {
 	}
 	rowCopy.C = append(make([]xlsxC, 0, len(rowCopy.C)), rowCopy.C...)
 	rowCopy.adjustSingleRowDimensions(row2 - row)
-	_ = f.adjustSingleRowFormulas(sheet, sheet, &rowCopy, row, row2-row, true)
+	_ = f.adjustSingleRowFormulas(sheet, sheet, &rowCopy, row, row2-row, true, f.getTableNames())
 
 	if idx2 != -1 {
 		ws.SheetData.Row[idx2] = rowCopy
@@ -68,7 +68,7 @@ func (rows *Rows) Values() ([]RichText, error) {
 	}
 	var rowIterator rowRichIterator
 	var token xml.Token
-	if rows.sst, rowIterator.err = rows.f.sharedStringsReader(); rowIterator.err != nil {
+	if rows.sst, rowIterator.err = rows.f.sharedStringsValueReader(); rowIterator.err != nil {
 		return nil, rowIterator.err
 	}
 	for {
@@ -3,9 +3,12 @@ package excelize
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -138,6 +141,182 @@ func TestRowsGetRowOpts(t *testing.T) {
 	assert.Equal(t, expectedRowStyleID3, rowOpts)
 }
 
+func TestReadSheets(t *testing.T) {
+	f := NewFile()
+	defer func() {
+		assert.NoError(t, f.Close())
+	}()
+	sheets := []string{"Sheet1", "Sheet2", "Sheet3", "Sheet4"}
+	for i, sheet := range sheets {
+		if i > 0 {
+			_, err := f.NewSheet(sheet)
+			assert.NoError(t, err)
+		}
+		for row := 1; row <= 100; row++ {
+			assert.NoError(t, f.SetSheetRow(sheet, fmt.Sprintf("A%d", row), &[]interface{}{sheet, row, 0.5}))
+		}
+	}
+	style, err := f.NewStyle(&Style{NumFmt: 10})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet2", "C1", "C100", style))
+	assert.NoError(t, f.AddChartSheet("Chart1", &Chart{Type: Col, Series: []ChartSeries{{Values: "Sheet1!$B$1:$B$3"}}}))
+
+	for _, opts := range []ReadSheetsOptions{{}, {Workers: 2}, {Workers: 1, RawCellValue: true}} {
+		var mu sync.Mutex
+		results := make(map[string][][]string)
+		assert.NoError(t, f.ReadSheets(func(sheet string, row int, columns []string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, len(results[sheet])+1, row)
+			results[sheet] = append(results[sheet], columns)
+			return nil
+		}, opts))
+		assert.Len(t, results, len(sheets))
+		for _, sheet := range sheets {
+			rows, err := f.GetRows(sheet, Options{RawCellValue: opts.RawCellValue})
+			assert.NoError(t, err)
+			assert.Equal(t, rows, results[sheet])
+		}
+		if !opts.RawCellValue {
+			assert.Equal(t, "50.00%", results["Sheet2"][0][2])
+		}
+	}
+
+	// Test read specified worksheets
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	assert.NoError(t, f.ReadSheets(func(sheet string, row int, columns []string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		counts[sheet]++
+		return nil
+	}, ReadSheetsOptions{Sheets: []string{"Sheet3", "Sheet1"}, Workers: 8}))
+	assert.Equal(t, map[string]int{"Sheet1": 100, "Sheet3": 100}, counts)
+
+	// Test read worksheets with callback function returns error
+	expected := errors.New("stop")
+	assert.Equal(t, expected, f.ReadSheets(func(sheet string, row int, columns []string) error {
+		return expected
+	}, ReadSheetsOptions{Workers: 2}))
+
+	// Test read worksheets with invalid worksheet names
+	fn := func(sheet string, row int, columns []string) error { return nil }
+	assert.Equal(t, ErrSheetNameInvalid, f.ReadSheets(fn, ReadSheetsOptions{Sheets: []string{"Sheet:1"}}))
+	assert.Equal(t, ErrSheetNotExist{"SheetN"}, f.ReadSheets(fn, ReadSheetsOptions{Sheets: []string{"SheetN"}}))
+	assert.Equal(t, newNotWorksheetError("Chart1"), f.ReadSheets(fn, ReadSheetsOptions{Sheets: []string{"Chart1"}}))
+
+	// Test read worksheets with unsupported charset styles and shared strings table
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ReadSheets(fn), "XML syntax error on line 1: invalid UTF-8")
+	f.Styles = nil
+	f.Pkg.Store(defaultXMLPathStyles, []byte(xml.Header+templateStyles))
+	f.SharedStrings = nil
+	f.Pkg.Store(defaultXMLPathSharedStrings, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ReadSheets(fn), "XML syntax error on line 1: invalid UTF-8")
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	assert.EqualError(t, f.ReadSheets(fn, ReadSheetsOptions{Sheets: []string{"Sheet1"}}), "XML syntax error on line 1: invalid UTF-8")
+}
+
+func TestRowsTypedColumns(t *testing.T) {
+	f := NewFile()
+	defer func() {
+		assert.NoError(t, f.Close())
+	}()
+	date := time.Date(2024, 3, 15, 12, 30, 0, 0, time.UTC)
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Text", 1.5, true, date, nil, 42}))
+	assert.NoError(t, f.SetCellFormula("Sheet1", "G1", "B1*2"))
+	assert.NoError(t, f.SetCellRichText("Sheet1", "A3", []RichTextRun{{Text: "Rich"}}))
+	assert.NoError(t, f.SetCellValue("Sheet1", "C3", "#N/A"))
+	style, err := f.NewStyle(&Style{NumFmt: 2})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetCellStyle("Sheet1", "F1", "F1", style))
+	ws, ok := f.Sheet.Load("xl/worksheets/sheet1.xml")
+	assert.True(t, ok)
+	ws.(*xlsxWorksheet).SheetData.Row[0].C[6].T, ws.(*xlsxWorksheet).SheetData.Row[0].C[6].V = "", "3"
+	ws.(*xlsxWorksheet).SheetData.Row[2].C = append(ws.(*xlsxWorksheet).SheetData.Row[2].C,
+		xlsxC{R: "D3", T: "d", V: "2024-03-15T12:30:00"}, xlsxC{R: "E3", T: "e", V: "#DIV/0!"}, xlsxC{R: "F3", T: "d", V: "invalid"})
+
+	rows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var results [][]TypedCell
+	for rows.Next() {
+		cells, err := rows.TypedColumns()
+		assert.NoError(t, err)
+		results = append(results, cells)
+	}
+	assert.NoError(t, rows.Close())
+	assert.Len(t, results, 3)
+	dateStyle, err := f.GetCellStyle("Sheet1", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, []TypedCell{
+		{Type: CellTypeSharedString, Raw: "Text", Value: "Text"},
+		{Type: CellTypeUnset, Raw: "1.5", Value: 1.5},
+		{Type: CellTypeBool, Raw: "1", Value: true},
+		{Type: CellTypeUnset, Raw: "45366.520833333336", Value: date, StyleID: dateStyle},
+		{},
+		{Type: CellTypeUnset, Raw: "42", Value: 42.0, StyleID: style},
+		{Type: CellTypeUnset, Raw: "3", Value: 3.0, Formula: "B1*2"},
+	}, results[0])
+	assert.Nil(t, results[1])
+	assert.Equal(t, []TypedCell{
+		{Type: CellTypeSharedString, Raw: "Rich", Value: "Rich"},
+		{},
+		{Type: CellTypeSharedString, Raw: "#N/A", Value: "#N/A"},
+		{Type: CellTypeDate, Raw: "2024-03-15T12:30:00", Value: date},
+		{Type: CellTypeError, Raw: "#DIV/0!", Value: "#DIV/0!"},
+		{Type: CellTypeDate, Raw: "invalid", Value: "invalid"},
+	}, results[2])
+
+	// Test typed columns with the shared formula cells
+	_, err = f.NewSheet("Sheet2")
+	assert.NoError(t, err)
+	formulaType, ref := STCellFormulaTypeShared, "B1:B3"
+	assert.NoError(t, f.SetCellFormula("Sheet2", "B1", "A1*2", FormulaOpts{Type: &formulaType, Ref: &ref}))
+	for _, skip := range []int{0, 1} {
+		rows, err = f.Rows("Sheet2")
+		assert.NoError(t, err)
+		var formulas []string
+		for row := 1; rows.Next(); row++ {
+			if row <= skip {
+				continue
+			}
+			cells, err := rows.TypedColumns()
+			assert.NoError(t, err)
+			formulas = append(formulas, cells[1].Formula)
+		}
+		assert.NoError(t, rows.Close())
+		assert.Equal(t, []string{"A1*2", "A2*2", "A3*2"}[skip:], formulas)
+	}
+
+	// Test typed columns with the 1904 date system
+	assert.NoError(t, f.SetWorkbookProps(&WorkbookPropsOptions{Date1904: boolPtr(true)}))
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	cells, err := rows.TypedColumns()
+	assert.NoError(t, err)
+	assert.Equal(t, date.AddDate(4, 0, 1), cells[3].Value)
+	assert.True(t, *rows.date1904)
+	assert.NoError(t, rows.Close())
+
+	// Test typed columns with unsupported charset workbook
+	f.WorkBook = nil
+	f.Pkg.Store(defaultXMLPathWorkbook, MacintoshCyrillicCharset)
+	rows, err = f.Rows("Sheet1")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_, err = rows.TypedColumns()
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test typed columns with the cached date system skip reading workbook
+	rows.date1904 = boolPtr(false)
+	assert.True(t, rows.Next())
+	_, err = rows.TypedColumns()
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+}
+
 func TestRowsError(t *testing.T) {
 	f, err := OpenFile(filepath.Join("test", "Book1.xlsx"))
 	if !assert.NoError(t, err) {
@@ -0,0 +1,169 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"encoding/binary"
+	"encoding/xml"
+	"io"
+	"os"
+)
+
+// sharedStringsCacheSize defined the maximum number of the shared string
+// items in the least recently used cache.
+const sharedStringsCacheSize = 4096
+
+// sharedStringsIndex directly maps the index of the shared string table which
+// read on demand. The start and end byte offsets of each si element in the
+// shared string table part will be stored in the system temporary file, the
+// items will be read from the shared string table part by the offsets, and
+// the recently used items will be kept in the cache.
+type sharedStringsIndex struct {
+	source io.ReaderAt
+	file   *os.File
+	count  int
+	items  map[int]*list.Element
+	lru    *list.List
+}
+
+// sharedStringsCacheItem directly maps the item in the least recently used
+// cache of the shared string items.
+type sharedStringsCacheItem struct {
+	idx int
+	si  *xlsxSI
+}
+
+// sharedStringsValueReader provides a function to get the shared string table
+// for reading cell values. In low memory mode, the shared string table will
+// not be loaded, returns nil and the shared string items will be looked up on
+// demand, unless the shared string table has already been loaded into memory.
+func (f *File) sharedStringsValueReader() (*xlsxSST, error) {
+	if f.options != nil && f.options.LowMemory {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.SharedStrings, nil
+	}
+	return f.sharedStringsReader()
+}
+
+// getSharedStringItem provides a function to get the shared string item by
+// given index from the shared string table part, the offsets of the items
+// will be indexed into the system temporary file on first use, and the
+// recently used items will be kept in the cache.
+func (f *File) getSharedStringItem(idx int) (*xlsxSI, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sharedStringTemp == nil {
+		if err := f.writeSharedStringsTemp(); err != nil {
+			return nil, err
+		}
+	}
+	sstIdx := f.sharedStringsIdx
+	if idx < 0 || idx >= sstIdx.count {
+		return nil, nil
+	}
+	if elem, ok := sstIdx.items[idx]; ok {
+		sstIdx.lru.MoveToFront(elem)
+		return elem.Value.(*sharedStringsCacheItem).si, nil
+	}
+	buf := make([]byte, 16)
+	if _, err := f.sharedStringTemp.ReadAt(buf, int64(idx)*16); err != nil {
+		return nil, err
+	}
+	start, end := binary.LittleEndian.Uint64(buf[:8]), binary.LittleEndian.Uint64(buf[8:])
+	content := make([]byte, end-start)
+	if _, err := sstIdx.source.ReadAt(content, int64(start)); err != nil {
+		return nil, err
+	}
+	si := new(xlsxSI)
+	if err := xml.Unmarshal(content, si); err != nil {
+		return nil, err
+	}
+	sstIdx.items[idx] = sstIdx.lru.PushFront(&sharedStringsCacheItem{idx: idx, si: si})
+	if sstIdx.lru.Len() > sharedStringsCacheSize {
+		elem := sstIdx.lru.Back()
+		sstIdx.lru.Remove(elem)
+		delete(sstIdx.items, elem.Value.(*sharedStringsCacheItem).idx)
+	}
+	return si, nil
+}
+
+// writeSharedStringsTemp provides a function to scan the shared string table
+// part from memory or system temporary file at one time, and write the start
+// and end byte offsets of each si element into a new system temporary file.
+func (f *File) writeSharedStringsTemp() error {
+	sstIdx := &sharedStringsIndex{items: make(map[int]*list.Element), lru: list.New()}
+	var (
+		rdr io.Reader
+		err error
+	)
+	if content := f.readXML(defaultXMLPathSharedStrings); len(content) > 0 {
+		r := bytes.NewReader(content)
+		sstIdx.source, rdr = r, r
+	} else if sstIdx.file, err = f.readTemp(defaultXMLPathSharedStrings); err != nil {
+		return err
+	} else if sstIdx.file != nil {
+		sstIdx.source, rdr = sstIdx.file, sstIdx.file
+	}
+	sstTemp, err := os.CreateTemp(os.TempDir(), "excelize-")
+	if err != nil {
+		_ = sstIdx.close()
+		return err
+	}
+	var (
+		w      = bufio.NewWriter(sstTemp)
+		buf    = make([]byte, 16)
+		offset int64
+		token  xml.Token
+	)
+	// The shared string table doesn't exist if there is no source part
+	err = io.EOF
+	for dec := f.xmlNewDecoder(rdr); rdr != nil; {
+		offset = dec.InputOffset()
+		if token, err = dec.Token(); err != nil {
+			break
+		}
+		if se, ok := token.(xml.StartElement); ok && se.Name.Local == "si" {
+			if err = dec.Skip(); err != nil {
+				break
+			}
+			binary.LittleEndian.PutUint64(buf[:8], uint64(offset))
+			binary.LittleEndian.PutUint64(buf[8:], uint64(dec.InputOffset()))
+			_, _ = w.Write(buf)
+			sstIdx.count++
+		}
+	}
+	if err == io.EOF {
+		err = w.Flush()
+	}
+	if err != nil {
+		_ = sstTemp.Close()
+		_ = os.Remove(sstTemp.Name())
+		_ = sstIdx.close()
+		return err
+	}
+	f.sharedStringTemp, f.sharedStringsIdx = sstTemp, sstIdx
+	f.tempFiles.Store(defaultTempFileSST, sstTemp.Name())
+	return nil
+}
+
+// close provides a function to close the shared string table part in the
+// system temporary directory which opened for reading the items.
+func (sstIdx *sharedStringsIndex) close() error {
+	if sstIdx == nil || sstIdx.file == nil {
+		return nil
+	}
+	return sstIdx.file.Close()
+}
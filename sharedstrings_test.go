@@ -0,0 +1,118 @@
+package excelize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLowMemorySharedStrings(t *testing.T) {
+	f := NewFile()
+	total := sharedStringsCacheSize + 100
+	for row := 1; row <= total; row++ {
+		assert.NoError(t, f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), fmt.Sprintf("Item %d", row)))
+	}
+	assert.NoError(t, f.SetCellRichText("Sheet1", "B1", []RichTextRun{
+		{Text: "Rich ", Font: &Font{Bold: true}},
+		{Text: "Text"},
+	}))
+	file := filepath.Join("test", "TestLowMemorySharedStrings.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+	assert.NoError(t, f.Close())
+
+	for _, opts := range []Options{
+		{LowMemory: true},
+		{LowMemory: true, UnzipXMLSizeLimit: 128},
+	} {
+		f, err := OpenFile(file, opts)
+		assert.NoError(t, err)
+		rows, err := f.Rows("Sheet1")
+		assert.NoError(t, err)
+		var row int
+		for rows.Next() {
+			row++
+			cols, err := rows.Columns()
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("Item %d", row), cols[0])
+		}
+		assert.NoError(t, rows.Close())
+		assert.Equal(t, total, row)
+		assert.Nil(t, f.SharedStrings)
+		assert.NotNil(t, f.sharedStringTemp)
+		assert.Equal(t, total+1, f.sharedStringsIdx.count)
+		assert.Equal(t, sharedStringsCacheSize, f.sharedStringsIdx.lru.Len())
+		info, err := f.sharedStringTemp.Stat()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(total+1)*16, info.Size())
+
+		cell, err := f.GetCellValue("Sheet1", "A1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Item 1", cell)
+		cell, err = f.GetCellValue("Sheet1", "B1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Rich Text", cell)
+		runs, err := f.GetCellRichText("Sheet1", "B1")
+		assert.NoError(t, err)
+		assert.Len(t, runs, 2)
+		assert.Equal(t, "Rich ", runs[0].Text)
+		assert.Nil(t, f.SharedStrings)
+
+		// Test set cell value after reading in low memory mode
+		tempPath := f.sharedStringTemp.Name()
+		assert.NoError(t, f.SetCellValue("Sheet1", "C1", "New"))
+		assert.Nil(t, f.sharedStringTemp)
+		assert.Nil(t, f.sharedStringsIdx)
+		assert.NoFileExists(t, tempPath)
+		assert.NotNil(t, f.SharedStrings)
+		cell, err = f.GetCellValue("Sheet1", "A2")
+		assert.NoError(t, err)
+		assert.Equal(t, "Item 2", cell)
+		cell, err = f.GetCellValue("Sheet1", "C1")
+		assert.NoError(t, err)
+		assert.Equal(t, "New", cell)
+		assert.NoError(t, f.Close())
+	}
+}
+
+func TestGetSharedStringItem(t *testing.T) {
+	f := NewFile()
+	// Test get shared string item without shared string table
+	f.Pkg.Delete(defaultXMLPathSharedStrings)
+	si, err := f.getSharedStringItem(0)
+	assert.NoError(t, err)
+	assert.Nil(t, si)
+	tempPath := f.sharedStringTemp.Name()
+	assert.NoError(t, f.Close())
+	assert.NoFileExists(t, tempPath)
+
+	// Test get shared string item with invalid shared string table
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathSharedStrings, []byte(`<sst><si><t>a</t></si><si>`))
+	_, err = f.getSharedStringItem(0)
+	assert.EqualError(t, err, "XML syntax error on line 1: unexpected EOF")
+	assert.Nil(t, f.sharedStringTemp)
+	assert.NoError(t, f.Close())
+
+	// Test get shared string item with character data section and out of range index
+	f = NewFile()
+	f.Pkg.Store(defaultXMLPathSharedStrings, []byte(`<sst><si><t>a</t></si><si><t><![CDATA[b]]></t></si></sst>`))
+	si, err = f.getSharedStringItem(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", si.String())
+	si, err = f.getSharedStringItem(-1)
+	assert.NoError(t, err)
+	assert.Nil(t, si)
+	assert.NoError(t, f.Close())
+
+	// Test get shared string item with not exist temporary file
+	f = NewFile()
+	f.Pkg.Delete(defaultXMLPathSharedStrings)
+	f.tempFiles.Store(defaultXMLPathSharedStrings, filepath.Join(os.TempDir(), "excelize-not-exist"))
+	_, err = f.getSharedStringItem(0)
+	assert.Error(t, err)
+	f.tempFiles.Delete(defaultXMLPathSharedStrings)
+	assert.NoError(t, f.Close())
+}
@@ -356,6 +356,7 @@ func (f *File) getActiveSheetID() int {
 // sheet name in the formula or reference associated with the cell. So there
 // may be problem formula error or reference missing.
 func (f *File) SetSheetName(source, target string) error {
+	f.clearCalcCache()
 	var err error
 	if err = checkSheetName(source); err != nil {
 		return err
@@ -555,6 +556,7 @@ func (f *File) setSheetBackground(sheet, extension string, file []byte) error {
 // value of the deleted worksheet, it will cause a file error when you open
 // it. This function will be invalid when only one worksheet is left.
 func (f *File) DeleteSheet(sheet string) error {
+	f.clearCalcCache()
 	if err := checkSheetName(sheet); err != nil {
 		return err
 	}
@@ -693,6 +695,7 @@ func (f *File) getSheetRelationshipsTargetByID(sheet, rID string) string {
 //	}
 //	err := f.CopySheet(1, index)
 func (f *File) CopySheet(from, to int) error {
+	f.clearCalcCache()
 	if from < 0 || to < 0 || from == to || f.GetSheetName(from) == "" || f.GetSheetName(to) == "" {
 		return ErrSheetIdx
 	}
@@ -1053,7 +1056,7 @@ func (f *File) searchSheet(name, value string, regSearch bool) (result []string,
 		sst                 *xlsxSST
 	)
 
-	if sst, err = f.sharedStringsReader(); err != nil {
+	if sst, err = f.sharedStringsValueReader(); err != nil {
 		return
 	}
 	regex := regexp.MustCompile(value)
@@ -1653,6 +1656,7 @@ func (f *File) GetPageLayout(sheet string) (PageLayoutOptions, error) {
 //	    Scope:    "Sheet2",
 //	})
 func (f *File) SetDefinedName(definedName *DefinedName) error {
+	f.clearCalcCache()
 	if definedName.Name == "" || definedName.RefersTo == "" {
 		return ErrParameterInvalid
 	}
@@ -1701,6 +1705,7 @@ func (f *File) SetDefinedName(definedName *DefinedName) error {
 //	    Scope:    "Sheet2",
 //	})
 func (f *File) DeleteDefinedName(definedName *DefinedName) error {
+	f.clearCalcCache()
 	wb, err := f.workbookReader()
 	if err != nil {
 		return err
@@ -23,7 +23,11 @@ import (
 	"time"
 )
 
-// StreamWriter defined the type of stream writer.
+// StreamWriter defined the type of stream writer. The worksheet which loaded
+// by the stream writer is shared with the File until calling 'Flush', so the
+// comments, hyperlinks, data validations and conditional formats set by the
+// stream writer are applied to this worksheet by the File functions directly,
+// and will be written after the sheet data when calling 'Flush'.
 type StreamWriter struct {
 	file            *File
 	Sheet           string
@@ -36,6 +40,16 @@ type StreamWriter struct {
 	mergeCellsCount int
 	mergeCells      strings.Builder
 	tableParts      string
+	keepRows        int
+	structStyles    map[reflect.Type][]int
+}
+
+// StreamWriterOptions directly maps the settings of the stream writer.
+// KeepRows specifies the number of leading rows in the existing worksheet
+// data which will be kept, the stream writer will append new rows after
+// them. The rows after that will be discarded.
+type StreamWriterOptions struct {
+	KeepRows int
 }
 
 // NewStreamWriter returns stream writer struct by given worksheet name used for
@@ -112,7 +126,18 @@ type StreamWriter struct {
 //	err := sw.SetRow("A1", []interface{}{
 //	    excelize.Cell{Value: 1}},
 //	    excelize.RowOpts{StyleID: styleID, Height: 20, Hidden: false});
-func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
+//
+// Append rows after the header block of an existing worksheet with stream
+// writer, keeping the first 5 rows, merged cells, column widths, conditional
+// formats and drawings of the worksheet:
+//
+//	sw, err := f.NewStreamWriter("Sheet1", excelize.StreamWriterOptions{KeepRows: 5})
+//	if err != nil {
+//	    fmt.Println(err)
+//	    return
+//	}
+//	err = sw.SetRow("A6", []interface{}{"Data"})
+func (f *File) NewStreamWriter(sheet string, opts ...StreamWriterOptions) (*StreamWriter, error) {
 	if err := checkSheetName(sheet); err != nil {
 		return nil, err
 	}
@@ -125,11 +150,18 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 		Sheet:   sheet,
 		SheetID: sheetID,
 	}
+	for _, opt := range opts {
+		if opt.KeepRows < 0 || opt.KeepRows > TotalRows {
+			return nil, ErrMaxRows
+		}
+		sw.keepRows = opt.KeepRows
+	}
 	var err error
 	sw.worksheet, err = f.workSheetReader(sheet)
 	if err != nil {
 		return nil, err
 	}
+	sw.prepareKeepRows()
 
 	sheetXMLPath, _ := f.getSheetXMLPath(sheet)
 	if f.streams == nil {
@@ -142,6 +174,52 @@ func (f *File) NewStreamWriter(sheet string) (*StreamWriter, error) {
 	return sw, err
 }
 
+// prepareKeepRows loads the column definitions and merged cells of the
+// existing worksheet into the stream writer buffers when keeping the leading
+// rows of the worksheet. Only the merged cells which are fully inside the kept
+// rows will be loaded, since the rows after them will be discarded.
+func (sw *StreamWriter) prepareKeepRows() {
+	if sw.keepRows == 0 {
+		return
+	}
+	sw.rows = sw.keepRows
+	if sw.worksheet.Cols != nil {
+		enc := xml.NewEncoder(&sw.cols)
+		for _, col := range sw.worksheet.Cols.Col {
+			_ = enc.EncodeElement(col, xml.StartElement{Name: xml.Name{Local: "col"}})
+		}
+	}
+	if sw.worksheet.MergeCells != nil {
+		for _, mergeCell := range sw.worksheet.MergeCells.Cells {
+			if mergeCell == nil || mergeCell.Ref == "" {
+				continue
+			}
+			if rect, err := mergeCell.Rect(); err != nil || rect[1] > sw.keepRows || rect[3] > sw.keepRows {
+				continue
+			}
+			sw.mergeCellsCount++
+			_, _ = sw.mergeCells.WriteString(`<mergeCell ref="`)
+			_, _ = sw.mergeCells.WriteString(mergeCell.Ref)
+			_, _ = sw.mergeCells.WriteString(`"/>`)
+		}
+	}
+}
+
+// writeKeepRows writes the kept leading rows of the existing worksheet data
+// to the buffer.
+func (sw *StreamWriter) writeKeepRows() {
+	enc := xml.NewEncoder(&sw.rawData)
+	for _, row := range sw.worksheet.SheetData.Row {
+		if row.R > sw.keepRows {
+			break
+		}
+		if row = trimCell(row); len(row.C) != 0 || row.hasAttr() {
+			_ = enc.EncodeElement(row, xml.StartElement{Name: xml.Name{Local: "row"}})
+		}
+	}
+	_ = enc.Flush()
+}
+
 // AddTable creates an Excel table for the StreamWriter using the given
 // cell range and format set. For example, create a table of A1:D5:
 //
@@ -239,7 +317,12 @@ func (sw *StreamWriter) AddTable(table *Table) error {
 	sheetRels := "xl/worksheets/_rels/" + strings.TrimPrefix(sheetPath, "xl/worksheets/") + ".rels"
 	rID := sw.file.addRels(sheetRels, SourceRelationshipTable, sheetRelationshipsTableXML, "")
 
-	sw.tableParts = fmt.Sprintf(`<tableParts count="1"><tablePart r:id="rId%d"></tablePart></tableParts>`, rID)
+	if sw.worksheet.TableParts != nil {
+		sw.worksheet.TableParts.Count++
+		sw.worksheet.TableParts.TableParts = append(sw.worksheet.TableParts.TableParts, &xlsxTablePart{RID: "rId" + strconv.Itoa(rID)})
+	} else {
+		sw.tableParts = fmt.Sprintf(`<tableParts count="1"><tablePart r:id="rId%d"></tablePart></tableParts>`, rID)
+	}
 
 	if err = sw.file.addContentTypePart(tableID, "table"); err != nil {
 		return err
@@ -284,7 +367,13 @@ func (sw *StreamWriter) getRowValues(hRow, hCol, vCol int) (res []string, err er
 			if col < hCol || col > vCol {
 				continue
 			}
-			res[col-hCol], _ = c.getValueFrom(sw.file, nil, false)
+			var sst *xlsxSST
+			if c.T == "s" {
+				if sst, err = sw.file.sharedStringsReader(); err != nil {
+					return nil, err
+				}
+			}
+			res[col-hCol], _ = c.getValueFrom(sw.file, sst, false)
 		}
 		return res, nil
 	}
@@ -498,6 +587,86 @@ func (sw *StreamWriter) MergeCell(topLeftCell, bottomRightCell string) error {
 	return nil
 }
 
+// AddComment provides a function to add comment on a cell for the
+// StreamWriter. The comments and VML drawing parts will be created by the
+// File.AddComment function immediately, and the legacy drawing of the shared
+// worksheet will be written when calling 'Flush'. For example, add a comment
+// in Sheet1!A1:
+//
+//	err := sw.AddComment(excelize.Comment{
+//	    Cell:   "A1",
+//	    Author: "Excelize",
+//	    Text:   "This is a comment.",
+//	})
+//
+// See File.AddComment for details on the comment options.
+func (sw *StreamWriter) AddComment(opts Comment) error {
+	if _, _, err := CellNameToCoordinates(opts.Cell); err != nil {
+		return err
+	}
+	return sw.file.AddComment(sw.Sheet, opts)
+}
+
+// SetCellHyperLink provides a function to set cell hyperlink by given cell
+// reference and link URL for the StreamWriter. The linkType must be
+// "External" for a website or "Location" for moving to one of the cells in
+// the workbook. Note that this only sets the hyperlink of the cell on the
+// shared worksheet by the File.SetCellHyperLink function, the cell value
+// should be written by the 'SetRow' function. For example, add an external
+// hyperlink on Sheet1!A3:
+//
+//	display, tooltip := "https://github.com/xuri/excelize", "Excelize on GitHub"
+//	err := sw.SetCellHyperLink("A3", "https://github.com/xuri/excelize",
+//	    "External", excelize.HyperlinkOpts{
+//	        Display: &display,
+//	        Tooltip: &tooltip,
+//	    })
+//
+// See File.SetCellHyperLink for details on the hyperlink options.
+func (sw *StreamWriter) SetCellHyperLink(cell, link, linkType string, opts ...HyperlinkOpts) error {
+	return sw.file.SetCellHyperLink(sw.Sheet, cell, link, linkType, opts...)
+}
+
+// AddDataValidation provides a function to set data validation on a range
+// of the worksheet for the StreamWriter, the data validation will be appended
+// to the shared worksheet by the File.AddDataValidation function. For example,
+// add a drop-down list on Sheet1!A1:A1048576:
+//
+//	dv := excelize.NewDataValidation(true)
+//	dv.Sqref = "A1:A1048576"
+//	err := dv.SetDropList([]string{"1", "2", "3"})
+//	err = sw.AddDataValidation(dv)
+//
+// See File.AddDataValidation for details on the data validation settings.
+func (sw *StreamWriter) AddDataValidation(dv *DataValidation) error {
+	if dv == nil {
+		return ErrParameterRequired
+	}
+	return sw.file.AddDataValidation(sw.Sheet, dv)
+}
+
+// SetConditionalFormat provides a function to create conditional formatting
+// rule for cell value by given range reference and format options for the
+// StreamWriter. The rules will be set on the shared worksheet by the
+// File.SetConditionalFormat function, so this function should be called
+// before 'Flush'. For example, highlight cells with values greater than 6 in
+// Sheet1!A1:A102400:
+//
+//	format, err := f.NewConditionalStyle(&excelize.Style{
+//	    Font: &excelize.Font{Color: "9A0511"},
+//	    Fill: excelize.Fill{Type: "pattern", Color: []string{"FEC7CE"}, Pattern: 1},
+//	})
+//	err = sw.SetConditionalFormat("A1:A102400",
+//	    []excelize.ConditionalFormatOptions{
+//	        {Type: "cell", Criteria: ">", Format: &format, Value: "6"},
+//	    },
+//	)
+//
+// See File.SetConditionalFormat for details on the format options.
+func (sw *StreamWriter) SetConditionalFormat(rangeRef string, opts []ConditionalFormatOptions) error {
+	return sw.file.SetConditionalFormat(sw.Sheet, rangeRef, opts)
+}
+
 // setCellFormula provides a function to set formula of a cell.
 func setCellFormula(c *xlsxC, formula string) {
 	if formula != "" {
@@ -648,12 +817,14 @@ func (sw *StreamWriter) writeSheetData() {
 			_, _ = sw.rawData.WriteString("</cols>")
 		}
 		_, _ = sw.rawData.WriteString(`<sheetData>`)
+		sw.writeKeepRows()
 		sw.sheetWritten = true
 	}
 }
 
 // Flush ending the streaming writing process.
 func (sw *StreamWriter) Flush() error {
+	sw.file.clearCalcCache()
 	sw.writeSheetData()
 	_, _ = sw.rawData.WriteString(`</sheetData>`)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 8, 15)
@@ -669,6 +840,11 @@ func (sw *StreamWriter) Flush() error {
 	bulkAppendFields(&sw.rawData, sw.worksheet, 17, 38)
 	_, _ = sw.rawData.WriteString(sw.tableParts)
 	bulkAppendFields(&sw.rawData, sw.worksheet, 40, 40)
+	if sw.worksheet.ExtLst != nil {
+		_, _ = sw.rawData.WriteString(`<extLst>`)
+		_, _ = sw.rawData.WriteString(sw.worksheet.ExtLst.Ext)
+		_, _ = sw.rawData.WriteString(`</extLst>`)
+	}
 	_, _ = sw.rawData.WriteString(`</worksheet>`)
 	if err := sw.rawData.Flush(); err != nil {
 		return err
@@ -261,6 +261,124 @@ func TestStreamInsertPageBreak(t *testing.T) {
 	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamInsertPageBreak.xlsx")))
 }
 
+func TestStreamAddComment(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"Data"}))
+	assert.NoError(t, streamWriter.AddComment(Comment{Cell: "A1", Author: "Excelize", Text: "This is a comment."}))
+	assert.NoError(t, streamWriter.AddComment(Comment{Cell: "B2", Text: "Another comment."}))
+	// Test add comment with illegal cell reference
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), streamWriter.AddComment(Comment{Cell: "A"}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamAddComment.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamAddComment.xlsx"))
+	assert.NoError(t, err)
+	comments, err := f.GetComments("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "A1", comments[0].Cell)
+	assert.Equal(t, "Excelize", comments[0].Author)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamSetCellHyperLink(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{"GitHub", "Sheet2"}))
+	display, tooltip := "GitHub", "Excelize on GitHub"
+	assert.NoError(t, streamWriter.SetCellHyperLink("A1", "https://github.com/xuri/excelize", "External", HyperlinkOpts{Display: &display, Tooltip: &tooltip}))
+	assert.NoError(t, streamWriter.SetCellHyperLink("B1", "Sheet1!A1", "Location"))
+	// Test set cell hyperlink with invalid link type
+	assert.Equal(t, newInvalidLinkTypeError(""), streamWriter.SetCellHyperLink("C1", "Sheet1!A1", ""))
+	// Test set cell hyperlink with illegal cell reference
+	assert.Equal(t, newInvalidCellNameError("A"), streamWriter.SetCellHyperLink("A", "Sheet1!A1", "Location"))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetCellHyperLink.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamSetCellHyperLink.xlsx"))
+	assert.NoError(t, err)
+	link, target, err := f.GetCellHyperLink("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	link, target, err = f.GetCellHyperLink("Sheet1", "B1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "Sheet1!A1", target)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamAddDataValidation(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetRow("A1", []interface{}{1}))
+	dv := NewDataValidation(true)
+	dv.Sqref = "A1:A1048576"
+	assert.NoError(t, dv.SetDropList([]string{"1", "2", "3"}))
+	assert.NoError(t, streamWriter.AddDataValidation(dv))
+	// Test add data validation without data validation settings
+	assert.Equal(t, ErrParameterRequired, streamWriter.AddDataValidation(nil))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamAddDataValidation.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamAddDataValidation.xlsx"))
+	assert.NoError(t, err)
+	dvs, err := f.GetDataValidations("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, dvs, 1)
+	assert.Equal(t, "A1:A1048576", dvs[0].Sqref)
+	assert.Equal(t, `"1,2,3"`, dvs[0].Formula1)
+	assert.NoError(t, f.Close())
+}
+
+func TestStreamSetConditionalFormat(t *testing.T) {
+	file := NewFile()
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	streamWriter, err := file.NewStreamWriter("Sheet1")
+	assert.NoError(t, err)
+	for rowID := 1; rowID <= 10; rowID++ {
+		cell, err := CoordinatesToCellName(1, rowID)
+		assert.NoError(t, err)
+		assert.NoError(t, streamWriter.SetRow(cell, []interface{}{rowID}))
+	}
+	format, err := file.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	assert.NoError(t, streamWriter.SetConditionalFormat("A1:A10", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: &format, Value: "6"},
+	}))
+	assert.NoError(t, streamWriter.SetConditionalFormat("B1:B10", []ConditionalFormatOptions{
+		{Type: "data_bar", Criteria: "=", MinType: "min", MaxType: "max", BarColor: "638EC6", BarSolid: true},
+	}))
+	// Test set conditional format with invalid options
+	assert.Equal(t, ErrParameterInvalid, streamWriter.SetConditionalFormat("A1:A10", []ConditionalFormatOptions{{Type: "unknown"}}))
+	assert.NoError(t, streamWriter.Flush())
+	assert.NoError(t, file.SaveAs(filepath.Join("test", "TestStreamSetConditionalFormat.xlsx")))
+
+	f, err := OpenFile(filepath.Join("test", "TestStreamSetConditionalFormat.xlsx"))
+	assert.NoError(t, err)
+	opts, err := f.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, opts, 2)
+	assert.Equal(t, "6", opts["A1:A10"][0].Value)
+	assert.True(t, opts["B1:B10"][0].BarSolid)
+	assert.NoError(t, f.Close())
+}
+
 func TestNewStreamWriter(t *testing.T) {
 	// Test error exceptions
 	file := NewFile()
@@ -276,6 +394,73 @@ func TestNewStreamWriter(t *testing.T) {
 	assert.Equal(t, ErrSheetNameInvalid, err)
 }
 
+func TestStreamWriterKeepRows(t *testing.T) {
+	f := NewFile()
+	defer func() {
+		assert.NoError(t, f.Close())
+	}()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"Monthly Report"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"Name", "Value"}))
+	assert.NoError(t, f.SetSheetRow("Sheet1", "A3", &[]interface{}{"Discarded", 1}))
+	assert.NoError(t, f.MergeCell("Sheet1", "A1", "B1"))
+	assert.NoError(t, f.MergeCell("Sheet1", "C2", "C3"))
+	assert.NoError(t, f.MergeCell("Sheet1", "A5", "B6"))
+	assert.NoError(t, f.SetColWidth("Sheet1", "A", "A", 30))
+	format, err := f.NewConditionalStyle(&Style{Font: &Font{Color: "9A0511"}})
+	assert.NoError(t, err)
+	assert.NoError(t, f.SetConditionalFormat("Sheet1", "B3:B10", []ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: &format, Value: "6"},
+	}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "D1:E2"}))
+
+	sw, err := f.NewStreamWriter("Sheet1", StreamWriterOptions{KeepRows: 2})
+	assert.NoError(t, err)
+	// Test set row before the kept rows
+	assert.Equal(t, newStreamSetRowError(2), sw.SetRow("A2", []interface{}{"Data"}))
+	assert.NoError(t, sw.SetColWidth(2, 2, 20))
+	for rowID := 3; rowID <= 10; rowID++ {
+		cell, err := CoordinatesToCellName(1, rowID)
+		assert.NoError(t, err)
+		assert.NoError(t, sw.SetRow(cell, []interface{}{"Item", rowID}))
+	}
+	assert.NoError(t, sw.AddTable(&Table{Range: "A2:B10"}))
+	assert.NoError(t, sw.Flush())
+	assert.NoError(t, f.SaveAs(filepath.Join("test", "TestStreamWriterKeepRows.xlsx")))
+
+	file, err := OpenFile(filepath.Join("test", "TestStreamWriterKeepRows.xlsx"))
+	assert.NoError(t, err)
+	rows, err := file.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, rows, 10)
+	assert.Equal(t, []string{"Monthly Report", "", "", "Column1", "Column2"}, rows[0])
+	assert.Equal(t, []string{"Name", "Value"}, rows[1])
+	assert.Equal(t, []string{"Item", "3"}, rows[2])
+	mergeCells, err := file.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A1", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B1", mergeCells[0].GetEndAxis())
+	width, err := file.GetColWidth("Sheet1", "A")
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, width)
+	width, err = file.GetColWidth("Sheet1", "B")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	condFmts, err := file.GetConditionalFormats("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, condFmts, 1)
+	tables, err := file.GetTables("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, tables, 2)
+	assert.NoError(t, file.Close())
+
+	// Test new stream writer with invalid keep rows
+	_, err = f.NewStreamWriter("Sheet1", StreamWriterOptions{KeepRows: -1})
+	assert.Equal(t, ErrMaxRows, err)
+	_, err = f.NewStreamWriter("Sheet1", StreamWriterOptions{KeepRows: TotalRows + 1})
+	assert.Equal(t, ErrMaxRows, err)
+}
+
 func TestStreamMarshalAttrs(t *testing.T) {
 	var r *RowOpts
 	attrs, err := r.marshalAttrs()
@@ -14,6 +14,7 @@ package excelize
 import (
 	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
@@ -79,6 +80,7 @@ func parseTableOptions(opts *Table) (*Table, error) {
 //	TableStyleMedium1 - TableStyleMedium28
 //	TableStyleDark1 - TableStyleDark11
 func (f *File) AddTable(sheet string, table *Table) error {
+	f.clearCalcCache()
 	options, err := parseTableOptions(table)
 	if err != nil {
 		return err
@@ -168,8 +170,226 @@ func (f *File) GetTables(sheet string) ([]Table, error) {
 	return tables, err
 }
 
+// getTableXML provides a function to get the definition of the table by given
+// table part path.
+func (f *File) getTableXML(tableXML string) (*xlsxTable, error) {
+	var t xlsxTable
+	content, ok := f.Pkg.Load(tableXML)
+	if !ok {
+		return nil, nil
+	}
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(content.([]byte)))).
+		Decode(&t); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// getTableByName provides a function to get the worksheet name and the
+// definition of the table by given table name, the table name is case
+// insensitive.
+func (f *File) getTableByName(name string) (string, *xlsxTable, error) {
+	for _, sheet := range f.GetSheetList() {
+		tables, err := f.GetTables(sheet)
+		if err != nil {
+			if err.Error() == newNotWorksheetError(sheet).Error() {
+				continue
+			}
+			return sheet, nil, err
+		}
+		for _, table := range tables {
+			if !strings.EqualFold(table.Name, name) {
+				continue
+			}
+			t, err := f.getTableXML(table.tableXML)
+			return sheet, t, err
+		}
+	}
+	return "", nil, nil
+}
+
+// getTableByCell provides a function to get the definition of the table which
+// contains the cell by given worksheet name and cell reference.
+func (f *File) getTableByCell(sheet, cell string) (*xlsxTable, error) {
+	col, row, err := CellNameToCoordinates(cell)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := f.GetTables(sheet)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		coordinates, err := rangeRefToCoordinates(table.Range)
+		if err != nil {
+			return nil, err
+		}
+		_ = sortCoordinates(coordinates)
+		if col >= coordinates[0] && col <= coordinates[2] && row >= coordinates[1] && row <= coordinates[3] {
+			return f.getTableXML(table.tableXML)
+		}
+	}
+	return nil, nil
+}
+
+// parseStructuredReference provides a function to convert the structured
+// reference to the range reference by given worksheet name and cell reference
+// of the formula. For example, convert Table1[[#Headers],[Column1]] to
+// Sheet1!A1:A1. The structured reference without the table name refers to the
+// table which contains the formula cell.
+func (f *File) parseStructuredReference(sheet, cell, reference string) (string, error) {
+	idx := strings.Index(reference, "[")
+	if idx == -1 || !strings.HasSuffix(reference, "]") {
+		return "", errors.New(formulaErrorREF)
+	}
+	var (
+		tableSheet = sheet
+		tbl        *xlsxTable
+		err        error
+		name       = reference[:idx]
+	)
+	if name == "" {
+		tbl, err = f.getTableByCell(sheet, cell)
+	} else {
+		tableSheet, tbl, err = f.getTableByName(name)
+	}
+	if err != nil {
+		return "", err
+	}
+	if tbl == nil {
+		return "", errors.New(formulaErrorREF)
+	}
+	items, err := splitStructuredReferenceItems(reference[idx+1 : len(reference)-1])
+	if err != nil {
+		return "", err
+	}
+	coordinates, err := rangeRefToCoordinates(tbl.Ref)
+	if err != nil {
+		return "", err
+	}
+	_ = sortCoordinates(coordinates)
+	headerRows := 1
+	if tbl.HeaderRowCount != nil {
+		headerRows = *tbl.HeaderRowCount
+	}
+	var (
+		fromCol, fromRow, toCol, toRow int
+		dataFromRow, dataToRow         = coordinates[1] + headerRows, coordinates[3] - tbl.TotalsRowCount
+	)
+	span := func(from, to int) {
+		if fromRow == 0 || from < fromRow {
+			fromRow = from
+		}
+		if to > toRow {
+			toRow = to
+		}
+	}
+	for _, item := range items {
+		switch strings.ToUpper(item) {
+		case "#ALL":
+			span(coordinates[1], coordinates[3])
+		case "#DATA":
+			span(dataFromRow, dataToRow)
+		case "#HEADERS":
+			if headerRows == 0 {
+				return "", errors.New(formulaErrorREF)
+			}
+			span(coordinates[1], dataFromRow-1)
+		case "#TOTALS":
+			if tbl.TotalsRowCount == 0 {
+				return "", errors.New(formulaErrorREF)
+			}
+			span(dataToRow+1, coordinates[3])
+		case "#THIS ROW":
+			_, row, err := CellNameToCoordinates(cell)
+			if err != nil || row < dataFromRow || row > dataToRow {
+				return "", errors.New(formulaErrorVALUE)
+			}
+			span(row, row)
+		default:
+			col := -1
+			if tbl.TableColumns != nil {
+				for i, column := range tbl.TableColumns.TableColumn {
+					if column != nil && strings.EqualFold(column.Name, item) {
+						col = coordinates[0] + i
+						break
+					}
+				}
+			}
+			if col == -1 {
+				return "", errors.New(formulaErrorREF)
+			}
+			if fromCol == 0 || col < fromCol {
+				fromCol = col
+			}
+			if col > toCol {
+				toCol = col
+			}
+		}
+	}
+	if fromRow == 0 {
+		span(dataFromRow, dataToRow)
+	}
+	if fromCol == 0 {
+		fromCol, toCol = coordinates[0], coordinates[2]
+	}
+	if fromRow > toRow {
+		return "", errors.New(formulaErrorREF)
+	}
+	ref, err := coordinatesToRangeRef([]int{fromCol, fromRow, toCol, toRow})
+	return tableSheet + "!" + ref, err
+}
+
+// splitStructuredReferenceItems provides a function to split the item
+// specifiers and the column specifiers in the brackets of the structured
+// reference, the "@" prefix will be converted to the "#This Row" specifier.
+// For example, split "[#Headers],[Column1]:[Column2]" into "#Headers",
+// "Column1" and "Column2".
+func splitStructuredReferenceItems(spec string) ([]string, error) {
+	var (
+		items []string
+		item  strings.Builder
+		depth int
+	)
+	if spec = strings.TrimSpace(spec); strings.HasPrefix(spec, "@") {
+		items, spec = append(items, "#This Row"), strings.TrimSpace(spec[1:])
+	}
+	if spec == "" {
+		return items, nil
+	}
+	if !strings.HasPrefix(spec, "[") {
+		spec = "[" + spec + "]"
+	}
+	for i := 0; i < len(spec); i++ {
+		switch ch := spec[i]; {
+		case ch == '\'' && depth == 1 && i+1 < len(spec):
+			i++
+			item.WriteByte(spec[i])
+		case ch == '[':
+			if depth++; depth > 1 {
+				return items, errors.New(formulaErrorREF)
+			}
+		case ch == ']':
+			if depth--; depth < 0 {
+				return items, errors.New(formulaErrorREF)
+			}
+			items = append(items, strings.TrimSpace(item.String()))
+			item.Reset()
+		case depth == 1:
+			item.WriteByte(ch)
+		case ch != ',' && ch != ':' && ch != ' ':
+			return items, errors.New(formulaErrorREF)
+		}
+	}
+	if depth != 0 {
+		return items, errors.New(formulaErrorREF)
+	}
+	return items, nil
+}
+
 // DeleteTable provides the method to delete table by given table name.
 func (f *File) DeleteTable(name string) error {
+	f.clearCalcCache()
 	if err := checkDefinedName(name); err != nil {
 		return err
 	}
@@ -1,6 +1,7 @@
 package excelize
 
 import (
+	"encoding/xml"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -137,6 +138,82 @@ func TestDeleteTable(t *testing.T) {
 	assert.Equal(t, "Values", val)
 }
 
+func TestParseStructuredReference(t *testing.T) {
+	f := NewFile()
+	assert.NoError(t, f.SetSheetRow("Sheet1", "B2", &[]interface{}{"Name", "Unit Price", "Qty [pcs]"}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "B2:D6", Name: "Table1"}))
+	assert.NoError(t, f.AddTable("Sheet1", &Table{Range: "F2:F4", Name: "Table2", ShowHeaderRow: boolPtr(false)}))
+	// Set the totals row for the first table
+	tbl, err := f.getTableXML("xl/tables/table1.xml")
+	assert.NoError(t, err)
+	tbl.TotalsRowCount = 1
+	output, err := xml.Marshal(tbl)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/tables/table1.xml", output)
+	for reference, expected := range map[string]string{
+		"Table1[]":                              "Sheet1!B3:D5",
+		"table1[#All]":                          "Sheet1!B2:D6",
+		"Table1[#Data]":                         "Sheet1!B3:D5",
+		"Table1[#Headers]":                      "Sheet1!B2:D2",
+		"Table1[#Totals]":                       "Sheet1!B6:D6",
+		"Table1[Name]":                          "Sheet1!B3:B5",
+		"Table1[[Unit Price]]":                  "Sheet1!C3:C5",
+		"Table1[[#Headers],[#Data],[Name]]":     "Sheet1!B2:B5",
+		"Table1[[#Totals],[Name]:[Unit Price]]": "Sheet1!B6:C6",
+		"Table1[[Qty '[pcs']]]":                 "Sheet1!D3:D5",
+		"Table1[@]":                             "Sheet1!B4:D4",
+		"Table1[@Name]":                         "Sheet1!B4:B4",
+		"[@[Unit Price]]":                       "Sheet1!C4:C4",
+		"[[#This Row],[Name]]":                  "Sheet1!B4:B4",
+		"Table2[#Data]":                         "Sheet1!F3:F4",
+	} {
+		ref, err := f.parseStructuredReference("Sheet1", "C4", reference)
+		assert.NoError(t, err, reference)
+		assert.Equal(t, expected, ref, reference)
+	}
+	for reference, expected := range map[string]string{
+		"Table1":              formulaErrorREF,
+		"Table1[Name":         formulaErrorREF,
+		"Table3[Name]":        formulaErrorREF,
+		"[Name]":              formulaErrorREF,
+		"Table1[Price]":       formulaErrorREF,
+		"Table1[[Name]]]":     formulaErrorREF,
+		"Table1[[[Name]]]":    formulaErrorREF,
+		"Table1[[Name]x]":     formulaErrorREF,
+		"Table1[[Name]":       formulaErrorREF,
+		"Table2[#Headers]":    formulaErrorREF,
+		"Table2[#Totals]":     formulaErrorREF,
+		"Table2[#This Row]":   formulaErrorVALUE,
+		"Table1[[#This Row]]": formulaErrorVALUE,
+		"Table1[[#Data],[@]]": formulaErrorREF,
+	} {
+		_, err := f.parseStructuredReference("Sheet1", "A1", reference)
+		assert.EqualError(t, err, expected, reference)
+	}
+	// Test parse structured reference with invalid cell reference
+	_, err = f.parseStructuredReference("Sheet1", "A", "[Name]")
+	assert.Equal(t, newCellNameToCoordinatesError("A", newInvalidCellNameError("A")), err)
+	// Test parse structured reference with invalid table range
+	tbl.Ref = "B2:D"
+	output, err = xml.Marshal(tbl)
+	assert.NoError(t, err)
+	f.Pkg.Store("xl/tables/table1.xml", output)
+	_, err = f.parseStructuredReference("Sheet1", "C4", "Table1[Name]")
+	assert.Equal(t, newCellNameToCoordinatesError("D", newInvalidCellNameError("D")), err)
+	// Test parse structured reference with unsupported charset table
+	f.Pkg.Store("xl/tables/table1.xml", MacintoshCyrillicCharset)
+	_, err = f.parseStructuredReference("Sheet1", "C4", "Table1[Name]")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test parse structured reference with unsupported charset worksheet
+	f.Sheet.Delete("xl/worksheets/sheet1.xml")
+	f.Pkg.Store("xl/worksheets/sheet1.xml", MacintoshCyrillicCharset)
+	_, err = f.parseStructuredReference("Sheet1", "C4", "Table1[Name]")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = f.parseStructuredReference("Sheet1", "C4", "[Name]")
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	assert.NoError(t, f.Close())
+}
+
 func TestSetTableColumns(t *testing.T) {
 	f := NewFile()
 	assert.Equal(t, newCoordinatesToCellNameError(1, 0), f.setTableColumns("Sheet1", true, 1, 0, 1, nil))
@@ -0,0 +1,758 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+
+	"github.com/richardlehane/mscfb"
+)
+
+// Binary Interchange File Format 8 (BIFF8) record types of the Excel 97-2003
+// workbook (.xls) file format, which defined in the [MS-XLS] specification.
+const (
+	biffFormula    = 0x0006
+	biffEOF        = 0x000A
+	biffDateMode   = 0x0022
+	biffFilePass   = 0x002F
+	biffFont       = 0x0031
+	biffContinue   = 0x003C
+	biffColInfo    = 0x007D
+	biffBoundSheet = 0x0085
+	biffMulRk      = 0x00BD
+	biffMulBlank   = 0x00BE
+	biffRString    = 0x00D6
+	biffXF         = 0x00E0
+	biffMergeCells = 0x00E5
+	biffSST        = 0x00FC
+	biffLabelSST   = 0x00FD
+	biffDimensions = 0x0200
+	biffBlank      = 0x0201
+	biffNumber     = 0x0203
+	biffLabel      = 0x0204
+	biffBoolErr    = 0x0205
+	biffString     = 0x0207
+	biffRow        = 0x0208
+	biffArray      = 0x0221
+	biffTable      = 0x0236
+	biffRK         = 0x027E
+	biffFormat     = 0x041E
+	biffShrFmla    = 0x04BC
+	biffBOF        = 0x0809
+	biffVersion    = 0x0600
+)
+
+// biffReader directly maps the reader of the BIFF8 record and the following
+// CONTINUE records, the data of each record will be kept as a segment. The
+// reader will be marked as failed if there is not enough data for reading,
+// and all the following read operations returns zero values.
+type biffReader struct {
+	segs     [][]byte
+	seg, off int
+	err      error
+}
+
+// read provides a function to read given number of bytes from the reader,
+// the bytes could be read across the segments.
+func (r *biffReader) read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 {
+		r.err = ErrWorkbookFileFormat
+		return nil
+	}
+	var b []byte
+	for n > 0 {
+		if r.seg >= len(r.segs) {
+			r.err = ErrWorkbookFileFormat
+			return nil
+		}
+		cur := r.segs[r.seg][r.off:]
+		if len(cur) == 0 {
+			r.seg, r.off = r.seg+1, 0
+			continue
+		}
+		if len(cur) >= n && b == nil {
+			r.off += n
+			return cur[:n]
+		}
+		m := len(cur)
+		if m > n {
+			m = n
+		}
+		b, r.off, n = append(b, cur[:m]...), r.off+m, n-m
+	}
+	return b
+}
+
+// uint8 provides a function to read an unsigned 8-bit integer.
+func (r *biffReader) uint8() uint8 {
+	if b := r.read(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// uint16 provides a function to read an unsigned 16-bit integer in little
+// endian.
+func (r *biffReader) uint16() uint16 {
+	if b := r.read(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+// uint32 provides a function to read an unsigned 32-bit integer in little
+// endian.
+func (r *biffReader) uint32() uint32 {
+	if b := r.read(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+// float64 provides a function to read an IEEE 754 floating-point number.
+func (r *biffReader) float64() float64 {
+	if b := r.read(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+// chars provides a function to read given number of characters, which
+// encoded in UTF-16 if the high byte flag is set, or the low bytes of the
+// UTF-16 characters otherwise. When the characters split across records,
+// the characters in the CONTINUE record are preceded by a new option flags
+// byte which specifies the high byte flag of the rest characters.
+func (r *biffReader) chars(cch int, highByte bool) string {
+	u := make([]uint16, 0, cch)
+	for len(u) < cch && r.err == nil {
+		if r.seg+1 < len(r.segs) && r.off == len(r.segs[r.seg]) {
+			r.seg, r.off = r.seg+1, 0
+			highByte = r.uint8()&0x01 != 0
+		}
+		if highByte {
+			u = append(u, r.uint16())
+			continue
+		}
+		u = append(u, uint16(r.uint8()))
+	}
+	return string(utf16.Decode(u))
+}
+
+// shortString provides a function to read a ShortXLUnicodeString, which an
+// 8-bit characters count followed by the option flags and the characters.
+func (r *biffReader) shortString() string {
+	cch := int(r.uint8())
+	return r.chars(cch, r.uint8()&0x01 != 0)
+}
+
+// unicodeString provides a function to read a XLUnicodeString, which a
+// 16-bit characters count followed by the option flags and the characters.
+func (r *biffReader) unicodeString() string {
+	cch := int(r.uint16())
+	return r.chars(cch, r.uint8()&0x01 != 0)
+}
+
+// richExtendedString provides a function to read a
+// XLUnicodeRichExtendedString in the shared strings table, the formatting
+// runs and phonetic properties of the string will be skipped.
+func (r *biffReader) richExtendedString() string {
+	cch, flags := int(r.uint16()), r.uint8()
+	var runs, ext int
+	if flags&0x08 != 0 {
+		runs = int(r.uint16())
+	}
+	if flags&0x04 != 0 {
+		ext = int(int32(r.uint32()))
+	}
+	str := r.chars(cch, flags&0x01 != 0)
+	r.read(runs*4 + ext)
+	return str
+}
+
+// readBIFFRecords provides a function to walk through the BIFF8 records of
+// the substream which begins with the BOF record at given offset of the
+// workbook stream, and call the given function with the record type and the
+// reader of each record. The records of the embedded substreams, such as
+// the charts in the worksheet, will be skipped.
+func readBIFFRecords(stream []byte, offset int, fn func(typ int, r *biffReader) error) error {
+	var depth int
+	for off := offset; off >= 0 && off+4 <= len(stream); {
+		typ, size := int(binary.LittleEndian.Uint16(stream[off:])), int(binary.LittleEndian.Uint16(stream[off+2:]))
+		if off += 4; off+size > len(stream) || (depth == 0 && typ != biffBOF) {
+			return ErrWorkbookFileFormat
+		}
+		r := &biffReader{segs: [][]byte{stream[off : off+size]}}
+		for off += size; off+4 <= len(stream) && binary.LittleEndian.Uint16(stream[off:]) == biffContinue; {
+			size = int(binary.LittleEndian.Uint16(stream[off+2:]))
+			if off += 4; off+size > len(stream) {
+				return ErrWorkbookFileFormat
+			}
+			r.segs, off = append(r.segs, stream[off:off+size]), off+size
+		}
+		if typ == biffBOF {
+			depth++
+		}
+		if typ == biffEOF {
+			if depth--; depth == 0 {
+				return nil
+			}
+		}
+		if depth > 1 || typ == biffEOF {
+			continue
+		}
+		if err := fn(typ, r); err != nil {
+			return err
+		}
+		if r.err != nil {
+			return r.err
+		}
+	}
+	return ErrWorkbookFileFormat
+}
+
+// getLegacyWorkbookStream provides a function to get the BIFF8 workbook
+// stream in the compound file binary, returns nil if the compound file
+// doesn't contain the workbook stream.
+func getLegacyWorkbookStream(raw []byte) []byte {
+	doc, err := mscfb.New(bytes.NewReader(raw))
+	if err != nil {
+		return nil
+	}
+	for entry, err := doc.Next(); err == nil; entry, err = doc.Next() {
+		if entry.Name == "Workbook" {
+			buf := make([]byte, entry.Size)
+			if _, err = io.ReadFull(doc, buf); err != nil {
+				return nil
+			}
+			return buf
+		}
+	}
+	return nil
+}
+
+// xlsStyles directly maps the fonts, number formats and cell formats in the
+// workbook globals substream of the BIFF8 workbook.
+type xlsStyles struct {
+	fonts   []*xlsxFont
+	numFmts []*xlsxNumFmt
+	xfs     []xlsXf
+	cellXfs []int
+}
+
+// xlsXf directly maps the cell format (XF) record of the BIFF8 workbook.
+type xlsXf struct {
+	style             bool
+	parent            int
+	xf                xlsxXf
+	fill              xlsxFill
+	border            xlsxBorder
+	fontID, numFmtID  int
+	alignment, locked bool
+}
+
+// xlsSheet directly maps the sheet information (BoundSheet8) record of the
+// BIFF8 workbook.
+type xlsSheet struct {
+	name, state string
+	offset      int
+}
+
+// readLegacyWorkbook provides a function to read the BIFF8 workbook stream
+// of the Excel 97-2003 workbook (.xls), and convert the workbook into the
+// Office Open XML parts. The sheet names, cell values, shared strings table,
+// number formats, fonts, fills, borders, alignments, merged cells, rows and
+// columns will be converted, the cached values of the formula cells will be
+// read as the cell values without formulas. It returns the parts and the
+// number of worksheets.
+func (f *File) readLegacyWorkbook(stream []byte) (map[string][]byte, int, error) {
+	var (
+		wb     = xlsxWorkbook{WorkbookPr: &xlsxWorkbookPr{}}
+		sst    xlsxSST
+		styles xlsStyles
+		sheets []xlsSheet
+	)
+	if err := readBIFFRecords(stream, 0, func(typ int, r *biffReader) error {
+		switch typ {
+		case biffBOF:
+			if r.uint16() != biffVersion {
+				return ErrWorkbookFileFormat
+			}
+		case biffFilePass:
+			return ErrWorkbookFileFormat
+		case biffDateMode:
+			wb.WorkbookPr.Date1904 = r.uint16() != 0
+		case biffBoundSheet:
+			offset, state, sheetType := int(r.uint32()), r.uint8()&0x03, r.uint8()
+			sheet := xlsSheet{offset: offset, name: r.shortString()}
+			sheet.state = map[uint8]string{1: "hidden", 2: "veryHidden"}[state]
+			if sheetType == 0 {
+				sheets = append(sheets, sheet)
+			}
+		case biffSST:
+			sst.Count = int(r.uint32())
+			unique := int(r.uint32())
+			for i := 0; i < unique && r.err == nil; i++ {
+				sst.SI = append(sst.SI, xlsxSI{T: &xlsxT{Val: r.richExtendedString()}})
+			}
+		case biffFont:
+			styles.fonts = append(styles.fonts, r.font())
+		case biffFormat:
+			numFmtID := int(r.uint16())
+			styles.numFmts = append(styles.numFmts, &xlsxNumFmt{NumFmtID: numFmtID, FormatCode: r.unicodeString()})
+		case biffXF:
+			styles.xfs = append(styles.xfs, r.xf())
+		}
+		return nil
+	}); err != nil {
+		return nil, 0, err
+	}
+	sst.UniqueCount = len(sst.SI)
+	files := map[string][]byte{
+		"_rels/.rels":              []byte(xml.Header + templateRels),
+		defaultXMLPathDocPropsApp:  []byte(xml.Header + templateDocpropsApp),
+		defaultXMLPathDocPropsCore: []byte(xml.Header + templateDocpropsCore),
+		defaultXMLPathTheme:        []byte(xml.Header + templateTheme),
+	}
+	contentTypes, wbRels := new(xlsxTypes), new(xlsxRelationships)
+	if err := xml.Unmarshal([]byte(templateContentTypes), contentTypes); err != nil {
+		return nil, 0, err
+	}
+	if err := xml.Unmarshal([]byte(templateWorkbookRels), wbRels); err != nil {
+		return nil, 0, err
+	}
+	// Replace the worksheet in the template with the shared strings table
+	contentTypes.setPartContentType("xl/worksheets/sheet1.xml", defaultXMLPathSharedStrings, ContentTypeSpreadSheetMLSharedStrings)
+	wbRels.Relationships[0] = xlsxRelationship{ID: "rId1", Type: SourceRelationshipSharedStrings, Target: "sharedStrings.xml"}
+	ss := styles.styleSheet()
+	for i, sheet := range sheets {
+		ws, err := readLegacyWorksheet(stream, sheet.offset, styles.cellXfs)
+		if err != nil {
+			return nil, 0, err
+		}
+		rID, sheetXMLPath := fmt.Sprintf("rId%d", i+4), fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+		wb.Sheets.Sheet = append(wb.Sheets.Sheet, xlsxSheet{Name: sheet.name, SheetID: i + 1, ID: rID, State: sheet.state})
+		wbRels.Relationships = append(wbRels.Relationships, xlsxRelationship{
+			ID: rID, Type: SourceRelationshipWorkSheet, Target: fmt.Sprintf("worksheets/sheet%d.xml", i+1),
+		})
+		contentTypes.Overrides = append(contentTypes.Overrides, xlsxOverride{
+			PartName: "/" + sheetXMLPath, ContentType: ContentTypeSpreadSheetMLWorksheet,
+		})
+		if files[sheetXMLPath], err = marshalPart(ws); err != nil {
+			return nil, 0, err
+		}
+	}
+	for name, v := range map[string]interface{}{
+		defaultXMLPathContentTypes:  contentTypes,
+		defaultXMLPathWorkbook:      &wb,
+		defaultXMLPathWorkbookRels:  wbRels,
+		defaultXMLPathSharedStrings: &sst,
+		defaultXMLPathStyles:        ss,
+	} {
+		output, err := marshalPart(v)
+		if err != nil {
+			return nil, 0, err
+		}
+		files[name] = output
+	}
+	return files, len(sheets), nil
+}
+
+// font provides a function to read a font (Font) record.
+func (r *biffReader) font() *xlsxFont {
+	height, flags, color, weight := r.uint16(), r.uint16(), r.uint16(), r.uint16()
+	r.uint16()
+	underline, family, charset := r.uint8(), r.uint8(), r.uint8()
+	r.uint8()
+	font := &xlsxFont{
+		Sz:   &attrValFloat{Val: float64Ptr(float64(height) / 20)},
+		Name: &attrValString{Val: stringPtr(r.shortString())},
+	}
+	for _, attr := range []struct {
+		val  **attrValBool
+		flag bool
+	}{
+		{&font.B, weight >= 700},
+		{&font.I, flags&0x02 != 0},
+		{&font.Strike, flags&0x08 != 0},
+		{&font.Outline, flags&0x10 != 0},
+		{&font.Shadow, flags&0x20 != 0},
+		{&font.Condense, flags&0x40 != 0},
+		{&font.Extend, flags&0x80 != 0},
+	} {
+		if attr.flag {
+			*attr.val = &attrValBool{Val: boolPtr(true)}
+		}
+	}
+	if color != 0x7FFF {
+		font.Color = &xlsxColor{Indexed: int(color)}
+	}
+	if u, ok := xlsbUnderlineTypes[underline]; ok {
+		font.U = &attrValString{Val: stringPtr(u)}
+	}
+	if family != 0 {
+		font.Family = &attrValInt{Val: intPtr(int(family))}
+	}
+	if charset != 0 {
+		font.Charset = &attrValInt{Val: intPtr(int(charset))}
+	}
+	return font
+}
+
+// xf provides a function to read a cell format (XF) record, the alignment,
+// border and fill settings are stored in the cell format record in BIFF8.
+func (r *biffReader) xf() xlsXf {
+	fontID, numFmtID, flags := int(r.uint16()), int(r.uint16()), r.uint16()
+	align, rotation, indent := r.uint8(), r.uint8(), r.uint8()
+	r.uint8()
+	border1, border2, fill := r.uint32(), r.uint32(), r.uint16()
+	// The font index 4 is omitted in the fonts
+	if fontID > 4 {
+		fontID--
+	}
+	xf := xlsXf{
+		style: flags&0x04 != 0, parent: int(flags >> 4), fontID: fontID, numFmtID: numFmtID,
+		locked: flags&0x01 != 0,
+	}
+	alignment := xlsxAlignment{
+		TextRotation:    int(rotation),
+		Indent:          int(indent & 0x0F),
+		WrapText:        align&0x08 != 0,
+		JustifyLastLine: align&0x80 != 0,
+		ShrinkToFit:     indent&0x10 != 0,
+		ReadingOrder:    uint64(indent >> 6),
+		Horizontal:      xlsbHorizontalAlignments[align&0x07],
+	}
+	if v := int(align>>4) & 0x07; v < len(xlsbVerticalAlignments) {
+		alignment.Vertical = xlsbVerticalAlignments[v]
+	}
+	if alignment != (xlsxAlignment{}) {
+		xf.xf.Alignment, xf.alignment = &alignment, true
+	}
+	if !xf.locked || flags&0x02 != 0 {
+		xf.xf.Protection = &xlsxProtection{Locked: boolPtr(xf.locked), Hidden: boolPtr(flags&0x02 != 0)}
+	}
+	xf.border = xlsxBorder{DiagonalDown: border1>>30&0x01 != 0, DiagonalUp: border1>>31 != 0}
+	for _, line := range []struct {
+		line         *xlsxLine
+		style, color uint32
+	}{
+		{&xf.border.Left, border1, border1 >> 16},
+		{&xf.border.Right, border1 >> 4, border1 >> 23},
+		{&xf.border.Top, border1 >> 8, border2},
+		{&xf.border.Bottom, border1 >> 12, border2 >> 7},
+		{&xf.border.Diagonal, border2 >> 21, border2 >> 14},
+	} {
+		if style := int(line.style & 0x0F); style != 0 && style < len(xlsbBorderStyles) {
+			line.line.Style, line.line.Color = xlsbBorderStyles[style], &xlsxColor{Indexed: int(line.color & 0x7F)}
+		}
+	}
+	xf.fill = xlsxFill{PatternFill: &xlsxPatternFill{PatternType: "none"}}
+	if pattern := int(border2>>26) & 0x3F; pattern != 0 && pattern < len(xlsbPatternTypes) {
+		xf.fill.PatternFill = &xlsxPatternFill{
+			PatternType: xlsbPatternTypes[pattern],
+			FgColor:     &xlsxColor{Indexed: int(fill & 0x7F)},
+			BgColor:     &xlsxColor{Indexed: int(fill>>7) & 0x7F},
+		}
+	}
+	return xf
+}
+
+// styleSheet provides a function to convert the BIFF8 styles into the style
+// sheet, and set the mapping of the cell format indexes in the workbook and
+// the cell formats in the style sheet.
+func (styles *xlsStyles) styleSheet() *xlsxStyleSheet {
+	ss := &xlsxStyleSheet{
+		NumFmts: &xlsxNumFmts{NumFmt: styles.numFmts},
+		Fonts:   &xlsxFonts{Font: styles.fonts},
+		Fills: &xlsxFills{Fill: []*xlsxFill{
+			{PatternFill: &xlsxPatternFill{PatternType: "none"}},
+			{PatternFill: &xlsxPatternFill{PatternType: "gray125"}},
+		}},
+		Borders:      &xlsxBorders{Border: []*xlsxBorder{{}}},
+		CellStyleXfs: &xlsxCellStyleXfs{},
+		CellXfs:      &xlsxCellXfs{},
+		CellStyles: &xlsxCellStyles{CellStyle: []*xlsxCellStyle{
+			{Name: "Normal", XfID: 0, BuiltInID: intPtr(0)},
+		}},
+	}
+	if len(ss.Fonts.Font) == 0 {
+		ss.Fonts.Font = append(ss.Fonts.Font, &xlsxFont{
+			Sz: &attrValFloat{Val: float64Ptr(11)}, Name: &attrValString{Val: stringPtr("Calibri")},
+		})
+	}
+	getFillID := func(fill *xlsxFill) int {
+		for i, fl := range ss.Fills.Fill {
+			if reflect.DeepEqual(fl, fill) {
+				return i
+			}
+		}
+		ss.Fills.Fill = append(ss.Fills.Fill, fill)
+		return len(ss.Fills.Fill) - 1
+	}
+	getBorderID := func(border *xlsxBorder) int {
+		for i, bdr := range ss.Borders.Border {
+			if reflect.DeepEqual(bdr, border) {
+				return i
+			}
+		}
+		ss.Borders.Border = append(ss.Borders.Border, border)
+		return len(ss.Borders.Border) - 1
+	}
+	styleXfs := make(map[int]int)
+	styles.cellXfs = make([]int, len(styles.xfs))
+	for i := range styles.xfs {
+		xf := &styles.xfs[i]
+		fontID := xf.fontID
+		if fontID >= len(ss.Fonts.Font) {
+			fontID = 0
+		}
+		xf.xf.NumFmtID, xf.xf.FontID = intPtr(xf.numFmtID), intPtr(fontID)
+		xf.xf.FillID = intPtr(getFillID(&xf.fill))
+		xf.xf.BorderID = intPtr(getBorderID(&xf.border))
+		if xf.alignment {
+			xf.xf.ApplyAlignment = boolPtr(true)
+		}
+		if xf.style {
+			styleXfs[i] = len(ss.CellStyleXfs.Xf)
+			ss.CellStyleXfs.Xf = append(ss.CellStyleXfs.Xf, xf.xf)
+			continue
+		}
+		xf.xf.XfID = intPtr(styleXfs[xf.parent])
+		styles.cellXfs[i] = len(ss.CellXfs.Xf)
+		ss.CellXfs.Xf = append(ss.CellXfs.Xf, xf.xf)
+	}
+	defaultXf := xlsxXf{NumFmtID: intPtr(0), FontID: intPtr(0), FillID: intPtr(0), BorderID: intPtr(0)}
+	if len(ss.CellStyleXfs.Xf) == 0 {
+		ss.CellStyleXfs.Xf = append(ss.CellStyleXfs.Xf, defaultXf)
+	}
+	if len(ss.CellXfs.Xf) == 0 {
+		defaultXf.XfID = intPtr(0)
+		ss.CellXfs.Xf = append(ss.CellXfs.Xf, defaultXf)
+	}
+	ss.NumFmts.Count, ss.Fonts.Count, ss.Fills.Count = len(ss.NumFmts.NumFmt), len(ss.Fonts.Font), len(ss.Fills.Fill)
+	ss.Borders.Count, ss.CellStyleXfs.Count = len(ss.Borders.Border), len(ss.CellStyleXfs.Xf)
+	ss.CellXfs.Count, ss.CellStyles.Count = len(ss.CellXfs.Xf), len(ss.CellStyles.CellStyle)
+	if ss.NumFmts.Count == 0 {
+		ss.NumFmts = nil
+	}
+	return ss
+}
+
+// xlsRow directly maps the row in the BIFF8 worksheet and the column numbers
+// of the cells in the row.
+type xlsRow struct {
+	row    xlsxRow
+	cols   []int
+	sorted bool
+}
+
+// readLegacyWorksheet provides a function to read the BIFF8 worksheet
+// substream at given offset of the workbook stream, and convert the
+// worksheet substream into the worksheet. The cell formats indexes in the
+// workbook will be converted to the cell formats in the style sheet by the
+// given mapping.
+func readLegacyWorksheet(stream []byte, offset int, cellXfs []int) (*xlsxWorksheet, error) {
+	var (
+		ws      = &xlsxWorksheet{}
+		rows    = make(map[int]*xlsRow)
+		pending *xlsxC
+	)
+	getRow := func(r int) *xlsRow {
+		row, ok := rows[r]
+		if !ok {
+			row = &xlsRow{row: xlsxRow{R: r + 1}, sorted: true}
+			rows[r] = row
+		}
+		return row
+	}
+	addCell := func(r, c, style int, cellType, value string) (*xlsxC, error) {
+		cell, err := CoordinatesToCellName(c+1, r+1)
+		if err != nil {
+			return nil, err
+		}
+		row, s := getRow(r), 0
+		if style < len(cellXfs) {
+			s = cellXfs[style]
+		}
+		if n := len(row.cols); n > 0 && row.cols[n-1] >= c {
+			row.sorted = false
+		}
+		row.cols, row.row.C = append(row.cols, c), append(row.row.C, xlsxC{R: cell, S: s, T: cellType, V: value})
+		return &row.row.C[len(row.row.C)-1], nil
+	}
+	if err := readBIFFRecords(stream, offset, func(typ int, r *biffReader) error {
+		var err error
+		if typ == biffString && pending != nil {
+			pending.V = r.unicodeString()
+		}
+		// The shared, array or table formula record could be written between
+		// the formula record and the string record of the cached value
+		if typ != biffShrFmla && typ != biffArray && typ != biffTable {
+			pending = nil
+		}
+		if typ == biffMulRk || typ == biffMulBlank {
+			row, col := int(r.uint16()), int(r.uint16())
+			for len(r.segs[0])-r.off > 2 && r.err == nil && err == nil {
+				style, value := int(r.uint16()), ""
+				if typ == biffMulRk {
+					value = strconv.FormatFloat(xlsbRkNumber(r.uint32()), 'f', -1, 64)
+				}
+				_, err = addCell(row, col, style, "", value)
+				col++
+			}
+			return err
+		}
+		switch typ {
+		case biffFormula, biffNumber, biffRK, biffLabelSST, biffLabel, biffRString, biffBoolErr, biffBlank:
+			row, col, style := int(r.uint16()), int(r.uint16()), int(r.uint16())
+			var cellType, value string
+			switch typ {
+			case biffFormula:
+				cellType, value, err = r.formulaValue()
+			case biffNumber:
+				value = strconv.FormatFloat(r.float64(), 'f', -1, 64)
+			case biffRK:
+				value = strconv.FormatFloat(xlsbRkNumber(r.uint32()), 'f', -1, 64)
+			case biffLabelSST:
+				cellType, value = "s", strconv.FormatUint(uint64(r.uint32()), 10)
+			case biffLabel, biffRString:
+				value = r.unicodeString()
+			case biffBoolErr:
+				val, isErr := r.uint8(), r.uint8()
+				cellType, value = "b", strconv.Itoa(int(val))
+				if isErr != 0 {
+					cellType, value = "e", xlsbErrors[val]
+				}
+			}
+			if r.err != nil || err != nil {
+				return ErrWorkbookFileFormat
+			}
+			c, err := addCell(row, col, style, cellType, value)
+			if err != nil {
+				return err
+			}
+			if typ == biffLabel || typ == biffRString {
+				c.T, c.V, c.IS = "inlineStr", "", &xlsxSI{T: &xlsxT{Val: value}}
+			}
+			if typ == biffFormula && cellType == "str" {
+				pending = c
+			}
+			return err
+		case biffRow:
+			rowNum, _, _, height := int(r.uint16()), r.uint16(), r.uint16(), r.uint16()
+			r.uint32()
+			flags, _, style := r.uint8(), r.uint8(), int(r.uint16()&0x0FFF)
+			row := getRow(rowNum)
+			row.row.OutlineLevel, row.row.Collapsed = flags&0x07, flags&0x10 != 0
+			row.row.Hidden, row.row.CustomHeight, row.row.CustomFormat = flags&0x20 != 0, flags&0x40 != 0, flags&0x80 != 0
+			if row.row.CustomHeight {
+				row.row.Ht = float64Ptr(float64(height&0x7FFF) / 20)
+			}
+			if row.row.CustomFormat && style < len(cellXfs) {
+				row.row.S = cellXfs[style]
+			}
+		case biffColInfo:
+			first, last, width, style, flags := int(r.uint16()), int(r.uint16()), r.uint16(), int(r.uint16()), r.uint16()
+			if ws.Cols == nil {
+				ws.Cols = &xlsxCols{}
+			}
+			col := xlsxCol{
+				Min: first + 1, Max: last + 1, Width: float64Ptr(float64(width) / 256),
+				Hidden: flags&0x01 != 0, CustomWidth: flags&0x02 != 0, BestFit: flags&0x04 != 0,
+				Phonetic: flags&0x08 != 0, OutlineLevel: uint8(flags>>8) & 0x07, Collapsed: flags&0x1000 != 0,
+			}
+			if style < len(cellXfs) {
+				col.Style = cellXfs[style]
+			}
+			ws.Cols.Col = append(ws.Cols.Col, col)
+		case biffMergeCells:
+			count := int(r.uint16())
+			if ws.MergeCells == nil {
+				ws.MergeCells = &xlsxMergeCells{}
+			}
+			for i := 0; i < count && r.err == nil; i++ {
+				rwFirst, rwLast, colFirst, colLast := int(r.uint16()), int(r.uint16()), int(r.uint16()), int(r.uint16())
+				ref, err := coordinatesToRangeRef([]int{colFirst + 1, rwFirst + 1, colLast + 1, rwLast + 1})
+				if err != nil {
+					return err
+				}
+				ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: ref})
+			}
+			ws.MergeCells.Count = len(ws.MergeCells.Cells)
+		case biffDimensions:
+			rwFirst, rwLast, colFirst, colLast := int(r.uint32()), int(r.uint32()), int(r.uint16()), int(r.uint16())
+			if rwLast > rwFirst && colLast > colFirst {
+				ref, err := coordinatesToRangeRef([]int{colFirst + 1, rwFirst + 1, colLast, rwLast})
+				ws.Dimension = &xlsxDimension{Ref: ref}
+				return err
+			}
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	rowNums := make([]int, 0, len(rows))
+	for rowNum := range rows {
+		rowNums = append(rowNums, rowNum)
+	}
+	sort.Ints(rowNums)
+	for _, rowNum := range rowNums {
+		row := rows[rowNum]
+		if !row.sorted {
+			sort.Sort(row)
+		}
+		ws.SheetData.Row = append(ws.SheetData.Row, row.row)
+	}
+	return ws, nil
+}
+
+// Len, Less and Swap implements the sort.Interface for sorting the cells in
+// the row by the column number.
+func (row *xlsRow) Len() int           { return len(row.cols) }
+func (row *xlsRow) Less(i, j int) bool { return row.cols[i] < row.cols[j] }
+func (row *xlsRow) Swap(i, j int) {
+	row.cols[i], row.cols[j] = row.cols[j], row.cols[i]
+	row.row.C[i], row.row.C[j] = row.row.C[j], row.row.C[i]
+}
+
+// formulaValue provides a function to read the cached value (FormulaValue)
+// of the formula cell, the string value will be read from the following
+// STRING record. It returns the cell type and value.
+func (r *biffReader) formulaValue() (string, string, error) {
+	b := r.read(8)
+	if r.err != nil {
+		return "", "", r.err
+	}
+	if binary.LittleEndian.Uint16(b[6:]) != 0xFFFF {
+		return "", strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)), 'f', -1, 64), nil
+	}
+	switch b[0] {
+	case 0x01:
+		return "b", strconv.Itoa(int(b[2])), nil
+	case 0x02:
+		return "e", xlsbErrors[b[2]], nil
+	default:
+		return "str", "", nil
+	}
+}
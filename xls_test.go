@@ -0,0 +1,327 @@
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// biffRecord returns the BIFF8 record by given record type and fields.
+func biffRecord(typ int, fields ...[]byte) []byte {
+	data := bytes.Join(fields, nil)
+	return append(append(xlsbUint(uint64(typ), 2), xlsbUint(uint64(len(data)), 2)...), data...)
+}
+
+// biffUnicodeString returns the XLUnicodeString bytes of the string in compressed
+// or UTF-16 encoding by given high byte flag.
+func biffUnicodeString(s string, highByte bool) []byte {
+	b := xlsbUint(uint64(len([]rune(s))), 2)
+	if !highByte {
+		return append(append(b, 0), s...)
+	}
+	return append(append(b, 1), xlsbWideString(s)[4:]...)
+}
+
+// biffCell returns the cell record by given record type, zero-based row and
+// column number, cell format index and value fields.
+func biffCell(typ, row, col, style int, fields ...[]byte) []byte {
+	return biffRecord(typ, append([][]byte{
+		xlsbUint(uint64(row), 2), xlsbUint(uint64(col), 2), xlsbUint(uint64(style), 2),
+	}, fields...)...)
+}
+
+// biffFormulaCell returns the formula record by given zero-based row and
+// column number and the cached value.
+func biffFormulaCell(row, col int, value []byte) []byte {
+	return biffCell(biffFormula, row, col, 0, value, xlsbUint(0, 2), xlsbUint(0, 4), xlsbUint(0, 2))
+}
+
+// biffXf returns the cell format record by given font, number format, flags,
+// alignment and border, fill settings.
+func biffXf(fontID, numFmtID, flags, align int, border1, border2 uint32, fill int) []byte {
+	return biffRecord(biffXF, xlsbUint(uint64(fontID), 2), xlsbUint(uint64(numFmtID), 2),
+		xlsbUint(uint64(flags), 2), []byte{byte(align), 0, 0, 0},
+		xlsbUint(uint64(border1), 4), xlsbUint(uint64(border2), 4), xlsbUint(uint64(fill), 2))
+}
+
+// prepareLegacyWorkbook returns an Excel 97-2003 workbook by given functions
+// for modifying the records of the workbook globals substream and the
+// worksheet substreams.
+func prepareLegacyWorkbook(globals func([][]byte) [][]byte, sheet func([][]byte) [][]byte) []byte {
+	font := func(height, flags, color, weight int, name string) []byte {
+		return biffRecord(biffFont, xlsbUint(uint64(height), 2), xlsbUint(uint64(flags), 2),
+			xlsbUint(uint64(color), 2), xlsbUint(uint64(weight), 2), xlsbUint(0, 2), []byte{1, 2, 0, 0},
+			[]byte{byte(len(name)), 0}, []byte(name))
+	}
+	sheet1 := [][]byte{
+		biffRecord(biffBOF, xlsbUint(biffVersion, 2), xlsbUint(0x10, 2), make([]byte, 12)),
+		biffRecord(biffDimensions, xlsbUint(0, 4), xlsbUint(4, 4), xlsbUint(0, 2), xlsbUint(7, 2), xlsbUint(0, 2)),
+		biffRecord(biffColInfo, xlsbUint(1, 2), xlsbUint(2, 2), xlsbUint(20*256, 2), xlsbUint(17, 2), xlsbUint(0x02, 2), xlsbUint(0, 2)),
+		biffRecord(biffColInfo, xlsbUint(6, 2), xlsbUint(6, 2), xlsbUint(9*256, 2), xlsbUint(15, 2), xlsbUint(0x01, 2), xlsbUint(0, 2)),
+		biffRecord(biffRow, xlsbUint(0, 2), xlsbUint(0, 2), xlsbUint(7, 2), xlsbUint(600, 2), xlsbUint(0, 4), []byte{0x40, 0x01}, xlsbUint(15, 2)),
+		biffRecord(biffRow, xlsbUint(2, 2), xlsbUint(0, 2), xlsbUint(7, 2), xlsbUint(0x80FF, 2), xlsbUint(0, 4), []byte{0xA0, 0x01}, xlsbUint(16, 2)),
+		biffCell(biffLabelSST, 0, 0, 15, xlsbUint(0, 4)),
+		biffCell(biffLabel, 0, 1, 15, biffUnicodeString("inline", false)),
+		biffCell(biffRK, 0, 2, 15, xlsbUint(123<<2|0x02, 4)),
+		biffCell(biffNumber, 0, 5, 16, xlsbUint(math.Float64bits(0.5), 8)),
+		biffCell(biffRString, 0, 3, 15, biffUnicodeString("富文本", true), xlsbUint(0, 2)),
+		biffCell(biffBoolErr, 0, 4, 15, []byte{1, 0}),
+		biffCell(biffBoolErr, 0, 6, 15, []byte{0x07, 1}),
+		biffRecord(biffMulRk, xlsbUint(1, 2), xlsbUint(0, 2),
+			xlsbUint(15, 2), xlsbUint(123<<2|0x03, 4), xlsbUint(15, 2), xlsbUint(math.Float64bits(1.5)>>32, 4),
+			xlsbUint(1, 2)),
+		biffRecord(biffMulBlank, xlsbUint(1, 2), xlsbUint(2, 2), xlsbUint(16, 2), xlsbUint(16, 2), xlsbUint(3, 2)),
+		biffFormulaCell(2, 0, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+		biffRecord(biffString, biffUnicodeString("calc", false)),
+		biffFormulaCell(2, 1, xlsbUint(math.Float64bits(3.5), 8)),
+		biffFormulaCell(2, 2, []byte{1, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+		biffFormulaCell(2, 3, []byte{2, 0, 0x2A, 0, 0, 0, 0xFF, 0xFF}),
+		biffFormulaCell(2, 4, []byte{3, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+		biffCell(biffNumber, 2, 5, 17, xlsbUint(math.Float64bits(45293), 8)),
+		biffCell(biffLabelSST, 3, 1, 15, xlsbUint(2, 4)),
+		biffCell(biffLabelSST, 3, 0, 15, xlsbUint(1, 4)),
+		// Embedded chart substream in the worksheet
+		biffRecord(biffBOF, xlsbUint(biffVersion, 2), xlsbUint(0x20, 2), make([]byte, 12)),
+		biffCell(biffNumber, 9, 9, 15, xlsbUint(math.Float64bits(1), 8)),
+		biffRecord(biffEOF),
+		biffRecord(biffMergeCells, xlsbUint(1, 2), xlsbUint(4, 2), xlsbUint(5, 2), xlsbUint(0, 2), xlsbUint(1, 2)),
+		biffRecord(biffEOF),
+	}
+	sheet2 := [][]byte{
+		biffRecord(biffBOF, xlsbUint(biffVersion, 2), xlsbUint(0x10, 2), make([]byte, 12)),
+		biffCell(biffRK, 0, 0, 15, xlsbUint(uint64(xlsbNegativeRk), 4)),
+		biffRecord(biffEOF),
+	}
+	if sheet != nil {
+		sheet1 = sheet(sheet1)
+	}
+	sheet1Data, sheet2Data := bytes.Join(sheet1, nil), bytes.Join(sheet2, nil)
+	build := func(offset int) []byte {
+		boundSheet := func(offset int, state, typ byte, name string) []byte {
+			return biffRecord(biffBoundSheet, xlsbUint(uint64(offset), 4), []byte{state, typ, byte(len(name)), 0}, []byte(name))
+		}
+		records := [][]byte{
+			biffRecord(biffBOF, xlsbUint(biffVersion, 2), xlsbUint(0x05, 2), make([]byte, 12)),
+			biffRecord(biffDateMode, xlsbUint(0, 2)),
+			font(220, 0, 0x7FFF, 400, "Arial"),
+			font(220, 0, 0x7FFF, 700, "Arial"),
+			font(220, 0, 0x7FFF, 400, "Arial"),
+			font(220, 0, 0x7FFF, 400, "Arial"),
+			font(280, 0x0A, 10, 700, "Times"),
+			biffRecord(biffFormat, xlsbUint(164, 2), biffUnicodeString("0.00%", false)),
+		}
+		for i := 0; i < 15; i++ {
+			records = append(records, biffXf(0, 0, 0xFFF5, 0x20, 0, 0, 0x20C0))
+		}
+		records = append(records,
+			biffXf(0, 0, 0x0001, 0x20, 0, 0, 0x20C0),
+			biffXf(5, 164, 0x0001, 0x20, 0x00080001, 0x04000000, 0x208A),
+			biffXf(0, 14, 0x0002, 0x2A, 0, 0, 0x20C0),
+			biffRecord(biffSST, xlsbUint(5, 4), xlsbUint(3, 4),
+				biffUnicodeString("Hello", false),
+				xlsbUint(5, 2), []byte{0x0C}, xlsbUint(1, 2), xlsbUint(2, 4), []byte("Wor")),
+			biffRecord(biffContinue, []byte{1}, xlsbWideString("ld")[4:], make([]byte, 6)),
+			biffRecord(biffContinue, biffUnicodeString("世界", true)),
+			boundSheet(offset, 0, 0, "Sheet1"),
+			boundSheet(0, 0, 2, "Chart1"),
+			boundSheet(offset+len(sheet1Data), 1, 0, "Sheet2"),
+			biffRecord(biffEOF),
+		)
+		if globals != nil {
+			records = globals(records)
+		}
+		return bytes.Join(records, nil)
+	}
+	stream := build(0)
+	stream = append(build(len(stream)), append(sheet1Data, sheet2Data...)...)
+	compoundFile := &cfb{
+		paths:   []string{"Root Entry/"},
+		sectors: []sector{{name: "Root Entry", typeID: 5}},
+	}
+	compoundFile.put("Workbook", stream)
+	return compoundFile.write()
+}
+
+func TestOpenLegacyWorkbook(t *testing.T) {
+	f, err := OpenReader(bytes.NewReader(prepareLegacyWorkbook(nil, nil)))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	visible, err := f.GetSheetVisible("Sheet2")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "inline", "123", "富文本", "TRUE", "50.00%", "#DIV/0!"},
+		{"1.23", "1.5"},
+		{"calc", "3.5", "FALSE", "#N/A", "", "01-02-24"},
+		{"World", "世界"},
+	}, rows)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"-5"}}, rows)
+
+	for cell, expected := range map[string]CellType{
+		"A1": CellTypeSharedString, "B1": CellTypeInlineString, "C1": CellTypeUnset,
+		"E1": CellTypeBool, "G1": CellTypeError, "A3": CellTypeFormula,
+	} {
+		cellType, err := f.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cellType, cell)
+	}
+	value, err := f.GetCellValue("Sheet1", "F1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.5", value)
+
+	// Test read rows, columns and merged cells
+	height, err := f.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	visible, err = f.GetRowVisible("Sheet1", 3)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	width, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	visible, err = f.GetColVisible("Sheet1", "G")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A5", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B6", mergeCells[0].GetEndAxis())
+
+	// Test read styles
+	styleID, err := f.GetCellStyle("Sheet1", "F1")
+	assert.NoError(t, err)
+	style, err := f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00%", *style.CustomNumFmt)
+	assert.True(t, style.Font.Bold)
+	assert.True(t, style.Font.Italic)
+	assert.Equal(t, "Times", style.Font.Family)
+	assert.Equal(t, 14.0, style.Font.Size)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}, style.Fill)
+	assert.Equal(t, []Border{{Type: "left", Color: "000000", Style: 1}}, style.Border)
+	styleID, err = f.GetCellStyle("Sheet1", "F3")
+	assert.NoError(t, err)
+	style, err = f.GetStyle(styleID)
+	assert.NoError(t, err)
+	assert.Equal(t, 14, style.NumFmt)
+	assert.Equal(t, &Alignment{Horizontal: "center", WrapText: true}, style.Alignment)
+	assert.Equal(t, &Protection{Hidden: true, Locked: false}, style.Protection)
+
+	// Test save the legacy workbook as XLSX workbook
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	value, err = f.GetCellValue("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "01-02-24", value)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "Excelize"))
+	value, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Excelize", value)
+	assert.NoError(t, f.Close())
+
+	// Test open legacy workbook without styles and shared strings table
+	f, err = OpenReader(bytes.NewReader(prepareLegacyWorkbook(func(records [][]byte) [][]byte {
+		var filtered [][]byte
+		for _, record := range records {
+			if typ := binary.LittleEndian.Uint16(record); typ != biffFont && typ != biffXF && typ != biffSST && typ != biffContinue {
+				filtered = append(filtered, record)
+			}
+		}
+		return filtered
+	}, func(records [][]byte) [][]byte {
+		return [][]byte{records[0], biffCell(biffNumber, 0, 0, 15, xlsbUint(math.Float64bits(1), 8)), records[len(records)-1]}
+	})))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1"}}, rows)
+	assert.NoError(t, f.Close())
+
+	// Test open legacy workbook with shared, array and table formula records
+	// between the formula record and the string record
+	f, err = OpenReader(bytes.NewReader(prepareLegacyWorkbook(nil, func(records [][]byte) [][]byte {
+		return [][]byte{
+			records[0],
+			biffFormulaCell(0, 0, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+			biffRecord(biffShrFmla, make([]byte, 10)),
+			biffRecord(biffString, biffUnicodeString("shared", false)),
+			biffFormulaCell(0, 1, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+			biffRecord(biffArray, make([]byte, 14)),
+			biffRecord(biffString, biffUnicodeString("array", false)),
+			biffFormulaCell(0, 2, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+			biffRecord(biffTable, make([]byte, 16)),
+			biffRecord(biffString, biffUnicodeString("table", false)),
+			biffFormulaCell(0, 3, []byte{0, 0, 0, 0, 0, 0, 0xFF, 0xFF}),
+			biffCell(biffNumber, 0, 4, 15, xlsbUint(math.Float64bits(1), 8)),
+			biffRecord(biffString, biffUnicodeString("orphan", false)),
+			records[len(records)-1],
+		}
+	})))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"shared", "array", "table", "", "1"}}, rows)
+	assert.NoError(t, f.Close())
+}
+
+func TestOpenLegacyWorkbookError(t *testing.T) {
+	replace := func(index int, record ...[]byte) func([][]byte) [][]byte {
+		return func(records [][]byte) [][]byte {
+			return append(append(append([][]byte{}, records[:index]...), record...), records[index+1:]...)
+		}
+	}
+	for _, c := range []struct {
+		globals, sheet func([][]byte) [][]byte
+		err            error
+	}{
+		{globals: replace(0, biffRecord(biffBOF, xlsbUint(0x0500, 2)))},
+		{globals: replace(1, biffRecord(biffFilePass))},
+		{globals: replace(1, biffRecord(biffDateMode))},
+		{globals: replace(0, biffRecord(biffDateMode, xlsbUint(0, 2)))},
+		{globals: func(records [][]byte) [][]byte { return records[:len(records)-1] }},
+		{globals: replace(1, biffRecord(biffSST, xlsbUint(1, 4), xlsbUint(1, 4), xlsbUint(4, 2), []byte{0x04}, xlsbUint(0xFFFFFFFF, 4), []byte("text")))},
+		{sheet: replace(6, biffCell(biffLabelSST, 0, MaxColumns, 15, xlsbUint(0, 4))), err: ErrColumnNumber},
+		{sheet: replace(6, biffCell(biffLabelSST, 0, 0, 15))},
+		{sheet: replace(6, biffCell(biffFormula, 0, 0, 15))},
+		{sheet: replace(6, biffRecord(biffMulRk, xlsbUint(0, 2), xlsbUint(0, 2), xlsbUint(15, 2), xlsbUint(0, 2)))},
+		{sheet: replace(6, biffRecord(biffMulBlank, xlsbUint(0, 2), xlsbUint(MaxColumns, 2), xlsbUint(15, 2), xlsbUint(0, 2))), err: ErrColumnNumber},
+		{sheet: replace(6, biffRecord(biffMergeCells, xlsbUint(1, 2), xlsbUint(0, 2), xlsbUint(0, 2), xlsbUint(0, 2), xlsbUint(MaxColumns, 2))), err: ErrColumnNumber},
+		{sheet: replace(1, biffRecord(biffDimensions, xlsbUint(0, 4), xlsbUint(1, 4), xlsbUint(0, 2), xlsbUint(MaxColumns+1, 2))), err: ErrColumnNumber},
+		{sheet: replace(1, biffRecord(biffDimensions, xlsbUint(0, 4)))},
+	} {
+		_, err := OpenReader(bytes.NewReader(prepareLegacyWorkbook(c.globals, c.sheet)))
+		if c.err == nil {
+			c.err = ErrWorkbookFileFormat
+		}
+		assert.Equal(t, c.err, err)
+	}
+	// Test read truncated records
+	assert.Equal(t, ErrWorkbookFileFormat, readBIFFRecords([]byte{0x09, 0x08, 0x10, 0x00}, 0, nil))
+	assert.Equal(t, ErrWorkbookFileFormat, readBIFFRecords([]byte{0x09, 0x08, 0x00, 0x00, 0x3C, 0x00, 0x10, 0x00}, 0, nil))
+	// Test open legacy workbook with invalid worksheet offset
+	_, err := OpenReader(bytes.NewReader(prepareLegacyWorkbook(func(records [][]byte) [][]byte {
+		records[len(records)-2] = biffRecord(biffBoundSheet, xlsbUint(math.MaxUint32, 4), []byte{0, 0, 6, 0}, []byte("Sheet2"))
+		return records
+	}, nil)))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+	// Test open Excel 5.0 workbook which stored in the book stream
+	compoundFile := &cfb{
+		paths:   []string{"Root Entry/"},
+		sectors: []sector{{name: "Root Entry", typeID: 5}},
+	}
+	compoundFile.put("Book", biffRecord(biffBOF, xlsbUint(0x0500, 2)))
+	_, err = OpenReader(bytes.NewReader(compoundFile.write()))
+	assert.Equal(t, ErrWorkbookFileFormat, err)
+}
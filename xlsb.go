@@ -0,0 +1,839 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Binary Interchange File Format 12 (BIFF12) record types of the Excel
+// binary workbook (.xlsb) file format, which defined in the [MS-XLSB]
+// specification.
+const (
+	xlsbRowHdr           = 0
+	xlsbCellBlank        = 1
+	xlsbCellRk           = 2
+	xlsbCellError        = 3
+	xlsbCellBool         = 4
+	xlsbCellReal         = 5
+	xlsbCellSt           = 6
+	xlsbCellIsst         = 7
+	xlsbFmlaString       = 8
+	xlsbFmlaNum          = 9
+	xlsbFmlaBool         = 10
+	xlsbFmlaError        = 11
+	xlsbSSTItem          = 19
+	xlsbFont             = 43
+	xlsbFmt              = 44
+	xlsbFill             = 45
+	xlsbBorder           = 46
+	xlsbXF               = 47
+	xlsbStyle            = 48
+	xlsbColInfo          = 60
+	xlsbWsDim            = 148
+	xlsbWbProp           = 153
+	xlsbBundleSh         = 156
+	xlsbBeginSst         = 159
+	xlsbMergeCell        = 176
+	xlsbHLink            = 494
+	xlsbBeginCellXFs     = 617
+	xlsbEndCellXFs       = 618
+	xlsbBeginCellStyleXF = 626
+	xlsbEndCellStyleXF   = 627
+)
+
+// xlsbContentTypePrefix defined the prefix of the content types of the
+// binary parts in the Excel binary workbook, and the printer settings content
+// type for the binary parts which kept in the converted workbook.
+const (
+	xlsbContentTypePrefix          = "application/vnd.ms-excel."
+	xlsbContentTypePrinterSettings = "application/vnd.openxmlformats-officedocument.spreadsheetml.printerSettings"
+)
+
+var (
+	// xlsbErrors defined the mapping of the BIFF12 error codes and the cell
+	// error values.
+	xlsbErrors = map[byte]string{
+		0x00: formulaErrorNULL,
+		0x07: formulaErrorDIV,
+		0x0F: formulaErrorVALUE,
+		0x17: formulaErrorREF,
+		0x1D: formulaErrorNAME,
+		0x24: formulaErrorNUM,
+		0x2A: formulaErrorNA,
+		0x2B: formulaErrorGETTINGDATA,
+	}
+	// xlsbUnderlineTypes defined the mapping of the BIFF12 font underline
+	// types and the underline style names.
+	xlsbUnderlineTypes = map[byte]string{
+		0x01: "single",
+		0x02: "double",
+		0x21: "singleAccounting",
+		0x22: "doubleAccounting",
+	}
+	// xlsbPatternTypes defined the BIFF12 fill pattern types in order.
+	xlsbPatternTypes = []string{
+		"none", "solid", "mediumGray", "darkGray", "lightGray",
+		"darkHorizontal", "darkVertical", "darkDown", "darkUp", "darkGrid",
+		"darkTrellis", "lightHorizontal", "lightVertical", "lightDown",
+		"lightUp", "lightGrid", "lightTrellis", "gray125", "gray0625",
+	}
+	// xlsbBorderStyles defined the BIFF12 border line styles in order.
+	xlsbBorderStyles = []string{
+		"", "thin", "medium", "dashed", "dotted", "thick", "double", "hair",
+		"mediumDashed", "dashDot", "mediumDashDot", "dashDotDot",
+		"mediumDashDotDot", "slantDashDot",
+	}
+	// xlsbHorizontalAlignments defined the BIFF12 horizontal alignment types
+	// in order.
+	xlsbHorizontalAlignments = []string{
+		"", "left", "center", "right", "fill", "justify", "centerContinuous",
+		"distributed",
+	}
+	// xlsbVerticalAlignments defined the BIFF12 vertical alignment types in
+	// order, the bottom alignment is the default value.
+	xlsbVerticalAlignments = []string{
+		"top", "center", "", "justify", "distributed",
+	}
+)
+
+// xlsbReader directly maps the reader of the BIFF12 records and the fields in
+// the record. The reader will be marked as failed if there is not enough
+// data for reading, and all the following read operations returns zero
+// values.
+type xlsbReader struct {
+	buf []byte
+	off int
+	err error
+}
+
+// read provides a function to read given number of bytes from the reader.
+func (r *xlsbReader) read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.buf)-r.off < n {
+		r.err = ErrWorkbookFileFormat
+		return nil
+	}
+	b := r.buf[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// uint8 provides a function to read an unsigned 8-bit integer.
+func (r *xlsbReader) uint8() uint8 {
+	if b := r.read(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+// uint16 provides a function to read an unsigned 16-bit integer in little
+// endian.
+func (r *xlsbReader) uint16() uint16 {
+	if b := r.read(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+// uint32 provides a function to read an unsigned 32-bit integer in little
+// endian.
+func (r *xlsbReader) uint32() uint32 {
+	if b := r.read(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+// float64 provides a function to read an IEEE 754 floating-point number.
+func (r *xlsbReader) float64() float64 {
+	if b := r.read(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+// varint provides a function to read a variable-length integer in the
+// record header, which encoded in up to given number of bytes, and the
+// highest bit of each byte indicates if there is another byte.
+func (r *xlsbReader) varint(n int) (v int) {
+	for i := 0; i < n; i++ {
+		b := r.uint8()
+		v |= int(b&0x7F) << (7 * i)
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return
+}
+
+// wideString provides a function to read an XLWideString or
+// XLNullableWideString, which a 32-bit characters count followed by the
+// UTF-16 encoded characters. The null string will be read as empty string.
+func (r *xlsbReader) wideString() string {
+	cch := r.uint32()
+	if cch == math.MaxUint32 || r.err != nil {
+		return ""
+	}
+	if int64(cch)*2 > int64(len(r.buf)-r.off) {
+		r.err = ErrWorkbookFileFormat
+		return ""
+	}
+	b, u := r.read(int(cch)*2), make([]uint16, cch)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// rfx provides a function to read a range reference (RfX), which contains
+// the first row, last row, first column and last column in zero-based
+// index, and returns the range reference in A1 reference style.
+func (r *xlsbReader) rfx() (string, error) {
+	rwFirst, rwLast, colFirst, colLast := r.uint32(), r.uint32(), r.uint32(), r.uint32()
+	if r.err != nil {
+		return "", r.err
+	}
+	first, err := CoordinatesToCellName(int(colFirst)+1, int(rwFirst)+1)
+	if err != nil {
+		return "", err
+	}
+	if rwFirst == rwLast && colFirst == colLast {
+		return first, err
+	}
+	last, err := CoordinatesToCellName(int(colLast)+1, int(rwLast)+1)
+	return first + ":" + last, err
+}
+
+// color provides a function to read a color (BrtColor), returns nil if the
+// color isn't set.
+func (r *xlsbReader) color() *xlsxColor {
+	flags, index, tint, rgba := r.uint8(), r.uint8(), int16(r.uint16()), r.read(4)
+	if r.err != nil {
+		return nil
+	}
+	color := &xlsxColor{}
+	switch flags >> 1 {
+	case 0:
+		color.Auto = true
+	case 1:
+		color.Indexed = int(index)
+	case 2:
+		color.RGB = fmt.Sprintf("%02X%02X%02X%02X", rgba[3], rgba[0], rgba[1], rgba[2])
+	case 3:
+		color.Theme = intPtr(int(index))
+	default:
+		return nil
+	}
+	if tint != 0 {
+		color.Tint = float64(tint) / math.MaxInt16
+	}
+	return color
+}
+
+// readXLSBRecords provides a function to walk through the BIFF12 records in
+// the binary part, and call the given function with the record type and the
+// reader of each record.
+func readXLSBRecords(data []byte, fn func(typ int, rec *xlsbReader) error) error {
+	r := &xlsbReader{buf: data}
+	for r.off < len(r.buf) {
+		typ, size := r.varint(2), r.varint(4)
+		rec := &xlsbReader{buf: r.read(size)}
+		if r.err != nil {
+			return r.err
+		}
+		if err := fn(typ, rec); err != nil {
+			return err
+		}
+		if rec.err != nil {
+			return rec.err
+		}
+	}
+	return nil
+}
+
+// xlsbRkNumber returns the number which encoded in the RkNumber structure.
+func xlsbRkNumber(rk uint32) float64 {
+	num := math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	if rk&0x02 != 0 {
+		num = float64(int32(rk) >> 2)
+	}
+	if rk&0x01 != 0 {
+		num /= 100
+	}
+	return num
+}
+
+// readBinaryWorkbook provides a function to convert the parts of the Excel
+// binary workbook (.xlsb) in the file list into the Office Open XML parts,
+// so that the workbook could be read as the XLSX workbook. The workbook,
+// worksheets, shared strings table and styles parts will be converted, the
+// other binary parts such as the calculation chain, comments and tables will
+// be dropped, and the relationships and content types will be updated
+// accordingly. The file list will not be changed if the workbook isn't a
+// binary workbook.
+func (f *File) readBinaryWorkbook(files map[string][]byte) error {
+	rootRels, err := f.decodeXLSBRels(files, "_rels/.rels")
+	if err != nil {
+		return err
+	}
+	var wbRel *xlsxRelationship
+	for i, rel := range rootRels.Relationships {
+		if rel.Type == SourceRelationshipOfficeDocument && strings.HasSuffix(strings.ToLower(rel.Target), ".bin") {
+			wbRel = &rootRels.Relationships[i]
+			break
+		}
+	}
+	if wbRel == nil {
+		return nil
+	}
+	contentTypes := new(xlsxTypes)
+	if err = f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(files[defaultXMLPathContentTypes]))).
+		Decode(contentTypes); err != nil && err != io.EOF {
+		return err
+	}
+	wbPath := strings.TrimPrefix(wbRel.Target, "/")
+	wbRels, err := f.decodeXLSBRels(files, getXLSBRelsPath(wbPath))
+	if err != nil {
+		return err
+	}
+	for i, rel := range wbRels.Relationships {
+		if rel.TargetMode == "External" || !strings.HasSuffix(strings.ToLower(rel.Target), ".bin") {
+			continue
+		}
+		partPath := path.Join(path.Dir(wbPath), rel.Target)
+		if strings.HasPrefix(rel.Target, "/") {
+			partPath = strings.TrimPrefix(rel.Target, "/")
+		}
+		var (
+			convert     func([]byte) ([]byte, error)
+			contentType string
+		)
+		switch rel.Type {
+		case SourceRelationshipWorkSheet:
+			convert, contentType = convertXLSBWorksheet, ContentTypeSpreadSheetMLWorksheet
+		case SourceRelationshipSharedStrings:
+			convert, contentType = convertXLSBSharedStrings, ContentTypeSpreadSheetMLSharedStrings
+		case SourceRelationshipStyles:
+			convert, contentType = convertXLSBStyles, ContentTypeSpreadSheetMLStyles
+		default:
+			continue
+		}
+		newPath, err := f.convertXLSBPart(files, partPath, convert)
+		if err != nil {
+			return err
+		}
+		contentTypes.setPartContentType(partPath, newPath, contentType)
+		wbRels.Relationships[i].Target = rel.Target[:len(rel.Target)-4] + ".xml"
+	}
+	newPath, err := f.convertXLSBPart(files, wbPath, convertXLSBWorkbook)
+	if err != nil {
+		return err
+	}
+	contentTypes.setPartContentType(wbPath, newPath, ContentTypeSheetML)
+	wbRel.Target = wbRel.Target[:len(wbRel.Target)-4] + ".xml"
+	delete(files, getXLSBRelsPath(wbPath))
+	dropped, err := f.dropXLSBParts(files, contentTypes)
+	if err != nil {
+		return err
+	}
+	for name, v := range map[string]interface{}{
+		"_rels/.rels":              rootRels,
+		getXLSBRelsPath(newPath):   wbRels,
+		defaultXMLPathContentTypes: contentTypes,
+	} {
+		output, err := xml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		files[name] = append([]byte(xml.Header), output...)
+	}
+	return f.dropXLSBRels(files, dropped)
+}
+
+// dropXLSBParts provides a function to remove the binary parts which haven't
+// been converted from the file list and the content types, such as the
+// calculation chain, comments and tables parts. The binary parts which are
+// also used in the XLSX workbook, such as the printer settings and VBA
+// project, will be kept. It returns the paths of the removed parts.
+func (f *File) dropXLSBParts(files map[string][]byte, ct *xlsxTypes) (map[string]bool, error) {
+	dropped := map[string]bool{}
+	for i := 0; i < len(ct.Overrides); i++ {
+		partPath := strings.TrimPrefix(ct.Overrides[i].PartName, "/")
+		if !strings.HasSuffix(strings.ToLower(partPath), ".bin") ||
+			!strings.HasPrefix(ct.Overrides[i].ContentType, xlsbContentTypePrefix) {
+			continue
+		}
+		dropped[strings.ToLower(partPath)] = true
+		delete(files, partPath)
+		delete(files, getXLSBRelsPath(partPath))
+		if tempFile, ok := f.tempFiles.LoadAndDelete(partPath); ok {
+			if err := os.Remove(tempFile.(string)); err != nil {
+				return dropped, err
+			}
+		}
+		ct.Overrides = append(ct.Overrides[:i], ct.Overrides[i+1:]...)
+		i--
+	}
+	for i, def := range ct.Defaults {
+		if strings.EqualFold(def.Extension, "bin") && strings.HasPrefix(def.ContentType, xlsbContentTypePrefix) {
+			ct.Defaults[i].ContentType = xlsbContentTypePrinterSettings
+		}
+	}
+	return dropped, nil
+}
+
+// dropXLSBRels provides a function to remove the relationships which target
+// to the dropped binary parts from the relationships parts in the file list.
+func (f *File) dropXLSBRels(files map[string][]byte, dropped map[string]bool) error {
+	for name := range files {
+		if !strings.HasSuffix(name, ".rels") {
+			continue
+		}
+		rels, err := f.decodeXLSBRels(files, name)
+		if err != nil {
+			return err
+		}
+		dir, n := path.Dir(path.Dir(name)), len(rels.Relationships)
+		for i := 0; i < len(rels.Relationships); i++ {
+			rel := rels.Relationships[i]
+			target := path.Join(dir, rel.Target)
+			if strings.HasPrefix(rel.Target, "/") {
+				target = strings.TrimPrefix(rel.Target, "/")
+			}
+			if rel.TargetMode != "External" && dropped[strings.ToLower(target)] {
+				rels.Relationships = append(rels.Relationships[:i], rels.Relationships[i+1:]...)
+				i--
+			}
+		}
+		if n != len(rels.Relationships) {
+			output, err := xml.Marshal(rels)
+			if err != nil {
+				return err
+			}
+			files[name] = append([]byte(xml.Header), output...)
+		}
+	}
+	return nil
+}
+
+// decodeXLSBRels provides a function to decode the relationships part in the
+// file list by given part path.
+func (f *File) decodeXLSBRels(files map[string][]byte, name string) (*xlsxRelationships, error) {
+	rels := new(xlsxRelationships)
+	if err := f.xmlNewDecoder(bytes.NewReader(namespaceStrictToTransitional(files[name]))).
+		Decode(rels); err != nil && err != io.EOF {
+		return rels, err
+	}
+	return rels, nil
+}
+
+// getXLSBRelsPath returns the relationships part path of the given part.
+func getXLSBRelsPath(partPath string) string {
+	return path.Join(path.Dir(partPath), "_rels", path.Base(partPath)+".rels")
+}
+
+// convertXLSBPart provides a function to convert the binary part in the file
+// list or system temporary directory by given part path and converter, and
+// store the converted part with the .xml extension name. The relationships
+// part of the binary part will be renamed accordingly. It returns the path of
+// the converted part.
+func (f *File) convertXLSBPart(files map[string][]byte, partPath string, convert func([]byte) ([]byte, error)) (string, error) {
+	data, ok := files[partPath]
+	if !ok {
+		if tempFile, ok := f.tempFiles.LoadAndDelete(partPath); ok {
+			var err error
+			if data, err = os.ReadFile(tempFile.(string)); err != nil {
+				return partPath, err
+			}
+			if err = os.Remove(tempFile.(string)); err != nil {
+				return partPath, err
+			}
+		}
+	}
+	content, err := convert(data)
+	if err != nil {
+		return partPath, err
+	}
+	newPath := partPath[:len(partPath)-4] + ".xml"
+	if strings.EqualFold(newPath, defaultXMLPathSharedStrings) {
+		newPath = defaultXMLPathSharedStrings
+	}
+	delete(files, partPath)
+	files[newPath] = content
+	if rels, ok := files[getXLSBRelsPath(partPath)]; ok {
+		delete(files, getXLSBRelsPath(partPath))
+		files[getXLSBRelsPath(newPath)] = rels
+	}
+	return newPath, err
+}
+
+// setPartContentType provides a function to replace the content type
+// override of the part with the converted part.
+func (ct *xlsxTypes) setPartContentType(partPath, newPath, contentType string) {
+	for i := 0; i < len(ct.Overrides); i++ {
+		if strings.EqualFold(strings.TrimPrefix(ct.Overrides[i].PartName, "/"), partPath) {
+			ct.Overrides = append(ct.Overrides[:i], ct.Overrides[i+1:]...)
+			i--
+		}
+	}
+	ct.Overrides = append(ct.Overrides, xlsxOverride{PartName: "/" + newPath, ContentType: contentType})
+}
+
+// marshalPart returns the XML document of the converted part.
+func marshalPart(v interface{}) ([]byte, error) {
+	output, err := xml.Marshal(v)
+	return append([]byte(xml.Header), output...), err
+}
+
+// convertXLSBWorkbook provides a function to convert the binary workbook
+// part into the workbook XML part. The sheets and the date system of the
+// workbook will be converted.
+func convertXLSBWorkbook(data []byte) ([]byte, error) {
+	wb := xlsxWorkbook{WorkbookPr: &xlsxWorkbookPr{}}
+	if err := readXLSBRecords(data, func(typ int, r *xlsbReader) error {
+		switch typ {
+		case xlsbWbProp:
+			wb.WorkbookPr.Date1904 = r.uint32()&0x01 != 0
+		case xlsbBundleSh:
+			sheet := xlsxSheet{}
+			state, sheetID := r.uint32(), r.uint32()
+			sheet.SheetID, sheet.ID, sheet.Name = int(sheetID), r.wideString(), r.wideString()
+			sheet.State = map[uint32]string{1: "hidden", 2: "veryHidden"}[state]
+			wb.Sheets.Sheet = append(wb.Sheets.Sheet, sheet)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return marshalPart(&wb)
+}
+
+// convertXLSBSharedStrings provides a function to convert the binary shared
+// strings table part into the shared strings table XML part. The rich text
+// formatting runs and phonetic properties of the strings will be ignored.
+func convertXLSBSharedStrings(data []byte) ([]byte, error) {
+	var sst xlsxSST
+	if err := readXLSBRecords(data, func(typ int, r *xlsbReader) error {
+		switch typ {
+		case xlsbBeginSst:
+			sst.Count = int(r.uint32())
+		case xlsbSSTItem:
+			r.uint8()
+			sst.SI = append(sst.SI, xlsxSI{T: &xlsxT{Val: r.wideString()}})
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	sst.UniqueCount = len(sst.SI)
+	return marshalPart(&sst)
+}
+
+// convertXLSBStyles provides a function to convert the binary styles part
+// into the styles XML part. The number formats, fonts, fills, borders, cell
+// formats and cell styles will be converted.
+func convertXLSBStyles(data []byte) ([]byte, error) {
+	ss := xlsxStyleSheet{
+		NumFmts: &xlsxNumFmts{}, Fonts: &xlsxFonts{}, Fills: &xlsxFills{}, Borders: &xlsxBorders{},
+		CellStyleXfs: &xlsxCellStyleXfs{}, CellXfs: &xlsxCellXfs{}, CellStyles: &xlsxCellStyles{},
+	}
+	var xfs *[]xlsxXf
+	if err := readXLSBRecords(data, func(typ int, r *xlsbReader) error {
+		switch typ {
+		case xlsbFmt:
+			numFmtID := int(r.uint16())
+			ss.NumFmts.NumFmt = append(ss.NumFmts.NumFmt, &xlsxNumFmt{NumFmtID: numFmtID, FormatCode: r.wideString()})
+		case xlsbFont:
+			ss.Fonts.Font = append(ss.Fonts.Font, r.font())
+		case xlsbFill:
+			ss.Fills.Fill = append(ss.Fills.Fill, r.fill())
+		case xlsbBorder:
+			ss.Borders.Border = append(ss.Borders.Border, r.border())
+		case xlsbBeginCellStyleXF:
+			xfs = &ss.CellStyleXfs.Xf
+		case xlsbBeginCellXFs:
+			xfs = &ss.CellXfs.Xf
+		case xlsbEndCellStyleXF, xlsbEndCellXFs:
+			xfs = nil
+		case xlsbXF:
+			if xfs != nil {
+				*xfs = append(*xfs, r.xf(xfs == &ss.CellXfs.Xf))
+			}
+		case xlsbStyle:
+			xfID := int(r.uint32())
+			flags, builtInID, level := r.uint16(), r.uint8(), r.uint8()
+			style := &xlsxCellStyle{XfID: xfID, Name: r.wideString()}
+			if flags&0x01 != 0 {
+				style.BuiltInID = intPtr(int(builtInID))
+				if builtInID == 1 || builtInID == 2 {
+					style.ILevel = intPtr(int(level))
+				}
+			}
+			if flags&0x02 != 0 {
+				style.Hidden = boolPtr(true)
+			}
+			if flags&0x04 != 0 {
+				style.CustomBuiltIn = boolPtr(true)
+			}
+			ss.CellStyles.CellStyle = append(ss.CellStyles.CellStyle, style)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	ss.NumFmts.Count, ss.Fonts.Count, ss.Fills.Count = len(ss.NumFmts.NumFmt), len(ss.Fonts.Font), len(ss.Fills.Fill)
+	ss.Borders.Count, ss.CellStyleXfs.Count = len(ss.Borders.Border), len(ss.CellStyleXfs.Xf)
+	ss.CellXfs.Count, ss.CellStyles.Count = len(ss.CellXfs.Xf), len(ss.CellStyles.CellStyle)
+	if ss.NumFmts.Count == 0 {
+		ss.NumFmts = nil
+	}
+	if ss.CellStyles.Count == 0 {
+		ss.CellStyles = nil
+	}
+	return marshalPart(&ss)
+}
+
+// font provides a function to read a font (BrtFont).
+func (r *xlsbReader) font() *xlsxFont {
+	height, flags, weight := r.uint16(), r.uint16(), r.uint16()
+	r.uint16()
+	underline, family, charset := r.uint8(), r.uint8(), r.uint8()
+	r.uint8()
+	color, scheme := r.color(), r.uint8()
+	font := &xlsxFont{
+		Sz:    &attrValFloat{Val: float64Ptr(float64(height) / 20)},
+		Color: color,
+		Name:  &attrValString{Val: stringPtr(r.wideString())},
+	}
+	for _, attr := range []struct {
+		val  **attrValBool
+		flag bool
+	}{
+		{&font.B, weight >= 700},
+		{&font.I, flags&0x02 != 0},
+		{&font.Strike, flags&0x08 != 0},
+		{&font.Outline, flags&0x10 != 0},
+		{&font.Shadow, flags&0x20 != 0},
+		{&font.Condense, flags&0x40 != 0},
+		{&font.Extend, flags&0x80 != 0},
+	} {
+		if attr.flag {
+			*attr.val = &attrValBool{Val: boolPtr(true)}
+		}
+	}
+	if u, ok := xlsbUnderlineTypes[underline]; ok {
+		font.U = &attrValString{Val: stringPtr(u)}
+	}
+	if family != 0 {
+		font.Family = &attrValInt{Val: intPtr(int(family))}
+	}
+	if charset != 0 {
+		font.Charset = &attrValInt{Val: intPtr(int(charset))}
+	}
+	if scheme == 1 || scheme == 2 {
+		font.Scheme = &attrValString{Val: stringPtr([]string{"major", "minor"}[scheme-1])}
+	}
+	return font
+}
+
+// fill provides a function to read a fill (BrtFill), the gradient fill will
+// be read as an empty fill.
+func (r *xlsbReader) fill() *xlsxFill {
+	pattern, fgColor, bgColor := r.uint32(), r.color(), r.color()
+	if int(pattern) >= len(xlsbPatternTypes) {
+		return &xlsxFill{}
+	}
+	fill := &xlsxFill{PatternFill: &xlsxPatternFill{PatternType: xlsbPatternTypes[pattern]}}
+	if pattern != 0 {
+		fill.PatternFill.FgColor, fill.PatternFill.BgColor = fgColor, bgColor
+	}
+	return fill
+}
+
+// border provides a function to read a border (BrtBorder).
+func (r *xlsbReader) border() *xlsxBorder {
+	flags := r.uint8()
+	border := &xlsxBorder{DiagonalDown: flags&0x01 != 0, DiagonalUp: flags&0x02 != 0}
+	for _, line := range []*xlsxLine{&border.Top, &border.Bottom, &border.Left, &border.Right, &border.Diagonal} {
+		style := r.uint8()
+		r.uint8()
+		color := r.color()
+		if style != 0 && int(style) < len(xlsbBorderStyles) {
+			line.Style, line.Color = xlsbBorderStyles[style], color
+		}
+	}
+	return border
+}
+
+// xf provides a function to read a cell format (BrtXF), the parent cell
+// style format index will be read for the cell formats.
+func (r *xlsbReader) xf(cellXf bool) xlsxXf {
+	parent, numFmtID, fontID, fillID, borderID := r.uint16(), r.uint16(), r.uint16(), r.uint16(), r.uint16()
+	rotation, indent, flags := r.uint8(), r.uint8(), r.uint16()
+	xf := xlsxXf{
+		NumFmtID: intPtr(int(numFmtID)), FontID: intPtr(int(fontID)),
+		FillID: intPtr(int(fillID)), BorderID: intPtr(int(borderID)),
+	}
+	if cellXf {
+		xf.XfID = intPtr(int(parent))
+	}
+	alignment := xlsxAlignment{
+		TextRotation:    int(rotation),
+		Indent:          int(indent),
+		WrapText:        flags&0x40 != 0,
+		JustifyLastLine: flags&0x80 != 0,
+		ShrinkToFit:     flags&0x100 != 0,
+		ReadingOrder:    uint64(flags>>10) & 0x03,
+	}
+	if h := int(flags & 0x07); h < len(xlsbHorizontalAlignments) {
+		alignment.Horizontal = xlsbHorizontalAlignments[h]
+	}
+	if v := int(flags>>3) & 0x07; v < len(xlsbVerticalAlignments) {
+		alignment.Vertical = xlsbVerticalAlignments[v]
+	}
+	if alignment != (xlsxAlignment{}) {
+		xf.Alignment, xf.ApplyAlignment = &alignment, boolPtr(true)
+	}
+	return xf
+}
+
+// convertXLSBWorksheet provides a function to convert the binary worksheet
+// part into the worksheet XML part. The cells, rows, columns, merged cells
+// and hyperlinks will be converted, the cached values of the formula cells
+// will be read as the cell values without formulas.
+func convertXLSBWorksheet(data []byte) ([]byte, error) {
+	var ws xlsxWorksheet
+	if err := readXLSBRecords(data, func(typ int, r *xlsbReader) error {
+		switch typ {
+		case xlsbRowHdr:
+			return ws.readXLSBRow(r)
+		case xlsbCellBlank, xlsbCellRk, xlsbCellError, xlsbCellBool, xlsbCellReal, xlsbCellSt,
+			xlsbCellIsst, xlsbFmlaString, xlsbFmlaNum, xlsbFmlaBool, xlsbFmlaError:
+			return ws.readXLSBCell(typ, r)
+		case xlsbWsDim:
+			ref, err := r.rfx()
+			ws.Dimension = &xlsxDimension{Ref: ref}
+			return err
+		case xlsbColInfo:
+			if ws.Cols == nil {
+				ws.Cols = &xlsxCols{}
+			}
+			first, last, width, style, flags := r.uint32(), r.uint32(), r.uint32(), r.uint32(), r.uint16()
+			ws.Cols.Col = append(ws.Cols.Col, xlsxCol{
+				Min: int(first) + 1, Max: int(last) + 1, Width: float64Ptr(float64(width) / 256), Style: int(style),
+				Hidden: flags&0x01 != 0, CustomWidth: flags&0x02 != 0, BestFit: flags&0x04 != 0,
+				Phonetic: flags&0x08 != 0, OutlineLevel: uint8(flags>>8) & 0x07, Collapsed: flags&0x1000 != 0,
+			})
+		case xlsbMergeCell:
+			ref, err := r.rfx()
+			if ws.MergeCells == nil {
+				ws.MergeCells = &xlsxMergeCells{}
+			}
+			ws.MergeCells.Cells = append(ws.MergeCells.Cells, &xlsxMergeCell{Ref: ref})
+			ws.MergeCells.Count = len(ws.MergeCells.Cells)
+			return err
+		case xlsbHLink:
+			ref, err := r.rfx()
+			if err != nil {
+				return err
+			}
+			if ws.Hyperlinks == nil {
+				ws.Hyperlinks = &xlsxHyperlinks{}
+			}
+			link := xlsxHyperlink{Ref: ref, RID: r.wideString(), Location: r.wideString()}
+			link.Tooltip, link.Display = r.wideString(), r.wideString()
+			ws.Hyperlinks.Hyperlink = append(ws.Hyperlinks.Hyperlink, link)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return marshalPart(&ws)
+}
+
+// readXLSBRow provides a function to read a row header (BrtRowHdr) and append
+// the row to the worksheet, the following cell records belong to this row.
+func (ws *xlsxWorksheet) readXLSBRow(r *xlsbReader) error {
+	row, style, height := r.uint32(), r.uint32(), r.uint16()
+	r.uint8()
+	flags := r.uint8()
+	if r.err != nil {
+		return r.err
+	}
+	if row >= TotalRows {
+		return ErrMaxRows
+	}
+	rowData := xlsxRow{
+		R: int(row) + 1, OutlineLevel: flags & 0x07, Collapsed: flags&0x08 != 0,
+		Hidden: flags&0x10 != 0, CustomHeight: flags&0x20 != 0, CustomFormat: flags&0x40 != 0,
+	}
+	if rowData.CustomFormat {
+		rowData.S = int(style)
+	}
+	if rowData.CustomHeight {
+		rowData.Ht = float64Ptr(float64(height) / 20)
+	}
+	ws.SheetData.Row = append(ws.SheetData.Row, rowData)
+	return nil
+}
+
+// readXLSBCell provides a function to read a cell record and append the cell
+// to the last row of the worksheet.
+func (ws *xlsxWorksheet) readXLSBCell(typ int, r *xlsbReader) error {
+	if len(ws.SheetData.Row) == 0 {
+		return ErrWorkbookFileFormat
+	}
+	row := &ws.SheetData.Row[len(ws.SheetData.Row)-1]
+	col, style := r.uint32(), r.uint32()&0xFFFFFF
+	if r.err != nil {
+		return r.err
+	}
+	cell, err := CoordinatesToCellName(int(col)+1, row.R)
+	if err != nil {
+		return err
+	}
+	c := xlsxC{R: cell, S: int(style)}
+	switch typ {
+	case xlsbCellRk:
+		c.V = strconv.FormatFloat(xlsbRkNumber(r.uint32()), 'f', -1, 64)
+	case xlsbCellError, xlsbFmlaError:
+		c.T, c.V = "e", xlsbErrors[r.uint8()]
+	case xlsbCellBool, xlsbFmlaBool:
+		c.T, c.V = "b", "0"
+		if r.uint8() != 0 {
+			c.V = "1"
+		}
+	case xlsbCellReal, xlsbFmlaNum:
+		c.V = strconv.FormatFloat(r.float64(), 'f', -1, 64)
+	case xlsbCellSt:
+		c.T, c.IS = "inlineStr", &xlsxSI{T: &xlsxT{Val: r.wideString()}}
+	case xlsbCellIsst:
+		c.T, c.V = "s", strconv.FormatUint(uint64(r.uint32()), 10)
+	case xlsbFmlaString:
+		c.T, c.V = "str", r.wideString()
+	}
+	row.C = append(row.C, c)
+	return r.err
+}
@@ -0,0 +1,404 @@
+package excelize
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// xlsbRecord returns the BIFF12 record by given record type and fields.
+func xlsbRecord(typ int, fields ...[]byte) []byte {
+	data := bytes.Join(fields, nil)
+	var rec []byte
+	for _, v := range []struct{ val, n int }{{typ, 2}, {len(data), 4}} {
+		for i := 0; i < v.n; i++ {
+			b := byte(v.val & 0x7F)
+			if v.val >>= 7; v.val > 0 {
+				b |= 0x80
+			}
+			rec = append(rec, b)
+			if v.val == 0 {
+				break
+			}
+		}
+	}
+	return append(rec, data...)
+}
+
+// xlsbUint returns the little endian bytes of the unsigned integer in given
+// size.
+func xlsbUint(v uint64, size int) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, v)
+	return b[:size]
+}
+
+// xlsbWideString returns the XLWideString bytes of the string.
+func xlsbWideString(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := xlsbUint(uint64(len(u)), 4)
+	for _, c := range u {
+		b = append(b, xlsbUint(uint64(c), 2)...)
+	}
+	return b
+}
+
+// xlsbCell returns the cell record by given record type, column, style and
+// value fields.
+func xlsbCell(typ, col, style int, fields ...[]byte) []byte {
+	return xlsbRecord(typ, append([][]byte{xlsbUint(uint64(col), 4), xlsbUint(uint64(style), 4)}, fields...)...)
+}
+
+// xlsbRow returns the row header record by given zero-based row number,
+// height in twips and flags.
+func xlsbRow(row, height, flags int) []byte {
+	return xlsbRecord(xlsbRowHdr, xlsbUint(uint64(row), 4), xlsbUint(0, 4), xlsbUint(uint64(height), 2),
+		[]byte{0, byte(flags), 0}, xlsbUint(0, 4))
+}
+
+// xlsbRfX returns the range reference bytes by given zero-based first row,
+// last row, first column and last column.
+func xlsbRfX(rwFirst, rwLast, colFirst, colLast int) []byte {
+	return bytes.Join([][]byte{
+		xlsbUint(uint64(rwFirst), 4), xlsbUint(uint64(rwLast), 4),
+		xlsbUint(uint64(colFirst), 4), xlsbUint(uint64(colLast), 4),
+	}, nil)
+}
+
+// xlsbColor returns the BrtColor bytes by given color type, index, tint and
+// RGB values.
+func xlsbColor(typ, index byte, tint int16, rgb ...byte) []byte {
+	rgba := []byte{0, 0, 0, 0xFF}
+	copy(rgba, rgb)
+	return append([]byte{typ << 1, index, byte(tint), byte(tint >> 8)}, rgba...)
+}
+
+// xlsbXf returns the cell format record by given parent cell style format,
+// number format, font, fill, border indexes and alignment flags.
+func xlsbXf(parent, numFmtID, fontID, fillID, borderID, flags int) []byte {
+	return xlsbRecord(xlsbXF, xlsbUint(uint64(parent), 2), xlsbUint(uint64(numFmtID), 2),
+		xlsbUint(uint64(fontID), 2), xlsbUint(uint64(fillID), 2), xlsbUint(uint64(borderID), 2),
+		[]byte{0, 0}, xlsbUint(uint64(flags), 2), []byte{0})
+}
+
+// prepareXLSBWorkbook returns a binary workbook with given parts, the default
+// parts will be used for the parts which not specified.
+func prepareXLSBWorkbook(t *testing.T, parts map[string][]byte) *bytes.Buffer {
+	font := func(height, flags, weight int, underline byte, name string) []byte {
+		return xlsbRecord(xlsbFont, xlsbUint(uint64(height), 2), xlsbUint(uint64(flags), 2),
+			xlsbUint(uint64(weight), 2), xlsbUint(0, 2), []byte{underline, 2, 0, 0},
+			xlsbColor(3, 1, 0, 0, 0, 0), []byte{2}, xlsbWideString(name))
+	}
+	files := map[string][]byte{
+		defaultXMLPathContentTypes:                []byte(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="bin" ContentType="application/vnd.ms-excel.sheet.binary.macroEnabled.main"/><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/worksheets/sheet1.bin" ContentType="application/vnd.ms-excel.worksheet"/><Override PartName="/xl/worksheets/sheet2.bin" ContentType="application/vnd.ms-excel.worksheet"/><Override PartName="/xl/sharedStrings.bin" ContentType="application/vnd.ms-excel.sharedStrings"/><Override PartName="/xl/styles.bin" ContentType="application/vnd.ms-excel.styles"/><Override PartName="/xl/calcChain.bin" ContentType="application/vnd.ms-excel.calcChain"/><Override PartName="/xl/comments1.bin" ContentType="application/vnd.ms-excel.comments"/><Override PartName="/xl/tables/table1.bin" ContentType="application/vnd.ms-excel.table"/></Types>`),
+		"_rels/.rels":                             []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.bin"/></Relationships>`),
+		"xl/_rels/workbook.bin.rels":              []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.bin"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.bin"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.bin"/><Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.bin"/><Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/printerSettings" Target="printerSettings/printerSettings1.bin"/><Relationship Id="rId6" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/theme" Target="theme/theme1.xml"/><Relationship Id="rId7" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/calcChain" Target="calcChain.bin"/></Relationships>`),
+		"xl/worksheets/_rels/sheet1.bin.rels":     []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://github.com/xuri/excelize" TargetMode="External"/><Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/comments" Target="../comments1.bin"/><Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/table" Target="/xl/tables/table1.bin"/></Relationships>`),
+		"xl/calcChain.bin":                        {0x01, 0x02},
+		"xl/comments1.bin":                        {0x01, 0x02},
+		"xl/tables/table1.bin":                    {0x01, 0x02},
+		"xl/tables/_rels/table1.bin.rels":         []byte(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`),
+		"xl/printerSettings/printerSettings1.bin": {0x01, 0x02},
+		defaultXMLPathTheme:                       []byte(templateTheme),
+		"xl/workbook.bin": bytes.Join([][]byte{
+			xlsbRecord(xlsbWbProp, xlsbUint(0, 4), xlsbUint(0, 4), xlsbWideString("")),
+			xlsbRecord(xlsbBundleSh, xlsbUint(0, 4), xlsbUint(1, 4), xlsbWideString("rId1"), xlsbWideString("Sheet1")),
+			xlsbRecord(xlsbBundleSh, xlsbUint(1, 4), xlsbUint(2, 4), xlsbWideString("rId2"), xlsbWideString("Sheet2")),
+		}, nil),
+		"xl/sharedStrings.bin": bytes.Join([][]byte{
+			xlsbRecord(xlsbBeginSst, xlsbUint(3, 4), xlsbUint(2, 4)),
+			xlsbRecord(xlsbSSTItem, []byte{0}, xlsbWideString("Hello")),
+			xlsbRecord(xlsbSSTItem, []byte{0}, xlsbWideString("世界")),
+		}, nil),
+		"xl/styles.bin": bytes.Join([][]byte{
+			xlsbRecord(xlsbFmt, xlsbUint(164, 2), xlsbWideString("0.00%")),
+			font(220, 0, 400, 0, "Calibri"),
+			font(280, 0x0A, 700, 1, "Arial"),
+			xlsbRecord(xlsbFill, xlsbUint(0, 4), xlsbColor(4, 0, 0), xlsbColor(4, 0, 0)),
+			xlsbRecord(xlsbFill, xlsbUint(1, 4), xlsbColor(2, 0, 0, 0xFF, 0, 0), xlsbColor(1, 64, 0)),
+			xlsbRecord(xlsbFill, xlsbUint(0x28, 4), xlsbColor(4, 0, 0), xlsbColor(4, 0, 0)),
+			xlsbRecord(xlsbBorder, []byte{0},
+				[]byte{1, 0}, xlsbColor(0, 0, 0), []byte{0, 0}, xlsbColor(4, 0, 0),
+				[]byte{6, 0}, xlsbColor(3, 4, -16384), []byte{0, 0}, xlsbColor(4, 0, 0),
+				[]byte{0, 0}, xlsbColor(4, 0, 0)),
+			xlsbRecord(xlsbBeginCellStyleXF, xlsbUint(1, 4)),
+			xlsbXf(0xFFFF, 0, 0, 0, 0, 0x10),
+			xlsbRecord(xlsbEndCellStyleXF),
+			xlsbRecord(xlsbBeginCellXFs, xlsbUint(4, 4)),
+			xlsbXf(0, 0, 0, 0, 0, 0x10),
+			xlsbXf(0, 164, 1, 1, 0, 0x10),
+			xlsbXf(0, 14, 0, 0, 0, 0x52),
+			xlsbXf(0, 0, 0, 2, 0, 0x0B),
+			xlsbRecord(xlsbEndCellXFs),
+			xlsbXf(0, 0, 0, 0, 0, 0),
+			xlsbRecord(xlsbStyle, xlsbUint(0, 4), xlsbUint(1, 2), []byte{0, 0xFF}, xlsbWideString("Normal")),
+		}, nil),
+		"xl/worksheets/sheet1.bin": bytes.Join([][]byte{
+			xlsbRecord(xlsbWsDim, xlsbRfX(0, 5, 0, 6)),
+			xlsbRecord(xlsbColInfo, xlsbUint(1, 4), xlsbUint(2, 4), xlsbUint(20*256, 4), xlsbUint(0, 4), xlsbUint(0x02, 2)),
+			xlsbRecord(xlsbColInfo, xlsbUint(6, 4), xlsbUint(6, 4), xlsbUint(9*256, 4), xlsbUint(0, 4), xlsbUint(0x01, 2)),
+			xlsbRow(0, 600, 0x20),
+			xlsbCell(xlsbCellIsst, 0, 0, xlsbUint(0, 4)),
+			xlsbCell(xlsbCellSt, 1, 0, xlsbWideString("inline")),
+			xlsbCell(xlsbCellRk, 2, 0, xlsbUint(123<<2|0x02, 4)),
+			xlsbCell(xlsbCellRk, 3, 0, xlsbUint(123<<2|0x03, 4)),
+			xlsbCell(xlsbCellRk, 4, 0, xlsbUint(math.Float64bits(1.5)>>32, 4)),
+			xlsbCell(xlsbCellReal, 5, 1, xlsbUint(math.Float64bits(0.5), 8)),
+			xlsbCell(xlsbCellBool, 6, 0, []byte{1}),
+			xlsbRow(2, 0, 0x10),
+			xlsbCell(xlsbFmlaString, 0, 0, xlsbWideString("calc"), xlsbUint(0, 2)),
+			xlsbCell(xlsbFmlaNum, 1, 0, xlsbUint(math.Float64bits(3.5), 8), xlsbUint(0, 2)),
+			xlsbCell(xlsbFmlaBool, 2, 0, []byte{0}, xlsbUint(0, 2)),
+			xlsbCell(xlsbFmlaError, 3, 0, []byte{0x2A}, xlsbUint(0, 2)),
+			xlsbCell(xlsbCellError, 4, 0, []byte{0x07}),
+			xlsbCell(xlsbCellReal, 5, 2, xlsbUint(math.Float64bits(45293), 8)),
+			xlsbCell(xlsbCellBlank, 6, 1),
+			xlsbRow(3, 0, 0),
+			xlsbCell(xlsbCellIsst, 0, 0, xlsbUint(1, 4)),
+			xlsbRecord(xlsbMergeCell, xlsbRfX(4, 5, 0, 1)),
+			xlsbRecord(xlsbHLink, xlsbRfX(0, 0, 0, 0), xlsbWideString("rId1"), xlsbWideString(""),
+				xlsbWideString("tooltip"), xlsbWideString("")),
+			xlsbRecord(xlsbHLink, xlsbRfX(3, 3, 0, 0), xlsbUint(math.MaxUint32, 4), xlsbWideString("Sheet2!A1"),
+				xlsbWideString(""), xlsbWideString("")),
+		}, nil),
+		"xl/worksheets/sheet2.bin": bytes.Join([][]byte{
+			xlsbRow(0, 0, 0),
+			xlsbCell(xlsbCellRk, 0, 0, xlsbUint(uint64(xlsbNegativeRk), 4)),
+		}, nil),
+	}
+	for name, content := range parts {
+		if content == nil {
+			delete(files, name)
+			continue
+		}
+		files[name] = content
+	}
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		fi, err := zw.Create(name)
+		assert.NoError(t, err)
+		_, err = fi.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf
+}
+
+// xlsbNegativeRk defined the RkNumber of the integer -5.
+var xlsbNegativeRk uint32 = 0xFFFFFFEE
+
+func TestOpenBinaryWorkbook(t *testing.T) {
+	f, err := OpenReader(prepareXLSBWorkbook(t, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Sheet1", "Sheet2"}, f.GetSheetList())
+	visible, err := f.GetSheetVisible("Sheet2")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+
+	rows, err := f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "inline", "123", "1.23", "1.5", "50.00%", "TRUE"},
+		nil,
+		{"calc", "3.5", "FALSE", "#N/A", "#DIV/0!", "01-02-24"},
+		{"世界"},
+	}, rows)
+	rows, err = f.GetRows("Sheet2")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"-5"}}, rows)
+
+	for cell, expected := range map[string]CellType{
+		"A1": CellTypeSharedString, "B1": CellTypeInlineString, "C1": CellTypeUnset,
+		"G1": CellTypeBool, "A3": CellTypeFormula, "E3": CellTypeError,
+	} {
+		cellType, err := f.GetCellType("Sheet1", cell)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, cellType, cell)
+	}
+	value, err := f.GetCellValue("Sheet1", "F1", Options{RawCellValue: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.5", value)
+
+	// Test read binary workbook by streaming rows iterator
+	sheetRows, err := f.Rows("Sheet1")
+	assert.NoError(t, err)
+	var cells []string
+	for sheetRows.Next() {
+		row, err := sheetRows.Columns()
+		assert.NoError(t, err)
+		cells = append(cells, row...)
+	}
+	assert.NoError(t, sheetRows.Close())
+	assert.Len(t, cells, 14)
+
+	// Test read rows, columns, merged cells and hyperlinks
+	height, err := f.GetRowHeight("Sheet1", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 30.0, height)
+	visible, err = f.GetRowVisible("Sheet1", 3)
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	width, err := f.GetColWidth("Sheet1", "C")
+	assert.NoError(t, err)
+	assert.Equal(t, 20.0, width)
+	visible, err = f.GetColVisible("Sheet1", "G")
+	assert.NoError(t, err)
+	assert.False(t, visible)
+	mergeCells, err := f.GetMergeCells("Sheet1")
+	assert.NoError(t, err)
+	assert.Len(t, mergeCells, 1)
+	assert.Equal(t, "A5", mergeCells[0].GetStartAxis())
+	assert.Equal(t, "B6", mergeCells[0].GetEndAxis())
+	link, target, err := f.GetCellHyperLink("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	link, target, err = f.GetCellHyperLink("Sheet1", "A4")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "Sheet2!A1", target)
+
+	// Test read styles
+	style, err := f.GetStyle(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00%", *style.CustomNumFmt)
+	assert.Equal(t, &Font{Bold: true, Italic: true, Strike: true, Underline: "single", Family: "Arial", Size: 14, Color: "", ColorTheme: intPtr(1)}, style.Font)
+	assert.Equal(t, Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}, style.Fill)
+	style, err = f.GetStyle(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 14, style.NumFmt)
+	assert.Equal(t, &Alignment{Horizontal: "center", WrapText: true}, style.Alignment)
+	assert.Len(t, style.Border, 2)
+	style, err = f.GetStyle(3)
+	assert.NoError(t, err)
+	assert.Equal(t, &Alignment{Horizontal: "right", Vertical: "center"}, style.Alignment)
+
+	// Test save the binary workbook as XLSX workbook
+	buf, err := f.WriteToBuffer()
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	f, err = OpenReader(buf)
+	assert.NoError(t, err)
+	value, err = f.GetCellValue("Sheet1", "F3")
+	assert.NoError(t, err)
+	assert.Equal(t, "01-02-24", value)
+	assert.NoError(t, f.SetCellValue("Sheet1", "A1", "World"))
+	value, err = f.GetCellValue("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.Equal(t, "World", value)
+	assert.NoError(t, f.Close())
+
+	// Test save the binary workbook as XLSX workbook by the SaveAs function, the
+	// binary parts which not converted should be dropped
+	f, err = OpenReader(prepareXLSBWorkbook(t, nil))
+	assert.NoError(t, err)
+	file := filepath.Join("test", "TestOpenBinaryWorkbook.xlsx")
+	assert.NoError(t, f.SaveAs(file))
+	assert.NoError(t, f.Close())
+	zr, err := zip.OpenReader(file)
+	assert.NoError(t, err)
+	var binParts []string
+	for _, zf := range zr.File {
+		if strings.HasSuffix(zf.Name, ".bin") || strings.HasSuffix(zf.Name, ".bin.rels") {
+			binParts = append(binParts, zf.Name)
+		}
+		if strings.HasSuffix(zf.Name, ".rels") || zf.Name == defaultXMLPathContentTypes {
+			content, err := readFile(zf)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(content), "application/vnd.ms-excel.", zf.Name)
+			for _, name := range []string{"calcChain.bin", "comments1.bin", "table1.bin", "workbook.bin", "sheet1.bin"} {
+				assert.NotContains(t, string(content), name, zf.Name)
+			}
+		}
+	}
+	assert.NoError(t, zr.Close())
+	assert.Equal(t, []string{"xl/printerSettings/printerSettings1.bin"}, binParts)
+	f, err = OpenFile(file)
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Hello", "inline", "123", "1.23", "1.5", "50.00%", "TRUE"},
+		nil,
+		{"calc", "3.5", "FALSE", "#N/A", "#DIV/0!", "01-02-24"},
+		{"世界"},
+	}, rows)
+	link, target, err = f.GetCellHyperLink("Sheet1", "A1")
+	assert.NoError(t, err)
+	assert.True(t, link)
+	assert.Equal(t, "https://github.com/xuri/excelize", target)
+	assert.NoError(t, f.Close())
+
+	// Test open binary workbook with the parts in system temporary directory
+	f, err = OpenReader(prepareXLSBWorkbook(t, nil), Options{UnzipXMLSizeLimit: 128})
+	assert.NoError(t, err)
+	value, err = f.GetCellValue("Sheet1", "A4")
+	assert.NoError(t, err)
+	assert.Equal(t, "世界", value)
+	assert.NoError(t, f.Close())
+
+	// Test open binary workbook without shared strings table and styles
+	f, err = OpenReader(prepareXLSBWorkbook(t, map[string][]byte{
+		"xl/sharedStrings.bin": nil, "xl/styles.bin": nil,
+		"xl/worksheets/sheet1.bin": xlsbRecord(xlsbWsDim, xlsbRfX(0, 0, 0, 0)),
+	}))
+	assert.NoError(t, err)
+	rows, err = f.GetRows("Sheet1")
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+	assert.NoError(t, f.Close())
+}
+
+func TestOpenBinaryWorkbookError(t *testing.T) {
+	for _, c := range []struct {
+		parts map[string][]byte
+		err   error
+	}{
+		{map[string][]byte{"xl/workbook.bin": {0x9C, 0x01, 0x04, 0x00}}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/workbook.bin": xlsbRecord(xlsbBundleSh, xlsbUint(0, 4), xlsbUint(1, 4), xlsbUint(10, 4))}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/sharedStrings.bin": {0x13, 0x80}}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/styles.bin": xlsbRecord(xlsbFont, xlsbUint(0, 2))}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbCell(xlsbCellIsst, 0, 0, xlsbUint(0, 4))}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbRecord(xlsbRowHdr, xlsbUint(0, 4))}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbRow(TotalRows, 0, 0)}, ErrMaxRows},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": append(xlsbRow(0, 0, 0), xlsbRecord(xlsbCellBlank, xlsbUint(0, 4))...)}, ErrWorkbookFileFormat},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": append(xlsbRow(0, 0, 0), xlsbCell(xlsbCellBlank, MaxColumns, 0)...)}, ErrColumnNumber},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbRecord(xlsbWsDim, xlsbRfX(0, 0, MaxColumns, MaxColumns))}, ErrColumnNumber},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbRecord(xlsbMergeCell, xlsbRfX(0, 0, 0, MaxColumns))}, ErrColumnNumber},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbRecord(xlsbHLink, xlsbRfX(0, 0, MaxColumns, MaxColumns))}, ErrColumnNumber},
+		{map[string][]byte{"xl/worksheets/sheet1.bin": xlsbRecord(xlsbHLink, xlsbUint(0, 4))}, ErrWorkbookFileFormat},
+	} {
+		_, err := OpenReader(prepareXLSBWorkbook(t, c.parts))
+		assert.Equal(t, c.err, err)
+	}
+	// Test open binary workbook with unsupported charset relationships and
+	// content types parts
+	for _, name := range []string{defaultXMLPathContentTypes, "xl/_rels/workbook.bin.rels"} {
+		_, err := OpenReader(prepareXLSBWorkbook(t, map[string][]byte{name: MacintoshCyrillicCharset}))
+		assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	}
+	_, err := OpenReader(prepareXLSBWorkbook(t, map[string][]byte{"_rels/.rels": MacintoshCyrillicCharset}))
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	_, err = OpenReader(prepareXLSBWorkbook(t, map[string][]byte{"xl/drawings/_rels/drawing1.xml.rels": MacintoshCyrillicCharset}))
+	assert.EqualError(t, err, "XML syntax error on line 1: invalid UTF-8")
+	// Test convert binary part with the temporary file doesn't exist
+	f := NewFile()
+	f.tempFiles.Store("xl/worksheets/sheet1.bin", "")
+	_, err = f.convertXLSBPart(map[string][]byte{}, "xl/worksheets/sheet1.bin", convertXLSBWorksheet)
+	assert.Error(t, err)
+}
+
+func TestXLSBRkNumber(t *testing.T) {
+	assert.Equal(t, 1.5, xlsbRkNumber(uint32(math.Float64bits(1.5)>>32)))
+	assert.Equal(t, 0.015, xlsbRkNumber(uint32(math.Float64bits(1.5)>>32)|0x01))
+	assert.Equal(t, -5.0, xlsbRkNumber(xlsbNegativeRk))
+	assert.Equal(t, 1.23, xlsbRkNumber(123<<2|0x03))
+}
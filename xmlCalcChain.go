@@ -122,3 +122,10 @@ type xlsxVolTopicRef struct {
 	R string `xml:"r,attr"`
 	S int    `xml:"s,attr"`
 }
+
+// DependencyOptions directly maps the settings of the formula cell precedents
+// and dependents query. Recursive specifies if returns the indirect
+// precedents or dependents in addition to the direct ones.
+type DependencyOptions struct {
+	Recursive bool
+}
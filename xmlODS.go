@@ -0,0 +1,314 @@
+// Copyright 2016 - 2024 The excelize Authors. All rights reserved. Use of
+// this source code is governed by a BSD-style license that can be found in
+// the LICENSE file.
+//
+// Package excelize providing a set of functions that allow you to write to and
+// read from XLAM / XLSM / XLSX / XLTM / XLTX files. Supports reading and
+// writing spreadsheet documents generated by Microsoft Excel™ 2007 and later.
+// Supports complex components by high compatibility, and provided streaming
+// API for generating or reading data from a worksheet with huge amounts of
+// data. This library needs Go version 1.18 or later.
+
+package excelize
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// decodeODSDocument defines the structure used to deserialize the root
+// element of the content.xml and styles.xml parts in the OpenDocument
+// Spreadsheet package.
+type decodeODSDocument struct {
+	Styles          decodeODSStyles      `xml:"styles"`
+	AutomaticStyles decodeODSStyles      `xml:"automatic-styles"`
+	Tables          []decodeODSTableNode `xml:"body>spreadsheet>table"`
+}
+
+// decodeODSStyles defines the structure used to deserialize the styles and
+// automatic-styles elements, the data styles such as number:number-style
+// and number:date-style will be stored in the DataStyles field.
+type decodeODSStyles struct {
+	Style      []decodeODSStyle     `xml:"style"`
+	DataStyles []decodeODSDataStyle `xml:",any"`
+}
+
+// decodeODSStyle defines the structure used to deserialize the style:style
+// element for the table, column, row and cell families.
+type decodeODSStyle struct {
+	Name                  string               `xml:"name,attr"`
+	Family                string               `xml:"family,attr"`
+	ParentStyleName       string               `xml:"parent-style-name,attr"`
+	DataStyleName         string               `xml:"data-style-name,attr"`
+	TableProperties       *decodeODSProperties `xml:"table-properties"`
+	TableColumnProperties *decodeODSProperties `xml:"table-column-properties"`
+	TableRowProperties    *decodeODSProperties `xml:"table-row-properties"`
+	TableCellProperties   *decodeODSProperties `xml:"table-cell-properties"`
+	ParagraphProperties   *decodeODSProperties `xml:"paragraph-properties"`
+	TextProperties        *decodeODSProperties `xml:"text-properties"`
+}
+
+// decodeODSProperties defines the structure used to deserialize the
+// formatting properties elements of the style:style element.
+type decodeODSProperties struct {
+	Display              string `xml:"display,attr"`
+	ColumnWidth          string `xml:"column-width,attr"`
+	RowHeight            string `xml:"row-height,attr"`
+	UseOptimalRowHeight  string `xml:"use-optimal-row-height,attr"`
+	BackgroundColor      string `xml:"background-color,attr"`
+	Border               string `xml:"border,attr"`
+	BorderLeft           string `xml:"border-left,attr"`
+	BorderRight          string `xml:"border-right,attr"`
+	BorderTop            string `xml:"border-top,attr"`
+	BorderBottom         string `xml:"border-bottom,attr"`
+	DiagonalTLBR         string `xml:"diagonal-tl-br,attr"`
+	DiagonalBLTR         string `xml:"diagonal-bl-tr,attr"`
+	WrapOption           string `xml:"wrap-option,attr"`
+	ShrinkToFit          string `xml:"shrink-to-fit,attr"`
+	VerticalAlign        string `xml:"vertical-align,attr"`
+	RotationAngle        string `xml:"rotation-angle,attr"`
+	TextAlign            string `xml:"text-align,attr"`
+	FontName             string `xml:"font-name,attr"`
+	FontFamily           string `xml:"font-family,attr"`
+	FontSize             string `xml:"font-size,attr"`
+	FontStyle            string `xml:"font-style,attr"`
+	FontWeight           string `xml:"font-weight,attr"`
+	Color                string `xml:"color,attr"`
+	TextUnderlineStyle   string `xml:"text-underline-style,attr"`
+	TextUnderlineType    string `xml:"text-underline-type,attr"`
+	TextLineThroughStyle string `xml:"text-line-through-style,attr"`
+}
+
+// decodeODSDataStyle defines the structure used to deserialize the data
+// styles, such as number:number-style, number:percentage-style,
+// number:currency-style, number:date-style, number:time-style and
+// number:text-style elements.
+type decodeODSDataStyle struct {
+	XMLName            xml.Name
+	Name               string                   `xml:"name,attr"`
+	TruncateOnOverflow string                   `xml:"truncate-on-overflow,attr"`
+	Items              []decodeODSDataStyleItem `xml:",any"`
+}
+
+// decodeODSDataStyleItem defines the structure used to deserialize the child
+// elements of the data styles.
+type decodeODSDataStyleItem struct {
+	XMLName           xml.Name
+	Style             string `xml:"style,attr"`
+	Textual           string `xml:"textual,attr"`
+	DecimalPlaces     string `xml:"decimal-places,attr"`
+	MinIntegerDigits  string `xml:"min-integer-digits,attr"`
+	MinExponentDigits string `xml:"min-exponent-digits,attr"`
+	Grouping          string `xml:"grouping,attr"`
+	ApplyStyleName    string `xml:"apply-style-name,attr"`
+	Value             string `xml:",chardata"`
+}
+
+// decodeODSTableNode defines the structure used to deserialize the
+// table:table element and its descendant column, row, cell elements and the
+// grouping elements of columns and rows.
+type decodeODSTableNode struct {
+	XMLName              xml.Name
+	Name                 string               `xml:"name,attr"`
+	StyleName            string               `xml:"style-name,attr"`
+	DefaultCellStyleName string               `xml:"default-cell-style-name,attr"`
+	Visibility           string               `xml:"visibility,attr"`
+	ColumnsRepeated      int                  `xml:"number-columns-repeated,attr"`
+	RowsRepeated         int                  `xml:"number-rows-repeated,attr"`
+	ColumnsSpanned       int                  `xml:"number-columns-spanned,attr"`
+	RowsSpanned          int                  `xml:"number-rows-spanned,attr"`
+	ValueType            string               `xml:"urn:oasis:names:tc:opendocument:xmlns:office:1.0 value-type,attr"`
+	CalcExtValueType     string               `xml:"urn:org:documentfoundation:names:experimental:calc:xmlns:calcext:1.0 value-type,attr"`
+	Value                string               `xml:"value,attr"`
+	DateValue            string               `xml:"date-value,attr"`
+	TimeValue            string               `xml:"time-value,attr"`
+	BooleanValue         string               `xml:"boolean-value,attr"`
+	StringValue          *string              `xml:"string-value,attr"`
+	Formula              string               `xml:"formula,attr"`
+	P                    []decodeODSParagraph `xml:"p"`
+	Children             []decodeODSTableNode `xml:",any"`
+}
+
+// decodeODSParagraph defines the structure used to deserialize the text:p
+// element, the text content of the paragraph includes the text of the
+// descendant text:span and text:a elements, and the text:s, text:tab and
+// text:line-break elements will be converted to the corresponding
+// characters.
+type decodeODSParagraph struct {
+	Text string
+}
+
+// UnmarshalXML extracts the text content of the text:p element by giving XML
+// decoder and start element.
+func (p *decodeODSParagraph) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var (
+		buf   strings.Builder
+		depth int
+	)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			buf.Write(t)
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "s":
+				count := 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "c" {
+						if c, err := strconv.Atoi(attr.Value); err == nil && c > 0 {
+							count = c
+						}
+					}
+				}
+				buf.WriteString(strings.Repeat(" ", count))
+			case "tab":
+				buf.WriteString("\t")
+			case "line-break":
+				buf.WriteString("\n")
+			case "note", "annotation":
+				if err = d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				p.Text = buf.String()
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// odsDocumentContent directly maps the root element of the content.xml part
+// in the OpenDocument Spreadsheet package.
+type odsDocumentContent struct {
+	XMLName         xml.Name           `xml:"office:document-content"`
+	XMLNSOffice     string             `xml:"xmlns:office,attr"`
+	XMLNSStyle      string             `xml:"xmlns:style,attr"`
+	XMLNSText       string             `xml:"xmlns:text,attr"`
+	XMLNSTable      string             `xml:"xmlns:table,attr"`
+	XMLNSFo         string             `xml:"xmlns:fo,attr"`
+	XMLNSNumber     string             `xml:"xmlns:number,attr"`
+	XMLNSOf         string             `xml:"xmlns:of,attr"`
+	Version         string             `xml:"office:version,attr"`
+	AutomaticStyles odsAutomaticStyles `xml:"office:automatic-styles"`
+	Tables          []odsTable         `xml:"office:body>office:spreadsheet>table:table"`
+}
+
+// odsAutomaticStyles directly maps the office:automatic-styles element.
+type odsAutomaticStyles struct {
+	DataStyles []odsDataStyle `xml:"number:number-style"`
+	Styles     []odsStyle     `xml:"style:style"`
+}
+
+// odsDataStyle directly maps the data style elements, the XMLName field
+// specifies the kind of the data style, such as number:date-style.
+type odsDataStyle struct {
+	XMLName            xml.Name
+	Name               string             `xml:"style:name,attr"`
+	TruncateOnOverflow string             `xml:"number:truncate-on-overflow,attr,omitempty"`
+	Items              []odsDataStyleItem `xml:"number:text"`
+}
+
+// odsDataStyleItem directly maps the child elements of the data styles, the
+// XMLName field specifies the kind of the element, such as number:year.
+type odsDataStyleItem struct {
+	XMLName          xml.Name
+	Style            string `xml:"number:style,attr,omitempty"`
+	Textual          string `xml:"number:textual,attr,omitempty"`
+	DecimalPlaces    *int   `xml:"number:decimal-places,attr"`
+	MinIntegerDigits *int   `xml:"number:min-integer-digits,attr"`
+	Grouping         bool   `xml:"number:grouping,attr,omitempty"`
+	Value            string `xml:",chardata"`
+}
+
+// odsStyle directly maps the style:style element.
+type odsStyle struct {
+	Name                  string         `xml:"style:name,attr"`
+	Family                string         `xml:"style:family,attr"`
+	ParentStyleName       string         `xml:"style:parent-style-name,attr,omitempty"`
+	DataStyleName         string         `xml:"style:data-style-name,attr,omitempty"`
+	TableProperties       *odsProperties `xml:"style:table-properties"`
+	TableColumnProperties *odsProperties `xml:"style:table-column-properties"`
+	TableRowProperties    *odsProperties `xml:"style:table-row-properties"`
+	TableCellProperties   *odsProperties `xml:"style:table-cell-properties"`
+	ParagraphProperties   *odsProperties `xml:"style:paragraph-properties"`
+	TextProperties        *odsProperties `xml:"style:text-properties"`
+}
+
+// odsProperties directly maps the formatting properties elements of the
+// style:style element.
+type odsProperties struct {
+	Display              string `xml:"table:display,attr,omitempty"`
+	ColumnWidth          string `xml:"style:column-width,attr,omitempty"`
+	RowHeight            string `xml:"style:row-height,attr,omitempty"`
+	UseOptimalRowHeight  string `xml:"style:use-optimal-row-height,attr,omitempty"`
+	BackgroundColor      string `xml:"fo:background-color,attr,omitempty"`
+	BorderLeft           string `xml:"fo:border-left,attr,omitempty"`
+	BorderRight          string `xml:"fo:border-right,attr,omitempty"`
+	BorderTop            string `xml:"fo:border-top,attr,omitempty"`
+	BorderBottom         string `xml:"fo:border-bottom,attr,omitempty"`
+	DiagonalTLBR         string `xml:"style:diagonal-tl-br,attr,omitempty"`
+	DiagonalBLTR         string `xml:"style:diagonal-bl-tr,attr,omitempty"`
+	WrapOption           string `xml:"fo:wrap-option,attr,omitempty"`
+	ShrinkToFit          string `xml:"style:shrink-to-fit,attr,omitempty"`
+	VerticalAlign        string `xml:"style:vertical-align,attr,omitempty"`
+	RotationAngle        string `xml:"style:rotation-angle,attr,omitempty"`
+	TextAlign            string `xml:"fo:text-align,attr,omitempty"`
+	FontFamily           string `xml:"fo:font-family,attr,omitempty"`
+	FontSize             string `xml:"fo:font-size,attr,omitempty"`
+	FontStyle            string `xml:"fo:font-style,attr,omitempty"`
+	FontWeight           string `xml:"fo:font-weight,attr,omitempty"`
+	Color                string `xml:"fo:color,attr,omitempty"`
+	TextUnderlineStyle   string `xml:"style:text-underline-style,attr,omitempty"`
+	TextUnderlineType    string `xml:"style:text-underline-type,attr,omitempty"`
+	TextLineThroughStyle string `xml:"style:text-line-through-style,attr,omitempty"`
+}
+
+// odsTable directly maps the table:table element.
+type odsTable struct {
+	Name      string           `xml:"table:name,attr"`
+	StyleName string           `xml:"table:style-name,attr,omitempty"`
+	Columns   []odsTableColumn `xml:"table:table-column"`
+	Rows      []odsTableRow    `xml:"table:table-row"`
+}
+
+// odsTableColumn directly maps the table:table-column element.
+type odsTableColumn struct {
+	StyleName  string `xml:"table:style-name,attr,omitempty"`
+	Visibility string `xml:"table:visibility,attr,omitempty"`
+	Repeated   int    `xml:"table:number-columns-repeated,attr,omitempty"`
+}
+
+// odsTableRow directly maps the table:table-row element.
+type odsTableRow struct {
+	StyleName  string         `xml:"table:style-name,attr,omitempty"`
+	Visibility string         `xml:"table:visibility,attr,omitempty"`
+	Repeated   int            `xml:"table:number-rows-repeated,attr,omitempty"`
+	Cells      []odsTableCell `xml:"table:table-cell"`
+}
+
+// odsTableCell directly maps the table:table-cell and
+// table:covered-table-cell elements.
+type odsTableCell struct {
+	XMLName        xml.Name
+	StyleName      string   `xml:"table:style-name,attr,omitempty"`
+	Repeated       int      `xml:"table:number-columns-repeated,attr,omitempty"`
+	ColumnsSpanned int      `xml:"table:number-columns-spanned,attr,omitempty"`
+	RowsSpanned    int      `xml:"table:number-rows-spanned,attr,omitempty"`
+	Formula        string   `xml:"table:formula,attr,omitempty"`
+	ValueType      string   `xml:"office:value-type,attr,omitempty"`
+	Value          string   `xml:"office:value,attr,omitempty"`
+	DateValue      string   `xml:"office:date-value,attr,omitempty"`
+	TimeValue      string   `xml:"office:time-value,attr,omitempty"`
+	BooleanValue   string   `xml:"office:boolean-value,attr,omitempty"`
+	P              []string `xml:"text:p"`
+}